@@ -0,0 +1,124 @@
+// Command migrate applies or reverts the embedded versioned migrations.
+//
+//	migrate up                         # public, every module in _template, then all live tenants
+//	migrate down <module> <schema> [n] # revert the last n (default 1) steps of module in schema
+//	migrate status <schema>            # list applied versions per module
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/joho/godotenv"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/database"
+	"github.com/HuynhHoangPhuc/mcs-erp/migrations"
+)
+
+func main() {
+	_ = godotenv.Load()
+
+	if err := run(context.Background(), os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "migrate:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: migrate up | down <module> <schema> [steps] | status <schema>")
+	}
+
+	dbURL := os.Getenv("DATABASE_URL")
+	if dbURL == "" {
+		return fmt.Errorf("DATABASE_URL is required")
+	}
+	pool, err := database.NewPool(ctx, dbURL)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+	migrator := database.NewMigrator(pool)
+
+	switch args[0] {
+	case "up":
+		if err := migrator.EnsureTemplateSchema(ctx); err != nil {
+			return err
+		}
+		if err := migrator.MigratePublic(ctx); err != nil {
+			return err
+		}
+		for _, module := range migrations.TenantModules {
+			if err := migrator.MigrateModule(ctx, module); err != nil {
+				return err
+			}
+		}
+		report, err := database.NewTenantRunner(pool, migrations.TenantModules, concurrency()).Run(ctx)
+		if err != nil {
+			return err
+		}
+		if failed := report.Failed(); len(failed) > 0 {
+			for _, res := range report.Results {
+				if res.Err != nil {
+					fmt.Fprintf(os.Stderr, "%s: %v\n", res.Schema, res.Err)
+				}
+			}
+			return fmt.Errorf("%d of %d tenant(s) failed and are quarantined", len(failed), len(report.Results))
+		}
+		fmt.Printf("all migrations applied (%d tenants)\n", len(report.Results))
+		return nil
+
+	case "down":
+		if len(args) < 3 {
+			return fmt.Errorf("usage: migrate down <module> <schema> [steps]")
+		}
+		steps := 1
+		if len(args) > 3 {
+			if steps, err = strconv.Atoi(args[3]); err != nil || steps < 1 {
+				return fmt.Errorf("invalid steps %q", args[3])
+			}
+		}
+		migs, err := database.LoadMigrations(args[1])
+		if err != nil {
+			return err
+		}
+		n, err := migrator.Down(ctx, args[2], migs, steps)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("reverted %d migration(s) of %s in %s\n", n, args[1], args[2])
+		return nil
+
+	case "status":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: migrate status <schema>")
+		}
+		modules := migrations.TenantModules
+		if args[1] == "public" {
+			modules = []string{migrations.Public}
+		}
+		for _, module := range modules {
+			migs, err := database.LoadMigrations(module)
+			if err != nil {
+				return err
+			}
+			applied, err := migrator.AppliedVersions(ctx, args[1], module)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%-10s applied %d/%d %v\n", module, len(applied), len(migs), applied)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown command %q", args[0])
+}
+
+// concurrency reads MIGRATION_CONCURRENCY, defaulting to 4.
+func concurrency() int {
+	if n, err := strconv.Atoi(os.Getenv("MIGRATION_CONCURRENCY")); err == nil && n > 0 {
+		return n
+	}
+	return 4
+}
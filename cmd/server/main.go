@@ -2,16 +2,20 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log/slog"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/joho/godotenv"
+	"google.golang.org/grpc"
 
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/agent"
 	agentdomain "github.com/HuynhHoangPhuc/mcs-erp/internal/agent/domain"
@@ -23,39 +27,80 @@ import (
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/database"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/eventbus"
 	platformgrpc "github.com/HuynhHoangPhuc/mcs-erp/internal/platform/grpc"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/health"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/idempotency"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/jobs"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/metrics"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/middleware"
 	platformmod "github.com/HuynhHoangPhuc/mcs-erp/internal/platform/module"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/openapi"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/outbox"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/ratelimit"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/requestinfo"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tracing"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/hr"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/room"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/subject"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/timetable"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/timetable/scheduler"
+	"github.com/HuynhHoangPhuc/mcs-erp/migrations"
+	pkgmod "github.com/HuynhHoangPhuc/mcs-erp/pkg/module"
 )
 
 func main() {
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	logLevel := new(slog.LevelVar)
+	logger := slog.New(requestinfo.LogHandler(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: logLevel})))
 	slog.SetDefault(logger)
 
 	// Load .env file if present (ignored in production where vars are injected)
 	_ = godotenv.Load()
 
-	// Load config
-	cfg, err := config.Load()
+	// server config print [flags] dumps the effective config instead of serving
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		os.Exit(configCommand(os.Args[2:]))
+	}
+
+	// Load config: defaults, YAML file, env vars, then flags
+	cfg, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
 	if err != nil {
 		slog.Error("config load failed", "error", err)
 		os.Exit(1)
 	}
+	_ = logLevel.UnmarshalText([]byte(cfg.LogLevel))
 
 	// Graceful shutdown context
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
+	// Tracing (no-op unless TRACE_EXPORTER is set)
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Config{
+		Exporter:    tracing.Exporter(cfg.Tracing.Exporter),
+		File:        cfg.Tracing.File,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		slog.Error("tracing setup failed", "error", err)
+		os.Exit(1)
+	}
+
 	// Database pool
-	pool, err := database.NewPool(ctx, cfg.DatabaseURL)
+	pool, err := database.NewPoolWithSize(ctx, cfg.Database.URL, database.PoolSize{
+		MinConns: int32(cfg.Database.MinConns),
+		MaxConns: int32(cfg.Database.MaxConns),
+	})
 	if err != nil {
 		slog.Error("database connection failed", "error", err)
 		os.Exit(1)
 	}
 	defer pool.Close()
+	if err := metrics.RegisterPool(pool); err != nil {
+		slog.Error("register pool metrics failed", "error", err)
+		os.Exit(1)
+	}
 
 	// Ensure _template schema exists
 	migrator := database.NewMigrator(pool)
@@ -64,22 +109,52 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Shared public tables (tenants, users_lookup), then platform tables (outbox)
+	// in _template. Module tables follow during Bootstrap, tenants after it.
+	if err := migrator.MigratePublic(ctx); err != nil {
+		slog.Error("public schema migration failed", "error", err)
+		os.Exit(1)
+	}
+	if err := migrator.MigrateModule(ctx, migrations.Platform); err != nil {
+		slog.Error("platform migration failed", "error", err)
+		os.Exit(1)
+	}
+
 	// Event bus
-	bus, err := eventbus.New()
+	bus, err := eventbus.New(eventbus.Config{
+		Driver:        eventbus.Driver(cfg.EventBus.Driver),
+		DatabaseURL:   cfg.Database.URL,
+		RedisURL:      cfg.Redis.URL,
+		ConsumerGroup: cfg.EventBus.ConsumerGroup,
+		MaxRetries:    cfg.EventBus.MaxRetries,
+	})
 	if err != nil {
 		slog.Error("event bus creation failed", "error", err)
 		os.Exit(1)
 	}
-	_ = bus // will be used by modules
+	defer bus.Close()
+	slog.Info("event bus ready", "driver", bus.Driver())
 
 	// JWT service
-	jwtSvc := infrastructure.NewJWTService(cfg.JWTSecret, cfg.JWTExpiry)
+	jwtSvc := infrastructure.NewJWTServiceWithRefresh(cfg.Core.JWTSecret, cfg.Core.AccessExpiry, cfg.Core.RefreshExpiry)
+
+	// Background job queue; modules register their job kinds on it
+	jobQueue := jobs.NewQueue(pool, jobs.Config{
+		Workers:      cfg.Jobs.Workers,
+		PollInterval: cfg.Jobs.PollInterval,
+		Lease:        cfg.Jobs.Lease,
+		Retention:    cfg.Jobs.Retention,
+	})
 
 	// Module registry
 	registry := platformmod.NewRegistry()
 
 	// Register core module (auth, users, roles)
 	coreMod := core.NewModuleWithDeps(pool, jwtSvc)
+	coreMod.SetPlatformAdminToken(cfg.Core.PlatformAdminToken)
+	tenantRunner := database.NewTenantRunner(pool, migrations.TenantModules, cfg.Database.MigrationConcurrency)
+	coreMod.SetMigrationRunner(tenantRunner)
+	coreMod.SetJobQueue(jobQueue)
 	if err := registry.Register(coreMod); err != nil {
 		slog.Error("failed to register core module", "error", err)
 		os.Exit(1)
@@ -113,6 +188,13 @@ func main() {
 		subjectMod.SubjectRepo(),
 		roomMod.RoomRepo(), roomMod.RoomAvailabilityRepo(),
 	)
+	timetableMod.SetSchedulerConfig(scheduler.SAConfig{
+		TInitial:    cfg.Timetable.InitialTemperature,
+		CoolingRate: cfg.Timetable.CoolingRate,
+		TMin:        cfg.Timetable.MinTemperature,
+		MaxIter:     cfg.Timetable.MaxIterations,
+	}, cfg.Timetable.Workers)
+	timetableMod.SetJobQueue(jobQueue)
 	if err := registry.Register(timetableMod); err != nil {
 		slog.Error("failed to register timetable module", "error", err)
 		os.Exit(1)
@@ -122,21 +204,21 @@ func main() {
 	toolRegistry := agentinfra.NewToolRegistry()
 	llmCfg := agentdomain.LLMConfig{
 		Primary: agentdomain.ProviderConfig{
-			Provider: agentdomain.LLMProvider(cfg.LLMProvider),
-			Model:    cfg.LLMModel,
-			APIKey:   cfg.LLMAPIKey,
-			BaseURL:  cfg.OllamaURL,
+			Provider: agentdomain.LLMProvider(cfg.Agent.Provider),
+			Model:    cfg.Agent.Model,
+			APIKey:   cfg.Agent.APIKey,
+			BaseURL:  cfg.Agent.OllamaURL,
 		},
 	}
-	if cfg.LLMFallbackProvider != "" {
+	if cfg.Agent.FallbackProvider != "" {
 		llmCfg.Fallback = &agentdomain.ProviderConfig{
-			Provider: agentdomain.LLMProvider(cfg.LLMFallbackProvider),
-			Model:    cfg.LLMFallbackModel,
-			APIKey:   cfg.LLMFallbackAPIKey,
-			BaseURL:  cfg.OllamaURL,
+			Provider: agentdomain.LLMProvider(cfg.Agent.FallbackProvider),
+			Model:    cfg.Agent.FallbackModel,
+			APIKey:   cfg.Agent.FallbackAPIKey,
+			BaseURL:  cfg.Agent.OllamaURL,
 		}
 	}
-	agentMod := agent.NewModule(pool, coreMod.AuthService(), toolRegistry, llmCfg, cfg.RedisURL)
+	agentMod := agent.NewModule(pool, coreMod.AuthService(), toolRegistry, llmCfg, cfg.Redis.URL)
 	if err := registry.Register(agentMod); err != nil {
 		slog.Error("failed to register agent module", "error", err)
 		os.Exit(1)
@@ -152,38 +234,165 @@ func main() {
 		w.Write([]byte(`{"status":"ok"}`))
 	})
 
+	// Readiness and dependency details (public, no tenant needed). Critical
+	// check failures turn /readyz into 503; the rest only report degraded.
+	checker := health.NewChecker(2 * time.Second)
+	checker.Add("platform",
+		pkgmod.HealthCheck{Name: "postgres", Critical: true, Check: pool.Ping},
+		pkgmod.HealthCheck{Name: "eventbus", Check: bus.CheckRunning},
+	)
+
+	// Rate limit buckets live in Redis, shared by replicas; while Redis is
+	// unavailable each replica limits on its own.
+	var rateStore ratelimit.Store = ratelimit.NewMemoryStore()
+	if cfg.Redis.URL != "" {
+		redisStore, err := ratelimit.NewRedisStore(cfg.Redis.URL)
+		if err != nil {
+			slog.Error("rate limit store creation failed", "error", err)
+			os.Exit(1)
+		}
+		defer redisStore.Close()
+		rateStore = ratelimit.Fallback(redisStore, rateStore)
+		checker.Add("platform", pkgmod.HealthCheck{Name: "ratelimit_redis", Check: redisStore.Ping})
+	}
+	if err := platformmod.RegisterHealthChecks(registry, checker); err != nil {
+		slog.Error("health check registration failed", "error", err)
+		os.Exit(1)
+	}
+	mux.Handle("GET /readyz", checker.ReadyHandler())
+	// Check errors name hosts and users, so details need the platform token.
+	mux.Handle("GET /healthz/details", coredelivery.PlatformAdminMiddleware(cfg.Core.PlatformAdminToken)(checker.DetailsHandler()))
+
+	// Prometheus scrape endpoint (public, no tenant needed)
+	mux.Handle("GET /metrics", metrics.Handler())
+
 	// Bootstrap modules (migrate, register routes, register events)
 	if err := platformmod.Bootstrap(ctx, registry, mux); err != nil {
 		slog.Error("module bootstrap failed", "error", err)
 		os.Exit(1)
 	}
 
-	// Wrap mux with body size limit + tenant middleware
-	handler := coredelivery.MaxBodySize(1 << 20)(tenant.Middleware(mux))
+	// OpenAPI document of the routes modules registered (public; filtered to
+	// what the caller's credentials allow)
+	routes, err := platformmod.Routes(registry)
+	if err != nil {
+		slog.Error("collect module routes failed", "error", err)
+		os.Exit(1)
+	}
+	apiDoc := openapi.NewDocument(openapi.Info{Title: "MCS ERP API", Version: "1"}, routes)
+	mux.Handle("GET "+openapi.Path, apiDoc.Handler(coreMod.RouteFilter))
 
-	// HTTP server
+	// Bring tenant schemas up to date. Failing tenants are quarantined (503)
+	// instead of blocking startup; retry them via the platform API.
+	if _, err := tenantRunner.Run(ctx); err != nil {
+		slog.Error("tenant migrations failed", "error", err)
+		os.Exit(1)
+	}
+
+	// Startup drift report: tenants that missed a migration or were hand-edited
+	if reports, err := database.NewDriftInspector(pool).InspectAll(ctx); err != nil {
+		slog.Warn("schema drift inspection failed", "error", err)
+	} else {
+		for _, r := range reports {
+			if r.HasDrift() {
+				slog.Warn("schema drift detected", "schema", r.Schema, "differences", len(r.Items), "first", r.Items[0].Object)
+			}
+		}
+		slog.Info("schema drift inspection complete", "tenants", len(reports))
+	}
+
+	// Event router consumes the handlers modules added in RegisterEvents
+	if err := bus.Start(ctx); err != nil {
+		slog.Error("event router start failed", "error", err)
+		os.Exit(1)
+	}
+
+	// Module background work, in dependency order
+	if err := platformmod.Start(ctx, registry); err != nil {
+		slog.Error("module start failed", "error", err)
+		os.Exit(1)
+	}
+
+	// Job workers run the background work modules queue
+	if err := jobQueue.Start(ctx); err != nil {
+		slog.Error("job queue start failed", "error", err)
+		os.Exit(1)
+	}
+
+	// Outbox relay publishes committed domain events to the bus
+	relay := outbox.NewRelay(pool, bus.Publisher())
+	relayDone := make(chan struct{})
+	go func() {
+		defer close(relayDone)
+		relay.Run(ctx)
+	}()
+
+	// Wrap mux in the middleware chain, outermost first: the trace span, request
+	// ID, access log, panic recovery, CORS, route timeouts, body size limit,
+	// tenant resolution, rate limits, idempotency keys and metrics. Metrics and
+	// route naming sit directly on the mux so they can read the matched route
+	// pattern (replayed idempotent responses are therefore not counted).
+	trustedProxies, err := requestinfo.ParseProxies(cfg.HTTP.TrustedProxies)
+	if err != nil {
+		slog.Error("trusted proxies invalid", "error", err)
+		os.Exit(1)
+	}
+	idem := idempotency.Middleware(idempotency.NewStore(pool, 24*time.Hour), coreMod.AuthService().ValidateToken)
+	limit := func(next http.Handler) http.Handler { return next }
+	if cfg.RateLimit.Enabled {
+		limit = ratelimit.Middleware(ratelimit.Config{
+			Store: rateStore, Rules: ratelimit.DefaultRules(),
+			Mux: mux, Routes: routes, Users: coreMod.AuthService().ValidateToken,
+		})
+	}
+	handler := middleware.Chain(mux,
+		tracing.HTTPHandler,
+		requestinfo.Middleware(trustedProxies),
+		middleware.AccessLog(logger),
+		middleware.Recover,
+		middleware.CORS(middleware.CORSConfig{Origins: cfg.HTTP.CORSAllowedOrigins, CheckTenant: coreMod.TenantDirectory().Check}),
+		middleware.Timeout(mux, cfg.HTTP.RequestTimeout, routes),
+		coredelivery.MaxBodySize(cfg.HTTP.MaxBodyBytes),
+		tenant.MiddlewareWithDirectory(coreMod.TenantDirectory()),
+		limit,
+		idem,
+		metrics.HTTPMiddleware,
+		tracing.RouteMiddleware,
+	)
+
+	// HTTP server. Requests derive from requestsCtx so shutdown can cancel
+	// long-lived ones (SSE chat, streamed gRPC generation) once the grace period ends.
+	// WriteTimeout is replaced per request by middleware.Timeout.
+	requestsCtx, cancelRequests := context.WithCancel(context.Background())
+	defer cancelRequests()
 	srv := &http.Server{
-		Addr:         ":" + cfg.Port,
+		Addr:         ":" + cfg.HTTP.Port,
 		Handler:      handler,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  60 * time.Second,
+		ReadTimeout:  cfg.HTTP.ReadTimeout,
+		WriteTimeout: cfg.HTTP.RequestTimeout,
+		IdleTimeout:  cfg.HTTP.IdleTimeout,
+		BaseContext:  func(net.Listener) context.Context { return requestsCtx },
 	}
 
 	// gRPC server
-	grpcSrv := platformgrpc.NewServer()
+	grpcPerms := platformgrpc.MethodPermissions{}
+	grpcSrv := platformgrpc.NewServer(coreMod.AuthService(), grpcPerms)
+	if err := platformmod.RegisterGRPC(registry, grpcSrv, grpcPerms); err != nil {
+		slog.Error("grpc registration failed", "error", err)
+		os.Exit(1)
+	}
 
 	// errCh collects fatal errors from server goroutines
 	errCh := make(chan error, 2)
 
 	// Start gRPC server
 	go func() {
-		lis, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+		lis, err := net.Listen("tcp", ":"+cfg.GRPC.Port)
 		if err != nil {
 			errCh <- fmt.Errorf("gRPC listen: %w", err)
 			return
 		}
-		slog.Info("gRPC server starting", "port", cfg.GRPCPort)
+		slog.Info("gRPC server starting", "port", cfg.GRPC.Port)
 		if err := grpcSrv.Serve(lis); err != nil {
 			slog.Error("gRPC server failed", "error", err)
 		}
@@ -191,7 +400,7 @@ func main() {
 
 	// Start HTTP server
 	go func() {
-		slog.Info("HTTP server starting", "port", cfg.Port)
+		slog.Info("HTTP server starting", "port", cfg.HTTP.Port)
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			errCh <- fmt.Errorf("HTTP server: %w", err)
 		}
@@ -206,14 +415,88 @@ func main() {
 	}
 	slog.Info("shutting down gracefully")
 
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
 	defer cancel()
 
-	grpcSrv.GracefulStop()
+	// 1. Stop accepting traffic; let in-flight requests and streams finish,
+	//    then cancel whatever is still running.
+	if err := stopServers(shutdownCtx, cfg.HTTP.DrainGrace, srv, grpcSrv, cancelRequests); err != nil {
+		slog.Error("server shutdown error", "error", err)
+	}
+
+	// 2. Stop the job workers; jobs still running go back to the queue.
+	if err := jobQueue.Stop(shutdownCtx); err != nil {
+		slog.Error("job queue stop error", "error", err)
+	}
 
-	if err := srv.Shutdown(shutdownCtx); err != nil {
-		slog.Error("HTTP shutdown error", "error", err)
-		os.Exit(1)
+	// 3. Stop producing and consuming events; Close waits for in-flight handlers.
+	<-relayDone
+	if err := bus.Close(); err != nil {
+		slog.Error("event bus close error", "error", err)
+	}
+
+	// 4. Modules release their resources in reverse dependency order.
+	if err := platformmod.Stop(shutdownCtx, registry); err != nil {
+		slog.Error("module stop error", "error", err)
+	}
+
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Error("tracing shutdown error", "error", err)
 	}
 	slog.Info("server stopped")
 }
+
+// configCommand runs "config print [flags]": the effective configuration as
+// YAML with secrets redacted, then any validation errors. It exits non-zero
+// if the configuration would not start the server.
+func configCommand(args []string) int {
+	if len(args) == 0 || args[0] != "print" {
+		fmt.Fprintln(os.Stderr, "usage: server config print [flags]")
+		return 2
+	}
+	cfg, err := config.Resolve(args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	if perr := cfg.Print(os.Stdout); perr != nil {
+		fmt.Fprintln(os.Stderr, "config:", perr)
+		return 1
+	}
+	if err = errors.Join(err, cfg.Validate()); err != nil {
+		fmt.Fprintln(os.Stderr, "invalid configuration:")
+		for _, line := range strings.Split(err.Error(), "\n") {
+			fmt.Fprintln(os.Stderr, "  "+line)
+		}
+		return 1
+	}
+	return 0
+}
+
+// stopServers drains the HTTP and gRPC servers. Requests and streams still
+// running after drainGrace are cancelled and awaited until ctx ends.
+func stopServers(ctx context.Context, drainGrace time.Duration, srv *http.Server, grpcSrv *grpc.Server, cancelRequests context.CancelFunc) error {
+	grpcDone := make(chan struct{})
+	go func() {
+		grpcSrv.GracefulStop()
+		close(grpcDone)
+	}()
+
+	graceCtx, cancel := context.WithTimeout(ctx, drainGrace)
+	defer cancel()
+
+	err := srv.Shutdown(graceCtx)
+	if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+		slog.Warn("cancelling in-flight HTTP requests after drain grace period")
+		cancelRequests()
+		err = srv.Shutdown(ctx)
+	}
+
+	select {
+	case <-grpcDone:
+	case <-graceCtx.Done():
+		slog.Warn("cancelling in-flight gRPC streams after drain grace period")
+		grpcSrv.Stop()
+		<-grpcDone
+	}
+	return err
+}
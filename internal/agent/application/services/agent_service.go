@@ -8,13 +8,20 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/tmc/langchaingo/llms"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/agent/domain"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/agent/infrastructure"
 	coredomain "github.com/HuynhHoangPhuc/mcs-erp/internal/core/domain"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/auth"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/metrics"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tracing"
 )
 
+var tracer = tracing.Tracer("mcs-erp/agent")
+
 const (
 	maxToolIterations = 5
 	toolTimeout       = 10 * time.Second
@@ -27,12 +34,18 @@ type ProcessMessageRequest struct {
 	UserMessage    string
 }
 
+// TenantSettingsReader returns a tenant's module switches; core's AuthService implements it.
+type TenantSettingsReader interface {
+	TenantSettings(ctx context.Context, schema string) (tenant.Settings, error)
+}
+
 // AgentService orchestrates the LLM + tool-calling loop for a single chat turn.
 type AgentService struct {
 	repo         domain.ConversationRepository
 	cache        *infrastructure.RedisMessageCache
 	registry     *infrastructure.ToolRegistry
 	providerSvc  *ProviderService
+	settings     TenantSettingsReader
 }
 
 // NewAgentService creates a wired AgentService.
@@ -41,12 +54,14 @@ func NewAgentService(
 	cache *infrastructure.RedisMessageCache,
 	registry *infrastructure.ToolRegistry,
 	providerSvc *ProviderService,
+	settings TenantSettingsReader,
 ) *AgentService {
 	return &AgentService{
 		repo:        repo,
 		cache:       cache,
 		registry:    registry,
 		providerSvc: providerSvc,
+		settings:    settings,
 	}
 }
 
@@ -78,15 +93,20 @@ func (s *AgentService) ProcessMessage(ctx context.Context, req ProcessMessageReq
 		return
 	}
 
-	// 3. Get permitted tools.
-	permittedTools := s.registry.GetTools(claims.Permissions)
+	// 3. Get permitted tools, hiding those of modules the tenant switched off.
+	settings, err := s.settings.TenantSettings(ctx, claims.TenantID)
+	if err != nil {
+		sendError(tokenCh, "failed to load tenant settings")
+		return
+	}
+	permittedTools := s.registry.GetTools(claims.Permissions, settings.ModuleEnabled)
 	systemPrompt := buildSystemPrompt(claims, permittedTools)
 
 	// 4. Build langchaingo messages.
 	lc := buildLangchainMessages(systemPrompt, history)
 
 	// 5. Get LLM.
-	llm, err := s.providerSvc.GetLLM()
+	llm, provider, err := s.providerSvc.ResolveLLM()
 	if err != nil {
 		sendError(tokenCh, "LLM provider unavailable")
 		return
@@ -113,12 +133,28 @@ func (s *AgentService) ProcessMessage(ctx context.Context, req ProcessMessageReq
 			}),
 		}
 
-		resp, err := llm.GenerateContent(ctx, lc, opts...)
+		llmCtx, span := tracer.Start(ctx, "llm.GenerateContent", trace.WithAttributes(
+			attribute.String("llm.provider", string(provider)),
+			attribute.Int("llm.iteration", iteration),
+		))
+		start := time.Now()
+		resp, err := llm.GenerateContent(llmCtx, lc, opts...)
+		metrics.ObserveLLMCall(string(provider), time.Since(start), err)
 		if err != nil {
+			tracing.RecordError(span, err)
+			span.End()
 			sendError(tokenCh, "LLM call failed")
 			return
 		}
 
+		prompt, completion := tokenUsage(resp)
+		metrics.AddLLMTokens(string(provider), prompt, completion)
+		span.SetAttributes(
+			attribute.Int("llm.tokens.prompt", prompt),
+			attribute.Int("llm.tokens.completion", completion),
+		)
+		span.End()
+
 		// Check if LLM requested tool calls.
 		toolCallRequests := extractToolCalls(resp)
 		if len(toolCallRequests) == 0 {
@@ -127,7 +163,7 @@ func (s *AgentService) ProcessMessage(ctx context.Context, req ProcessMessageReq
 		}
 
 		// 7. Execute each requested tool.
-		toolResults := s.executeTools(ctx, toolCallRequests, claims.Permissions)
+		toolResults := s.executeTools(ctx, toolCallRequests, claims.Permissions, settings.ModuleEnabled)
 		allToolCalls = append(allToolCalls, toolResults...)
 
 		// Append tool results back into the message chain for next LLM turn.
@@ -163,45 +199,59 @@ func (s *AgentService) loadHistory(ctx context.Context, conversationID uuid.UUID
 	return s.repo.ListMessages(ctx, conversationID, historyLimit)
 }
 
-// executeTools runs each tool call, checking permissions before execution.
-func (s *AgentService) executeTools(ctx context.Context, requests []toolCallRequest, userPerms []string) []domain.ToolCall {
+// executeTools runs each tool call, checking permissions and module enablement before execution.
+func (s *AgentService) executeTools(ctx context.Context, requests []toolCallRequest, userPerms []string, moduleEnabled func(string) bool) []domain.ToolCall {
 	results := make([]domain.ToolCall, 0, len(requests))
 
 	for _, req := range requests {
-		tool, ok := s.registry.GetByName(req.name)
-		if !ok {
-			results = append(results, domain.ToolCall{
-				Name:      req.name,
-				Arguments: req.args,
-				Result:    `{"error":"tool not found"}`,
-			})
-			continue
-		}
+		results = append(results, s.executeTool(ctx, req, userPerms, moduleEnabled))
+	}
+	return results
+}
 
-		// Enforce RBAC before executing tool.
-		if perm := tool.RequiredPermission(); perm != "" && !coredomain.HasPermission(userPerms, perm) {
-			results = append(results, domain.ToolCall{
-				Name:      req.name,
-				Arguments: req.args,
-				Result:    `{"error":"permission denied"}`,
-			})
-			continue
-		}
+// executeTool runs one tool call inside its own span.
+func (s *AgentService) executeTool(ctx context.Context, req toolCallRequest, userPerms []string, moduleEnabled func(string) bool) domain.ToolCall {
+	ctx, span := tracer.Start(ctx, "agent.tool", trace.WithAttributes(attribute.String("agent.tool", req.name)))
+	defer span.End()
+
+	call := domain.ToolCall{Name: req.name, Arguments: req.args}
+	outcome := metrics.OutcomeOK
+	defer func() {
+		metrics.ObserveToolCall(req.name, outcome)
+		span.SetAttributes(attribute.String("agent.tool.outcome", outcome))
+	}()
+
+	tool, ok := s.registry.GetByName(req.name)
+	if !ok {
+		outcome = metrics.OutcomeNotFound
+		call.Result = `{"error":"tool not found"}`
+		return call
+	}
 
-		toolCtx, cancel := context.WithTimeout(ctx, toolTimeout)
-		result, err := tool.Call(toolCtx, req.args)
-		cancel()
-		if err != nil {
-			result = fmt.Sprintf(`{"error":%q}`, err.Error())
-		}
+	// The LLM may name a tool it was never offered; re-check the module.
+	if !moduleEnabled(tool.Module()) {
+		outcome = metrics.OutcomeDenied
+		call.Result = `{"error":"module not enabled"}`
+		return call
+	}
 
-		results = append(results, domain.ToolCall{
-			Name:      req.name,
-			Arguments: req.args,
-			Result:    result,
-		})
+	// Enforce RBAC before executing tool.
+	if perm := tool.RequiredPermission(); perm != "" && !coredomain.HasPermission(userPerms, perm) {
+		outcome = metrics.OutcomeDenied
+		call.Result = `{"error":"permission denied"}`
+		return call
 	}
-	return results
+
+	toolCtx, cancel := context.WithTimeout(ctx, toolTimeout)
+	result, err := tool.Call(toolCtx, req.args)
+	cancel()
+	if err != nil {
+		outcome = metrics.OutcomeError
+		tracing.RecordError(span, err)
+		result = fmt.Sprintf(`{"error":%q}`, err.Error())
+	}
+	call.Result = result
+	return call
 }
 
 // --- helpers ---
@@ -274,6 +324,38 @@ func extractToolCalls(resp *llms.ContentResponse) []toolCallRequest {
 	return calls
 }
 
+// tokenUsage reads provider-reported token counts from the first choice that
+// has them; providers repeat the totals on every choice and name them differently.
+func tokenUsage(resp *llms.ContentResponse) (prompt, completion int) {
+	if resp == nil {
+		return 0, 0
+	}
+	for _, choice := range resp.Choices {
+		prompt = intInfo(choice.GenerationInfo, "PromptTokens", "InputTokens")
+		completion = intInfo(choice.GenerationInfo, "CompletionTokens", "OutputTokens")
+		if prompt > 0 || completion > 0 {
+			return prompt, completion
+		}
+	}
+	return 0, 0
+}
+
+func intInfo(info map[string]any, keys ...string) int {
+	for _, k := range keys {
+		switch v := info[k].(type) {
+		case int:
+			return v
+		case int32:
+			return int(v)
+		case int64:
+			return int(v)
+		case float64:
+			return int(v)
+		}
+	}
+	return 0
+}
+
 func appendToolResults(msgs []llms.MessageContent, _ []toolCallRequest, results []domain.ToolCall) []llms.MessageContent {
 	for _, tc := range results {
 		msgs = append(msgs, llms.MessageContent{
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/agent/domain"
@@ -26,22 +27,52 @@ func NewProviderServiceWithLLM(cfg domain.LLMConfig, llm infrastructure.LLMModel
 // GetLLM returns a usable LLM, trying primary first then fallback.
 // Returns error only if both providers fail (or no fallback configured).
 func (s *ProviderService) GetLLM() (infrastructure.LLMModel, error) {
+	llm, _, err := s.ResolveLLM()
+	return llm, err
+}
+
+// ResolveLLM is GetLLM that also reports which provider was chosen.
+func (s *ProviderService) ResolveLLM() (infrastructure.LLMModel, domain.LLMProvider, error) {
 	if s.staticLLM != nil {
-		return s.staticLLM, nil
+		provider := s.cfg.Primary.Provider
+		if provider == "" {
+			provider = "static"
+		}
+		return s.staticLLM, provider, nil
 	}
 
 	llm, err := infrastructure.NewLLM(s.cfg.Primary)
 	if err == nil {
-		return llm, nil
+		return llm, s.cfg.Primary.Provider, nil
 	}
 
 	if s.cfg.Fallback == nil {
-		return nil, fmt.Errorf("provider_service: primary provider failed and no fallback configured: %w", err)
+		return nil, "", fmt.Errorf("provider_service: primary provider failed and no fallback configured: %w", err)
 	}
 
 	fallbackLLM, fallbackErr := infrastructure.NewLLM(*s.cfg.Fallback)
 	if fallbackErr != nil {
-		return nil, fmt.Errorf("provider_service: both primary (%v) and fallback (%v) providers failed", err, fallbackErr)
+		return nil, "", fmt.Errorf("provider_service: both primary (%v) and fallback (%v) providers failed", err, fallbackErr)
+	}
+	return fallbackLLM, s.cfg.Fallback.Provider, nil
+}
+
+// Check reports whether the primary provider can be constructed, mentioning
+// the fallback when chat is being served from it. No completion is requested,
+// so it neither spends tokens nor proves the remote API is reachable.
+func (s *ProviderService) Check(_ context.Context) error {
+	if s.staticLLM != nil {
+		return nil
+	}
+	_, err := infrastructure.NewLLM(s.cfg.Primary)
+	if err == nil {
+		return nil
+	}
+	if s.cfg.Fallback == nil {
+		return fmt.Errorf("primary provider %s unavailable: %w", s.cfg.Primary.Provider, err)
+	}
+	if _, fallbackErr := infrastructure.NewLLM(*s.cfg.Fallback); fallbackErr != nil {
+		return fmt.Errorf("primary (%v) and fallback (%v) providers unavailable", err, fallbackErr)
 	}
-	return fallbackLLM, nil
+	return fmt.Errorf("primary provider %s unavailable, using fallback %s: %w", s.cfg.Primary.Provider, s.cfg.Fallback.Provider, err)
 }
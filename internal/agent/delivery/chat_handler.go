@@ -10,6 +10,9 @@ import (
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/agent/application/services"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/agent/domain"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/auth"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/problem"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/validate"
+	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
 )
 
 // ChatHandler handles the SSE chat endpoint.
@@ -23,9 +26,18 @@ func NewChatHandler(agentSvc *services.AgentService, convRepo domain.Conversatio
 	return &ChatHandler{agentSvc: agentSvc, convRepo: convRepo}
 }
 
-type chatRequest struct {
+// ChatRequest is the body of POST /api/v1/agent/chat. Without
+// conversation_id a new conversation is started.
+type ChatRequest struct {
 	ConversationID string `json:"conversation_id"`
 	Message        string `json:"message"`
+
+	conversationID *uuid.UUID
+}
+
+func (req *ChatRequest) Validate(v *validate.Validator) {
+	v.Required("message", req.Message)
+	req.conversationID = v.OptionalUUID("conversation_id", req.ConversationID)
 }
 
 // HandleChat handles POST /api/v1/agent/chat with SSE streaming response.
@@ -33,31 +45,27 @@ type chatRequest struct {
 func (h *ChatHandler) HandleChat(w http.ResponseWriter, r *http.Request) {
 	claims, err := auth.UserFromContext(r.Context())
 	if err != nil {
-		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+		problem.Write(w, r, erptypes.Unauthorized("unauthorized"))
 		return
 	}
 
-	var req chatRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
-		return
-	}
-	if req.Message == "" {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "message is required"})
+	var req ChatRequest
+	if err := validate.DecodeJSON(r, &req); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
 	// Resolve or create conversation.
-	convID, err := h.resolveConversation(r, claims, req.ConversationID)
+	convID, err := h.resolveConversation(r, claims, req.conversationID)
 	if err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		problem.Write(w, r, err)
 		return
 	}
 
 	// Verify SSE support.
 	flusher, ok := w.(http.Flusher)
 	if !ok {
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "streaming not supported"})
+		problem.Write(w, r, erptypes.Internal("streaming not supported", nil))
 		return
 	}
 
@@ -85,23 +93,19 @@ func (h *ChatHandler) HandleChat(w http.ResponseWriter, r *http.Request) {
 }
 
 // resolveConversation returns an existing conversation ID or creates a new conversation.
-func (h *ChatHandler) resolveConversation(r *http.Request, claims *auth.Claims, rawID string) (uuid.UUID, error) {
-	if rawID != "" {
-		id, err := uuid.Parse(rawID)
-		if err != nil {
-			return uuid.Nil, fmt.Errorf("invalid conversation_id")
-		}
+func (h *ChatHandler) resolveConversation(r *http.Request, claims *auth.Claims, id *uuid.UUID) (uuid.UUID, error) {
+	if id != nil {
 		// Verify conversation exists (repo uses tenant from context).
-		if _, err := h.convRepo.FindConversationByID(r.Context(), id); err != nil {
-			return uuid.Nil, fmt.Errorf("conversation not found")
+		if _, err := h.convRepo.FindConversationByID(r.Context(), *id); err != nil {
+			return uuid.Nil, erptypes.NotFoundAs(err, "conversation not found")
 		}
-		return id, nil
+		return *id, nil
 	}
 
 	// Auto-create a new conversation.
 	conv := domain.NewConversation(claims.UserID, "New conversation")
 	if err := h.convRepo.SaveConversation(r.Context(), conv); err != nil {
-		return uuid.Nil, fmt.Errorf("failed to create conversation")
+		return uuid.Nil, erptypes.Internal("failed to create conversation", err)
 	}
 	return conv.ID, nil
 }
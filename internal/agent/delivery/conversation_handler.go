@@ -1,14 +1,17 @@
 package delivery
 
 import (
-	"encoding/json"
 	"net/http"
-	"strconv"
+	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/agent/domain"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/auth"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/problem"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/validate"
+	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
 )
 
 // ConversationHandler handles conversation CRUD endpoints.
@@ -25,129 +28,126 @@ func NewConversationHandler(repo domain.ConversationRepository) *ConversationHan
 func (h *ConversationHandler) ListConversations(w http.ResponseWriter, r *http.Request) {
 	claims, err := auth.UserFromContext(r.Context())
 	if err != nil {
-		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+		problem.Write(w, r, erptypes.Unauthorized("unauthorized"))
 		return
 	}
 
-	q := r.URL.Query()
-	offset, _ := strconv.Atoi(q.Get("offset"))
-	limit, _ := strconv.Atoi(q.Get("limit"))
-	if limit <= 0 || limit > 100 {
-		limit = 20
-	}
-
-	convs, total, err := h.repo.ListConversationsByUser(r.Context(), claims.UserID, offset, limit)
+	spec, err := listquery.Parse(r.URL.Query(), domain.ConversationListSchema)
 	if err != nil {
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to list conversations"})
+		problem.Write(w, r, validate.Query(err))
 		return
 	}
 
-	items := make([]map[string]any, len(convs))
-	for i, c := range convs {
-		items[i] = conversationResponse(c)
+	page, err := h.repo.ListConversationsByUser(r.Context(), claims.UserID, spec)
+	if err != nil {
+		problem.Write(w, r, erptypes.Internal("failed to list conversations", err))
+		return
 	}
-	writeJSON(w, http.StatusOK, map[string]any{"items": items, "total": total})
+	writeJSON(w, http.StatusOK, listquery.Map(page, toConversationResponse))
 }
 
 // GetConversation handles GET /api/v1/agent/conversations/{id}
 func (h *ConversationHandler) GetConversation(w http.ResponseWriter, r *http.Request) {
 	claims, err := auth.UserFromContext(r.Context())
 	if err != nil {
-		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+		problem.Write(w, r, erptypes.Unauthorized("unauthorized"))
 		return
 	}
 
-	id, err := uuid.Parse(r.PathValue("id"))
+	id, err := validate.PathUUID(r, "id")
 	if err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid conversation id"})
+		problem.Write(w, r, err)
 		return
 	}
 
 	conv, err := h.repo.FindConversationByID(r.Context(), id)
 	if err != nil {
-		writeJSON(w, http.StatusNotFound, map[string]string{"error": "conversation not found"})
+		problem.Write(w, r, erptypes.NotFoundAs(err, "conversation not found"))
 		return
 	}
 	if conv.UserID != claims.UserID {
-		writeJSON(w, http.StatusForbidden, map[string]string{"error": "forbidden"})
+		problem.Write(w, r, erptypes.Forbidden("forbidden"))
 		return
 	}
 
 	msgs, err := h.repo.ListMessages(r.Context(), id, 50)
 	if err != nil {
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to load messages"})
+		problem.Write(w, r, erptypes.Internal("failed to load messages", err))
 		return
 	}
 
-	writeJSON(w, http.StatusOK, map[string]any{
-		"conversation": conversationResponse(conv),
-		"messages":     msgs,
-	})
+	writeJSON(w, http.StatusOK, ConversationDetailResponse{Conversation: toConversationResponse(conv), Messages: msgs})
 }
 
 // CreateConversation handles POST /api/v1/agent/conversations
 func (h *ConversationHandler) CreateConversation(w http.ResponseWriter, r *http.Request) {
 	claims, err := auth.UserFromContext(r.Context())
 	if err != nil {
-		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+		problem.Write(w, r, erptypes.Unauthorized("unauthorized"))
 		return
 	}
 
-	var body struct {
-		Title string `json:"title"`
-	}
-	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	var body CreateConversationRequest
+	if err := validate.DecodeJSON(r, &body); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
 	conv := domain.NewConversation(claims.UserID, body.Title)
 	if err := h.repo.SaveConversation(r.Context(), conv); err != nil {
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to create conversation"})
+		problem.Write(w, r, erptypes.Internal("failed to create conversation", err))
 		return
 	}
 
-	writeJSON(w, http.StatusCreated, conversationResponse(conv))
+	writeJSON(w, http.StatusCreated, toConversationResponse(conv))
+}
+
+// CreateConversationRequest is the body of POST /api/v1/agent/conversations.
+type CreateConversationRequest struct {
+	Title string `json:"title,omitempty"`
+}
+
+// UpdateConversationRequest renames a conversation.
+type UpdateConversationRequest struct {
+	Title string `json:"title"`
+}
+
+func (req *UpdateConversationRequest) Validate(v *validate.Validator) {
+	v.Required("title", req.Title)
 }
 
 // UpdateConversation handles PATCH /api/v1/agent/conversations/{id}
 func (h *ConversationHandler) UpdateConversation(w http.ResponseWriter, r *http.Request) {
 	claims, err := auth.UserFromContext(r.Context())
 	if err != nil {
-		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+		problem.Write(w, r, erptypes.Unauthorized("unauthorized"))
 		return
 	}
 
-	id, err := uuid.Parse(r.PathValue("id"))
+	id, err := validate.PathUUID(r, "id")
 	if err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid conversation id"})
+		problem.Write(w, r, err)
 		return
 	}
 
 	conv, err := h.repo.FindConversationByID(r.Context(), id)
 	if err != nil {
-		writeJSON(w, http.StatusNotFound, map[string]string{"error": "conversation not found"})
+		problem.Write(w, r, erptypes.NotFoundAs(err, "conversation not found"))
 		return
 	}
 	if conv.UserID != claims.UserID {
-		writeJSON(w, http.StatusForbidden, map[string]string{"error": "forbidden"})
+		problem.Write(w, r, erptypes.Forbidden("forbidden"))
 		return
 	}
 
-	var body struct {
-		Title string `json:"title"`
-	}
-	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
-		return
-	}
-	if body.Title == "" {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "title is required"})
+	var body UpdateConversationRequest
+	if err := validate.DecodeJSON(r, &body); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
 	if err := h.repo.UpdateConversationTitle(r.Context(), id, body.Title); err != nil {
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to update conversation"})
+		problem.Write(w, r, erptypes.Internal("failed to update conversation", err))
 		return
 	}
 
@@ -158,40 +158,49 @@ func (h *ConversationHandler) UpdateConversation(w http.ResponseWriter, r *http.
 func (h *ConversationHandler) DeleteConversation(w http.ResponseWriter, r *http.Request) {
 	claims, err := auth.UserFromContext(r.Context())
 	if err != nil {
-		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+		problem.Write(w, r, erptypes.Unauthorized("unauthorized"))
 		return
 	}
 
-	id, err := uuid.Parse(r.PathValue("id"))
+	id, err := validate.PathUUID(r, "id")
 	if err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid conversation id"})
+		problem.Write(w, r, err)
 		return
 	}
 
 	conv, err := h.repo.FindConversationByID(r.Context(), id)
 	if err != nil {
-		writeJSON(w, http.StatusNotFound, map[string]string{"error": "conversation not found"})
+		problem.Write(w, r, erptypes.NotFoundAs(err, "conversation not found"))
 		return
 	}
 	if conv.UserID != claims.UserID {
-		writeJSON(w, http.StatusForbidden, map[string]string{"error": "forbidden"})
+		problem.Write(w, r, erptypes.Forbidden("forbidden"))
 		return
 	}
 
 	if err := h.repo.DeleteConversation(r.Context(), id); err != nil {
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to delete conversation"})
+		problem.Write(w, r, erptypes.Internal("failed to delete conversation", err))
 		return
 	}
 
 	writeJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
 }
 
-func conversationResponse(c *domain.Conversation) map[string]any {
-	return map[string]any{
-		"id":         c.ID,
-		"user_id":    c.UserID,
-		"title":      c.Title,
-		"created_at": c.CreatedAt,
-		"updated_at": c.UpdatedAt,
-	}
+// ConversationResponse is the JSON representation of a Conversation.
+type ConversationResponse struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Title     string    `json:"title"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ConversationDetailResponse is a conversation with its latest messages.
+type ConversationDetailResponse struct {
+	Conversation ConversationResponse `json:"conversation"`
+	Messages     []*domain.Message    `json:"messages"`
+}
+
+func toConversationResponse(c *domain.Conversation) ConversationResponse {
+	return ConversationResponse{ID: c.ID, UserID: c.UserID, Title: c.Title, CreatedAt: c.CreatedAt, UpdatedAt: c.UpdatedAt}
 }
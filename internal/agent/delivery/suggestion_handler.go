@@ -19,6 +19,13 @@ func NewSuggestionHandler() *SuggestionHandler {
 	return &SuggestionHandler{}
 }
 
+// SuggestionsResponse lists the suggestions for an entity.
+type SuggestionsResponse struct {
+	EntityType  string       `json:"entity_type,omitempty"`
+	EntityID    string       `json:"entity_id,omitempty"`
+	Suggestions []Suggestion `json:"suggestions"`
+}
+
 // suggestionRules maps entity_type to contextual suggestions.
 // Rule-based: no LLM call needed — deterministic based on entity type.
 var suggestionRules = map[string][]Suggestion{
@@ -53,7 +60,7 @@ func (h *SuggestionHandler) HandleSuggestions(w http.ResponseWriter, r *http.Req
 	suggestions, ok := suggestionRules[entityType]
 	if !ok {
 		// Unknown entity type — return empty list, not an error.
-		writeJSON(w, http.StatusOK, map[string]any{"suggestions": []Suggestion{}})
+		writeJSON(w, http.StatusOK, SuggestionsResponse{Suggestions: []Suggestion{}})
 		return
 	}
 
@@ -66,9 +73,5 @@ func (h *SuggestionHandler) HandleSuggestions(w http.ResponseWriter, r *http.Req
 		}
 	}
 
-	writeJSON(w, http.StatusOK, map[string]any{
-		"entity_type": entityType,
-		"entity_id":   entityID,
-		"suggestions": enriched,
-	})
+	writeJSON(w, http.StatusOK, SuggestionsResponse{EntityType: entityType, EntityID: entityID, Suggestions: enriched})
 }
@@ -4,14 +4,28 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
 )
 
+// ConversationListSchema declares how a user's conversations can be filtered and sorted.
+var ConversationListSchema = &listquery.Schema{
+	Fields: map[string]listquery.Field{
+		"id":         {Kind: listquery.UUID, Filter: listquery.Eq | listquery.In, Sort: true},
+		"title":      {Kind: listquery.Text, Filter: listquery.Eq | listquery.Contains, Sort: true},
+		"created_at": {Kind: listquery.Time, Filter: listquery.Range, Sort: true},
+		"updated_at": {Kind: listquery.Time, Filter: listquery.Range, Sort: true},
+	},
+	Key:         "id",
+	DefaultSort: []listquery.Sort{{Field: "updated_at", Desc: true}},
+}
+
 // ConversationRepository defines persistence operations for conversations and messages.
 type ConversationRepository interface {
 	// Conversation CRUD
 	SaveConversation(ctx context.Context, c *Conversation) error
 	FindConversationByID(ctx context.Context, id uuid.UUID) (*Conversation, error)
-	ListConversationsByUser(ctx context.Context, userID uuid.UUID, offset, limit int) ([]*Conversation, int, error)
+	ListConversationsByUser(ctx context.Context, userID uuid.UUID, spec listquery.Spec) (listquery.Page[*Conversation], error)
 	DeleteConversation(ctx context.Context, id uuid.UUID) error
 	UpdateConversationTitle(ctx context.Context, id uuid.UUID, title string) error
 
@@ -9,6 +9,9 @@ type AgentTool interface {
 	Name() string
 	// Description explains what the tool does (shown to LLM in system prompt).
 	Description() string
+	// Module returns the owning module; the tool is hidden from tenants that
+	// switched the module off.
+	Module() string
 	// RequiredPermission returns the RBAC permission string needed to use this tool.
 	// Empty string means no permission required.
 	RequiredPermission() string
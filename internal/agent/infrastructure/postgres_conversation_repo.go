@@ -3,6 +3,7 @@ package infrastructure
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/agent/domain"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/database"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
 	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
 )
@@ -62,7 +64,7 @@ func (r *PostgresConversationRepo) FindConversationByID(ctx context.Context, id
 		).Scan(&c.ID, &c.UserID, &c.Title, &c.CreatedAt, &c.UpdatedAt)
 	})
 	if err != nil {
-		if err == pgx.ErrNoRows {
+		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, erptypes.ErrNotFound
 		}
 		return nil, fmt.Errorf("find conversation by id: %w", err)
@@ -70,47 +72,33 @@ func (r *PostgresConversationRepo) FindConversationByID(ctx context.Context, id
 	return &c, nil
 }
 
-// ListConversationsByUser returns paginated conversations for a user, newest first.
-func (r *PostgresConversationRepo) ListConversationsByUser(ctx context.Context, userID uuid.UUID, offset, limit int) ([]*domain.Conversation, int, error) {
+// conversationScope lets the repository restrict lists to one user.
+var conversationScope = domain.ConversationListSchema.With("user_id", listquery.Field{Kind: listquery.UUID, Filter: listquery.Eq})
+
+// ListConversationsByUser returns one page of a user's conversations, newest first by default.
+func (r *PostgresConversationRepo) ListConversationsByUser(ctx context.Context, userID uuid.UUID, spec listquery.Spec) (listquery.Page[*domain.Conversation], error) {
 	schema, err := r.schema(ctx)
 	if err != nil {
-		return nil, 0, err
+		return listquery.Page[*domain.Conversation]{}, err
+	}
+	q, err := listquery.Build(spec.Where("user_id", userID.String()), conversationScope)
+	if err != nil {
+		return listquery.Page[*domain.Conversation]{}, err
 	}
 
-	var conversations []*domain.Conversation
-	var total int
-
+	var page listquery.Page[*domain.Conversation]
 	err = database.WithTenantTx(ctx, r.pool, schema, func(tx pgx.Tx) error {
-		if err := tx.QueryRow(ctx,
-			`SELECT COUNT(*) FROM conversations WHERE user_id = $1`, userID,
-		).Scan(&total); err != nil {
-			return err
-		}
-
-		rows, err := tx.Query(ctx,
-			`SELECT id, user_id, title, created_at, updated_at
-			 FROM conversations WHERE user_id = $1
-			 ORDER BY updated_at DESC LIMIT $2 OFFSET $3`,
-			userID, limit, offset,
-		)
-		if err != nil {
-			return err
-		}
-		defer rows.Close()
-
-		for rows.Next() {
-			var c domain.Conversation
-			if err := rows.Scan(&c.ID, &c.UserID, &c.Title, &c.CreatedAt, &c.UpdatedAt); err != nil {
-				return err
-			}
-			conversations = append(conversations, &c)
-		}
-		return rows.Err()
+		page, err = listquery.Fetch(ctx, tx, q, "id, user_id, title, created_at, updated_at", "conversations",
+			func(rows pgx.Rows, cursor *string) (*domain.Conversation, error) {
+				var c domain.Conversation
+				return &c, rows.Scan(&c.ID, &c.UserID, &c.Title, &c.CreatedAt, &c.UpdatedAt, cursor)
+			})
+		return err
 	})
 	if err != nil {
-		return nil, 0, fmt.Errorf("list conversations: %w", err)
+		return listquery.Page[*domain.Conversation]{}, fmt.Errorf("list conversations: %w", err)
 	}
-	return conversations, total, nil
+	return page, nil
 }
 
 // DeleteConversation removes a conversation and cascades to messages.
@@ -91,3 +91,8 @@ func (c *RedisMessageCache) InvalidateConversation(ctx context.Context, conversa
 func (c *RedisMessageCache) Ping(ctx context.Context) error {
 	return c.client.Ping(ctx).Err()
 }
+
+// Close releases the Redis connection pool.
+func (c *RedisMessageCache) Close() error {
+	return c.client.Close()
+}
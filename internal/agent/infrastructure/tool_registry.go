@@ -31,14 +31,18 @@ func (r *ToolRegistry) Register(t agentdomain.AgentTool) {
 	r.tools[t.Name()] = t
 }
 
-// GetTools returns all tools the user is permitted to use based on their permissions.
-// Tools with an empty RequiredPermission are always included.
-func (r *ToolRegistry) GetTools(userPerms []string) []agentdomain.AgentTool {
+// GetTools returns all tools the user is permitted to use based on their permissions
+// and the modules enabled for their tenant; a nil moduleEnabled allows every module.
+// Tools with an empty RequiredPermission are included unless their module is disabled.
+func (r *ToolRegistry) GetTools(userPerms []string, moduleEnabled func(module string) bool) []agentdomain.AgentTool {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	var permitted []agentdomain.AgentTool
 	for _, t := range r.tools {
+		if moduleEnabled != nil && !moduleEnabled(t.Module()) {
+			continue
+		}
 		req := t.RequiredPermission()
 		if req == "" || coredomain.HasPermission(userPerms, req) {
 			permitted = append(permitted, t)
@@ -2,6 +2,7 @@ package agent
 
 import (
 	"context"
+	"log/slog"
 	"net/http"
 
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -13,7 +14,10 @@ import (
 	coreservices "github.com/HuynhHoangPhuc/mcs-erp/internal/core/application/services"
 	coredelivery "github.com/HuynhHoangPhuc/mcs-erp/internal/core/delivery"
 	coredomain "github.com/HuynhHoangPhuc/mcs-erp/internal/core/domain"
-	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/auth"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/database"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/openapi"
+	pkgmod "github.com/HuynhHoangPhuc/mcs-erp/pkg/module"
 )
 
 // Module implements pkg/module.Module for the AI Agent bounded context.
@@ -24,7 +28,9 @@ type Module struct {
 	providerSvc *services.ProviderService
 	convRepo    domain.ConversationRepository
 	cache       *infrastructure.RedisMessageCache
+	cacheErr    error
 	agentSvc    *services.AgentService
+	routes      []pkgmod.Route
 }
 
 // NewModule wires all agent dependencies.
@@ -49,14 +55,18 @@ func NewModuleWithProvider(
 ) *Module {
 	convRepo := infrastructure.NewPostgresConversationRepo(pool)
 
+	// Chat works without the cache, so a bad URL only disables it; the
+	// reason is logged and kept for the redis_cache health check.
 	var cache *infrastructure.RedisMessageCache
+	var cacheErr error
 	if redisURL != "" {
-		if c, err := infrastructure.NewRedisMessageCache(redisURL); err == nil {
-			cache = c
+		cache, cacheErr = infrastructure.NewRedisMessageCache(redisURL)
+		if cacheErr != nil {
+			slog.Warn("agent message cache disabled", "error", cacheErr)
 		}
 	}
 
-	agentSvc := services.NewAgentService(convRepo, cache, registry, providerSvc)
+	agentSvc := services.NewAgentService(convRepo, cache, registry, providerSvc, authSvc)
 
 	return &Module{
 		pool:        pool,
@@ -65,6 +75,7 @@ func NewModuleWithProvider(
 		providerSvc: providerSvc,
 		convRepo:    convRepo,
 		cache:       cache,
+		cacheErr:    cacheErr,
 		agentSvc:    agentSvc,
 	}
 }
@@ -74,34 +85,85 @@ func (m *Module) ToolRegistry() *infrastructure.ToolRegistry { return m.registry
 
 func (m *Module) Name() string                           { return "agent" }
 func (m *Module) Dependencies() []string                 { return []string{"core"} }
-func (m *Module) Migrate(_ context.Context) error        { return nil }
+// Migrate applies the module's pending migrations to _template; tenants follow via database.TenantRunner.
+func (m *Module) Migrate(ctx context.Context) error {
+	return database.NewMigrator(m.pool).MigrateModule(ctx, m.Name())
+}
 func (m *Module) RegisterEvents(_ context.Context) error { return nil }
 
+// HealthChecks covers the Redis message cache and the LLM provider. Neither is
+// critical: chat falls back to Postgres history, and the rest of the ERP does
+// not need the LLM.
+func (m *Module) HealthChecks() []pkgmod.HealthCheck {
+	checks := []pkgmod.HealthCheck{{Name: "llm_provider", Check: m.providerSvc.Check}}
+	switch {
+	case m.cacheErr != nil:
+		checks = append(checks, pkgmod.HealthCheck{Name: "redis_cache", Check: func(context.Context) error { return m.cacheErr }})
+	case m.cache != nil:
+		checks = append(checks, pkgmod.HealthCheck{Name: "redis_cache", Check: m.cache.Ping})
+	}
+	return checks
+}
+
+// Stop closes the Redis message cache. In-flight chats have already been
+// drained or cancelled with the HTTP server.
+func (m *Module) Stop(_ context.Context) error {
+	if m.cache == nil {
+		return nil
+	}
+	return m.cache.Close()
+}
+
+// Routes describes the routes mounted by RegisterRoutes.
+func (m *Module) Routes() []pkgmod.Route { return m.routes }
+
 func (m *Module) RegisterRoutes(mux *http.ServeMux) {
 	chatHandler := delivery.NewChatHandler(m.agentSvc, m.convRepo)
 	convHandler := delivery.NewConversationHandler(m.convRepo)
 	suggHandler := delivery.NewSuggestionHandler()
 
-	authMw := coredelivery.AuthMiddleware(m.authSvc)
-	requireChat := auth.RequirePermission(coredomain.PermAgentChat)
+	api := openapi.NewRouter(mux, m.Name(), coredelivery.ModuleAuthMiddleware(m.authSvc, m.Name()))
+	chat := coredomain.PermAgentChat
 
 	// Chat (SSE streaming)
-	mux.Handle("POST /api/v1/agent/chat",
-		authMw(requireChat(http.HandlerFunc(chatHandler.HandleChat))))
+	api.Handle(pkgmod.Route{
+		Method: "POST", Path: "/api/v1/agent/chat", Summary: "Send a message and stream the reply", Permission: chat,
+		OperationID: "chat",
+		Request:     delivery.ChatRequest{}, Produces: "text/event-stream", Timeout: -1, RateClass: pkgmod.RateLLM,
+	}, chatHandler.HandleChat)
 
 	// Conversations CRUD
-	mux.Handle("GET /api/v1/agent/conversations",
-		authMw(requireChat(http.HandlerFunc(convHandler.ListConversations))))
-	mux.Handle("POST /api/v1/agent/conversations",
-		authMw(requireChat(http.HandlerFunc(convHandler.CreateConversation))))
-	mux.Handle("GET /api/v1/agent/conversations/{id}",
-		authMw(requireChat(http.HandlerFunc(convHandler.GetConversation))))
-	mux.Handle("PATCH /api/v1/agent/conversations/{id}",
-		authMw(requireChat(http.HandlerFunc(convHandler.UpdateConversation))))
-	mux.Handle("DELETE /api/v1/agent/conversations/{id}",
-		authMw(requireChat(http.HandlerFunc(convHandler.DeleteConversation))))
+	api.Handle(pkgmod.Route{
+		Method: "GET", Path: "/api/v1/agent/conversations", Summary: "List the caller's conversations", Permission: chat,
+		Query: openapi.ListParams(domain.ConversationListSchema), Response: listquery.Page[delivery.ConversationResponse]{},
+	}, convHandler.ListConversations)
+	api.Handle(pkgmod.Route{
+		Method: "POST", Path: "/api/v1/agent/conversations", Summary: "Start a conversation", Permission: chat,
+		Request: delivery.CreateConversationRequest{}, Response: delivery.ConversationResponse{}, Status: http.StatusCreated,
+	}, convHandler.CreateConversation)
+	api.Handle(pkgmod.Route{
+		Method: "GET", Path: "/api/v1/agent/conversations/{id}", Summary: "Get a conversation with its latest messages", Permission: chat,
+		Response: delivery.ConversationDetailResponse{},
+	}, convHandler.GetConversation)
+	api.Handle(pkgmod.Route{
+		Method: "PATCH", Path: "/api/v1/agent/conversations/{id}", Summary: "Rename a conversation", Permission: chat,
+		Request: delivery.UpdateConversationRequest{}, Response: map[string]string{},
+	}, convHandler.UpdateConversation)
+	api.Handle(pkgmod.Route{
+		Method: "DELETE", Path: "/api/v1/agent/conversations/{id}", Summary: "Delete a conversation", Permission: chat,
+		Response: map[string]string{},
+	}, convHandler.DeleteConversation)
 
 	// Inline suggestions (rule-based, no LLM call)
-	mux.Handle("GET /api/v1/agent/suggestions",
-		authMw(requireChat(http.HandlerFunc(suggHandler.HandleSuggestions))))
+	api.Handle(pkgmod.Route{
+		Method: "GET", Path: "/api/v1/agent/suggestions", Summary: "Suggest prompts for an entity", Permission: chat,
+		OperationID: "listSuggestions",
+		Query: []pkgmod.Param{
+			{Name: "entity_type", Description: "teacher, subject, room, timetable or semester"},
+			{Name: "entity_id"},
+		},
+		Response: delivery.SuggestionsResponse{},
+	}, suggHandler.HandleSuggestions)
+
+	m.routes = api.Routes()
 }
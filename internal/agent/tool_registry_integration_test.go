@@ -0,0 +1,37 @@
+//go:build integration
+
+package agent_test
+
+import (
+	"context"
+	"testing"
+
+	agentinfra "github.com/HuynhHoangPhuc/mcs-erp/internal/agent/infrastructure"
+	coredomain "github.com/HuynhHoangPhuc/mcs-erp/internal/core/domain"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
+)
+
+type stubTool struct{ name, module, perm string }
+
+func (s stubTool) Name() string                                 { return s.name }
+func (s stubTool) Description() string                          { return s.name }
+func (s stubTool) Module() string                               { return s.module }
+func (s stubTool) RequiredPermission() string                   { return s.perm }
+func (s stubTool) Call(context.Context, string) (string, error) { return "{}", nil }
+
+func TestToolRegistry_HidesToolsOfDisabledModules(t *testing.T) {
+	reg := agentinfra.NewToolRegistry()
+	reg.Register(stubTool{name: "list_teachers", module: "hr", perm: coredomain.PermTeacherRead})
+	reg.Register(stubTool{name: "list_rooms", module: "room", perm: coredomain.PermRoomRead})
+
+	perms := []string{coredomain.PermTeacherRead, coredomain.PermRoomRead}
+	settings := tenant.Settings{Modules: map[string]bool{"hr": false}}
+
+	tools := reg.GetTools(perms, settings.ModuleEnabled)
+	if len(tools) != 1 || tools[0].Name() != "list_rooms" {
+		t.Fatalf("expected only list_rooms, got %v", tools)
+	}
+	if got := reg.GetTools(perms, nil); len(got) != 2 {
+		t.Fatalf("nil filter should allow every module, got %d tools", len(got))
+	}
+}
@@ -12,12 +12,21 @@ import (
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
 )
 
+// TenantChecker reports whether a tenant may currently be served and which
+// modules and features it has.
+type TenantChecker interface {
+	Check(ctx context.Context, schema string) error
+	CheckModule(ctx context.Context, schema, module string) error
+	Settings(ctx context.Context, schema string) (tenant.Settings, error)
+}
+
 // AuthService handles authentication (login, refresh, validate).
 type AuthService struct {
 	userRepo   domain.UserRepository
 	roleRepo   domain.RoleRepository
 	lookupRepo domain.UsersLookupRepository
 	jwt        *infrastructure.JWTService
+	tenants    TenantChecker
 }
 
 // NewAuthService creates a new auth service.
@@ -26,12 +35,14 @@ func NewAuthService(
 	roleRepo domain.RoleRepository,
 	lookupRepo domain.UsersLookupRepository,
 	jwt *infrastructure.JWTService,
+	tenants TenantChecker,
 ) *AuthService {
 	return &AuthService{
 		userRepo:   userRepo,
 		roleRepo:   roleRepo,
 		lookupRepo: lookupRepo,
 		jwt:        jwt,
+		tenants:    tenants,
 	}
 }
 
@@ -59,6 +70,11 @@ func (s *AuthService) Login(ctx context.Context, email, password string) (*infra
 		return nil, fmt.Errorf("invalid credentials")
 	}
 
+	// Only reveal tenant state to callers holding valid credentials.
+	if err := s.CheckTenant(ctx, schema); err != nil {
+		return nil, err
+	}
+
 	// Gather permissions from all roles
 	perms, err := s.getUserPermissions(ctx, user)
 	if err != nil {
@@ -75,6 +91,10 @@ func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*infras
 		return nil, fmt.Errorf("invalid refresh token: %w", err)
 	}
 
+	if err := s.CheckTenant(ctx, claims.TenantID); err != nil {
+		return nil, err
+	}
+
 	// Set tenant context
 	ctx = tenant.WithTenant(ctx, claims.TenantID)
 
@@ -100,6 +120,33 @@ func (s *AuthService) ValidateToken(token string) (*auth.Claims, error) {
 	return s.jwt.ValidateToken(token)
 }
 
+// CheckTenant returns a tenant availability error (see tenant.HTTPStatus) when
+// the tenant is unknown, suspended or archived.
+func (s *AuthService) CheckTenant(ctx context.Context, schema string) error {
+	if s.tenants == nil {
+		return nil
+	}
+	return s.tenants.Check(ctx, schema)
+}
+
+// CheckModule returns tenant.ErrModuleDisabled when the tenant has switched
+// module off.
+func (s *AuthService) CheckModule(ctx context.Context, schema, module string) error {
+	if s.tenants == nil {
+		return nil
+	}
+	return s.tenants.CheckModule(ctx, schema, module)
+}
+
+// TenantSettings returns the tenant's module switches and feature flags, so
+// callers can hide agent tools or gate features per tenant.
+func (s *AuthService) TenantSettings(ctx context.Context, schema string) (tenant.Settings, error) {
+	if s.tenants == nil {
+		return tenant.Settings{}, nil
+	}
+	return s.tenants.Settings(ctx, schema)
+}
+
 func (s *AuthService) getUserPermissions(ctx context.Context, user *domain.User) ([]string, error) {
 	roles, err := s.roleRepo.FindByUserID(ctx, user.ID)
 	if err != nil {
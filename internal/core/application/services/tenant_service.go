@@ -0,0 +1,390 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/core/domain"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/database"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/validate"
+	"github.com/HuynhHoangPhuc/mcs-erp/migrations"
+	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
+)
+
+const minAdminPasswordLength = 8
+
+// SchemaProvisioner creates a tenant schema and applies module migrations to it.
+type SchemaProvisioner interface {
+	ProvisionSchema(ctx context.Context, schema string, modules ...string) error
+}
+
+// TenantStatusCache is told when a tenant's lifecycle state changes.
+type TenantStatusCache interface {
+	Invalidate(schema string)
+}
+
+// TenantMigrationRunner migrates tenant schemas and reports their status.
+type TenantMigrationRunner interface {
+	Retry(ctx context.Context, schema string) database.TenantRunResult
+	Statuses(ctx context.Context) ([]database.TenantMigrationState, error)
+}
+
+// SchemaInspector compares tenant schemas against _template.
+type SchemaInspector interface {
+	Inspect(ctx context.Context, schema string) (*database.SchemaDrift, error)
+	InspectAll(ctx context.Context) ([]*database.SchemaDrift, error)
+}
+
+// TenantService onboards new tenants (schema, migrations, admin role and first
+// admin user) and drives their lifecycle: suspend, resume, archive, export, delete.
+type TenantService struct {
+	tenants     domain.TenantRepository
+	userRepo    domain.UserRepository
+	roleRepo    domain.RoleRepository
+	lookupRepo  domain.UsersLookupRepository
+	provisioner SchemaProvisioner
+	statusCache TenantStatusCache
+	inspector   SchemaInspector
+	migrations  TenantMigrationRunner
+}
+
+// NewTenantService creates a new tenant service.
+func NewTenantService(
+	tenants domain.TenantRepository,
+	userRepo domain.UserRepository,
+	roleRepo domain.RoleRepository,
+	lookupRepo domain.UsersLookupRepository,
+	provisioner SchemaProvisioner,
+	statusCache TenantStatusCache,
+	inspector SchemaInspector,
+) *TenantService {
+	return &TenantService{
+		tenants:     tenants,
+		userRepo:    userRepo,
+		roleRepo:    roleRepo,
+		lookupRepo:  lookupRepo,
+		provisioner: provisioner,
+		statusCache: statusCache,
+		inspector:   inspector,
+	}
+}
+
+// SetMigrationRunner enables the migration status and retry operations.
+func (s *TenantService) SetMigrationRunner(r TenantMigrationRunner) { s.migrations = r }
+
+// RegisterTenantInput describes a new tenant and its first administrator.
+type RegisterTenantInput struct {
+	Name          string
+	Schema        string
+	AdminEmail    string
+	AdminName     string
+	AdminPassword string
+}
+
+// RegisterTenantResult is the provisioned tenant. Created is false when the
+// request replayed an already completed registration.
+type RegisterTenantResult struct {
+	Tenant      *domain.Tenant
+	AdminUserID uuid.UUID
+	Created     bool
+}
+
+// Register provisions a tenant end to end. It is idempotent: repeating a
+// completed registration for the same schema, admin email and password
+// returns the existing tenant, and an interrupted one is resumed. Any failure removes the schema and
+// its public rows so the tenant never exists half-built.
+func (s *TenantService) Register(ctx context.Context, in RegisterTenantInput) (*RegisterTenantResult, error) {
+	in.Schema = tenant.NormalizeSchema(strings.TrimSpace(in.Schema))
+	in.Name = strings.TrimSpace(in.Name)
+	in.AdminEmail = strings.ToLower(strings.TrimSpace(in.AdminEmail))
+	in.AdminName = strings.TrimSpace(in.AdminName)
+	if err := validateRegisterInput(in); err != nil {
+		return nil, err
+	}
+
+	var result *RegisterTenantResult
+	err := s.tenants.WithLock(ctx, in.Schema, func(ctx context.Context) error {
+		owner, err := s.lookupRepo.FindTenantByEmail(ctx, in.AdminEmail)
+		if err != nil && !errors.Is(err, erptypes.ErrNotFound) {
+			return err
+		}
+		if err == nil && owner != in.Schema {
+			return fmt.Errorf("%w: email is already registered", erptypes.ErrConflict)
+		}
+
+		now := time.Now()
+		t, created, err := s.tenants.Claim(ctx, &domain.Tenant{
+			ID:        uuid.New(),
+			Name:      in.Name,
+			Schema:    in.Schema,
+			CreatedAt: now,
+		})
+		if err != nil {
+			return err
+		}
+
+		if !created && t.Status != domain.TenantStatusProvisioning {
+			// Replay of a completed registration by the same admin, who must
+			// prove it with the password.
+			if owner == in.Schema {
+				user, err := s.userRepo.FindByEmail(tenant.WithTenant(ctx, in.Schema), in.AdminEmail)
+				if err != nil && !errors.Is(err, erptypes.ErrNotFound) {
+					return err
+				}
+				if err == nil && bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(in.AdminPassword)) == nil {
+					result = &RegisterTenantResult{Tenant: t, AdminUserID: user.ID}
+					return nil
+				}
+			}
+			return fmt.Errorf("%w: tenant %q already exists", erptypes.ErrConflict, in.Schema)
+		}
+
+		adminID, err := s.provision(ctx, in)
+		if err != nil {
+			if perr := s.tenants.Purge(context.WithoutCancel(ctx), in.Schema); perr != nil {
+				slog.Error("tenant provisioning rollback failed", "schema", in.Schema, "error", perr)
+			}
+			return fmt.Errorf("provision tenant %s: %w", in.Schema, err)
+		}
+
+		t.Status = domain.TenantStatusActive
+		t.IsActive = true
+		result = &RegisterTenantResult{Tenant: t, AdminUserID: adminID, Created: true}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.statusCache.Invalidate(in.Schema)
+	return result, nil
+}
+
+// provision builds the schema and seeds it. Every step tolerates being re-run.
+func (s *TenantService) provision(ctx context.Context, in RegisterTenantInput) (uuid.UUID, error) {
+	if err := s.provisioner.ProvisionSchema(ctx, in.Schema, migrations.TenantModules...); err != nil {
+		return uuid.Nil, err
+	}
+
+	tctx := tenant.WithTenant(ctx, in.Schema)
+
+	role, err := s.roleRepo.FindByName(tctx, domain.AdminRoleName)
+	switch {
+	case errors.Is(err, erptypes.ErrNotFound):
+		role = &domain.Role{
+			ID:          uuid.New(),
+			Name:        domain.AdminRoleName,
+			Permissions: domain.AllPermissions(),
+			Description: "Tenant administrator with every permission",
+			CreatedAt:   time.Now(),
+		}
+		if err := s.roleRepo.Save(tctx, role); err != nil {
+			return uuid.Nil, fmt.Errorf("seed admin role: %w", err)
+		}
+	case err != nil:
+		return uuid.Nil, err
+	default:
+		role.Permissions = domain.AllPermissions()
+		if err := s.roleRepo.Update(tctx, role); err != nil {
+			return uuid.Nil, fmt.Errorf("update admin role: %w", err)
+		}
+	}
+
+	user, err := s.userRepo.FindByEmail(tctx, in.AdminEmail)
+	if errors.Is(err, erptypes.ErrNotFound) {
+		hash, herr := bcrypt.GenerateFromPassword([]byte(in.AdminPassword), 12)
+		if herr != nil {
+			return uuid.Nil, fmt.Errorf("hash password: %w", herr)
+		}
+		now := time.Now()
+		user = &domain.User{
+			ID:           uuid.New(),
+			Email:        in.AdminEmail,
+			PasswordHash: string(hash),
+			Name:         in.AdminName,
+			IsActive:     true,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		}
+		err = s.userRepo.Save(tctx, user)
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("create admin user: %w", err)
+	}
+
+	if err := s.roleRepo.AssignRoleToUser(tctx, user.ID, role.ID); err != nil {
+		return uuid.Nil, fmt.Errorf("assign admin role: %w", err)
+	}
+	if err := s.lookupRepo.Upsert(ctx, in.AdminEmail, in.Schema); err != nil {
+		return uuid.Nil, fmt.Errorf("register users lookup: %w", err)
+	}
+	if err := s.tenants.Activate(ctx, in.Schema); err != nil {
+		return uuid.Nil, err
+	}
+	return user.ID, nil
+}
+
+// GetTenant returns a tenant by schema name.
+func (s *TenantService) GetTenant(ctx context.Context, schema string) (*domain.Tenant, error) {
+	return s.tenants.FindBySchema(ctx, tenant.NormalizeSchema(schema))
+}
+
+// ListTenants returns every tenant regardless of status.
+func (s *TenantService) ListTenants(ctx context.Context) ([]*domain.Tenant, error) {
+	return s.tenants.List(ctx)
+}
+
+// Suspend blocks logins and API access for an active tenant without touching its data.
+func (s *TenantService) Suspend(ctx context.Context, schema string) (*domain.Tenant, error) {
+	return s.transition(ctx, schema, domain.TenantStatusSuspended, domain.TenantStatusActive)
+}
+
+// Resume reactivates a suspended tenant.
+func (s *TenantService) Resume(ctx context.Context, schema string) (*domain.Tenant, error) {
+	return s.transition(ctx, schema, domain.TenantStatusActive, domain.TenantStatusSuspended)
+}
+
+// Archive freezes an active or suspended tenant. Archived tenants are no longer
+// served or migrated and are the only ones that may be deleted.
+func (s *TenantService) Archive(ctx context.Context, schema string) (*domain.Tenant, error) {
+	return s.transition(ctx, schema, domain.TenantStatusArchived, domain.TenantStatusActive, domain.TenantStatusSuspended)
+}
+
+func (s *TenantService) transition(ctx context.Context, schema string, to domain.TenantStatus, from ...domain.TenantStatus) (*domain.Tenant, error) {
+	schema = tenant.NormalizeSchema(schema)
+	t, err := s.tenants.Transition(ctx, schema, from, to)
+	if err != nil {
+		return nil, err
+	}
+	s.statusCache.Invalidate(schema)
+	slog.Info("tenant status changed", "schema", schema, "status", to)
+	return t, nil
+}
+
+// Export writes a portable archive of the tenant schema to w.
+func (s *TenantService) Export(ctx context.Context, schema string, w io.Writer) error {
+	schema = tenant.NormalizeSchema(schema)
+	t, err := s.tenants.FindBySchema(ctx, schema)
+	if err != nil {
+		return err
+	}
+	if t.Status == domain.TenantStatusProvisioning {
+		return fmt.Errorf("%w: tenant is still being provisioned", erptypes.ErrConflict)
+	}
+	return s.tenants.Export(ctx, schema, w)
+}
+
+// Delete permanently drops an archived tenant. confirm must repeat the schema
+// name so a mistyped path can never destroy a different tenant.
+func (s *TenantService) Delete(ctx context.Context, schema, confirm string) error {
+	schema = tenant.NormalizeSchema(schema)
+	if confirm != schema {
+		return erptypes.Validation(erptypes.FieldViolation{
+			Field: "confirm", Code: validate.Invalid, Message: fmt.Sprintf("must equal the tenant schema %q", schema),
+		})
+	}
+
+	err := s.tenants.WithLock(ctx, schema, func(ctx context.Context) error {
+		t, err := s.tenants.FindBySchema(ctx, schema)
+		if err != nil {
+			return err
+		}
+		if t.Status != domain.TenantStatusArchived {
+			return fmt.Errorf("%w: tenant must be archived before deletion, it is %s", erptypes.ErrConflict, t.Status)
+		}
+		return s.tenants.Purge(ctx, schema)
+	})
+	if err != nil {
+		return err
+	}
+	s.statusCache.Invalidate(schema)
+	slog.Info("tenant deleted", "schema", schema)
+	return nil
+}
+
+// MigrationStatuses lists the last migration outcome of every tenant.
+func (s *TenantService) MigrationStatuses(ctx context.Context) ([]database.TenantMigrationState, error) {
+	if s.migrations == nil {
+		return nil, errors.New("tenant migration runner not configured")
+	}
+	return s.migrations.Statuses(ctx)
+}
+
+// CheckMigrations reports an error naming the tenants whose last migration
+// run failed or never finished.
+func (s *TenantService) CheckMigrations(ctx context.Context) error {
+	states, err := s.MigrationStatuses(ctx)
+	if err != nil {
+		return err
+	}
+	var failed, pending []string
+	for _, st := range states {
+		switch st.Status {
+		case database.TenantMigrationFailed:
+			failed = append(failed, st.Schema)
+		case database.TenantMigrationPending:
+			pending = append(pending, st.Schema)
+		}
+	}
+	if len(failed) == 0 && len(pending) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d tenant(s) quarantined %v, %d pending %v", len(failed), failed, len(pending), pending)
+}
+
+// RetryMigration re-runs pending migrations for a quarantined tenant. On
+// success the quarantine is lifted and the tenant is served again.
+func (s *TenantService) RetryMigration(ctx context.Context, schema string) (database.TenantRunResult, error) {
+	schema = tenant.NormalizeSchema(schema)
+	if s.migrations == nil {
+		return database.TenantRunResult{}, errors.New("tenant migration runner not configured")
+	}
+	t, err := s.tenants.FindBySchema(ctx, schema)
+	if err != nil {
+		return database.TenantRunResult{}, err
+	}
+	if t.Status != domain.TenantStatusActive && t.Status != domain.TenantStatusSuspended {
+		return database.TenantRunResult{}, fmt.Errorf("%w: tenant is %s", erptypes.ErrConflict, t.Status)
+	}
+
+	res := s.migrations.Retry(ctx, schema)
+	s.statusCache.Invalidate(schema)
+	return res, nil
+}
+
+// Drift compares one tenant's schema against _template.
+func (s *TenantService) Drift(ctx context.Context, schema string) (*database.SchemaDrift, error) {
+	schema = tenant.NormalizeSchema(schema)
+	if _, err := s.tenants.FindBySchema(ctx, schema); err != nil {
+		return nil, err
+	}
+	return s.inspector.Inspect(ctx, schema)
+}
+
+// DriftReport compares every live tenant's schema against _template.
+func (s *TenantService) DriftReport(ctx context.Context) ([]*database.SchemaDrift, error) {
+	return s.inspector.InspectAll(ctx)
+}
+
+func validateRegisterInput(in RegisterTenantInput) error {
+	var v validate.Validator
+	v.Required("name", in.Name)
+	if err := tenant.ValidateSchema(in.Schema); err != nil {
+		v.Add("schema", validate.Invalid, err.Error())
+	}
+	if v.Required("admin_email", in.AdminEmail) {
+		v.Email("admin_email", in.AdminEmail)
+	}
+	v.Required("admin_name", in.AdminName)
+	v.Check(len(in.AdminPassword) >= minAdminPasswordLength, "admin_password", validate.TooShort,
+		fmt.Sprintf("must be at least %d characters", minAdminPasswordLength))
+	return v.Err()
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/core/domain"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
+	"github.com/HuynhHoangPhuc/mcs-erp/migrations"
+	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
+)
+
+var validFeatureFlag = regexp.MustCompile(`^[a-z][a-z0-9_.-]{0,63}$`)
+
+// SettingsPatch changes some of a tenant's settings. A nil value removes the
+// key, restoring its default (module enabled, flag off).
+type SettingsPatch struct {
+	Modules  map[string]*bool
+	Features map[string]*bool
+}
+
+// ToggleableModules lists the modules a tenant may switch off: every module
+// with tenant tables except the platform tables and core.
+func ToggleableModules() []string {
+	var out []string
+	for _, m := range migrations.TenantModules {
+		if m != migrations.Platform && m != tenant.CoreModule {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// Settings returns the tenant's stored module switches and feature flags.
+func (s *TenantService) Settings(ctx context.Context, schema string) (domain.TenantSettings, error) {
+	schema = tenant.NormalizeSchema(schema)
+	if _, err := s.tenants.FindBySchema(ctx, schema); err != nil {
+		return domain.TenantSettings{}, err
+	}
+	return s.tenants.FindSettings(ctx, schema)
+}
+
+// UpdateSettings applies patch to the tenant's settings and returns the result.
+// Every replica sees the change within the tenant directory TTL.
+func (s *TenantService) UpdateSettings(ctx context.Context, schema string, patch SettingsPatch) (domain.TenantSettings, error) {
+	schema = tenant.NormalizeSchema(schema)
+	if err := validateSettingsPatch(patch); err != nil {
+		return domain.TenantSettings{}, err
+	}
+	if _, err := s.tenants.FindBySchema(ctx, schema); err != nil {
+		return domain.TenantSettings{}, err
+	}
+
+	var out domain.TenantSettings
+	err := s.tenants.WithLock(ctx, schema, func(ctx context.Context) error {
+		cur, err := s.tenants.FindSettings(ctx, schema)
+		if err != nil {
+			return err
+		}
+		applyPatch(cur.Modules, patch.Modules)
+		applyPatch(cur.Features, patch.Features)
+		if err := s.tenants.SaveSettings(ctx, schema, cur); err != nil {
+			return err
+		}
+		out = cur
+		return nil
+	})
+	if err != nil {
+		return domain.TenantSettings{}, err
+	}
+	s.statusCache.Invalidate(schema)
+	slog.Info("tenant settings changed", "schema", schema, "modules", out.Modules, "features", out.Features)
+	return out, nil
+}
+
+func applyPatch(dst map[string]bool, patch map[string]*bool) {
+	for k, v := range patch {
+		if v == nil {
+			delete(dst, k)
+		} else {
+			dst[k] = *v
+		}
+	}
+}
+
+func validateSettingsPatch(p SettingsPatch) error {
+	var problems []string
+	toggleable := ToggleableModules()
+	for m := range p.Modules {
+		if !slices.Contains(toggleable, m) {
+			problems = append(problems, fmt.Sprintf("unknown or mandatory module %q (allowed: %s)", m, strings.Join(toggleable, ", ")))
+		}
+	}
+	for f := range p.Features {
+		if !validFeatureFlag.MatchString(f) {
+			problems = append(problems, fmt.Sprintf("invalid feature flag %q", f))
+		}
+	}
+	if len(problems) > 0 {
+		slices.Sort(problems)
+		return fmt.Errorf("%w: %s", erptypes.ErrValidation, strings.Join(problems, "; "))
+	}
+	return nil
+}
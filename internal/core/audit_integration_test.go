@@ -0,0 +1,147 @@
+//go:build integration
+
+package core_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	coredomain "github.com/HuynhHoangPhuc/mcs-erp/internal/core/domain"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/audit"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/testutil"
+)
+
+func TestAuditLog_RecordsWritesWithActorAndRequest(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	srv := testutil.TestServer(t, db.Pool)
+	defer srv.Close()
+
+	schema := db.CreateTenantSchema(t)
+	actor := uuid.New()
+	token := testutil.GenerateTestToken(t, actor, schema, []string{
+		coredomain.PermTeacherRead, coredomain.PermTeacherWrite, coredomain.PermAuditRead,
+	})
+
+	send := func(method, url, requestID string, body any) *http.Response {
+		t.Helper()
+		raw, _ := json.Marshal(body)
+		req, err := testutil.AuthenticatedRequest(method, url, token, schema, bytes.NewReader(raw))
+		if err != nil {
+			t.Fatalf("build request: %v", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Request-ID", requestID)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("do request: %v", err)
+		}
+		return resp
+	}
+
+	email := "audit_" + uuid.NewString() + "@example.com"
+	resp := send(http.MethodPost, srv.URL+"/api/v1/teachers", "req-create-1",
+		map[string]any{"name": "Ada", "email": email, "qualifications": []string{}})
+	var created struct {
+		ID string `json:"id"`
+	}
+	json.NewDecoder(resp.Body).Decode(&created)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
+	}
+	if got := resp.Header.Get("X-Request-ID"); got != "req-create-1" {
+		t.Fatalf("expected request id echoed, got %q", got)
+	}
+
+	expectStatus(t, send(http.MethodPut, srv.URL+"/api/v1/teachers/"+created.ID, "req-update-1",
+		map[string]any{"name": "Ada Lovelace", "email": email, "qualifications": []string{}, "is_active": true}), http.StatusOK)
+
+	req, err := testutil.AuthenticatedRequest(http.MethodGet,
+		srv.URL+"/api/v1/audit?entity_type=teachers&entity_id="+created.ID, token, schema, nil)
+	if err != nil {
+		t.Fatalf("build audit request: %v", err)
+	}
+	page, status := testutil.DoJSON[listquery.Page[audit.Entry]](t, http.DefaultClient, req)
+	if status != http.StatusOK || page.Total != 2 {
+		t.Fatalf("expected 2 audit entries, got status %d total %d", status, page.Total)
+	}
+
+	update, create := page.Items[0], page.Items[1]
+	if create.Action != audit.ActionCreate || update.Action != audit.ActionUpdate {
+		t.Fatalf("unexpected actions %q, %q", create.Action, update.Action)
+	}
+	if update.ActorID == nil || *update.ActorID != actor || update.Tenant != schema {
+		t.Fatalf("unexpected actor/tenant: %+v", update)
+	}
+	if update.RequestID == nil || *update.RequestID != "req-update-1" || update.ClientIP == nil {
+		t.Fatalf("unexpected request metadata: %+v", update)
+	}
+	var diff map[string]struct {
+		Before any `json:"before"`
+		After  any `json:"after"`
+	}
+	if err := json.Unmarshal(update.Changes, &diff); err != nil {
+		t.Fatalf("decode changes: %v", err)
+	}
+	if diff["name"].Before != "Ada" || diff["name"].After != "Ada Lovelace" {
+		t.Fatalf("unexpected name diff: %s", update.Changes)
+	}
+	if _, ok := diff["email"]; ok {
+		t.Fatalf("unchanged column recorded: %s", update.Changes)
+	}
+
+	// The list pages with cursors like every other list.
+	req, _ = testutil.AuthenticatedRequest(http.MethodGet,
+		srv.URL+"/api/v1/audit?entity_id="+created.ID+"&action[in]=create,update&limit=1", token, schema, nil)
+	first, status := testutil.DoJSON[listquery.Page[audit.Entry]](t, http.DefaultClient, req)
+	if status != http.StatusOK || len(first.Items) != 1 || first.Items[0].ID != update.ID || first.NextCursor == "" {
+		t.Fatalf("expected the update entry and a next cursor, got status %d %+v", status, first)
+	}
+	req, _ = testutil.AuthenticatedRequest(http.MethodGet,
+		srv.URL+"/api/v1/audit?entity_id="+created.ID+"&action[in]=create,update&limit=1&cursor="+first.NextCursor, token, schema, nil)
+	second, status := testutil.DoJSON[listquery.Page[audit.Entry]](t, http.DefaultClient, req)
+	if status != http.StatusOK || len(second.Items) != 1 || second.Items[0].ID != create.ID || second.NextCursor != "" {
+		t.Fatalf("expected the create entry on the last page, got status %d %+v", status, second)
+	}
+	req, _ = testutil.AuthenticatedRequest(http.MethodGet, srv.URL+"/api/v1/audit?limit=many", token, schema, nil)
+	if _, status := testutil.DoJSON[map[string]any](t, http.DefaultClient, req); status != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a malformed limit, got %d", status)
+	}
+
+	// Export streams the same entries oldest first.
+	req, _ = testutil.AuthenticatedRequest(http.MethodGet,
+		srv.URL+"/api/v1/audit/export?format=jsonl&request_id=req-create-1", token, schema, nil)
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	defer resp.Body.Close()
+	var lines int
+	for sc := bufio.NewScanner(resp.Body); sc.Scan(); lines++ {
+		var e audit.Entry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil || e.Action != audit.ActionCreate {
+			t.Fatalf("unexpected export line %q: %v", sc.Text(), err)
+		}
+	}
+	if resp.StatusCode != http.StatusOK || lines != 1 {
+		t.Fatalf("expected 1 exported line, got status %d lines %d", resp.StatusCode, lines)
+	}
+
+	// The log is append-only, even for direct SQL.
+	_, err = db.Pool.Exec(context.Background(), "DELETE FROM "+schema+".audit_log")
+	if err == nil || !strings.Contains(err.Error(), "append-only") {
+		t.Fatalf("expected append-only error, got %v", err)
+	}
+
+	// Reading the log needs the audit permission.
+	noAudit := testutil.GenerateTestToken(t, actor, schema, []string{coredomain.PermTeacherRead})
+	expectStatus(t, tenantRequest(t, srv.URL+"/api/v1/audit", noAudit, schema), http.StatusForbidden)
+}
@@ -1,10 +1,13 @@
 package delivery
 
 import (
-	"encoding/json"
 	"net/http"
 
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/core/application/services"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/problem"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/validate"
+	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
 )
 
 // AuthHandler handles authentication endpoints.
@@ -16,31 +19,37 @@ func NewAuthHandler(auth *services.AuthService) *AuthHandler {
 	return &AuthHandler{auth: auth}
 }
 
-type loginRequest struct {
+// LoginRequest is the body of POST /api/v1/auth/login.
+type LoginRequest struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
 }
 
-type refreshRequest struct {
+func (req *LoginRequest) Validate(v *validate.Validator) {
+	v.Required("email", req.Email)
+	v.Required("password", req.Password)
+}
+
+// RefreshRequest is the body of POST /api/v1/auth/refresh.
+type RefreshRequest struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
+func (req *RefreshRequest) Validate(v *validate.Validator) {
+	v.Required("refresh_token", req.RefreshToken)
+}
+
 // Login handles POST /api/v1/auth/login
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
-	var req loginRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
-		return
-	}
-
-	if req.Email == "" || req.Password == "" {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "email and password required"})
+	var req LoginRequest
+	if err := validate.DecodeJSON(r, &req); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
 	tokens, err := h.auth.Login(r.Context(), req.Email, req.Password)
 	if err != nil {
-		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": err.Error()})
+		writeAuthError(w, r, err)
 		return
 	}
 
@@ -49,21 +58,31 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 
 // Refresh handles POST /api/v1/auth/refresh
 func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
-	var req refreshRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	var req RefreshRequest
+	if err := validate.DecodeJSON(r, &req); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
 	tokens, err := h.auth.Refresh(r.Context(), req.RefreshToken)
 	if err != nil {
-		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": err.Error()})
+		writeAuthError(w, r, err)
 		return
 	}
 
 	writeJSON(w, http.StatusOK, tokens)
 }
 
+// writeAuthError reports tenant lifecycle errors with their own status; any
+// other authentication failure is a 401.
+func writeAuthError(w http.ResponseWriter, r *http.Request, err error) {
+	if e := tenant.Problem(err); e != nil {
+		problem.Write(w, r, e)
+		return
+	}
+	problem.Write(w, r, erptypes.Unauthorized(err.Error()))
+}
+
 // Logout handles POST /api/v1/auth/logout (client-side token discard for MVP)
 func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]string{"message": "logged out"})
@@ -1,12 +1,19 @@
 package delivery
 
 import (
+	"fmt"
 	"net/http"
 	"strings"
 
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/core/application/services"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/auth"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/problem"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/requestinfo"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tracing"
+	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
 )
 
 // AuthMiddleware validates JWT from Authorization header and sets user+tenant in context.
@@ -15,17 +22,34 @@ func AuthMiddleware(authSvc *services.AuthService) func(http.Handler) http.Handl
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			header := r.Header.Get("Authorization")
 			if header == "" || !strings.HasPrefix(header, "Bearer ") {
-				writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "missing or invalid authorization header"})
+				problem.Write(w, r, erptypes.Unauthorized("missing or invalid authorization header"))
 				return
 			}
 
 			token := strings.TrimPrefix(header, "Bearer ")
 			claims, err := authSvc.ValidateToken(token)
 			if err != nil {
-				writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid or expired token"})
+				problem.Write(w, r, erptypes.Unauthorized("invalid or expired token"))
+				return
+			}
+
+			// Tokens outlive tenant state changes; re-check on every request.
+			if err := authSvc.CheckTenant(r.Context(), claims.TenantID); err != nil {
+				e := tenant.Problem(err)
+				if e == nil {
+					e = erptypes.Internal("internal error", fmt.Errorf("tenant status check for %s: %w", claims.TenantID, err))
+				}
+				problem.Write(w, r, e)
 				return
 			}
 
+			trace.SpanFromContext(r.Context()).SetAttributes(
+				tracing.AttrTenant.String(claims.TenantID),
+				tracing.AttrUserID.String(claims.UserID.String()),
+			)
+			requestinfo.SetTenant(r.Context(), claims.TenantID)
+			requestinfo.SetUser(r.Context(), claims.UserID.String())
+
 			// Set both user claims and tenant in context
 			ctx := auth.WithUser(r.Context(), claims)
 			ctx = tenant.WithTenant(ctx, claims.TenantID)
@@ -33,3 +57,24 @@ func AuthMiddleware(authSvc *services.AuthService) func(http.Handler) http.Handl
 		})
 	}
 }
+
+// ModuleAuthMiddleware is AuthMiddleware that also rejects tenants which have
+// switched module off (403), so a module's routes only exist for tenants
+// that enabled it.
+func ModuleAuthMiddleware(authSvc *services.AuthService, module string) func(http.Handler) http.Handler {
+	authMw := AuthMiddleware(authSvc)
+	return func(next http.Handler) http.Handler {
+		return authMw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			schema, _ := tenant.FromContext(r.Context())
+			if err := authSvc.CheckModule(r.Context(), schema, module); err != nil {
+				e := tenant.Problem(err)
+				if e == nil {
+					e = erptypes.Internal("internal error", fmt.Errorf("module %s check for %s: %w", module, schema, err))
+				}
+				problem.Write(w, r, e)
+				return
+			}
+			next.ServeHTTP(w, r)
+		}))
+	}
+}
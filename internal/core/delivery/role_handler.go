@@ -1,13 +1,16 @@
 package delivery
 
 import (
-	"encoding/json"
 	"net/http"
 	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/core/domain"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/problem"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/validate"
+	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
 )
 
 // RoleHandler handles role CRUD endpoints.
@@ -19,22 +22,22 @@ func NewRoleHandler(roleRepo domain.RoleRepository) *RoleHandler {
 	return &RoleHandler{roleRepo: roleRepo}
 }
 
-type createRoleRequest struct {
+// CreateRoleRequest is the body of POST /api/v1/roles.
+type CreateRoleRequest struct {
 	Name        string   `json:"name"`
 	Permissions []string `json:"permissions"`
 	Description string   `json:"description"`
 }
 
+func (req *CreateRoleRequest) Validate(v *validate.Validator) {
+	v.Required("name", req.Name)
+}
+
 // CreateRole handles POST /api/v1/roles
 func (h *RoleHandler) CreateRole(w http.ResponseWriter, r *http.Request) {
-	var req createRoleRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
-		return
-	}
-
-	if req.Name == "" {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "name is required"})
+	var req CreateRoleRequest
+	if err := validate.DecodeJSON(r, &req); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -47,7 +50,7 @@ func (h *RoleHandler) CreateRole(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.roleRepo.Save(r.Context(), role); err != nil {
-		writeJSON(w, http.StatusConflict, map[string]string{"error": "role already exists or save failed"})
+		problem.Write(w, r, erptypes.Conflict("role already exists or save failed"))
 		return
 	}
 
@@ -56,26 +59,31 @@ func (h *RoleHandler) CreateRole(w http.ResponseWriter, r *http.Request) {
 
 // ListRoles handles GET /api/v1/roles
 func (h *RoleHandler) ListRoles(w http.ResponseWriter, r *http.Request) {
-	roles, err := h.roleRepo.List(r.Context())
+	spec, err := listquery.Parse(r.URL.Query(), domain.RoleListSchema)
 	if err != nil {
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to list roles"})
+		problem.Write(w, r, validate.Query(err))
 		return
 	}
 
-	writeJSON(w, http.StatusOK, map[string]any{"items": roles})
+	page, err := h.roleRepo.List(r.Context(), spec)
+	if err != nil {
+		problem.Write(w, r, erptypes.Internal("failed to list roles", err))
+		return
+	}
+	writeJSON(w, http.StatusOK, page)
 }
 
 // GetRole handles GET /api/v1/roles/{id}
 func (h *RoleHandler) GetRole(w http.ResponseWriter, r *http.Request) {
-	id, err := uuid.Parse(r.PathValue("id"))
+	id, err := validate.PathUUID(r, "id")
 	if err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid role id"})
+		problem.Write(w, r, err)
 		return
 	}
 
 	role, err := h.roleRepo.FindByID(r.Context(), id)
 	if err != nil {
-		writeJSON(w, http.StatusNotFound, map[string]string{"error": "role not found"})
+		problem.Write(w, r, erptypes.NotFoundAs(err, "role not found"))
 		return
 	}
 
@@ -84,14 +92,14 @@ func (h *RoleHandler) GetRole(w http.ResponseWriter, r *http.Request) {
 
 // DeleteRole handles DELETE /api/v1/roles/{id}
 func (h *RoleHandler) DeleteRole(w http.ResponseWriter, r *http.Request) {
-	id, err := uuid.Parse(r.PathValue("id"))
+	id, err := validate.PathUUID(r, "id")
 	if err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid role id"})
+		problem.Write(w, r, err)
 		return
 	}
 
 	if err := h.roleRepo.Delete(r.Context(), id); err != nil {
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to delete role"})
+		problem.Write(w, r, erptypes.Internal("failed to delete role", err))
 		return
 	}
 
@@ -0,0 +1,53 @@
+package delivery
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/core/application/services"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/core/domain"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/auth"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/openapi"
+	pkgmod "github.com/HuynhHoangPhuc/mcs-erp/pkg/module"
+)
+
+// RouteFilter selects the routes of the OpenAPI document a caller may use,
+// applying the same checks as the route middleware: public routes for
+// everyone; with a valid bearer token, the routes of the tenant's enabled
+// modules that its permissions allow; with the platform token, the platform
+// routes. Missing or invalid credentials are not an error, they just show less.
+func RouteFilter(authSvc *services.AuthService, platformToken string) func(*http.Request) openapi.Filter {
+	return func(r *http.Request) openapi.Filter {
+		var claims *auth.Claims
+		if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+			if c, err := authSvc.ValidateToken(token); err == nil && authSvc.CheckTenant(r.Context(), c.TenantID) == nil {
+				claims = c
+			}
+		}
+		platform := platformToken != "" &&
+			subtle.ConstantTimeCompare([]byte(r.Header.Get(PlatformTokenHeader)), []byte(platformToken)) == 1
+
+		enabled := map[string]bool{}
+		return func(route pkgmod.Route) bool {
+			switch route.Access {
+			case pkgmod.AccessPublic:
+				return true
+			case pkgmod.AccessPlatform:
+				return platform
+			}
+			if claims == nil {
+				return false
+			}
+			if route.Permission != "" && !domain.HasPermission(claims.Permissions, route.Permission) {
+				return false
+			}
+			on, ok := enabled[route.Module]
+			if !ok {
+				on = authSvc.CheckModule(r.Context(), claims.TenantID, route.Module) == nil
+				enabled[route.Module] = on
+			}
+			return on
+		}
+	}
+}
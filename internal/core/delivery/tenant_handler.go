@@ -0,0 +1,306 @@
+package delivery
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/core/application/services"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/core/domain"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/database"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/problem"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/validate"
+	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
+)
+
+// PlatformTokenHeader carries the operator token for platform-level endpoints.
+const PlatformTokenHeader = "X-Platform-Token"
+
+// PlatformAdminMiddleware restricts a route to platform operators holding the
+// configured token. With no token configured the routes are disabled.
+func PlatformAdminMiddleware(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if token == "" {
+				problem.Write(w, r, erptypes.Forbidden("platform administration is disabled"))
+				return
+			}
+			got := r.Header.Get(PlatformTokenHeader)
+			if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				problem.Write(w, r, erptypes.Unauthorized("invalid platform token"))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// TenantHandler handles tenant onboarding endpoints.
+type TenantHandler struct {
+	svc *services.TenantService
+}
+
+func NewTenantHandler(svc *services.TenantService) *TenantHandler {
+	return &TenantHandler{svc: svc}
+}
+
+// RegisterTenantRequest is the body of POST /api/v1/auth/register.
+type RegisterTenantRequest struct {
+	Name          string `json:"name"`
+	Schema        string `json:"schema"`
+	AdminEmail    string `json:"admin_email"`
+	AdminName     string `json:"admin_name"`
+	AdminPassword string `json:"admin_password"`
+}
+
+// Register handles POST /api/v1/auth/register
+func (h *TenantHandler) Register(w http.ResponseWriter, r *http.Request) {
+	var req RegisterTenantRequest
+	if err := validate.DecodeJSON(r, &req); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	res, err := h.svc.Register(r.Context(), services.RegisterTenantInput{
+		Name:          req.Name,
+		Schema:        req.Schema,
+		AdminEmail:    req.AdminEmail,
+		AdminName:     req.AdminName,
+		AdminPassword: req.AdminPassword,
+	})
+	if err != nil {
+		writeTenantError(w, r, err)
+		return
+	}
+
+	status := http.StatusCreated
+	if !res.Created {
+		status = http.StatusOK
+	}
+	writeJSON(w, status, RegisterTenantResponse{TenantResponse: toTenantResponse(res.Tenant), AdminUserID: res.AdminUserID})
+}
+
+// GetTenant handles GET /api/v1/platform/tenants/{schema}
+func (h *TenantHandler) GetTenant(w http.ResponseWriter, r *http.Request) {
+	t, err := h.svc.GetTenant(r.Context(), r.PathValue("schema"))
+	if err != nil {
+		writeTenantError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toTenantResponse(t))
+}
+
+// ListTenants handles GET /api/v1/platform/tenants
+func (h *TenantHandler) ListTenants(w http.ResponseWriter, r *http.Request) {
+	tenants, err := h.svc.ListTenants(r.Context())
+	if err != nil {
+		writeTenantError(w, r, err)
+		return
+	}
+	items := make([]TenantResponse, 0, len(tenants))
+	for _, t := range tenants {
+		items = append(items, toTenantResponse(t))
+	}
+	writeJSON(w, http.StatusOK, items)
+}
+
+// Suspend handles POST /api/v1/platform/tenants/{schema}/suspend
+func (h *TenantHandler) Suspend(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.svc.Suspend)
+}
+
+// Resume handles POST /api/v1/platform/tenants/{schema}/resume
+func (h *TenantHandler) Resume(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.svc.Resume)
+}
+
+// Archive handles POST /api/v1/platform/tenants/{schema}/archive
+func (h *TenantHandler) Archive(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.svc.Archive)
+}
+
+func (h *TenantHandler) transition(w http.ResponseWriter, r *http.Request, fn func(context.Context, string) (*domain.Tenant, error)) {
+	t, err := fn(r.Context(), r.PathValue("schema"))
+	if err != nil {
+		writeTenantError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toTenantResponse(t))
+}
+
+// Export handles GET /api/v1/platform/tenants/{schema}/export
+// The archive is built in a temp file first so failures still yield a JSON error.
+func (h *TenantHandler) Export(w http.ResponseWriter, r *http.Request) {
+	schema := r.PathValue("schema")
+	f, err := os.CreateTemp("", "tenant-export-*.tar.gz")
+	if err != nil {
+		writeTenantError(w, r, err)
+		return
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	if err := h.svc.Export(r.Context(), schema, f); err != nil {
+		writeTenantError(w, r, err)
+		return
+	}
+	size, err := f.Seek(0, io.SeekCurrent)
+	if err == nil {
+		_, err = f.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		writeTenantError(w, r, err)
+		return
+	}
+
+	filename := fmt.Sprintf("%s-%s.tar.gz", schema, time.Now().UTC().Format("20060102T150405Z"))
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, f); err != nil {
+		slog.Error("stream tenant export", "schema", schema, "error", err)
+	}
+}
+
+// ListMigrations handles GET /api/v1/platform/migrations
+func (h *TenantHandler) ListMigrations(w http.ResponseWriter, r *http.Request) {
+	states, err := h.svc.MigrationStatuses(r.Context())
+	if err != nil {
+		writeTenantError(w, r, err)
+		return
+	}
+	if states == nil {
+		states = []database.TenantMigrationState{}
+	}
+	writeJSON(w, http.StatusOK, states)
+}
+
+// RetryMigration handles POST /api/v1/platform/tenants/{schema}/migrations/retry
+// It answers 200 when the tenant is fully migrated and 500 with the failure otherwise.
+func (h *TenantHandler) RetryMigration(w http.ResponseWriter, r *http.Request) {
+	res, err := h.svc.RetryMigration(r.Context(), r.PathValue("schema"))
+	if err != nil {
+		writeTenantError(w, r, err)
+		return
+	}
+	body := MigrationRetryResponse{Schema: res.Schema, Applied: res.Applied, Status: database.TenantMigrationApplied}
+	status := http.StatusOK
+	if res.Err != nil {
+		body.Status = database.TenantMigrationFailed
+		body.Error = res.Err.Error()
+		status = http.StatusInternalServerError
+	}
+	writeJSON(w, status, body)
+}
+
+// DriftReport handles GET /api/v1/platform/drift
+// Only schemas that differ from _template are listed unless ?all=true.
+func (h *TenantHandler) DriftReport(w http.ResponseWriter, r *http.Request) {
+	reports, err := h.svc.DriftReport(r.Context())
+	if err != nil {
+		writeTenantError(w, r, err)
+		return
+	}
+	all := r.URL.Query().Get("all") == "true"
+	items := make([]*database.SchemaDrift, 0, len(reports))
+	for _, rep := range reports {
+		if all || rep.HasDrift() {
+			items = append(items, rep)
+		}
+	}
+	writeJSON(w, http.StatusOK, DriftReportResponse{Checked: len(reports), Drifted: countDrifted(reports), Items: items})
+}
+
+// GetDrift handles GET /api/v1/platform/tenants/{schema}/drift
+func (h *TenantHandler) GetDrift(w http.ResponseWriter, r *http.Request) {
+	rep, err := h.svc.Drift(r.Context(), r.PathValue("schema"))
+	if err != nil {
+		writeTenantError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, rep)
+}
+
+func countDrifted(reports []*database.SchemaDrift) int {
+	n := 0
+	for _, rep := range reports {
+		if rep.HasDrift() {
+			n++
+		}
+	}
+	return n
+}
+
+// DeleteTenantRequest confirms a deletion by repeating the schema.
+type DeleteTenantRequest struct {
+	Confirm string `json:"confirm"`
+}
+
+// Delete handles DELETE /api/v1/platform/tenants/{schema}
+// The body must be {"confirm": "<schema>"} and the tenant must be archived.
+func (h *TenantHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	var req DeleteTenantRequest
+	if err := validate.DecodeJSON(r, &req); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	if err := h.svc.Delete(r.Context(), r.PathValue("schema"), req.Confirm); err != nil {
+		writeTenantError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// TenantResponse is the JSON representation of a Tenant.
+type TenantResponse struct {
+	ID        uuid.UUID           `json:"id"`
+	Name      string              `json:"name"`
+	Schema    string              `json:"schema"`
+	Status    domain.TenantStatus `json:"status"`
+	IsActive  bool                `json:"is_active"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
+
+// RegisterTenantResponse is a registered tenant and its first admin.
+type RegisterTenantResponse struct {
+	TenantResponse
+	AdminUserID uuid.UUID `json:"admin_user_id"`
+}
+
+// MigrationRetryResponse is the outcome of migrating one tenant again.
+type MigrationRetryResponse struct {
+	Schema  string                         `json:"schema"`
+	Applied int                            `json:"applied"`
+	Status  database.TenantMigrationStatus `json:"status"`
+	Error   string                         `json:"error,omitempty"`
+}
+
+// DriftReportResponse summarises the drift of every tenant schema.
+type DriftReportResponse struct {
+	Checked int                     `json:"checked"`
+	Drifted int                     `json:"drifted"`
+	Items   []*database.SchemaDrift `json:"items"`
+}
+
+func toTenantResponse(t *domain.Tenant) TenantResponse {
+	return TenantResponse{
+		ID: t.ID, Name: t.Name, Schema: t.Schema, Status: t.Status,
+		IsActive: t.IsActive, CreatedAt: t.CreatedAt, UpdatedAt: t.UpdatedAt,
+	}
+}
+
+// writeTenantError reports service errors; the service wraps the standard
+// errors with a message meant for the operator.
+func writeTenantError(w http.ResponseWriter, r *http.Request, err error) {
+	problem.Write(w, r, erptypes.NotFoundAs(err, "tenant not found"))
+}
@@ -0,0 +1,85 @@
+package delivery
+
+import (
+	"net/http"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/core/application/services"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/core/domain"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/problem"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/validate"
+	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
+)
+
+// SettingsPatchRequest sets (true/false) or resets (null) module and
+// feature flags; absent keys are left alone.
+type SettingsPatchRequest struct {
+	Modules  map[string]*bool `json:"modules"`
+	Features map[string]*bool `json:"features"`
+}
+
+// GetSettings handles GET /api/v1/platform/tenants/{schema}/settings
+func (h *TenantHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	s, err := h.svc.Settings(r.Context(), r.PathValue("schema"))
+	if err != nil {
+		writeTenantError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toSettingsResponse(s))
+}
+
+// UpdateSettings handles PATCH /api/v1/platform/tenants/{schema}/settings
+// Body: {"modules": {"agent": false}, "features": {"new_grading": true}}; a
+// null value resets the key to its default (module on, flag off).
+func (h *TenantHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	var req SettingsPatchRequest
+	if err := validate.DecodeJSON(r, &req); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	s, err := h.svc.UpdateSettings(r.Context(), r.PathValue("schema"), services.SettingsPatch{
+		Modules:  req.Modules,
+		Features: req.Features,
+	})
+	if err != nil {
+		writeTenantError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toSettingsResponse(s))
+}
+
+// MySettings handles GET /api/v1/settings: the caller's tenant modules and
+// flags, so clients can hide what the tenant has not enabled.
+func (h *TenantHandler) MySettings(w http.ResponseWriter, r *http.Request) {
+	schema, err := tenant.FromContext(r.Context())
+	if err != nil {
+		problem.Write(w, r, erptypes.InvalidRequest("missing tenant"))
+		return
+	}
+	s, err := h.svc.Settings(r.Context(), schema)
+	if err != nil {
+		writeTenantError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toSettingsResponse(s))
+}
+
+// SettingsResponse is a tenant's effective module and feature flags.
+type SettingsResponse struct {
+	Modules  map[string]bool `json:"modules"`
+	Features map[string]bool `json:"features"`
+}
+
+// toSettingsResponse resolves defaults: every toggleable module is listed with
+// its effective state; only flags that are set appear.
+func toSettingsResponse(s domain.TenantSettings) SettingsResponse {
+	modules := map[string]bool{tenant.CoreModule: true}
+	for _, m := range services.ToggleableModules() {
+		modules[m] = s.ModuleEnabled(m)
+	}
+	features := s.Features
+	if features == nil {
+		features = map[string]bool{}
+	}
+	return SettingsResponse{Modules: modules, Features: features}
+}
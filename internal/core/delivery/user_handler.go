@@ -1,9 +1,7 @@
 package delivery
 
 import (
-	"encoding/json"
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,6 +9,10 @@ import (
 
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/core/domain"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/auth"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/problem"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/validate"
+	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
 )
 
 // UserHandler handles user CRUD endpoints.
@@ -24,28 +26,32 @@ func NewUserHandler(userRepo domain.UserRepository, roleRepo domain.RoleReposito
 	return &UserHandler{userRepo: userRepo, roleRepo: roleRepo, lookupRepo: lookupRepo}
 }
 
-type createUserRequest struct {
+// CreateUserRequest is the body of POST /api/v1/users.
+type CreateUserRequest struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
 	Name     string `json:"name"`
 }
 
-// CreateUser handles POST /api/v1/users
-func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
-	var req createUserRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
-		return
+func (req *CreateUserRequest) Validate(v *validate.Validator) {
+	if v.Required("email", req.Email) {
+		v.Email("email", req.Email)
 	}
+	v.Required("password", req.Password)
+	v.Required("name", req.Name)
+}
 
-	if req.Email == "" || req.Password == "" || req.Name == "" {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "email, password, and name required"})
+// CreateUser handles POST /api/v1/users
+func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	var req CreateUserRequest
+	if err := validate.DecodeJSON(r, &req); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
 	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), 12)
 	if err != nil {
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to hash password"})
+		problem.Write(w, r, erptypes.Internal("failed to hash password", err))
 		return
 	}
 
@@ -61,7 +67,7 @@ func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.userRepo.Save(r.Context(), user); err != nil {
-		writeJSON(w, http.StatusConflict, map[string]string{"error": "user already exists or save failed"})
+		problem.Write(w, r, erptypes.Conflict("user already exists or save failed"))
 		return
 	}
 
@@ -71,82 +77,83 @@ func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 		h.lookupRepo.Upsert(r.Context(), user.Email, claims.TenantID)
 	}
 
-	writeJSON(w, http.StatusCreated, map[string]any{
-		"id": user.ID, "email": user.Email, "name": user.Name, "is_active": user.IsActive,
-	})
+	writeJSON(w, http.StatusCreated, toUserResponse(user))
 }
 
 // ListUsers handles GET /api/v1/users
 func (h *UserHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
-	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
-	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-	if limit <= 0 || limit > 100 {
-		limit = 20
-	}
-
-	users, total, err := h.userRepo.List(r.Context(), offset, limit)
+	spec, err := listquery.Parse(r.URL.Query(), domain.UserListSchema)
 	if err != nil {
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to list users"})
+		problem.Write(w, r, validate.Query(err))
 		return
 	}
 
-	items := make([]map[string]any, len(users))
-	for i, u := range users {
-		items[i] = map[string]any{
-			"id": u.ID, "email": u.Email, "name": u.Name,
-			"is_active": u.IsActive, "created_at": u.CreatedAt,
-		}
+	page, err := h.userRepo.List(r.Context(), spec)
+	if err != nil {
+		problem.Write(w, r, erptypes.Internal("failed to list users", err))
+		return
 	}
-
-	writeJSON(w, http.StatusOK, map[string]any{"items": items, "total": total})
+	writeJSON(w, http.StatusOK, listquery.Map(page, toUserResponse))
 }
 
 // GetUser handles GET /api/v1/users/{id}
 func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
-	id, err := uuid.Parse(r.PathValue("id"))
+	id, err := validate.PathUUID(r, "id")
 	if err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid user id"})
+		problem.Write(w, r, err)
 		return
 	}
 
 	user, err := h.userRepo.FindByID(r.Context(), id)
 	if err != nil {
-		writeJSON(w, http.StatusNotFound, map[string]string{"error": "user not found"})
+		problem.Write(w, r, erptypes.NotFoundAs(err, "user not found"))
 		return
 	}
 
-	writeJSON(w, http.StatusOK, map[string]any{
-		"id": user.ID, "email": user.Email, "name": user.Name,
-		"is_active": user.IsActive, "created_at": user.CreatedAt,
-	})
+	writeJSON(w, http.StatusOK, toUserResponse(user))
 }
 
-type assignRoleRequest struct {
+// UserResponse is the JSON representation of a User; the password hash is
+// never returned.
+type UserResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Email     string    `json:"email"`
+	Name      string    `json:"name"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func toUserResponse(u *domain.User) UserResponse {
+	return UserResponse{ID: u.ID, Email: u.Email, Name: u.Name, IsActive: u.IsActive, CreatedAt: u.CreatedAt}
+}
+
+// AssignRoleRequest is the body of POST /api/v1/users/{id}/roles.
+type AssignRoleRequest struct {
 	RoleID string `json:"role_id"`
+
+	roleID uuid.UUID
+}
+
+func (req *AssignRoleRequest) Validate(v *validate.Validator) {
+	req.roleID = v.UUID("role_id", req.RoleID)
 }
 
 // AssignRole handles POST /api/v1/users/{id}/roles
 func (h *UserHandler) AssignRole(w http.ResponseWriter, r *http.Request) {
-	userID, err := uuid.Parse(r.PathValue("id"))
+	userID, err := validate.PathUUID(r, "id")
 	if err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid user id"})
+		problem.Write(w, r, err)
 		return
 	}
 
-	var req assignRoleRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
-		return
-	}
-
-	roleID, err := uuid.Parse(req.RoleID)
-	if err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid role id"})
+	var req AssignRoleRequest
+	if err := validate.DecodeJSON(r, &req); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
-	if err := h.roleRepo.AssignRoleToUser(r.Context(), userID, roleID); err != nil {
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to assign role"})
+	if err := h.roleRepo.AssignRoleToUser(r.Context(), userID, req.roleID); err != nil {
+		problem.Write(w, r, erptypes.Internal("failed to assign role", err))
 		return
 	}
 
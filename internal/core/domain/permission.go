@@ -6,6 +6,9 @@ const (
 	PermUserWrite = "core:user:write"
 	PermRoleRead  = "core:role:read"
 	PermRoleWrite = "core:role:write"
+	PermAuditRead = "core:audit:read"
+	PermJobRead   = "core:job:read"  // every user's background jobs
+	PermJobWrite  = "core:job:write" // cancel anyone's jobs
 
 	PermTeacherRead  = "hr:teacher:read"
 	PermTeacherWrite = "hr:teacher:write"
@@ -31,6 +34,8 @@ func AllPermissions() []string {
 	return []string{
 		PermUserRead, PermUserWrite,
 		PermRoleRead, PermRoleWrite,
+		PermAuditRead,
+		PermJobRead, PermJobWrite,
 		PermTeacherRead, PermTeacherWrite,
 		PermDeptRead, PermDeptWrite,
 		PermSubjectRead, PermSubjectWrite,
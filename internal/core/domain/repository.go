@@ -4,15 +4,43 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
 )
 
+// UserListSchema declares how users can be filtered and sorted.
+var UserListSchema = &listquery.Schema{
+	Fields: map[string]listquery.Field{
+		"id":         {Kind: listquery.UUID, Filter: listquery.Eq | listquery.In, Sort: true},
+		"email":      {Kind: listquery.Text, Filter: listquery.Eq | listquery.Contains, Sort: true},
+		"name":       {Kind: listquery.Text, Filter: listquery.Eq | listquery.Contains, Sort: true},
+		"is_active":  {Kind: listquery.Bool, Filter: listquery.Eq},
+		"created_at": {Kind: listquery.Time, Filter: listquery.Range, Sort: true},
+		"updated_at": {Kind: listquery.Time, Filter: listquery.Range, Sort: true},
+	},
+	Key:         "id",
+	DefaultSort: []listquery.Sort{{Field: "created_at", Desc: true}},
+}
+
+// RoleListSchema declares how roles can be filtered and sorted.
+var RoleListSchema = &listquery.Schema{
+	Fields: map[string]listquery.Field{
+		"id":         {Kind: listquery.UUID, Filter: listquery.Eq | listquery.In, Sort: true},
+		"name":       {Kind: listquery.Text, Filter: listquery.Eq | listquery.In | listquery.Contains, Sort: true},
+		"permission": {Column: "permissions", Kind: listquery.Text, Array: true, Filter: listquery.Eq | listquery.In | listquery.Contains},
+		"created_at": {Kind: listquery.Time, Filter: listquery.Range, Sort: true},
+	},
+	Key:         "id",
+	DefaultSort: []listquery.Sort{{Field: "name"}},
+}
+
 // UserRepository defines persistence operations for User entities.
 type UserRepository interface {
 	FindByID(ctx context.Context, id uuid.UUID) (*User, error)
 	FindByEmail(ctx context.Context, email string) (*User, error)
 	Save(ctx context.Context, user *User) error
 	Update(ctx context.Context, user *User) error
-	List(ctx context.Context, offset, limit int) ([]*User, int, error)
+	List(ctx context.Context, spec listquery.Spec) (listquery.Page[*User], error)
 }
 
 // RoleRepository defines persistence operations for Role entities.
@@ -22,7 +50,7 @@ type RoleRepository interface {
 	Save(ctx context.Context, role *Role) error
 	Update(ctx context.Context, role *Role) error
 	Delete(ctx context.Context, id uuid.UUID) error
-	List(ctx context.Context) ([]*Role, error)
+	List(ctx context.Context, spec listquery.Spec) (listquery.Page[*Role], error)
 	FindByUserID(ctx context.Context, userID uuid.UUID) ([]*Role, error)
 	AssignRoleToUser(ctx context.Context, userID, roleID uuid.UUID) error
 	RemoveRoleFromUser(ctx context.Context, userID, roleID uuid.UUID) error
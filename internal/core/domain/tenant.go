@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
+)
+
+// TenantStatus is the lifecycle state of a tenant in public.tenants.
+type TenantStatus = tenant.Status
+
+const (
+	// TenantStatusProvisioning marks a tenant whose schema is still being built.
+	TenantStatusProvisioning = tenant.StatusProvisioning
+	// TenantStatusActive marks a fully provisioned, routable tenant.
+	TenantStatusActive = tenant.StatusActive
+	// TenantStatusSuspended blocks logins and API calls but keeps the schema
+	// migrated so the tenant can be resumed.
+	TenantStatusSuspended = tenant.StatusSuspended
+	// TenantStatusArchived freezes the tenant ahead of export and deletion.
+	TenantStatusArchived = tenant.StatusArchived
+)
+
+// TenantSettings are a tenant's module switches and feature flags.
+type TenantSettings = tenant.Settings
+
+// AdminRoleName is the role seeded with every permission for a new tenant.
+const AdminRoleName = "admin"
+
+// Tenant is a customer organisation isolated in its own Postgres schema.
+type Tenant struct {
+	ID        uuid.UUID
+	Name      string
+	Schema    string
+	Status    TenantStatus
+	IsActive  bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TenantRepository manages public.tenants and the lifecycle of tenant schemas.
+type TenantRepository interface {
+	FindBySchema(ctx context.Context, schema string) (*Tenant, error)
+	// Claim inserts t in provisioning state. When the schema is already taken it
+	// returns the existing row and created=false.
+	Claim(ctx context.Context, t *Tenant) (existing *Tenant, created bool, err error)
+	// Activate marks a provisioned tenant active and routable.
+	Activate(ctx context.Context, schema string) error
+	// List returns all tenants ordered by schema name.
+	List(ctx context.Context) ([]*Tenant, error)
+	// Transition moves a tenant to status `to` if it is currently in one of
+	// `from`, returning ErrConflict otherwise.
+	Transition(ctx context.Context, schema string, from []TenantStatus, to TenantStatus) (*Tenant, error)
+	// Export writes the tenant schema's data as a gzipped tar archive.
+	Export(ctx context.Context, schema string, w io.Writer) error
+	// Purge drops the tenant schema and removes its tenants and users_lookup rows.
+	Purge(ctx context.Context, schema string) error
+	// FindSettings returns the tenant's settings; empty maps when none are stored.
+	FindSettings(ctx context.Context, schema string) (TenantSettings, error)
+	// SaveSettings replaces the tenant's stored settings.
+	SaveSettings(ctx context.Context, schema string, s TenantSettings) error
+	// WithLock serialises lifecycle operations on one tenant across replicas.
+	WithLock(ctx context.Context, schema string, fn func(ctx context.Context) error) error
+}
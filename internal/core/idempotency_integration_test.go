@@ -0,0 +1,86 @@
+//go:build integration
+
+package core_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+
+	coredomain "github.com/HuynhHoangPhuc/mcs-erp/internal/core/domain"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/idempotency"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/testutil"
+)
+
+func TestIdempotencyKey_ReplaysResponseAndRejectsReuse(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	srv := testutil.TestServer(t, db.Pool)
+	defer srv.Close()
+
+	schema := db.CreateTenantSchema(t)
+	token := testutil.GenerateTestToken(t, uuid.New(), schema, []string{
+		coredomain.PermTeacherRead, coredomain.PermTeacherWrite,
+	})
+
+	post := func(key string, body any) (*http.Response, []byte) {
+		t.Helper()
+		raw, _ := json.Marshal(body)
+		req, err := testutil.AuthenticatedRequest(http.MethodPost, srv.URL+"/api/v1/teachers", token, schema, bytes.NewReader(raw))
+		if err != nil {
+			t.Fatalf("build request: %v", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(idempotency.Header, key)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("do request: %v", err)
+		}
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return resp, data
+	}
+
+	key := uuid.NewString()
+	teacher := map[string]any{"name": "Grace", "email": "idem_" + uuid.NewString() + "@example.com", "qualifications": []string{}}
+
+	first, firstBody := post(key, teacher)
+	if first.StatusCode != http.StatusCreated || first.Header.Get(idempotency.ReplayedHeader) != "" {
+		t.Fatalf("expected fresh 201, got %d (replayed=%q)", first.StatusCode, first.Header.Get(idempotency.ReplayedHeader))
+	}
+
+	retry, retryBody := post(key, teacher)
+	if retry.StatusCode != http.StatusCreated || retry.Header.Get(idempotency.ReplayedHeader) != "true" {
+		t.Fatalf("expected replayed 201, got %d (replayed=%q)", retry.StatusCode, retry.Header.Get(idempotency.ReplayedHeader))
+	}
+	if !bytes.Equal(firstBody, retryBody) {
+		t.Fatalf("replayed body differs:\n%s\n%s", firstBody, retryBody)
+	}
+
+	// Only one teacher was created.
+	req, _ := testutil.AuthenticatedRequest(http.MethodGet, srv.URL+"/api/v1/teachers", token, schema, nil)
+	page, _ := testutil.DoJSON[struct {
+		Total int `json:"total"`
+	}](t, http.DefaultClient, req)
+	if page.Total != 1 {
+		t.Fatalf("expected 1 teacher, got %d", page.Total)
+	}
+
+	teacher["name"] = "Grace Hopper"
+	reused, _ := post(key, teacher)
+	if reused.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422 for key reuse, got %d", reused.StatusCode)
+	}
+
+	// Client errors are final and replayed too; only 5xx responses free the key.
+	badKey := uuid.NewString()
+	if resp, _ := post(badKey, map[string]any{"name": ""}); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", resp.StatusCode)
+	}
+	if resp, _ := post(badKey, map[string]any{"name": ""}); resp.Header.Get(idempotency.ReplayedHeader) != "true" {
+		t.Fatal("expected 400 response to be replayed")
+	}
+}
@@ -17,12 +17,20 @@ type JWTService struct {
 	refreshExpiry time.Duration
 }
 
+// DefaultRefreshExpiry is the refresh token lifetime NewJWTService uses.
+const DefaultRefreshExpiry = 7 * 24 * time.Hour
+
 // NewJWTService creates a new JWT service with the given secret and expiry.
 func NewJWTService(secret string, accessExpiry time.Duration) *JWTService {
+	return NewJWTServiceWithRefresh(secret, accessExpiry, DefaultRefreshExpiry)
+}
+
+// NewJWTServiceWithRefresh is NewJWTService with an explicit refresh token lifetime.
+func NewJWTServiceWithRefresh(secret string, accessExpiry, refreshExpiry time.Duration) *JWTService {
 	return &JWTService{
 		secret:        []byte(secret),
 		accessExpiry:  accessExpiry,
-		refreshExpiry: 7 * 24 * time.Hour, // 7 days
+		refreshExpiry: refreshExpiry,
 	}
 }
 
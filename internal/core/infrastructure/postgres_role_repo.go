@@ -2,6 +2,7 @@ package infrastructure
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/google/uuid"
@@ -10,7 +11,9 @@ import (
 
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/core/domain"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/database"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
+	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
 )
 
 // PostgresRoleRepo implements domain.RoleRepository using pgx.
@@ -39,6 +42,9 @@ func (r *PostgresRoleRepo) FindByID(ctx context.Context, id uuid.UUID) (*domain.
 		).Scan(&role.ID, &role.Name, &role.Permissions, &role.Description, &role.CreatedAt)
 	})
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, erptypes.ErrNotFound
+		}
 		return nil, fmt.Errorf("find role by id: %w", err)
 	}
 	return &role, nil
@@ -57,6 +63,9 @@ func (r *PostgresRoleRepo) FindByName(ctx context.Context, name string) (*domain
 		).Scan(&role.ID, &role.Name, &role.Permissions, &role.Description, &role.CreatedAt)
 	})
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, erptypes.ErrNotFound
+		}
 		return nil, fmt.Errorf("find role by name: %w", err)
 	}
 	return &role, nil
@@ -104,33 +113,29 @@ func (r *PostgresRoleRepo) Delete(ctx context.Context, id uuid.UUID) error {
 	})
 }
 
-func (r *PostgresRoleRepo) List(ctx context.Context) ([]*domain.Role, error) {
+func (r *PostgresRoleRepo) List(ctx context.Context, spec listquery.Spec) (listquery.Page[*domain.Role], error) {
 	schema, err := r.schema(ctx)
 	if err != nil {
-		return nil, err
+		return listquery.Page[*domain.Role]{}, err
+	}
+	q, err := listquery.Build(spec, domain.RoleListSchema)
+	if err != nil {
+		return listquery.Page[*domain.Role]{}, err
 	}
 
-	var roles []*domain.Role
+	var page listquery.Page[*domain.Role]
 	err = database.WithTenantTx(ctx, r.pool, schema, func(tx pgx.Tx) error {
-		rows, err := tx.Query(ctx, "SELECT id, name, permissions, description, created_at FROM roles ORDER BY name")
-		if err != nil {
-			return err
-		}
-		defer rows.Close()
-
-		for rows.Next() {
-			var role domain.Role
-			if err := rows.Scan(&role.ID, &role.Name, &role.Permissions, &role.Description, &role.CreatedAt); err != nil {
-				return err
-			}
-			roles = append(roles, &role)
-		}
-		return rows.Err()
+		page, err = listquery.Fetch(ctx, tx, q, "id, name, permissions, description, created_at", "roles",
+			func(rows pgx.Rows, cursor *string) (*domain.Role, error) {
+				var role domain.Role
+				return &role, rows.Scan(&role.ID, &role.Name, &role.Permissions, &role.Description, &role.CreatedAt, cursor)
+			})
+		return err
 	})
 	if err != nil {
-		return nil, fmt.Errorf("list roles: %w", err)
+		return listquery.Page[*domain.Role]{}, fmt.Errorf("list roles: %w", err)
 	}
-	return roles, nil
+	return page, nil
 }
 
 func (r *PostgresRoleRepo) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Role, error) {
@@ -0,0 +1,311 @@
+package infrastructure
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/core/domain"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
+	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
+)
+
+// PostgresTenantRepo implements domain.TenantRepository on the public schema.
+type PostgresTenantRepo struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresTenantRepo creates a new tenant repository.
+func NewPostgresTenantRepo(pool *pgxpool.Pool) *PostgresTenantRepo {
+	return &PostgresTenantRepo{pool: pool}
+}
+
+const tenantColumns = `id, name, schema_name, status, is_active, created_at, updated_at`
+
+func scanTenant(row pgx.Row) (*domain.Tenant, error) {
+	var t domain.Tenant
+	if err := row.Scan(&t.ID, &t.Name, &t.Schema, &t.Status, &t.IsActive, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *PostgresTenantRepo) FindBySchema(ctx context.Context, schema string) (*domain.Tenant, error) {
+	t, err := scanTenant(r.pool.QueryRow(ctx,
+		`SELECT `+tenantColumns+` FROM public.tenants WHERE schema_name = $1`, schema,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, erptypes.ErrNotFound
+		}
+		return nil, fmt.Errorf("find tenant by schema: %w", err)
+	}
+	return t, nil
+}
+
+func (r *PostgresTenantRepo) Claim(ctx context.Context, t *domain.Tenant) (*domain.Tenant, bool, error) {
+	claimed, err := scanTenant(r.pool.QueryRow(ctx,
+		`INSERT INTO public.tenants (id, name, schema_name, status, is_active, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, false, $5, $5)
+		 ON CONFLICT (schema_name) DO NOTHING
+		 RETURNING `+tenantColumns,
+		t.ID, t.Name, t.Schema, domain.TenantStatusProvisioning, t.CreatedAt,
+	))
+	if err == nil {
+		return claimed, true, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, false, fmt.Errorf("claim tenant: %w", err)
+	}
+
+	existing, err := r.FindBySchema(ctx, t.Schema)
+	if err != nil {
+		return nil, false, err
+	}
+	return existing, false, nil
+}
+
+func (r *PostgresTenantRepo) Activate(ctx context.Context, schema string) error {
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE public.tenants SET status = $2, is_active = true, updated_at = now() WHERE schema_name = $1`,
+		schema, domain.TenantStatusActive,
+	)
+	if err != nil {
+		return fmt.Errorf("activate tenant: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return erptypes.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresTenantRepo) List(ctx context.Context) ([]*domain.Tenant, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+tenantColumns+` FROM public.tenants ORDER BY schema_name`)
+	if err != nil {
+		return nil, fmt.Errorf("list tenants: %w", err)
+	}
+	defer rows.Close()
+
+	var tenants []*domain.Tenant
+	for rows.Next() {
+		t, err := scanTenant(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan tenant: %w", err)
+		}
+		tenants = append(tenants, t)
+	}
+	return tenants, rows.Err()
+}
+
+// Transition updates status and keeps is_active in step: the schema stays live
+// (migrated, outbox relayed) while a tenant is active or suspended.
+func (r *PostgresTenantRepo) Transition(ctx context.Context, schema string, from []domain.TenantStatus, to domain.TenantStatus) (*domain.Tenant, error) {
+	allowed := make([]string, len(from))
+	for i, s := range from {
+		allowed[i] = string(s)
+	}
+
+	t, err := scanTenant(r.pool.QueryRow(ctx,
+		`UPDATE public.tenants
+		 SET status = $2, is_active = $2 IN ('active', 'suspended'), updated_at = now()
+		 WHERE schema_name = $1 AND status = ANY($3)
+		 RETURNING `+tenantColumns,
+		schema, to, allowed,
+	))
+	if err == nil {
+		return t, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("update tenant status: %w", err)
+	}
+
+	current, err := r.FindBySchema(ctx, schema)
+	if err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("%w: tenant is %s, cannot become %s", erptypes.ErrConflict, current.Status, to)
+}
+
+// exportManifest describes the contents of a tenant export archive.
+type exportManifest struct {
+	Tenant     string        `json:"tenant"`
+	Name       string        `json:"name"`
+	Schema     string        `json:"schema"`
+	Status     string        `json:"status"`
+	ExportedAt time.Time     `json:"exported_at"`
+	Tables     []exportTable `json:"tables"`
+}
+
+type exportTable struct {
+	Name string `json:"name"`
+	File string `json:"file"`
+	Rows int64  `json:"rows"`
+}
+
+// Export streams every table in the tenant schema as CSV (with header) from a
+// single repeatable-read snapshot, followed by manifest.json. The archive can
+// be restored by replaying the recorded schema_migrations and COPYing the files back.
+func (r *PostgresTenantRepo) Export(ctx context.Context, schema string, w io.Writer) error {
+	t, err := r.FindBySchema(ctx, schema)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx,
+		`SELECT table_name FROM information_schema.tables
+		 WHERE table_schema = $1 AND table_type = 'BASE TABLE'
+		 ORDER BY table_name`, schema,
+	)
+	if err != nil {
+		return fmt.Errorf("list tenant tables: %w", err)
+	}
+	tables, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return fmt.Errorf("scan tenant tables: %w", err)
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	manifest := exportManifest{
+		Tenant: t.ID.String(), Name: t.Name, Schema: t.Schema, Status: string(t.Status),
+		ExportedAt: time.Now().UTC(),
+	}
+
+	for _, table := range tables {
+		entry := exportTable{Name: table, File: "data/" + table + ".csv"}
+		copySQL := "COPY " + pgx.Identifier{schema, table}.Sanitize() + " TO STDOUT WITH (FORMAT csv, HEADER true)"
+		entry.Rows, err = copyTableToTar(ctx, tx, tw, entry.File, copySQL)
+		if err != nil {
+			return fmt.Errorf("export table %s: %w", table, err)
+		}
+		manifest.Tables = append(manifest.Tables, entry)
+	}
+
+	raw, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal manifest: %w", err)
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: "manifest.json", Mode: 0o644, Size: int64(len(raw)), ModTime: manifest.ExportedAt}); err != nil {
+		return fmt.Errorf("write manifest: %w", err)
+	}
+	if _, err := tw.Write(raw); err != nil {
+		return fmt.Errorf("write manifest: %w", err)
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("close tar: %w", err)
+	}
+	return gz.Close()
+}
+
+// copyTableToTar spools one COPY to a temp file so the tar header can carry its size.
+func copyTableToTar(ctx context.Context, tx pgx.Tx, tw *tar.Writer, name, copySQL string) (int64, error) {
+	f, err := os.CreateTemp("", "tenant-export-*.csv")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	tag, err := tx.Conn().PgConn().CopyTo(ctx, f, copySQL)
+	if err != nil {
+		return 0, err
+	}
+	size, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: size, ModTime: time.Now().UTC()}); err != nil {
+		return 0, err
+	}
+	if _, err := io.Copy(tw, f); err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *PostgresTenantRepo) Purge(ctx context.Context, schema string) error {
+	if err := tenant.ValidateSchema(schema); err != nil {
+		return err
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "DROP SCHEMA IF EXISTS "+pgx.Identifier{schema}.Sanitize()+" CASCADE"); err != nil {
+		return fmt.Errorf("drop tenant schema: %w", err)
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM public.users_lookup WHERE tenant_schema = $1", schema); err != nil {
+		return fmt.Errorf("delete users lookup: %w", err)
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM public.tenants WHERE schema_name = $1", schema); err != nil {
+		return fmt.Errorf("delete tenant: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *PostgresTenantRepo) FindSettings(ctx context.Context, schema string) (domain.TenantSettings, error) {
+	s := domain.TenantSettings{Modules: map[string]bool{}, Features: map[string]bool{}}
+	err := r.pool.QueryRow(ctx,
+		`SELECT modules, features FROM public.tenant_settings WHERE schema_name = $1`, schema,
+	).Scan(&s.Modules, &s.Features)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return s, fmt.Errorf("find tenant settings: %w", err)
+	}
+	return s, nil
+}
+
+func (r *PostgresTenantRepo) SaveSettings(ctx context.Context, schema string, s domain.TenantSettings) error {
+	_, err := r.pool.Exec(ctx,
+		`INSERT INTO public.tenant_settings (schema_name, modules, features, updated_at)
+		 VALUES ($1, $2, $3, now())
+		 ON CONFLICT (schema_name) DO UPDATE
+		 SET modules = EXCLUDED.modules, features = EXCLUDED.features, updated_at = now()`,
+		schema, s.Modules, s.Features,
+	)
+	if err != nil {
+		return fmt.Errorf("save tenant settings: %w", err)
+	}
+	return nil
+}
+
+// WithLock holds a session-level advisory lock keyed on the schema while fn runs.
+func (r *PostgresTenantRepo) WithLock(ctx context.Context, schema string, fn func(ctx context.Context) error) error {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock(hashtext('tenant:' || $1))", schema); err != nil {
+		return fmt.Errorf("acquire tenant lock: %w", err)
+	}
+	// Unlock with a fresh context so a cancelled request never leaks the lock.
+	defer conn.Exec(context.Background(), "SELECT pg_advisory_unlock(hashtext('tenant:' || $1))", schema)
+
+	return fn(ctx)
+}
+
+var _ domain.TenantRepository = (*PostgresTenantRepo)(nil)
@@ -2,6 +2,7 @@ package infrastructure
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/google/uuid"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/core/domain"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/database"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
 	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
 )
@@ -42,6 +44,9 @@ func (r *PostgresUserRepo) FindByID(ctx context.Context, id uuid.UUID) (*domain.
 		).Scan(&u.ID, &u.Email, &u.PasswordHash, &u.Name, &u.IsActive, &u.CreatedAt, &u.UpdatedAt)
 	})
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, erptypes.ErrNotFound
+		}
 		return nil, fmt.Errorf("find user by id: %w", err)
 	}
 	return &u, nil
@@ -61,6 +66,9 @@ func (r *PostgresUserRepo) FindByEmail(ctx context.Context, email string) (*doma
 		).Scan(&u.ID, &u.Email, &u.PasswordHash, &u.Name, &u.IsActive, &u.CreatedAt, &u.UpdatedAt)
 	})
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, erptypes.ErrNotFound
+		}
 		return nil, fmt.Errorf("find user by email: %w", err)
 	}
 	return &u, nil
@@ -97,41 +105,29 @@ func (r *PostgresUserRepo) Update(ctx context.Context, user *domain.User) error
 	})
 }
 
-func (r *PostgresUserRepo) List(ctx context.Context, offset, limit int) ([]*domain.User, int, error) {
+func (r *PostgresUserRepo) List(ctx context.Context, spec listquery.Spec) (listquery.Page[*domain.User], error) {
 	schema, err := r.schema(ctx)
 	if err != nil {
-		return nil, 0, err
+		return listquery.Page[*domain.User]{}, err
+	}
+	q, err := listquery.Build(spec, domain.UserListSchema)
+	if err != nil {
+		return listquery.Page[*domain.User]{}, err
 	}
 
-	var users []*domain.User
-	var total int
+	var page listquery.Page[*domain.User]
 	err = database.WithTenantTx(ctx, r.pool, schema, func(tx pgx.Tx) error {
-		if err := tx.QueryRow(ctx, "SELECT COUNT(*) FROM users").Scan(&total); err != nil {
-			return err
-		}
-
-		rows, err := tx.Query(ctx,
-			"SELECT id, email, password_hash, name, is_active, created_at, updated_at FROM users ORDER BY created_at DESC LIMIT $1 OFFSET $2",
-			limit, offset,
-		)
-		if err != nil {
-			return err
-		}
-		defer rows.Close()
-
-		for rows.Next() {
-			var u domain.User
-			if err := rows.Scan(&u.ID, &u.Email, &u.PasswordHash, &u.Name, &u.IsActive, &u.CreatedAt, &u.UpdatedAt); err != nil {
-				return err
-			}
-			users = append(users, &u)
-		}
-		return rows.Err()
+		page, err = listquery.Fetch(ctx, tx, q, "id, email, password_hash, name, is_active, created_at, updated_at", "users",
+			func(rows pgx.Rows, cursor *string) (*domain.User, error) {
+				var u domain.User
+				return &u, rows.Scan(&u.ID, &u.Email, &u.PasswordHash, &u.Name, &u.IsActive, &u.CreatedAt, &u.UpdatedAt, cursor)
+			})
+		return err
 	})
 	if err != nil {
-		return nil, 0, fmt.Errorf("list users: %w", err)
+		return listquery.Page[*domain.User]{}, fmt.Errorf("list users: %w", err)
 	}
-	return users, total, nil
+	return page, nil
 }
 
 // Ensure interface compliance.
@@ -152,7 +148,7 @@ func (r *PostgresUsersLookupRepo) FindTenantByEmail(ctx context.Context, email s
 		"SELECT tenant_schema FROM public.users_lookup WHERE email = $1", email,
 	).Scan(&schema)
 	if err != nil {
-		if err == pgx.ErrNoRows {
+		if errors.Is(err, pgx.ErrNoRows) {
 			return "", erptypes.ErrNotFound
 		}
 		return "", fmt.Errorf("lookup tenant by email: %w", err)
@@ -3,6 +3,8 @@ package core
 import (
 	"context"
 	"net/http"
+	"slices"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 
@@ -11,9 +13,19 @@ import (
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/core/delivery"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/core/domain"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/core/infrastructure"
-	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/auth"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/audit"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/database"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/jobs"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/openapi"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
+	pkgmod "github.com/HuynhHoangPhuc/mcs-erp/pkg/module"
 )
 
+// tenantStatusTTL bounds how long another replica may keep serving a tenant
+// after it is suspended or archived.
+const tenantStatusTTL = 5 * time.Second
+
 // Module implements pkg/module.Module for the core (auth/user/role) module.
 type Module struct {
 	pool       *pgxpool.Pool
@@ -22,6 +34,14 @@ type Module struct {
 	userRepo   domain.UserRepository
 	roleRepo   domain.RoleRepository
 	lookupRepo domain.UsersLookupRepository
+	tenantSvc  *services.TenantService
+	tenantDir  *tenant.Directory
+	auditStore *audit.Store
+	jobQueue   *jobs.Queue
+	routes     []pkgmod.Route
+
+	platformToken string
+	migrationsSet bool
 }
 
 // NewModuleWithDeps creates the core module wired with concrete dependencies.
@@ -29,7 +49,15 @@ func NewModuleWithDeps(pool *pgxpool.Pool, jwtSvc *infrastructure.JWTService) *M
 	userRepo := infrastructure.NewPostgresUserRepo(pool)
 	roleRepo := infrastructure.NewPostgresRoleRepo(pool)
 	lookupRepo := infrastructure.NewPostgresUsersLookupRepo(pool)
-	authSvc := services.NewAuthService(userRepo, roleRepo, lookupRepo, jwtSvc)
+	tenantDir := tenant.NewDirectory(pool, tenantStatusTTL)
+	authSvc := services.NewAuthService(userRepo, roleRepo, lookupRepo, jwtSvc, tenantDir)
+	tenantSvc := services.NewTenantService(
+		infrastructure.NewPostgresTenantRepo(pool),
+		userRepo, roleRepo, lookupRepo,
+		database.NewMigrator(pool),
+		tenantDir,
+		database.NewDriftInspector(pool),
+	)
 
 	return &Module{
 		pool:       pool,
@@ -38,42 +66,207 @@ func NewModuleWithDeps(pool *pgxpool.Pool, jwtSvc *infrastructure.JWTService) *M
 		userRepo:   userRepo,
 		roleRepo:   roleRepo,
 		lookupRepo: lookupRepo,
+		tenantSvc:  tenantSvc,
+		tenantDir:  tenantDir,
+		auditStore: audit.NewStore(pool),
 	}
 }
 
+// SetPlatformAdminToken enables the platform-level tenant endpoints for callers
+// presenting this token. An empty token keeps them disabled.
+func (m *Module) SetPlatformAdminToken(token string) { m.platformToken = token }
+
+// SetMigrationRunner enables the tenant migration status and retry endpoints
+// and the tenant_migrations health check.
+func (m *Module) SetMigrationRunner(r services.TenantMigrationRunner) {
+	m.tenantSvc.SetMigrationRunner(r)
+	m.migrationsSet = true
+}
+
+// SetJobQueue enables the background job status API (GET /api/v1/jobs,
+// GET /api/v1/jobs/{id}, POST /api/v1/jobs/{id}/cancel).
+func (m *Module) SetJobQueue(q *jobs.Queue) { m.jobQueue = q }
+
+// HealthChecks reports quarantined or unfinished tenant migrations. Not
+// critical: healthy tenants are still served.
+func (m *Module) HealthChecks() []pkgmod.HealthCheck {
+	if !m.migrationsSet {
+		return nil
+	}
+	return []pkgmod.HealthCheck{{Name: "tenant_migrations", Check: m.tenantSvc.CheckMigrations}}
+}
+
 func (m *Module) Name() string            { return "core" }
 func (m *Module) Dependencies() []string   { return nil }
-func (m *Module) Migrate(ctx context.Context) error { return nil }
+// Migrate applies the module's pending migrations to _template; tenants follow via database.TenantRunner.
+func (m *Module) Migrate(ctx context.Context) error {
+	return database.NewMigrator(m.pool).MigrateModule(ctx, m.Name())
+}
 func (m *Module) RegisterEvents(ctx context.Context) error { return nil }
 
+// Routes describes the routes mounted by RegisterRoutes.
+func (m *Module) Routes() []pkgmod.Route { return m.routes }
+
+// RouteFilter selects the OpenAPI routes the caller of r may use.
+func (m *Module) RouteFilter(r *http.Request) openapi.Filter {
+	return delivery.RouteFilter(m.authSvc, m.platformToken)(r)
+}
+
 func (m *Module) RegisterRoutes(mux *http.ServeMux) {
 	authHandler := delivery.NewAuthHandler(m.authSvc)
 	userHandler := delivery.NewUserHandler(m.userRepo, m.roleRepo, m.lookupRepo)
 	roleHandler := delivery.NewRoleHandler(m.roleRepo)
+	tenantHandler := delivery.NewTenantHandler(m.tenantSvc)
+	auditHandler := audit.NewHandler(m.auditStore)
+
+	api := openapi.NewRouter(mux, m.Name(), delivery.AuthMiddleware(m.authSvc)).
+		WithPlatform(delivery.PlatformAdminMiddleware(m.platformToken))
 
 	// Public auth routes (no JWT required)
-	mux.HandleFunc("POST /api/v1/auth/login", authHandler.Login)
-	mux.HandleFunc("POST /api/v1/auth/refresh", authHandler.Refresh)
-	mux.HandleFunc("POST /api/v1/auth/logout", authHandler.Logout)
+	api.Handle(pkgmod.Route{
+		Method: "POST", Path: "/api/v1/auth/login", Summary: "Sign in", Access: pkgmod.AccessPublic,
+		Request: delivery.LoginRequest{}, Response: infrastructure.TokenPair{}, RateClass: pkgmod.RateAuth,
+	}, authHandler.Login)
+	api.Handle(pkgmod.Route{
+		Method: "POST", Path: "/api/v1/auth/refresh", Summary: "Exchange a refresh token", Access: pkgmod.AccessPublic,
+		OperationID: "refreshToken",
+		Request:     delivery.RefreshRequest{}, Response: infrastructure.TokenPair{}, RateClass: pkgmod.RateAuth,
+	}, authHandler.Refresh)
+	api.Handle(pkgmod.Route{
+		Method: "POST", Path: "/api/v1/auth/logout", Summary: "Sign out", Access: pkgmod.AccessPublic,
+		Response: map[string]string{},
+	}, authHandler.Logout)
+
+	// Platform routes (operator token, no tenant)
+	schemaRoute := func(method, path, operationID, summary string, response any) pkgmod.Route {
+		return pkgmod.Route{
+			Method: method, Path: "/api/v1/platform/tenants/{schema}" + path, Summary: summary, Access: pkgmod.AccessPlatform,
+			OperationID: operationID, Response: response,
+		}
+	}
+	api.Handle(pkgmod.Route{
+		Method: "POST", Path: "/api/v1/auth/register", Summary: "Register a tenant", Access: pkgmod.AccessPlatform,
+		OperationID: "registerTenant",
+		Request:     delivery.RegisterTenantRequest{}, Response: delivery.RegisterTenantResponse{}, Status: http.StatusCreated,
+	}, tenantHandler.Register)
+	api.Handle(pkgmod.Route{
+		Method: "GET", Path: "/api/v1/platform/tenants", Summary: "List tenants", Access: pkgmod.AccessPlatform,
+		OperationID: "listTenants", Response: []delivery.TenantResponse{},
+	}, tenantHandler.ListTenants)
+	api.Handle(schemaRoute("GET", "", "getTenant", "Get a tenant", delivery.TenantResponse{}), tenantHandler.GetTenant)
+	api.Handle(schemaRoute("POST", "/suspend", "suspendTenant", "Suspend a tenant", delivery.TenantResponse{}), tenantHandler.Suspend)
+	api.Handle(schemaRoute("POST", "/resume", "resumeTenant", "Resume a suspended tenant", delivery.TenantResponse{}), tenantHandler.Resume)
+	api.Handle(schemaRoute("POST", "/archive", "archiveTenant", "Archive a tenant", delivery.TenantResponse{}), tenantHandler.Archive)
+	export := schemaRoute("GET", "/export", "exportTenant", "Export a tenant's data as tar.gz", nil)
+	export.Produces, export.Timeout, export.RateClass = "application/gzip", 10*time.Minute, pkgmod.RateCompute
+	api.Handle(export, tenantHandler.Export)
+	del := schemaRoute("DELETE", "", "deleteTenant", "Delete an archived tenant", nil)
+	del.Request, del.Status = delivery.DeleteTenantRequest{}, http.StatusNoContent
+	api.Handle(del, tenantHandler.Delete)
+	api.Handle(pkgmod.Route{
+		Method: "GET", Path: "/api/v1/platform/migrations", Summary: "List tenant migration states", Access: pkgmod.AccessPlatform,
+		OperationID: "listTenantMigrations", Response: []database.TenantMigrationState{},
+	}, tenantHandler.ListMigrations)
+	api.Handle(pkgmod.Route{
+		Method: "GET", Path: "/api/v1/platform/drift", Summary: "Report schema drift of every tenant", Access: pkgmod.AccessPlatform,
+		OperationID: "getDriftReport",
+		Query:       []pkgmod.Param{{Name: "all", Type: "boolean", Description: "Include schemas without drift"}},
+		Response:    delivery.DriftReportResponse{},
+	}, tenantHandler.DriftReport)
+	api.Handle(schemaRoute("GET", "/drift", "getTenantDrift", "Report a tenant's schema drift", database.SchemaDrift{}), tenantHandler.GetDrift)
+	api.Handle(schemaRoute("POST", "/migrations/retry", "retryTenantMigration", "Migrate a quarantined tenant again", delivery.MigrationRetryResponse{}), tenantHandler.RetryMigration)
+	api.Handle(schemaRoute("GET", "/settings", "getTenantSettings", "Get a tenant's settings", delivery.SettingsResponse{}), tenantHandler.GetSettings)
+	patch := schemaRoute("PATCH", "/settings", "updateTenantSettings", "Update a tenant's settings", delivery.SettingsResponse{})
+	patch.Request = delivery.SettingsPatchRequest{}
+	api.Handle(patch, tenantHandler.UpdateSettings)
 
 	// Protected routes — wrapped with auth middleware + permission checks
-	authMw := delivery.AuthMiddleware(m.authSvc)
-	userPerm := auth.RequirePermission(domain.PermUserWrite)
-	rolePerm := auth.RequirePermission(domain.PermRoleWrite)
-	readPerm := auth.RequirePermission(domain.PermUserRead)
 
 	// Users
-	mux.Handle("POST /api/v1/users", authMw(userPerm(http.HandlerFunc(userHandler.CreateUser))))
-	mux.Handle("GET /api/v1/users", authMw(readPerm(http.HandlerFunc(userHandler.ListUsers))))
-	mux.Handle("GET /api/v1/users/{id}", authMw(readPerm(http.HandlerFunc(userHandler.GetUser))))
-	mux.Handle("POST /api/v1/users/{id}/roles", authMw(userPerm(http.HandlerFunc(userHandler.AssignRole))))
+	api.Handle(pkgmod.Route{
+		Method: "POST", Path: "/api/v1/users", Summary: "Create a user", Permission: domain.PermUserWrite,
+		Request: delivery.CreateUserRequest{}, Response: delivery.UserResponse{}, Status: http.StatusCreated,
+	}, userHandler.CreateUser)
+	api.Handle(pkgmod.Route{
+		Method: "GET", Path: "/api/v1/users", Summary: "List users", Permission: domain.PermUserRead,
+		Query: openapi.ListParams(domain.UserListSchema), Response: listquery.Page[delivery.UserResponse]{},
+	}, userHandler.ListUsers)
+	api.Handle(pkgmod.Route{
+		Method: "GET", Path: "/api/v1/users/{id}", Summary: "Get a user", Permission: domain.PermUserRead,
+		Response: delivery.UserResponse{},
+	}, userHandler.GetUser)
+	api.Handle(pkgmod.Route{
+		Method: "POST", Path: "/api/v1/users/{id}/roles", Summary: "Assign a role to a user", Permission: domain.PermUserWrite,
+		Request: delivery.AssignRoleRequest{}, Response: map[string]string{},
+	}, userHandler.AssignRole)
 
 	// Roles
-	mux.Handle("POST /api/v1/roles", authMw(rolePerm(http.HandlerFunc(roleHandler.CreateRole))))
-	mux.Handle("GET /api/v1/roles", authMw(auth.RequirePermission(domain.PermRoleRead)(http.HandlerFunc(roleHandler.ListRoles))))
-	mux.Handle("GET /api/v1/roles/{id}", authMw(auth.RequirePermission(domain.PermRoleRead)(http.HandlerFunc(roleHandler.GetRole))))
-	mux.Handle("DELETE /api/v1/roles/{id}", authMw(rolePerm(http.HandlerFunc(roleHandler.DeleteRole))))
+	api.Handle(pkgmod.Route{
+		Method: "POST", Path: "/api/v1/roles", Summary: "Create a role", Permission: domain.PermRoleWrite,
+		Request: delivery.CreateRoleRequest{}, Response: domain.Role{}, Status: http.StatusCreated,
+	}, roleHandler.CreateRole)
+	api.Handle(pkgmod.Route{
+		Method: "GET", Path: "/api/v1/roles", Summary: "List roles", Permission: domain.PermRoleRead,
+		Query: openapi.ListParams(domain.RoleListSchema), Response: listquery.Page[*domain.Role]{},
+	}, roleHandler.ListRoles)
+	api.Handle(pkgmod.Route{
+		Method: "GET", Path: "/api/v1/roles/{id}", Summary: "Get a role", Permission: domain.PermRoleRead,
+		Response: domain.Role{},
+	}, roleHandler.GetRole)
+	api.Handle(pkgmod.Route{
+		Method: "DELETE", Path: "/api/v1/roles/{id}", Summary: "Delete a role", Permission: domain.PermRoleWrite,
+		Response: map[string]string{},
+	}, roleHandler.DeleteRole)
+
+	// Audit log (written by database triggers; read-only here)
+	auditQuery := append(openapi.ListParams(audit.ListSchema),
+		pkgmod.Param{Name: "from", Format: "date-time", Description: "Same as occurred_at[gte]"},
+		pkgmod.Param{Name: "to", Format: "date-time", Description: "Same as occurred_at[lt]"})
+	auditFilters := slices.DeleteFunc(slices.Clone(auditQuery), func(p pkgmod.Param) bool {
+		return p.Name == "limit" || p.Name == "offset" || p.Name == "cursor" || p.Name == "sort"
+	})
+	api.Handle(pkgmod.Route{
+		Method: "GET", Path: "/api/v1/audit", Summary: "Search the audit log", Permission: domain.PermAuditRead,
+		OperationID: "listAudit",
+		Query:       auditQuery, Response: listquery.Page[audit.Entry]{},
+	}, auditHandler.List)
+	api.Handle(pkgmod.Route{
+		Method: "GET", Path: "/api/v1/audit/export", Summary: "Export the audit log", Permission: domain.PermAuditRead,
+		OperationID: "exportAudit",
+		Query:       append(auditFilters, pkgmod.Param{Name: "format", Description: "csv (default) or jsonl"}),
+		Produces:    "text/csv", Timeout: 5 * time.Minute, RateClass: pkgmod.RateCompute,
+	}, auditHandler.Export)
+
+	// Background jobs: callers see their own, PermJobRead/PermJobWrite everyone's
+	if m.jobQueue != nil {
+		jobHandler := jobs.NewHandler(m.jobQueue, domain.PermJobRead, domain.PermJobWrite)
+		api.Handle(pkgmod.Route{
+			Method: "GET", Path: jobs.Path, Summary: "List background jobs",
+			OperationID: "listJobs",
+			Query:       openapi.ListParams(jobs.ListSchema), Response: listquery.Page[jobs.Job]{},
+		}, jobHandler.List)
+		api.Handle(pkgmod.Route{
+			Method: "GET", Path: jobs.Path + "/{id}", Summary: "Get a background job's status",
+			OperationID: "getJob", Response: jobs.Job{},
+		}, jobHandler.Get)
+		api.Handle(pkgmod.Route{
+			Method: "POST", Path: jobs.Path + "/{id}/cancel", Summary: "Cancel a background job",
+			OperationID: "cancelJob", Response: jobs.Job{},
+		}, jobHandler.Cancel)
+	}
+
+	// Tenant settings (read-only for any signed-in user; toggled via the platform API)
+	api.Handle(pkgmod.Route{
+		Method: "GET", Path: "/api/v1/settings", Summary: "Get the tenant's settings",
+		OperationID: "getMySettings", Response: delivery.SettingsResponse{},
+	}, tenantHandler.MySettings)
+
+	m.routes = api.Routes()
 }
 
 // AuthService returns the auth service for use by other modules or main.
 func (m *Module) AuthService() *services.AuthService { return m.authSvc }
+
+// TenantDirectory returns the cached tenant status lookup used to reject
+// suspended and archived tenants at the edge.
+func (m *Module) TenantDirectory() *tenant.Directory { return m.tenantDir }
@@ -0,0 +1,82 @@
+//go:build integration
+
+package core_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+
+	coredomain "github.com/HuynhHoangPhuc/mcs-erp/internal/core/domain"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/testutil"
+)
+
+func TestOpenAPI_DocumentIsFilteredByCaller(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	schema := db.CreateTenantSchema(t)
+	srv := testutil.TestServer(t, db.Pool)
+	defer srv.Close()
+
+	type document struct {
+		OpenAPI string                                `json:"openapi"`
+		Paths   map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	fetch := func(header, value string) document {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+"/api/v1/openapi.json", nil)
+		req.Header.Set("X-Tenant-ID", schema)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("get openapi.json: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200, got %d", resp.StatusCode)
+		}
+		var doc document
+		if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+			t.Fatalf("decode document: %v", err)
+		}
+		return doc
+	}
+	has := func(doc document, method, path string) bool {
+		_, ok := doc.Paths[path][method]
+		return ok
+	}
+
+	// Anonymous callers see the public routes only.
+	anon := fetch("", "")
+	if anon.OpenAPI != "3.1.0" {
+		t.Fatalf("expected openapi 3.1.0, got %q", anon.OpenAPI)
+	}
+	if !has(anon, "post", "/api/v1/auth/login") {
+		t.Fatal("expected login in anonymous document")
+	}
+	if has(anon, "get", "/api/v1/teachers") || has(anon, "get", "/api/v1/platform/tenants") {
+		t.Fatalf("anonymous document exposes protected routes: %v", anon.Paths)
+	}
+
+	// A user sees what their permissions allow.
+	token := testutil.GenerateTestToken(t, uuid.New(), schema, []string{coredomain.PermTeacherRead})
+	user := fetch("Authorization", "Bearer "+token)
+	if !has(user, "get", "/api/v1/teachers") || !has(user, "get", "/api/v1/teachers/{id}") {
+		t.Fatal("expected teacher reads in user document")
+	}
+	if has(user, "post", "/api/v1/teachers") || has(user, "get", "/api/v1/rooms") || has(user, "get", "/api/v1/platform/tenants") {
+		t.Fatal("user document exposes routes beyond the token's permissions")
+	}
+
+	// The platform operator sees the platform routes.
+	platform := fetch("X-Platform-Token", testutil.TestPlatformToken)
+	if !has(platform, "get", "/api/v1/platform/tenants") || !has(platform, "post", "/api/v1/auth/register") {
+		t.Fatal("expected platform routes in operator document")
+	}
+	if has(platform, "get", "/api/v1/teachers") {
+		t.Fatal("operator document exposes tenant routes")
+	}
+}
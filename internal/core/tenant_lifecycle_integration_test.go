@@ -0,0 +1,143 @@
+//go:build integration
+
+package core_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/testutil"
+)
+
+func TestTenantLifecycle_SuspendArchiveExportDelete(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	srv := testutil.TestServer(t, db.Pool)
+	defer srv.Close()
+
+	schema := "life_" + strings.ReplaceAll(uuid.NewString(), "-", "_")[:12]
+	t.Cleanup(func() { purgeTenant(t, db, schema) })
+
+	email := "owner_" + uuid.NewString() + "@example.com"
+	password := "s3cret-pass"
+	reg := registerTenant(t, srv.URL, map[string]string{
+		"name": "Lifecycle Faculty", "schema": schema,
+		"admin_email": email, "admin_name": "Owner", "admin_password": password,
+	}, testutil.TestPlatformToken)
+	reg.Body.Close()
+	if reg.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", reg.StatusCode)
+	}
+	token := loginAndGetToken(t, srv.URL, email, password)
+
+	// Suspended: existing tokens and new logins are rejected with 403.
+	expectStatus(t, platformRequest(t, http.MethodPost, srv.URL+"/api/v1/platform/tenants/"+schema+"/suspend", nil), http.StatusOK)
+	expectStatus(t, tenantUsersRequest(t, srv.URL, token, schema), http.StatusForbidden)
+	expectStatus(t, postJSON(t, srv.URL+"/api/v1/auth/login", map[string]string{"email": email, "password": password}), http.StatusForbidden)
+
+	// Deleting a tenant that is not archived is refused.
+	expectStatus(t, platformRequest(t, http.MethodDelete, srv.URL+"/api/v1/platform/tenants/"+schema, map[string]string{"confirm": schema}), http.StatusConflict)
+
+	// Resumed: the old token works again.
+	expectStatus(t, platformRequest(t, http.MethodPost, srv.URL+"/api/v1/platform/tenants/"+schema+"/resume", nil), http.StatusOK)
+	expectStatus(t, tenantUsersRequest(t, srv.URL, token, schema), http.StatusOK)
+
+	// Archived: 410 Gone, but the data can still be exported.
+	expectStatus(t, platformRequest(t, http.MethodPost, srv.URL+"/api/v1/platform/tenants/"+schema+"/archive", nil), http.StatusOK)
+	expectStatus(t, tenantUsersRequest(t, srv.URL, token, schema), http.StatusGone)
+
+	export := platformRequest(t, http.MethodGet, srv.URL+"/api/v1/platform/tenants/"+schema+"/export", nil)
+	defer export.Body.Close()
+	if export.StatusCode != http.StatusOK {
+		t.Fatalf("expected export 200, got %d", export.StatusCode)
+	}
+	files := readExport(t, export.Body)
+	if _, ok := files["manifest.json"]; !ok {
+		t.Fatalf("export missing manifest.json")
+	}
+	if !bytes.Contains(files["data/users.csv"], []byte(email)) {
+		t.Fatalf("export users.csv does not contain the admin user")
+	}
+
+	// Hard delete requires the schema name as confirmation.
+	expectStatus(t, platformRequest(t, http.MethodDelete, srv.URL+"/api/v1/platform/tenants/"+schema, map[string]string{"confirm": "wrong"}), http.StatusBadRequest)
+	expectStatus(t, platformRequest(t, http.MethodDelete, srv.URL+"/api/v1/platform/tenants/"+schema, map[string]string{"confirm": schema}), http.StatusNoContent)
+	expectStatus(t, platformRequest(t, http.MethodGet, srv.URL+"/api/v1/platform/tenants/"+schema, nil), http.StatusNotFound)
+	expectStatus(t, tenantUsersRequest(t, srv.URL, token, schema), http.StatusNotFound)
+}
+
+func platformRequest(t *testing.T, method, url string, body any) *http.Response {
+	t.Helper()
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("marshal body: %v", err)
+		}
+		reader = bytes.NewReader(raw)
+	}
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		t.Fatalf("build request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Platform-Token", testutil.TestPlatformToken)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, url, err)
+	}
+	return resp
+}
+
+func tenantUsersRequest(t *testing.T, baseURL, token, schema string) *http.Response {
+	t.Helper()
+	req, err := testutil.AuthenticatedRequest(http.MethodGet, baseURL+"/api/v1/users", token, schema, nil)
+	if err != nil {
+		t.Fatalf("create users request: %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("list users: %v", err)
+	}
+	return resp
+}
+
+func expectStatus(t *testing.T, resp *http.Response, want int) {
+	t.Helper()
+	defer resp.Body.Close()
+	if resp.StatusCode != want {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("%s %s: expected %d, got %d: %s", resp.Request.Method, resp.Request.URL.Path, want, resp.StatusCode, body)
+	}
+}
+
+func readExport(t *testing.T, r io.Reader) map[string][]byte {
+	t.Helper()
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		t.Fatalf("open gzip: %v", err)
+	}
+	tr := tar.NewReader(gz)
+	files := map[string][]byte{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		if err != nil {
+			t.Fatalf("read tar: %v", err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatalf("read %s: %v", hdr.Name, err)
+		}
+		files[hdr.Name] = data
+	}
+}
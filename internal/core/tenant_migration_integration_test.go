@@ -0,0 +1,77 @@
+//go:build integration
+
+package core_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/database"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/testutil"
+	"github.com/HuynhHoangPhuc/mcs-erp/migrations"
+)
+
+func TestTenantRunner_QuarantinesFailedTenantUntilRetry(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	srv := testutil.TestServer(t, db.Pool)
+	defer srv.Close()
+	ctx := context.Background()
+
+	healthy := db.CreateTenantSchema(t)
+	broken := db.CreateTenantSchema(t)
+	admin := testutil.SeedAdmin(t, db.Pool, broken)
+	token := loginAndGetToken(t, srv.URL, admin.Email, admin.Password)
+
+	// Roll back the last hr step and squat its table name with a view so
+	// re-applying it fails on CREATE INDEX.
+	hrMigs, err := database.LoadMigrations("hr")
+	if err != nil {
+		t.Fatalf("load hr migrations: %v", err)
+	}
+	migrator := database.NewMigrator(db.Pool)
+	if _, err := migrator.Down(ctx, broken, hrMigs, 1); err != nil {
+		t.Fatalf("roll back hr: %v", err)
+	}
+	view := pgx.Identifier{broken, "teacher_availability"}.Sanitize()
+	if _, err := db.Pool.Exec(ctx, "CREATE VIEW "+view+" AS SELECT 1 AS x"); err != nil {
+		t.Fatalf("create blocking view: %v", err)
+	}
+
+	runner := database.NewTenantRunner(db.Pool, migrations.TenantModules, 2)
+	report := runner.RunSchemas(ctx, []string{broken, healthy})
+	failed := report.Failed()
+	if len(failed) != 1 || failed[0] != broken {
+		t.Fatalf("expected only %s to fail, got %v", broken, failed)
+	}
+
+	// The broken tenant is quarantined with a maintenance response.
+	expectStatus(t, tenantUsersRequest(t, srv.URL, token, broken), http.StatusServiceUnavailable)
+
+	resp := platformRequest(t, http.MethodGet, srv.URL+"/api/v1/platform/migrations", nil)
+	var states []database.TenantMigrationState
+	if err := json.NewDecoder(resp.Body).Decode(&states); err != nil {
+		t.Fatalf("decode migration states: %v", err)
+	}
+	resp.Body.Close()
+	got := map[string]database.TenantMigrationStatus{}
+	for _, s := range states {
+		got[s.Schema] = s.Status
+	}
+	if got[broken] != database.TenantMigrationFailed || got[healthy] != database.TenantMigrationApplied {
+		t.Fatalf("unexpected migration states: %v", got)
+	}
+
+	// Retrying while still broken keeps the quarantine.
+	expectStatus(t, platformRequest(t, http.MethodPost, srv.URL+"/api/v1/platform/tenants/"+broken+"/migrations/retry", nil), http.StatusInternalServerError)
+
+	// Fix the schema and retry: the tenant is served again.
+	if _, err := db.Pool.Exec(ctx, "DROP VIEW "+view); err != nil {
+		t.Fatalf("drop blocking view: %v", err)
+	}
+	expectStatus(t, platformRequest(t, http.MethodPost, srv.URL+"/api/v1/platform/tenants/"+broken+"/migrations/retry", nil), http.StatusOK)
+	expectStatus(t, tenantUsersRequest(t, srv.URL, token, broken), http.StatusOK)
+}
@@ -0,0 +1,136 @@
+//go:build integration
+
+package core_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/testutil"
+)
+
+func TestRegisterTenant_ProvisionsSchemaAndAdmin(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	srv := testutil.TestServer(t, db.Pool)
+	defer srv.Close()
+
+	schema := "reg_" + strings.ReplaceAll(uuid.NewString(), "-", "_")[:12]
+	t.Cleanup(func() { purgeTenant(t, db, schema) })
+
+	payload := map[string]string{
+		"name":           "Faculty of Registration",
+		"schema":         schema,
+		"admin_email":    "owner_" + uuid.NewString() + "@example.com",
+		"admin_name":     "Owner",
+		"admin_password": "s3cret-pass",
+	}
+
+	resp := registerTenant(t, srv.URL, payload, testutil.TestPlatformToken)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
+	}
+	var body map[string]any
+	decodeJSON(t, resp, &body)
+	if body["status"] != "active" {
+		t.Fatalf("expected active tenant, got %v", body["status"])
+	}
+
+	// The new admin can log in and use a permission-guarded endpoint.
+	token := loginAndGetToken(t, srv.URL, payload["admin_email"], payload["admin_password"])
+	users := listUsers(t, srv.URL, token, schema)
+	if !containsEmail(users, payload["admin_email"]) {
+		t.Fatalf("expected admin user in new tenant")
+	}
+
+	// Replaying the same registration is a no-op.
+	replay := registerTenant(t, srv.URL, payload, testutil.TestPlatformToken)
+	defer replay.Body.Close()
+	if replay.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 on replay, got %d", replay.StatusCode)
+	}
+
+	// Nor does a replay with another password reveal the tenant.
+	wrongPassword := map[string]string{}
+	for k, v := range payload {
+		wrongPassword[k] = v
+	}
+	wrongPassword["admin_password"] = "not-the-password"
+	guess := registerTenant(t, srv.URL, wrongPassword, testutil.TestPlatformToken)
+	defer guess.Body.Close()
+	if guess.StatusCode != http.StatusConflict {
+		t.Fatalf("expected 409 for a replay with the wrong password, got %d", guess.StatusCode)
+	}
+
+	// A different admin cannot claim the same schema.
+	other := map[string]string{}
+	for k, v := range payload {
+		other[k] = v
+	}
+	other["admin_email"] = "intruder_" + uuid.NewString() + "@example.com"
+	conflict := registerTenant(t, srv.URL, other, testutil.TestPlatformToken)
+	defer conflict.Body.Close()
+	if conflict.StatusCode != http.StatusConflict {
+		t.Fatalf("expected 409 for taken schema, got %d", conflict.StatusCode)
+	}
+}
+
+func TestRegisterTenant_RejectsReservedSchemaAndMissingToken(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	srv := testutil.TestServer(t, db.Pool)
+	defer srv.Close()
+
+	payload := map[string]string{
+		"name":           "Bad Tenant",
+		"schema":         "pg_catalog",
+		"admin_email":    "bad_" + uuid.NewString() + "@example.com",
+		"admin_name":     "Bad",
+		"admin_password": "s3cret-pass",
+	}
+
+	resp := registerTenant(t, srv.URL, payload, testutil.TestPlatformToken)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400 for reserved schema, got %d", resp.StatusCode)
+	}
+
+	payload["schema"] = "ok_" + strings.ReplaceAll(uuid.NewString(), "-", "_")[:12]
+	unauth := registerTenant(t, srv.URL, payload, "wrong-token")
+	defer unauth.Body.Close()
+	if unauth.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without platform token, got %d", unauth.StatusCode)
+	}
+}
+
+func registerTenant(t *testing.T, baseURL string, payload any, token string) *http.Response {
+	t.Helper()
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("marshal payload: %v", err)
+	}
+	req, err := http.NewRequest(http.MethodPost, baseURL+"/api/v1/auth/register", bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("build request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Platform-Token", token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("register tenant: %v", err)
+	}
+	return resp
+}
+
+func purgeTenant(t *testing.T, db *testutil.TestDB, schema string) {
+	t.Helper()
+	ctx := context.Background()
+	_, _ = db.Pool.Exec(ctx, "DELETE FROM public.users_lookup WHERE tenant_schema = $1", schema)
+	_, _ = db.Pool.Exec(ctx, "DELETE FROM public.tenants WHERE schema_name = $1", schema)
+	_, _ = db.Pool.Exec(ctx, "DROP SCHEMA IF EXISTS "+schema+" CASCADE")
+}
@@ -0,0 +1,85 @@
+//go:build integration
+
+package core_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/testutil"
+)
+
+func TestTenantSettings_DisabledModuleIsForbidden(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	srv := testutil.TestServer(t, db.Pool)
+	defer srv.Close()
+
+	schema := "set_" + strings.ReplaceAll(uuid.NewString(), "-", "_")[:12]
+	t.Cleanup(func() { purgeTenant(t, db, schema) })
+
+	email := "owner_" + uuid.NewString() + "@example.com"
+	password := "s3cret-pass"
+	reg := registerTenant(t, srv.URL, map[string]string{
+		"name": "Settings Faculty", "schema": schema,
+		"admin_email": email, "admin_name": "Owner", "admin_password": password,
+	}, testutil.TestPlatformToken)
+	reg.Body.Close()
+	if reg.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", reg.StatusCode)
+	}
+	token := loginAndGetToken(t, srv.URL, email, password)
+	settingsURL := srv.URL + "/api/v1/platform/tenants/" + schema + "/settings"
+
+	expectStatus(t, tenantRequest(t, srv.URL+"/api/v1/teachers", token, schema), http.StatusOK)
+
+	expectStatus(t, platformRequest(t, http.MethodPatch, settingsURL, map[string]any{
+		"modules":  map[string]any{"hr": false},
+		"features": map[string]any{"new_grading": true},
+	}), http.StatusOK)
+
+	// hr is off for this tenant; core stays reachable.
+	expectStatus(t, tenantRequest(t, srv.URL+"/api/v1/teachers", token, schema), http.StatusForbidden)
+	expectStatus(t, tenantUsersRequest(t, srv.URL, token, schema), http.StatusOK)
+
+	req, err := testutil.AuthenticatedRequest(http.MethodGet, srv.URL+"/api/v1/settings", token, schema, nil)
+	if err != nil {
+		t.Fatalf("build settings request: %v", err)
+	}
+	got, status := testutil.DoJSON[struct {
+		Modules  map[string]bool `json:"modules"`
+		Features map[string]bool `json:"features"`
+	}](t, http.DefaultClient, req)
+	if status != http.StatusOK {
+		t.Fatalf("expected settings 200, got %d", status)
+	}
+	if got.Modules["hr"] || !got.Modules["subject"] || !got.Modules["core"] || !got.Features["new_grading"] {
+		t.Fatalf("unexpected settings %+v", got)
+	}
+
+	// core cannot be switched off, unknown modules and bad flag names are rejected.
+	expectStatus(t, platformRequest(t, http.MethodPatch, settingsURL, map[string]any{"modules": map[string]any{"core": false}}), http.StatusBadRequest)
+	expectStatus(t, platformRequest(t, http.MethodPatch, settingsURL, map[string]any{"modules": map[string]any{"billing": true}}), http.StatusBadRequest)
+	expectStatus(t, platformRequest(t, http.MethodPatch, settingsURL, map[string]any{"features": map[string]any{"Bad Flag": true}}), http.StatusBadRequest)
+
+	// null resets the module to its default (enabled).
+	expectStatus(t, platformRequest(t, http.MethodPatch, settingsURL, map[string]any{"modules": map[string]any{"hr": nil}}), http.StatusOK)
+	expectStatus(t, tenantRequest(t, srv.URL+"/api/v1/teachers", token, schema), http.StatusOK)
+
+	expectStatus(t, platformRequest(t, http.MethodGet, srv.URL+"/api/v1/platform/tenants/missing_tenant/settings", nil), http.StatusNotFound)
+}
+
+func tenantRequest(t *testing.T, url, token, schema string) *http.Response {
+	t.Helper()
+	req, err := testutil.AuthenticatedRequest(http.MethodGet, url, token, schema, nil)
+	if err != nil {
+		t.Fatalf("build request: %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	return resp
+}
@@ -1,12 +1,17 @@
 package delivery
 
 import (
-	"encoding/json"
+	"context"
+	"fmt"
 	"net/http"
 
 	"github.com/google/uuid"
 
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/hr/domain"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/history"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/problem"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/validate"
+	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
 )
 
 // AvailabilityHandler handles teacher availability endpoints.
@@ -20,85 +25,100 @@ func NewAvailabilityHandler(availRepo domain.AvailabilityRepository, teacherRepo
 	return &AvailabilityHandler{availRepo: availRepo, teacherRepo: teacherRepo}
 }
 
-// slotRequest represents a single day+period slot in the request body.
-type slotRequest struct {
+// Slot is a single day+period slot of a teacher's week.
+type Slot struct {
 	Day         int  `json:"day"`    // 0-6
 	Period      int  `json:"period"` // 1-10
 	IsAvailable bool `json:"is_available"`
 }
 
-// setAvailabilityRequest is the payload for replacing teacher availability.
-type setAvailabilityRequest struct {
-	Slots []slotRequest `json:"slots"`
+// SetAvailabilityRequest is the payload for replacing teacher availability.
+type SetAvailabilityRequest struct {
+	Slots []Slot `json:"slots"`
 }
 
-// GetAvailability handles GET /api/v1/teachers/{id}/availability
+// AvailabilityResponse is a teacher's weekly availability.
+type AvailabilityResponse struct {
+	TeacherID uuid.UUID `json:"teacher_id"`
+	Slots     []Slot    `json:"slots"`
+}
+
+// SetAvailabilityResponse reports a replaced availability.
+type SetAvailabilityResponse struct {
+	TeacherID  uuid.UUID `json:"teacher_id"`
+	SlotsCount int       `json:"slots_count"`
+	Message    string    `json:"message"`
+}
+
+func (req *SetAvailabilityRequest) Validate(v *validate.Validator) {
+	for i, s := range req.Slots {
+		v.Range(fmt.Sprintf("slots[%d].day", i), s.Day, 0, 6)
+		v.Range(fmt.Sprintf("slots[%d].period", i), s.Period, 1, 10)
+	}
+}
+
+// GetAvailability handles GET /api/v1/teachers/{id}/availability[?as_of=<RFC 3339>]
 func (h *AvailabilityHandler) GetAvailability(w http.ResponseWriter, r *http.Request) {
-	teacherID, err := uuid.Parse(r.PathValue("id"))
+	teacherID, err := validate.PathUUID(r, "id")
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	asOf, err := history.ParseAsOf(r.URL.Query())
 	if err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid teacher id"})
+		problem.Write(w, r, validate.Query(err))
 		return
 	}
 
-	// Verify teacher exists
-	if _, err := h.teacherRepo.FindByID(r.Context(), teacherID); err != nil {
-		writeJSON(w, http.StatusNotFound, map[string]string{"error": "teacher not found"})
+	// Verify teacher exists (at that time, for as_of)
+	findTeacher, getSlots := h.teacherRepo.FindByID, h.availRepo.GetByTeacherID
+	if asOf != nil {
+		findTeacher = func(ctx context.Context, id uuid.UUID) (*domain.Teacher, error) {
+			return h.teacherRepo.FindByIDAsOf(ctx, id, *asOf)
+		}
+		getSlots = func(ctx context.Context, id uuid.UUID) ([]*domain.Availability, error) {
+			return h.availRepo.GetByTeacherIDAsOf(ctx, id, *asOf)
+		}
+	}
+	if _, err := findTeacher(r.Context(), teacherID); err != nil {
+		problem.Write(w, r, erptypes.NotFoundAs(err, "teacher not found"))
 		return
 	}
 
-	slots, err := h.availRepo.GetByTeacherID(r.Context(), teacherID)
+	slots, err := getSlots(r.Context(), teacherID)
 	if err != nil {
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get availability"})
+		problem.Write(w, r, erptypes.Internal("failed to get availability", err))
 		return
 	}
 
-	items := make([]map[string]any, len(slots))
+	items := make([]Slot, len(slots))
 	for i, s := range slots {
-		items[i] = map[string]any{
-			"day":          s.Day,
-			"period":       s.Period,
-			"is_available": s.IsAvailable,
-		}
+		items[i] = Slot{Day: s.Day, Period: s.Period, IsAvailable: s.IsAvailable}
 	}
-	writeJSON(w, http.StatusOK, map[string]any{
-		"teacher_id": teacherID,
-		"slots":      items,
-	})
+	writeJSON(w, http.StatusOK, AvailabilityResponse{TeacherID: teacherID, Slots: items})
 }
 
 // SetAvailability handles PUT /api/v1/teachers/{id}/availability
 // Replaces all availability slots for the teacher.
 func (h *AvailabilityHandler) SetAvailability(w http.ResponseWriter, r *http.Request) {
-	teacherID, err := uuid.Parse(r.PathValue("id"))
+	teacherID, err := validate.PathUUID(r, "id")
 	if err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid teacher id"})
+		problem.Write(w, r, err)
 		return
 	}
 
 	// Verify teacher exists
 	if _, err := h.teacherRepo.FindByID(r.Context(), teacherID); err != nil {
-		writeJSON(w, http.StatusNotFound, map[string]string{"error": "teacher not found"})
+		problem.Write(w, r, erptypes.NotFoundAs(err, "teacher not found"))
 		return
 	}
 
-	var req setAvailabilityRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	var req SetAvailabilityRequest
+	if err := validate.DecodeJSON(r, &req); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
-	// Validate slot values
-	for _, s := range req.Slots {
-		if s.Day < 0 || s.Day > 6 {
-			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "day must be 0-6"})
-			return
-		}
-		if s.Period < 1 || s.Period > 10 {
-			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "period must be 1-10"})
-			return
-		}
-	}
-
 	slots := make([]*domain.Availability, len(req.Slots))
 	for i, s := range req.Slots {
 		slots[i] = &domain.Availability{
@@ -110,13 +130,13 @@ func (h *AvailabilityHandler) SetAvailability(w http.ResponseWriter, r *http.Req
 	}
 
 	if err := h.availRepo.SetSlots(r.Context(), teacherID, slots); err != nil {
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to set availability"})
+		problem.Write(w, r, erptypes.Internal("failed to set availability", err))
 		return
 	}
 
-	writeJSON(w, http.StatusOK, map[string]any{
-		"teacher_id":  teacherID,
-		"slots_count": len(slots),
-		"message":     "availability updated",
+	writeJSON(w, http.StatusOK, SetAvailabilityResponse{
+		TeacherID:  teacherID,
+		SlotsCount: len(slots),
+		Message:    "availability updated",
 	})
 }
@@ -1,13 +1,18 @@
 package delivery
 
 import (
-	"encoding/json"
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/hr/domain"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/history"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/problem"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/validate"
+	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
 )
 
 // DepartmentHandler handles department CRUD endpoints.
@@ -20,153 +25,168 @@ func NewDepartmentHandler(repo domain.DepartmentRepository) *DepartmentHandler {
 	return &DepartmentHandler{repo: repo}
 }
 
-type createDepartmentRequest struct {
+// DepartmentRequest is the body of POST and PUT /api/v1/departments.
+type DepartmentRequest struct {
 	Name          string  `json:"name"`
 	Description   string  `json:"description"`
 	HeadTeacherID *string `json:"head_teacher_id,omitempty"`
+
+	headTeacherID *uuid.UUID
 }
 
-type updateDepartmentRequest struct {
-	Name          string  `json:"name"`
-	Description   string  `json:"description"`
-	HeadTeacherID *string `json:"head_teacher_id,omitempty"`
+func (req *DepartmentRequest) Validate(v *validate.Validator) {
+	v.Required("name", req.Name)
+	if req.HeadTeacherID != nil {
+		req.headTeacherID = v.OptionalUUID("head_teacher_id", *req.HeadTeacherID)
+	}
 }
 
 // CreateDepartment handles POST /api/v1/departments
 func (h *DepartmentHandler) CreateDepartment(w http.ResponseWriter, r *http.Request) {
-	var req createDepartmentRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
-		return
-	}
-	if req.Name == "" {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "name is required"})
+	var req DepartmentRequest
+	if err := validate.DecodeJSON(r, &req); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
 	d := &domain.Department{
-		ID:          uuid.New(),
-		Name:        req.Name,
-		Description: req.Description,
-		CreatedAt:   time.Now(),
-	}
-	if req.HeadTeacherID != nil {
-		id, err := uuid.Parse(*req.HeadTeacherID)
-		if err != nil {
-			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid head_teacher_id"})
-			return
-		}
-		d.HeadTeacherID = &id
+		ID:            uuid.New(),
+		Name:          req.Name,
+		Description:   req.Description,
+		HeadTeacherID: req.headTeacherID,
+		CreatedAt:     time.Now(),
 	}
 
 	if err := h.repo.Save(r.Context(), d); err != nil {
-		writeJSON(w, http.StatusConflict, map[string]string{"error": "department already exists or save failed"})
+		if errors.Is(err, erptypes.ErrConflict) {
+			problem.Write(w, r, erptypes.Conflict("department name already exists"))
+			return
+		}
+		problem.Write(w, r, erptypes.Internal("failed to create department", err))
 		return
 	}
-	writeJSON(w, http.StatusCreated, deptResponse(d))
+	writeJSON(w, http.StatusCreated, toDepartmentResponse(d))
 }
 
 // ListDepartments handles GET /api/v1/departments
 func (h *DepartmentHandler) ListDepartments(w http.ResponseWriter, r *http.Request) {
-	depts, err := h.repo.List(r.Context())
+	spec, err := listquery.Parse(r.URL.Query(), domain.DepartmentListSchema)
 	if err != nil {
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to list departments"})
+		problem.Write(w, r, validate.Query(err))
 		return
 	}
 
-	items := make([]map[string]any, len(depts))
-	for i, d := range depts {
-		items[i] = deptResponse(d)
+	page, err := h.repo.List(r.Context(), spec)
+	if err != nil {
+		problem.Write(w, r, erptypes.Internal("failed to list departments", err))
+		return
 	}
-	writeJSON(w, http.StatusOK, map[string]any{"items": items, "total": len(items)})
+	writeJSON(w, http.StatusOK, listquery.Map(page, toDepartmentResponse))
 }
 
-// GetDepartment handles GET /api/v1/departments/{id}
+// GetDepartment handles GET /api/v1/departments/{id}[?as_of=<RFC 3339>]
 func (h *DepartmentHandler) GetDepartment(w http.ResponseWriter, r *http.Request) {
-	id, err := uuid.Parse(r.PathValue("id"))
+	id, err := validate.PathUUID(r, "id")
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	asOf, err := history.ParseAsOf(r.URL.Query())
+	if err != nil {
+		problem.Write(w, r, validate.Query(err))
+		return
+	}
+
+	var d *domain.Department
+	if asOf != nil {
+		d, err = h.repo.FindByIDAsOf(r.Context(), id, *asOf)
+	} else {
+		d, err = h.repo.FindByID(r.Context(), id)
+	}
+	if err != nil {
+		problem.Write(w, r, erptypes.NotFoundAs(err, "department not found"))
+		return
+	}
+	writeJSON(w, http.StatusOK, toDepartmentResponse(d))
+}
+
+// DepartmentHistory handles GET /api/v1/departments/{id}/history
+func (h *DepartmentHandler) DepartmentHistory(w http.ResponseWriter, r *http.Request) {
+	id, err := validate.PathUUID(r, "id")
 	if err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid department id"})
+		problem.Write(w, r, err)
 		return
 	}
 
-	d, err := h.repo.FindByID(r.Context(), id)
+	versions, err := h.repo.History(r.Context(), id)
 	if err != nil {
-		writeJSON(w, http.StatusNotFound, map[string]string{"error": "department not found"})
+		problem.Write(w, r, erptypes.NotFoundAs(err, "department not found"))
 		return
 	}
-	writeJSON(w, http.StatusOK, deptResponse(d))
+	writeJSON(w, http.StatusOK, history.List[DepartmentResponse]{Items: history.Map(versions, toDepartmentResponse)})
 }
 
 // UpdateDepartment handles PUT /api/v1/departments/{id}
 func (h *DepartmentHandler) UpdateDepartment(w http.ResponseWriter, r *http.Request) {
-	id, err := uuid.Parse(r.PathValue("id"))
+	id, err := validate.PathUUID(r, "id")
 	if err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid department id"})
+		problem.Write(w, r, err)
 		return
 	}
 
 	existing, err := h.repo.FindByID(r.Context(), id)
 	if err != nil {
-		writeJSON(w, http.StatusNotFound, map[string]string{"error": "department not found"})
+		problem.Write(w, r, erptypes.NotFoundAs(err, "department not found"))
 		return
 	}
 
-	var req updateDepartmentRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
-		return
-	}
-	if req.Name == "" {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "name is required"})
+	var req DepartmentRequest
+	if err := validate.DecodeJSON(r, &req); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
 	existing.Name = req.Name
 	existing.Description = req.Description
-	existing.HeadTeacherID = nil
-	if req.HeadTeacherID != nil {
-		htID, err := uuid.Parse(*req.HeadTeacherID)
-		if err != nil {
-			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid head_teacher_id"})
-			return
-		}
-		existing.HeadTeacherID = &htID
-	}
+	existing.HeadTeacherID = req.headTeacherID
 
 	if err := h.repo.Update(r.Context(), existing); err != nil {
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to update department"})
+		problem.Write(w, r, erptypes.Internal("failed to update department", err))
 		return
 	}
-	writeJSON(w, http.StatusOK, deptResponse(existing))
+	writeJSON(w, http.StatusOK, toDepartmentResponse(existing))
 }
 
 // DeleteDepartment handles DELETE /api/v1/departments/{id}
 func (h *DepartmentHandler) DeleteDepartment(w http.ResponseWriter, r *http.Request) {
-	id, err := uuid.Parse(r.PathValue("id"))
+	id, err := validate.PathUUID(r, "id")
 	if err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid department id"})
+		problem.Write(w, r, err)
 		return
 	}
 
 	if err := h.repo.Delete(r.Context(), id); err != nil {
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to delete department"})
+		problem.Write(w, r, erptypes.Internal("failed to delete department", err))
 		return
 	}
 	writeJSON(w, http.StatusOK, map[string]string{"message": "department deleted"})
 }
 
-// deptResponse converts a Department entity to a JSON-safe map.
-func deptResponse(d *domain.Department) map[string]any {
-	resp := map[string]any{
-		"id":          d.ID,
-		"name":        d.Name,
-		"description": d.Description,
-		"created_at":  d.CreatedAt,
-	}
-	if d.HeadTeacherID != nil {
-		resp["head_teacher_id"] = *d.HeadTeacherID
-	} else {
-		resp["head_teacher_id"] = nil
+// DepartmentResponse is the JSON representation of a Department.
+type DepartmentResponse struct {
+	ID            uuid.UUID  `json:"id"`
+	Name          string     `json:"name"`
+	Description   string     `json:"description"`
+	HeadTeacherID *uuid.UUID `json:"head_teacher_id"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+func toDepartmentResponse(d *domain.Department) DepartmentResponse {
+	return DepartmentResponse{
+		ID:            d.ID,
+		Name:          d.Name,
+		Description:   d.Description,
+		HeadTeacherID: d.HeadTeacherID,
+		CreatedAt:     d.CreatedAt,
 	}
-	return resp
 }
@@ -0,0 +1,97 @@
+package delivery
+
+import (
+	"context"
+	"strconv"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/hr/domain"
+	platformgrpc "github.com/HuynhHoangPhuc/mcs-erp/internal/platform/grpc"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
+	hrv1 "github.com/HuynhHoangPhuc/mcs-erp/proto/gen/hr/v1"
+)
+
+// TeacherGRPCServer implements hrv1.TeacherServiceServer over the HR repositories.
+type TeacherGRPCServer struct {
+	hrv1.UnimplementedTeacherServiceServer
+	teachers domain.TeacherRepository
+	avail    domain.AvailabilityRepository
+}
+
+func NewTeacherGRPCServer(teachers domain.TeacherRepository, avail domain.AvailabilityRepository) *TeacherGRPCServer {
+	return &TeacherGRPCServer{teachers: teachers, avail: avail}
+}
+
+func (s *TeacherGRPCServer) GetTeacher(ctx context.Context, req *hrv1.GetTeacherRequest) (*hrv1.GetTeacherResponse, error) {
+	id, err := platformgrpc.ParseUUID("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	t, err := s.teachers.FindByID(ctx, id)
+	if err != nil {
+		return nil, platformgrpc.Error(err)
+	}
+	return &hrv1.GetTeacherResponse{Teacher: teacherToProto(t)}, nil
+}
+
+func (s *TeacherGRPCServer) ListTeachers(ctx context.Context, req *hrv1.ListTeachersRequest) (*hrv1.ListTeachersResponse, error) {
+	deptID, err := platformgrpc.ParseOptionalUUID("department_id", req.GetDepartmentId())
+	if err != nil {
+		return nil, err
+	}
+	spec := listquery.Spec{Offset: int(req.GetOffset()), Limit: platformgrpc.PageLimit(req.GetLimit())}
+	if deptID != nil {
+		spec = spec.Where("department_id", deptID.String())
+	}
+	if req.IsActive != nil {
+		spec = spec.Where("is_active", strconv.FormatBool(req.GetIsActive()))
+	}
+	if q := req.GetQualification(); q != "" {
+		spec = spec.Where("qualification", q)
+	}
+
+	page, err := s.teachers.List(ctx, spec)
+	if err != nil {
+		return nil, platformgrpc.Error(err)
+	}
+	resp := &hrv1.ListTeachersResponse{Teachers: make([]*hrv1.Teacher, 0, len(page.Items)), Total: int32(page.Total)}
+	for _, t := range page.Items {
+		resp.Teachers = append(resp.Teachers, teacherToProto(t))
+	}
+	return resp, nil
+}
+
+func (s *TeacherGRPCServer) GetTeacherAvailability(ctx context.Context, req *hrv1.GetTeacherAvailabilityRequest) (*hrv1.GetTeacherAvailabilityResponse, error) {
+	id, err := platformgrpc.ParseUUID("teacher_id", req.GetTeacherId())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.teachers.FindByID(ctx, id); err != nil {
+		return nil, platformgrpc.Error(err)
+	}
+	slots, err := s.avail.GetByTeacherID(ctx, id)
+	if err != nil {
+		return nil, platformgrpc.Error(err)
+	}
+	resp := &hrv1.GetTeacherAvailabilityResponse{TeacherId: id.String(), Slots: make([]*hrv1.AvailabilitySlot, 0, len(slots))}
+	for _, sl := range slots {
+		resp.Slots = append(resp.Slots, &hrv1.AvailabilitySlot{Day: int32(sl.Day), Period: int32(sl.Period), IsAvailable: sl.IsAvailable})
+	}
+	return resp, nil
+}
+
+func teacherToProto(t *domain.Teacher) *hrv1.Teacher {
+	return &hrv1.Teacher{
+		Id:             t.ID.String(),
+		Name:           t.Name,
+		Email:          t.Email,
+		DepartmentId:   platformgrpc.UUIDString(t.DepartmentID),
+		Qualifications: t.Qualifications,
+		IsActive:       t.IsActive,
+		CreatedAt:      timestamppb.New(t.CreatedAt),
+		UpdatedAt:      timestamppb.New(t.UpdatedAt),
+	}
+}
+
+var _ hrv1.TeacherServiceServer = (*TeacherGRPCServer)(nil)
@@ -1,7 +1,7 @@
 package delivery
 
 import (
-	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -9,6 +9,12 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/hr/domain"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/etag"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/history"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/problem"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/validate"
+	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
 )
 
 // TeacherHandler handles teacher CRUD endpoints.
@@ -21,30 +27,37 @@ func NewTeacherHandler(repo domain.TeacherRepository) *TeacherHandler {
 	return &TeacherHandler{repo: repo}
 }
 
-type createTeacherRequest struct {
+// CreateTeacherRequest is the body of POST /api/v1/teachers.
+type CreateTeacherRequest struct {
 	Name           string   `json:"name"`
 	Email          string   `json:"email"`
 	DepartmentID   *string  `json:"department_id,omitempty"`
 	Qualifications []string `json:"qualifications"`
+
+	departmentID *uuid.UUID
 }
 
-type updateTeacherRequest struct {
-	Name           string   `json:"name"`
-	Email          string   `json:"email"`
-	DepartmentID   *string  `json:"department_id,omitempty"`
-	Qualifications []string `json:"qualifications"`
-	IsActive       bool     `json:"is_active"`
+func (req *CreateTeacherRequest) Validate(v *validate.Validator) {
+	v.Required("name", req.Name)
+	if v.Required("email", req.Email) {
+		v.Email("email", req.Email)
+	}
+	if req.DepartmentID != nil {
+		req.departmentID = v.OptionalUUID("department_id", *req.DepartmentID)
+	}
+}
+
+// UpdateTeacherRequest is the body of PUT /api/v1/teachers/{id}.
+type UpdateTeacherRequest struct {
+	CreateTeacherRequest
+	IsActive bool `json:"is_active"`
 }
 
 // CreateTeacher handles POST /api/v1/teachers
 func (h *TeacherHandler) CreateTeacher(w http.ResponseWriter, r *http.Request) {
-	var req createTeacherRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
-		return
-	}
-	if req.Name == "" || req.Email == "" {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "name and email are required"})
+	var req CreateTeacherRequest
+	if err := validate.DecodeJSON(r, &req); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -53,104 +66,113 @@ func (h *TeacherHandler) CreateTeacher(w http.ResponseWriter, r *http.Request) {
 		ID:             uuid.New(),
 		Name:           req.Name,
 		Email:          req.Email,
+		DepartmentID:   req.departmentID,
 		Qualifications: req.Qualifications,
 		IsActive:       true,
 		CreatedAt:      now,
 		UpdatedAt:      now,
 	}
-	if req.DepartmentID != nil {
-		id, err := uuid.Parse(*req.DepartmentID)
-		if err != nil {
-			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid department_id"})
-			return
-		}
-		t.DepartmentID = &id
-	}
 
 	if err := h.repo.Save(r.Context(), t); err != nil {
-		writeJSON(w, http.StatusConflict, map[string]string{"error": "teacher already exists or save failed"})
+		if errors.Is(err, erptypes.ErrConflict) {
+			problem.Write(w, r, erptypes.Conflict("teacher email already exists"))
+			return
+		}
+		problem.Write(w, r, erptypes.Internal("failed to create teacher", err))
 		return
 	}
 
-	writeJSON(w, http.StatusCreated, teacherResponse(t))
+	etag.Set(w, t.Version)
+	writeJSON(w, http.StatusCreated, toTeacherResponse(t))
 }
 
 // ListTeachers handles GET /api/v1/teachers
 func (h *TeacherHandler) ListTeachers(w http.ResponseWriter, r *http.Request) {
 	q := r.URL.Query()
-	offset, _ := strconv.Atoi(q.Get("offset"))
-	limit, _ := strconv.Atoi(q.Get("limit"))
-	if limit <= 0 || limit > 100 {
-		limit = 20
+	// status=active|inactive predates the shared list syntax (is_active=true).
+	if status := q.Get("status"); status != "" {
+		q.Set("is_active", strconv.FormatBool(status == "active"))
+	}
+	spec, err := listquery.Parse(q, domain.TeacherListSchema)
+	if err != nil {
+		problem.Write(w, r, validate.Query(err))
+		return
 	}
 
-	filter := domain.TeacherFilter{}
-	if raw := q.Get("department_id"); raw != "" {
-		id, err := uuid.Parse(raw)
-		if err != nil {
-			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid department_id"})
-			return
-		}
-		filter.DepartmentID = &id
+	page, err := h.repo.List(r.Context(), spec)
+	if err != nil {
+		problem.Write(w, r, erptypes.Internal("failed to list teachers", err))
+		return
 	}
-	if raw := q.Get("status"); raw != "" {
-		active := raw == "active"
-		filter.IsActive = &active
+	writeJSON(w, http.StatusOK, listquery.Map(page, toTeacherResponse))
+}
+
+// GetTeacher handles GET /api/v1/teachers/{id}[?as_of=<RFC 3339>]
+func (h *TeacherHandler) GetTeacher(w http.ResponseWriter, r *http.Request) {
+	id, err := validate.PathUUID(r, "id")
+	if err != nil {
+		problem.Write(w, r, err)
+		return
 	}
-	if qual := q.Get("qualification"); qual != "" {
-		filter.Qualification = qual
+	asOf, err := history.ParseAsOf(r.URL.Query())
+	if err != nil {
+		problem.Write(w, r, validate.Query(err))
+		return
 	}
 
-	teachers, total, err := h.repo.List(r.Context(), filter, offset, limit)
+	var t *domain.Teacher
+	if asOf != nil {
+		t, err = h.repo.FindByIDAsOf(r.Context(), id, *asOf)
+	} else {
+		t, err = h.repo.FindByID(r.Context(), id)
+	}
 	if err != nil {
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to list teachers"})
+		problem.Write(w, r, erptypes.NotFoundAs(err, "teacher not found"))
 		return
 	}
-
-	items := make([]map[string]any, len(teachers))
-	for i, t := range teachers {
-		items[i] = teacherResponse(t)
+	if asOf == nil {
+		etag.Set(w, t.Version)
 	}
-	writeJSON(w, http.StatusOK, map[string]any{"items": items, "total": total})
+	writeJSON(w, http.StatusOK, toTeacherResponse(t))
 }
 
-// GetTeacher handles GET /api/v1/teachers/{id}
-func (h *TeacherHandler) GetTeacher(w http.ResponseWriter, r *http.Request) {
-	id, err := uuid.Parse(r.PathValue("id"))
+// TeacherHistory handles GET /api/v1/teachers/{id}/history
+func (h *TeacherHandler) TeacherHistory(w http.ResponseWriter, r *http.Request) {
+	id, err := validate.PathUUID(r, "id")
 	if err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid teacher id"})
+		problem.Write(w, r, err)
 		return
 	}
 
-	t, err := h.repo.FindByID(r.Context(), id)
+	versions, err := h.repo.History(r.Context(), id)
 	if err != nil {
-		writeJSON(w, http.StatusNotFound, map[string]string{"error": "teacher not found"})
+		problem.Write(w, r, erptypes.NotFoundAs(err, "teacher not found"))
 		return
 	}
-	writeJSON(w, http.StatusOK, teacherResponse(t))
+	writeJSON(w, http.StatusOK, history.List[TeacherResponse]{Items: history.Map(versions, toTeacherResponse)})
 }
 
 // UpdateTeacher handles PUT /api/v1/teachers/{id}
 func (h *TeacherHandler) UpdateTeacher(w http.ResponseWriter, r *http.Request) {
-	id, err := uuid.Parse(r.PathValue("id"))
+	id, err := validate.PathUUID(r, "id")
 	if err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid teacher id"})
+		problem.Write(w, r, err)
 		return
 	}
 
 	existing, err := h.repo.FindByID(r.Context(), id)
 	if err != nil {
-		writeJSON(w, http.StatusNotFound, map[string]string{"error": "teacher not found"})
+		problem.Write(w, r, erptypes.NotFoundAs(err, "teacher not found"))
 		return
 	}
-
-	var req updateTeacherRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	if !etag.Match(r, existing.Version) {
+		problem.Write(w, r, erptypes.PreconditionFailed("teacher was modified; reload and retry"))
 		return
 	}
-	if req.Name == "" || req.Email == "" {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "name and email are required"})
+
+	var req UpdateTeacherRequest
+	if err := validate.DecodeJSON(r, &req); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -158,38 +180,44 @@ func (h *TeacherHandler) UpdateTeacher(w http.ResponseWriter, r *http.Request) {
 	existing.Email = req.Email
 	existing.Qualifications = req.Qualifications
 	existing.IsActive = req.IsActive
-	existing.DepartmentID = nil
-	if req.DepartmentID != nil {
-		deptID, err := uuid.Parse(*req.DepartmentID)
-		if err != nil {
-			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid department_id"})
-			return
-		}
-		existing.DepartmentID = &deptID
-	}
+	existing.DepartmentID = req.departmentID
 
 	if err := h.repo.Update(r.Context(), existing); err != nil {
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to update teacher"})
+		switch {
+		case errors.Is(err, erptypes.ErrNotFound):
+			problem.Write(w, r, erptypes.NotFound("teacher not found"))
+		case errors.Is(err, erptypes.ErrConflict):
+			problem.Write(w, r, etag.Conflict(r, "teacher was modified; reload and retry"))
+		default:
+			problem.Write(w, r, erptypes.Internal("failed to update teacher", err))
+		}
 		return
 	}
-	writeJSON(w, http.StatusOK, teacherResponse(existing))
+	etag.Set(w, existing.Version)
+	writeJSON(w, http.StatusOK, toTeacherResponse(existing))
 }
 
-// teacherResponse converts a Teacher entity to a JSON-safe map.
-func teacherResponse(t *domain.Teacher) map[string]any {
-	resp := map[string]any{
-		"id":             t.ID,
-		"name":           t.Name,
-		"email":          t.Email,
-		"qualifications": t.Qualifications,
-		"is_active":      t.IsActive,
-		"created_at":     t.CreatedAt,
-		"updated_at":     t.UpdatedAt,
-	}
-	if t.DepartmentID != nil {
-		resp["department_id"] = *t.DepartmentID
-	} else {
-		resp["department_id"] = nil
+// TeacherResponse is the JSON representation of a Teacher.
+type TeacherResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	Name           string     `json:"name"`
+	Email          string     `json:"email"`
+	DepartmentID   *uuid.UUID `json:"department_id"`
+	Qualifications []string   `json:"qualifications"`
+	IsActive       bool       `json:"is_active"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+func toTeacherResponse(t *domain.Teacher) TeacherResponse {
+	return TeacherResponse{
+		ID:             t.ID,
+		Name:           t.Name,
+		Email:          t.Email,
+		DepartmentID:   t.DepartmentID,
+		Qualifications: t.Qualifications,
+		IsActive:       t.IsActive,
+		CreatedAt:      t.CreatedAt,
+		UpdatedAt:      t.UpdatedAt,
 	}
-	return resp
 }
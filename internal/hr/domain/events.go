@@ -6,6 +6,13 @@ import (
 	"github.com/google/uuid"
 )
 
+// Event topics published through the transactional outbox.
+const (
+	TopicTeacherCreated      = "hr.teacher.created"
+	TopicTeacherUpdated      = "hr.teacher.updated"
+	TopicAvailabilityUpdated = "hr.availability.updated"
+)
+
 // TeacherCreated is published when a new teacher is successfully persisted.
 type TeacherCreated struct {
 	TeacherID uuid.UUID
@@ -2,39 +2,76 @@ package domain
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/history"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
 )
 
-// TeacherFilter holds optional filters for listing teachers.
-type TeacherFilter struct {
-	DepartmentID  *uuid.UUID
-	IsActive      *bool
-	Qualification string // filter by any matching qualification
+// TeacherListSchema declares how teachers can be filtered and sorted.
+var TeacherListSchema = &listquery.Schema{
+	Fields: map[string]listquery.Field{
+		"id":            {Kind: listquery.UUID, Filter: listquery.Eq | listquery.In, Sort: true},
+		"name":          {Kind: listquery.Text, Filter: listquery.Eq | listquery.Contains, Sort: true},
+		"email":         {Kind: listquery.Text, Filter: listquery.Eq | listquery.Contains, Sort: true},
+		"department_id": {Kind: listquery.UUID, Filter: listquery.Eq | listquery.In},
+		"qualification": {Column: "qualifications", Kind: listquery.Text, Array: true, Filter: listquery.Eq | listquery.In | listquery.Contains},
+		"is_active":     {Kind: listquery.Bool, Filter: listquery.Eq},
+		"created_at":    {Kind: listquery.Time, Filter: listquery.Range, Sort: true},
+		"updated_at":    {Kind: listquery.Time, Filter: listquery.Range, Sort: true},
+	},
+	Key:         "id",
+	DefaultSort: []listquery.Sort{{Field: "created_at", Desc: true}},
+}
+
+// DepartmentListSchema declares how departments can be filtered and sorted.
+var DepartmentListSchema = &listquery.Schema{
+	Fields: map[string]listquery.Field{
+		"id":              {Kind: listquery.UUID, Filter: listquery.Eq | listquery.In, Sort: true},
+		"name":            {Kind: listquery.Text, Filter: listquery.Eq | listquery.Contains, Sort: true},
+		"head_teacher_id": {Kind: listquery.UUID, Filter: listquery.Eq},
+		"created_at":      {Kind: listquery.Time, Filter: listquery.Range, Sort: true},
+	},
+	Key:         "id",
+	DefaultSort: []listquery.Sort{{Field: "name"}},
 }
 
 // TeacherRepository defines persistence operations for Teacher entities.
 type TeacherRepository interface {
 	FindByID(ctx context.Context, id uuid.UUID) (*Teacher, error)
+	// FindByIDAsOf returns the teacher as it was at the given time.
+	FindByIDAsOf(ctx context.Context, id uuid.UUID, at time.Time) (*Teacher, error)
+	// History returns every version of the teacher, oldest first.
+	History(ctx context.Context, id uuid.UUID) ([]history.Version[*Teacher], error)
 	FindByEmail(ctx context.Context, email string) (*Teacher, error)
+	// Save inserts the teacher; a duplicate email is erptypes.ErrConflict.
 	Save(ctx context.Context, teacher *Teacher) error
+	// Update stores the teacher if it is still at teacher.Version and bumps
+	// the version; otherwise it returns erptypes.ErrConflict.
 	Update(ctx context.Context, teacher *Teacher) error
-	List(ctx context.Context, filter TeacherFilter, offset, limit int) ([]*Teacher, int, error)
+	List(ctx context.Context, spec listquery.Spec) (listquery.Page[*Teacher], error)
 }
 
 // DepartmentRepository defines persistence operations for Department entities.
 type DepartmentRepository interface {
 	FindByID(ctx context.Context, id uuid.UUID) (*Department, error)
+	FindByIDAsOf(ctx context.Context, id uuid.UUID, at time.Time) (*Department, error)
+	History(ctx context.Context, id uuid.UUID) ([]history.Version[*Department], error)
+	// Save inserts the department; a duplicate name is erptypes.ErrConflict.
 	Save(ctx context.Context, dept *Department) error
 	Update(ctx context.Context, dept *Department) error
 	Delete(ctx context.Context, id uuid.UUID) error
-	List(ctx context.Context) ([]*Department, error)
+	List(ctx context.Context, spec listquery.Spec) (listquery.Page[*Department], error)
 }
 
 // AvailabilityRepository manages teacher weekly slot availability.
 type AvailabilityRepository interface {
 	// GetByTeacherID returns all stored slots for the given teacher.
 	GetByTeacherID(ctx context.Context, teacherID uuid.UUID) ([]*Availability, error)
+	// GetByTeacherIDAsOf returns the slots stored for the teacher at the given time.
+	GetByTeacherIDAsOf(ctx context.Context, teacherID uuid.UUID, at time.Time) ([]*Availability, error)
 	// SetSlots replaces all availability rows for the teacher (upsert + delete).
 	SetSlots(ctx context.Context, teacherID uuid.UUID, slots []*Availability) error
 }
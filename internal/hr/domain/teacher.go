@@ -14,6 +14,7 @@ type Teacher struct {
 	DepartmentID   *uuid.UUID
 	Qualifications []string
 	IsActive       bool
+	Version        int // optimistic lock, bumped by every update
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
 }
@@ -0,0 +1,87 @@
+//go:build integration
+
+package hr_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/etag"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/testutil"
+)
+
+func TestTeacherETagAndIfMatch(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	schema := db.CreateTenantSchema(t)
+	admin := testutil.SeedAdmin(t, db.Pool, schema)
+	srv := testutil.TestServer(t, db.Pool)
+	defer srv.Close()
+
+	token := loginAndGetToken(t, srv.URL, admin.Email, admin.Password)
+	teacherID := createTeacher(t, srv.URL, token, schema, map[string]any{
+		"name":           "Ada",
+		"email":          fmt.Sprintf("ada_%s@example.com", uuid.NewString()),
+		"qualifications": []string{},
+	})
+	url := srv.URL + "/api/v1/teachers/" + teacherID
+
+	do := func(method, ifMatch string, body map[string]any) *http.Response {
+		t.Helper()
+		var raw []byte
+		if body != nil {
+			raw = jsonBody(t, body)
+		}
+		req := mustAuthReq(t, method, url, token, schema, raw)
+		if ifMatch != "" {
+			req.Header.Set(etag.IfMatchHeader, ifMatch)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s: %v", method, url, err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+	update := func(name string) map[string]any {
+		return map[string]any{"name": name, "email": fmt.Sprintf("ada_%s@example.com", uuid.NewString()), "is_active": true}
+	}
+
+	get := do(http.MethodGet, "", nil)
+	first := get.Header.Get(etag.Header)
+	if get.StatusCode != http.StatusOK || first != etag.Of(1) {
+		t.Fatalf("expected 200 with ETag %s, got %d %q", etag.Of(1), get.StatusCode, first)
+	}
+
+	// A matching tag writes and moves the ETag on.
+	ok := do(http.MethodPut, first, update("Ada Lovelace"))
+	second := ok.Header.Get(etag.Header)
+	if ok.StatusCode != http.StatusOK || second != etag.Of(2) {
+		t.Fatalf("expected 200 with ETag %s, got %d %q", etag.Of(2), ok.StatusCode, second)
+	}
+
+	// The first tag is now stale: the write is refused and nothing changes.
+	if resp := do(http.MethodPut, first, update("Ada Byron")); resp.StatusCode != http.StatusPreconditionFailed {
+		t.Fatalf("expected 412 for stale If-Match, got %d", resp.StatusCode)
+	}
+	current := getJSON(t, mustAuthReq(t, http.MethodGet, url, token, schema, nil), http.StatusOK)
+	if current["name"] != "Ada Lovelace" {
+		t.Fatalf("stale write was applied: name=%v", current["name"])
+	}
+
+	// Weak tags never match; "*" and tag lists do; no If-Match is unconditional.
+	if resp := do(http.MethodPut, "W/"+second, update("Ada W")); resp.StatusCode != http.StatusPreconditionFailed {
+		t.Fatalf("expected 412 for weak If-Match, got %d", resp.StatusCode)
+	}
+	if resp := do(http.MethodPut, first+", "+second, update("Ada K")); resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 for tag list, got %d", resp.StatusCode)
+	}
+	if resp := do(http.MethodPut, "*", update("Ada L")); resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 for If-Match *, got %d", resp.StatusCode)
+	}
+	if resp := do(http.MethodPut, "", update("Ada M")); resp.StatusCode != http.StatusOK || resp.Header.Get(etag.Header) != etag.Of(5) {
+		t.Fatalf("expected unconditional 200 with ETag %s, got %d %q", etag.Of(5), resp.StatusCode, resp.Header.Get(etag.Header))
+	}
+}
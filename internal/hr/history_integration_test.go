@@ -0,0 +1,83 @@
+//go:build integration
+
+package hr_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/testutil"
+)
+
+func TestTeacherHistoryAndAsOf(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	schema := db.CreateTenantSchema(t)
+	admin := testutil.SeedAdmin(t, db.Pool, schema)
+	srv := testutil.TestServer(t, db.Pool)
+	defer srv.Close()
+
+	token := loginAndGetToken(t, srv.URL, admin.Email, admin.Password)
+	beforeCreate := time.Now().UTC()
+	email := fmt.Sprintf("hist_%s@example.com", uuid.NewString())
+	teacherID := createTeacher(t, srv.URL, token, schema, map[string]any{
+		"name": "Alice", "email": email, "qualifications": []string{"MSc"},
+	})
+	setSlots := func(slots []map[string]any) {
+		t.Helper()
+		_ = getJSON(t, mustAuthReq(t, http.MethodPut, srv.URL+"/api/v1/teachers/"+teacherID+"/availability",
+			token, schema, jsonBody(t, map[string]any{"slots": slots})), http.StatusOK)
+	}
+	setSlots([]map[string]any{{"day": 1, "period": 2, "is_available": true}})
+
+	time.Sleep(50 * time.Millisecond)
+	snapshot := time.Now().UTC()
+	time.Sleep(50 * time.Millisecond)
+
+	_ = getJSON(t, mustAuthReq(t, http.MethodPut, srv.URL+"/api/v1/teachers/"+teacherID, token, schema, jsonBody(t, map[string]any{
+		"name": "Alice Updated", "email": email, "qualifications": []string{"PhD"}, "is_active": true,
+	})), http.StatusOK)
+	setSlots([]map[string]any{
+		{"day": 1, "period": 2, "is_available": false},
+		{"day": 2, "period": 3, "is_available": true},
+	})
+
+	at := func(path string, ts time.Time) string {
+		return srv.URL + path + "?as_of=" + ts.Format(time.RFC3339Nano)
+	}
+
+	then := getJSON(t, mustAuthReq(t, http.MethodGet, at("/api/v1/teachers/"+teacherID, snapshot), token, schema, nil), http.StatusOK)
+	if then["name"] != "Alice" {
+		t.Fatalf("expected name Alice as of snapshot, got %v", then["name"])
+	}
+	now := getJSON(t, mustAuthReq(t, http.MethodGet, srv.URL+"/api/v1/teachers/"+teacherID, token, schema, nil), http.StatusOK)
+	if now["name"] != "Alice Updated" {
+		t.Fatalf("expected current name, got %v", now["name"])
+	}
+
+	avail := getJSON(t, mustAuthReq(t, http.MethodGet, at("/api/v1/teachers/"+teacherID+"/availability", snapshot), token, schema, nil), http.StatusOK)
+	slots, _ := avail["slots"].([]any)
+	if len(slots) != 1 || slots[0].(map[string]any)["is_available"] != true {
+		t.Fatalf("expected the single available slot as of snapshot, got %v", avail["slots"])
+	}
+
+	hist := getJSON(t, mustAuthReq(t, http.MethodGet, srv.URL+"/api/v1/teachers/"+teacherID+"/history", token, schema, nil), http.StatusOK)
+	items, _ := hist["items"].([]any)
+	if len(items) != 2 {
+		t.Fatalf("expected 2 versions, got %d", len(items))
+	}
+	first, last := items[0].(map[string]any), items[1].(map[string]any)
+	if first["valid_to"] == nil || last["valid_to"] != nil {
+		t.Fatalf("expected first version closed and last open, got %v / %v", first["valid_to"], last["valid_to"])
+	}
+	if first["data"].(map[string]any)["name"] != "Alice" || last["data"].(map[string]any)["name"] != "Alice Updated" {
+		t.Fatalf("unexpected version data: %v", items)
+	}
+
+	_ = getJSON(t, mustAuthReq(t, http.MethodGet, at("/api/v1/teachers/"+teacherID, beforeCreate.Add(-time.Second)), token, schema, nil), http.StatusNotFound)
+	_ = getJSON(t, mustAuthReq(t, http.MethodGet, srv.URL+"/api/v1/teachers/"+teacherID+"?as_of=yesterday", token, schema, nil), http.StatusBadRequest)
+	_ = getJSON(t, mustAuthReq(t, http.MethodGet, srv.URL+"/api/v1/teachers/"+uuid.NewString()+"/history", token, schema, nil), http.StatusNotFound)
+}
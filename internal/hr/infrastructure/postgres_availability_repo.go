@@ -3,6 +3,7 @@ package infrastructure
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -10,6 +11,8 @@ import (
 
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/hr/domain"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/database"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/history"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/outbox"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
 )
 
@@ -29,6 +32,15 @@ func (r *PostgresAvailabilityRepo) schema(ctx context.Context) (string, error) {
 
 // GetByTeacherID returns all availability rows for a given teacher.
 func (r *PostgresAvailabilityRepo) GetByTeacherID(ctx context.Context, teacherID uuid.UUID) ([]*domain.Availability, error) {
+	return r.getByTeacherID(ctx, "teacher_availability", teacherID)
+}
+
+// GetByTeacherIDAsOf returns the availability rows stored for a teacher at the given time.
+func (r *PostgresAvailabilityRepo) GetByTeacherIDAsOf(ctx context.Context, teacherID uuid.UUID, at time.Time) ([]*domain.Availability, error) {
+	return r.getByTeacherID(ctx, history.AsOf("teacher_availability", 2), teacherID, at)
+}
+
+func (r *PostgresAvailabilityRepo) getByTeacherID(ctx context.Context, from string, args ...any) ([]*domain.Availability, error) {
 	schema, err := r.schema(ctx)
 	if err != nil {
 		return nil, err
@@ -38,8 +50,8 @@ func (r *PostgresAvailabilityRepo) GetByTeacherID(ctx context.Context, teacherID
 	err = database.WithTenantTx(ctx, r.pool, schema, func(tx pgx.Tx) error {
 		rows, err := tx.Query(ctx,
 			`SELECT teacher_id, day, period, is_available
-			 FROM teacher_availability WHERE teacher_id = $1 ORDER BY day, period`,
-			teacherID,
+			 FROM `+from+` WHERE teacher_id = $1 ORDER BY day, period`,
+			args...,
 		)
 		if err != nil {
 			return err
@@ -87,7 +99,18 @@ func (r *PostgresAvailabilityRepo) SetSlots(ctx context.Context, teacherID uuid.
 				return fmt.Errorf("insert slot day=%d period=%d: %w", s.Day, s.Period, err)
 			}
 		}
-		return nil
+
+		available := 0
+		for _, s := range slots {
+			if s.IsAvailable {
+				available++
+			}
+		}
+		return outbox.Write(ctx, tx, domain.TopicAvailabilityUpdated, domain.AvailabilityUpdated{
+			TeacherID:  teacherID,
+			SlotCount:  available,
+			OccurredAt: time.Now(),
+		})
 	})
 }
 
@@ -2,7 +2,9 @@ package infrastructure
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -10,6 +12,8 @@ import (
 
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/hr/domain"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/database"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/history"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
 	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
 )
@@ -29,6 +33,15 @@ func (r *PostgresDepartmentRepo) schema(ctx context.Context) (string, error) {
 }
 
 func (r *PostgresDepartmentRepo) FindByID(ctx context.Context, id uuid.UUID) (*domain.Department, error) {
+	return r.findByID(ctx, "departments", id)
+}
+
+// FindByIDAsOf returns the department as it was at the given time.
+func (r *PostgresDepartmentRepo) FindByIDAsOf(ctx context.Context, id uuid.UUID, at time.Time) (*domain.Department, error) {
+	return r.findByID(ctx, history.AsOf("departments", 2), id, at)
+}
+
+func (r *PostgresDepartmentRepo) findByID(ctx context.Context, from string, args ...any) (*domain.Department, error) {
 	schema, err := r.schema(ctx)
 	if err != nil {
 		return nil, err
@@ -38,12 +51,12 @@ func (r *PostgresDepartmentRepo) FindByID(ctx context.Context, id uuid.UUID) (*d
 	err = database.WithTenantTx(ctx, r.pool, schema, func(tx pgx.Tx) error {
 		return tx.QueryRow(ctx,
 			`SELECT id, name, description, head_teacher_id, created_at
-			 FROM departments WHERE id = $1`,
-			id,
+			 FROM `+from+` WHERE id = $1`,
+			args...,
 		).Scan(&d.ID, &d.Name, &d.Description, &d.HeadTeacherID, &d.CreatedAt)
 	})
 	if err != nil {
-		if err == pgx.ErrNoRows {
+		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, erptypes.ErrNotFound
 		}
 		return nil, fmt.Errorf("find department by id: %w", err)
@@ -63,6 +76,9 @@ func (r *PostgresDepartmentRepo) Save(ctx context.Context, d *domain.Department)
 			 VALUES ($1, $2, $3, $4, $5)`,
 			d.ID, d.Name, d.Description, d.HeadTeacherID, d.CreatedAt,
 		)
+		if database.IsUniqueViolation(err) {
+			return fmt.Errorf("department name %q: %w", d.Name, erptypes.ErrConflict)
+		}
 		return err
 	})
 }
@@ -94,16 +110,45 @@ func (r *PostgresDepartmentRepo) Delete(ctx context.Context, id uuid.UUID) error
 	})
 }
 
-func (r *PostgresDepartmentRepo) List(ctx context.Context) ([]*domain.Department, error) {
+func (r *PostgresDepartmentRepo) List(ctx context.Context, spec listquery.Spec) (listquery.Page[*domain.Department], error) {
+	schema, err := r.schema(ctx)
+	if err != nil {
+		return listquery.Page[*domain.Department]{}, err
+	}
+	q, err := listquery.Build(spec, domain.DepartmentListSchema)
+	if err != nil {
+		return listquery.Page[*domain.Department]{}, err
+	}
+
+	var page listquery.Page[*domain.Department]
+	err = database.WithTenantTx(ctx, r.pool, schema, func(tx pgx.Tx) error {
+		page, err = listquery.Fetch(ctx, tx, q, "id, name, description, head_teacher_id, created_at", "departments",
+			func(rows pgx.Rows, cursor *string) (*domain.Department, error) {
+				var d domain.Department
+				return &d, rows.Scan(&d.ID, &d.Name, &d.Description, &d.HeadTeacherID, &d.CreatedAt, cursor)
+			})
+		return err
+	})
+	if err != nil {
+		return listquery.Page[*domain.Department]{}, fmt.Errorf("list departments: %w", err)
+	}
+	return page, nil
+}
+
+// History returns every version of the department, oldest first, or
+// erptypes.ErrNotFound when it never existed.
+func (r *PostgresDepartmentRepo) History(ctx context.Context, id uuid.UUID) ([]history.Version[*domain.Department], error) {
 	schema, err := r.schema(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	var depts []*domain.Department
+	var versions []history.Version[*domain.Department]
 	err = database.WithTenantTx(ctx, r.pool, schema, func(tx pgx.Tx) error {
 		rows, err := tx.Query(ctx,
-			`SELECT id, name, description, head_teacher_id, created_at FROM departments ORDER BY name`,
+			`SELECT valid_from, valid_to, id, name, description, head_teacher_id, created_at
+			 FROM `+history.Versions("departments", 1)+` ORDER BY valid_from`,
+			id.String(),
 		)
 		if err != nil {
 			return err
@@ -112,17 +157,21 @@ func (r *PostgresDepartmentRepo) List(ctx context.Context) ([]*domain.Department
 
 		for rows.Next() {
 			var d domain.Department
-			if err := rows.Scan(&d.ID, &d.Name, &d.Description, &d.HeadTeacherID, &d.CreatedAt); err != nil {
+			v := history.Version[*domain.Department]{Data: &d}
+			if err := rows.Scan(&v.ValidFrom, &v.ValidTo, &d.ID, &d.Name, &d.Description, &d.HeadTeacherID, &d.CreatedAt); err != nil {
 				return err
 			}
-			depts = append(depts, &d)
+			versions = append(versions, v)
 		}
 		return rows.Err()
 	})
 	if err != nil {
-		return nil, fmt.Errorf("list departments: %w", err)
+		return nil, fmt.Errorf("department history: %w", err)
+	}
+	if len(versions) == 0 {
+		return nil, erptypes.ErrNotFound
 	}
-	return depts, nil
+	return versions, nil
 }
 
 // Ensure interface compliance.
@@ -2,8 +2,9 @@ package infrastructure
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -11,6 +12,9 @@ import (
 
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/hr/domain"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/database"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/history"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/outbox"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
 	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
 )
@@ -30,6 +34,15 @@ func (r *PostgresTeacherRepo) schema(ctx context.Context) (string, error) {
 }
 
 func (r *PostgresTeacherRepo) FindByID(ctx context.Context, id uuid.UUID) (*domain.Teacher, error) {
+	return r.findByID(ctx, "teachers", id)
+}
+
+// FindByIDAsOf returns the teacher as it was at the given time.
+func (r *PostgresTeacherRepo) FindByIDAsOf(ctx context.Context, id uuid.UUID, at time.Time) (*domain.Teacher, error) {
+	return r.findByID(ctx, history.AsOf("teachers", 2), id, at)
+}
+
+func (r *PostgresTeacherRepo) findByID(ctx context.Context, from string, args ...any) (*domain.Teacher, error) {
 	schema, err := r.schema(ctx)
 	if err != nil {
 		return nil, err
@@ -38,13 +51,13 @@ func (r *PostgresTeacherRepo) FindByID(ctx context.Context, id uuid.UUID) (*doma
 	var t domain.Teacher
 	err = database.WithTenantTx(ctx, r.pool, schema, func(tx pgx.Tx) error {
 		return tx.QueryRow(ctx,
-			`SELECT id, name, email, department_id, qualifications, is_active, created_at, updated_at
-			 FROM teachers WHERE id = $1`,
-			id,
-		).Scan(&t.ID, &t.Name, &t.Email, &t.DepartmentID, &t.Qualifications, &t.IsActive, &t.CreatedAt, &t.UpdatedAt)
+			`SELECT id, name, email, department_id, qualifications, is_active, version, created_at, updated_at
+			 FROM `+from+` WHERE id = $1`,
+			args...,
+		).Scan(&t.ID, &t.Name, &t.Email, &t.DepartmentID, &t.Qualifications, &t.IsActive, &t.Version, &t.CreatedAt, &t.UpdatedAt)
 	})
 	if err != nil {
-		if err == pgx.ErrNoRows {
+		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, erptypes.ErrNotFound
 		}
 		return nil, fmt.Errorf("find teacher by id: %w", err)
@@ -61,13 +74,13 @@ func (r *PostgresTeacherRepo) FindByEmail(ctx context.Context, email string) (*d
 	var t domain.Teacher
 	err = database.WithTenantTx(ctx, r.pool, schema, func(tx pgx.Tx) error {
 		return tx.QueryRow(ctx,
-			`SELECT id, name, email, department_id, qualifications, is_active, created_at, updated_at
+			`SELECT id, name, email, department_id, qualifications, is_active, version, created_at, updated_at
 			 FROM teachers WHERE email = $1`,
 			email,
-		).Scan(&t.ID, &t.Name, &t.Email, &t.DepartmentID, &t.Qualifications, &t.IsActive, &t.CreatedAt, &t.UpdatedAt)
+		).Scan(&t.ID, &t.Name, &t.Email, &t.DepartmentID, &t.Qualifications, &t.IsActive, &t.Version, &t.CreatedAt, &t.UpdatedAt)
 	})
 	if err != nil {
-		if err == pgx.ErrNoRows {
+		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, erptypes.ErrNotFound
 		}
 		return nil, fmt.Errorf("find teacher by email: %w", err)
@@ -82,12 +95,24 @@ func (r *PostgresTeacherRepo) Save(ctx context.Context, t *domain.Teacher) error
 	}
 
 	return database.WithTenantTx(ctx, r.pool, schema, func(tx pgx.Tx) error {
-		_, err := tx.Exec(ctx,
+		err := tx.QueryRow(ctx,
 			`INSERT INTO teachers (id, name, email, department_id, qualifications, is_active, created_at, updated_at)
-			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			 RETURNING version`,
 			t.ID, t.Name, t.Email, t.DepartmentID, t.Qualifications, t.IsActive, t.CreatedAt, t.UpdatedAt,
-		)
-		return err
+		).Scan(&t.Version)
+		if database.IsUniqueViolation(err) {
+			return fmt.Errorf("teacher email %q: %w", t.Email, erptypes.ErrConflict)
+		}
+		if err != nil {
+			return err
+		}
+		return outbox.Write(ctx, tx, domain.TopicTeacherCreated, domain.TeacherCreated{
+			TeacherID:  t.ID,
+			Name:       t.Name,
+			Email:      t.Email,
+			OccurredAt: time.Now(),
+		})
 	})
 }
 
@@ -98,64 +123,73 @@ func (r *PostgresTeacherRepo) Update(ctx context.Context, t *domain.Teacher) err
 	}
 
 	return database.WithTenantTx(ctx, r.pool, schema, func(tx pgx.Tx) error {
-		_, err := tx.Exec(ctx,
+		err := tx.QueryRow(ctx,
 			`UPDATE teachers
-			 SET name = $2, email = $3, department_id = $4, qualifications = $5, is_active = $6, updated_at = now()
-			 WHERE id = $1`,
-			t.ID, t.Name, t.Email, t.DepartmentID, t.Qualifications, t.IsActive,
-		)
-		return err
+			 SET name = $2, email = $3, department_id = $4, qualifications = $5, is_active = $6,
+			     version = version + 1, updated_at = now()
+			 WHERE id = $1 AND version = $7
+			 RETURNING version, updated_at`,
+			t.ID, t.Name, t.Email, t.DepartmentID, t.Qualifications, t.IsActive, t.Version,
+		).Scan(&t.Version, &t.UpdatedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return database.VersionMismatch(ctx, tx, "teachers", "version", t.ID, t.Version)
+		}
+		if err != nil {
+			return err
+		}
+		return outbox.Write(ctx, tx, domain.TopicTeacherUpdated, domain.TeacherUpdated{
+			TeacherID:    t.ID,
+			Name:         t.Name,
+			Email:        t.Email,
+			DepartmentID: t.DepartmentID,
+			IsActive:     t.IsActive,
+			OccurredAt:   time.Now(),
+		})
 	})
 }
 
-func (r *PostgresTeacherRepo) List(ctx context.Context, filter domain.TeacherFilter, offset, limit int) ([]*domain.Teacher, int, error) {
+func (r *PostgresTeacherRepo) List(ctx context.Context, spec listquery.Spec) (listquery.Page[*domain.Teacher], error) {
 	schema, err := r.schema(ctx)
 	if err != nil {
-		return nil, 0, err
-	}
-
-	// Build dynamic WHERE clause
-	conds := []string{}
-	args := []any{}
-	argIdx := 1
-
-	if filter.DepartmentID != nil {
-		conds = append(conds, fmt.Sprintf("department_id = $%d", argIdx))
-		args = append(args, *filter.DepartmentID)
-		argIdx++
-	}
-	if filter.IsActive != nil {
-		conds = append(conds, fmt.Sprintf("is_active = $%d", argIdx))
-		args = append(args, *filter.IsActive)
-		argIdx++
+		return listquery.Page[*domain.Teacher]{}, err
 	}
-	if filter.Qualification != "" {
-		conds = append(conds, fmt.Sprintf("$%d = ANY(qualifications)", argIdx))
-		args = append(args, filter.Qualification)
-		argIdx++
+	q, err := listquery.Build(spec, domain.TeacherListSchema)
+	if err != nil {
+		return listquery.Page[*domain.Teacher]{}, err
 	}
 
-	where := ""
-	if len(conds) > 0 {
-		where = "WHERE " + strings.Join(conds, " AND ")
+	var page listquery.Page[*domain.Teacher]
+	err = database.WithTenantTx(ctx, r.pool, schema, func(tx pgx.Tx) error {
+		page, err = listquery.Fetch(ctx, tx, q,
+			"id, name, email, department_id, qualifications, is_active, version, created_at, updated_at", "teachers",
+			func(rows pgx.Rows, cursor *string) (*domain.Teacher, error) {
+				var t domain.Teacher
+				return &t, rows.Scan(&t.ID, &t.Name, &t.Email, &t.DepartmentID, &t.Qualifications,
+					&t.IsActive, &t.Version, &t.CreatedAt, &t.UpdatedAt, cursor)
+			})
+		return err
+	})
+	if err != nil {
+		return listquery.Page[*domain.Teacher]{}, fmt.Errorf("list teachers: %w", err)
 	}
+	return page, nil
+}
 
-	var teachers []*domain.Teacher
-	var total int
+// History returns every version of the teacher, oldest first, or
+// erptypes.ErrNotFound when it never existed.
+func (r *PostgresTeacherRepo) History(ctx context.Context, id uuid.UUID) ([]history.Version[*domain.Teacher], error) {
+	schema, err := r.schema(ctx)
+	if err != nil {
+		return nil, err
+	}
 
+	var versions []history.Version[*domain.Teacher]
 	err = database.WithTenantTx(ctx, r.pool, schema, func(tx pgx.Tx) error {
-		countQuery := fmt.Sprintf("SELECT COUNT(*) FROM teachers %s", where)
-		if err := tx.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
-			return err
-		}
-
-		listArgs := append(args, limit, offset)
-		listQuery := fmt.Sprintf(
-			`SELECT id, name, email, department_id, qualifications, is_active, created_at, updated_at
-			 FROM teachers %s ORDER BY created_at DESC LIMIT $%d OFFSET $%d`,
-			where, argIdx, argIdx+1,
+		rows, err := tx.Query(ctx,
+			`SELECT valid_from, valid_to, id, name, email, department_id, qualifications, is_active, version, created_at, updated_at
+			 FROM `+history.Versions("teachers", 1)+` ORDER BY valid_from`,
+			id.String(),
 		)
-		rows, err := tx.Query(ctx, listQuery, listArgs...)
 		if err != nil {
 			return err
 		}
@@ -163,18 +197,22 @@ func (r *PostgresTeacherRepo) List(ctx context.Context, filter domain.TeacherFil
 
 		for rows.Next() {
 			var t domain.Teacher
-			if err := rows.Scan(&t.ID, &t.Name, &t.Email, &t.DepartmentID, &t.Qualifications,
-				&t.IsActive, &t.CreatedAt, &t.UpdatedAt); err != nil {
+			v := history.Version[*domain.Teacher]{Data: &t}
+			if err := rows.Scan(&v.ValidFrom, &v.ValidTo, &t.ID, &t.Name, &t.Email, &t.DepartmentID,
+				&t.Qualifications, &t.IsActive, &t.CreatedAt, &t.UpdatedAt); err != nil {
 				return err
 			}
-			teachers = append(teachers, &t)
+			versions = append(versions, v)
 		}
 		return rows.Err()
 	})
 	if err != nil {
-		return nil, 0, fmt.Errorf("list teachers: %w", err)
+		return nil, fmt.Errorf("teacher history: %w", err)
+	}
+	if len(versions) == 0 {
+		return nil, erptypes.ErrNotFound
 	}
-	return teachers, total, nil
+	return versions, nil
 }
 
 // Ensure interface compliance.
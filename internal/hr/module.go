@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	"google.golang.org/grpc"
 
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/core/application/services"
 	coredelivery "github.com/HuynhHoangPhuc/mcs-erp/internal/core/delivery"
@@ -12,7 +13,13 @@ import (
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/hr/delivery"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/hr/domain"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/hr/infrastructure"
-	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/auth"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/database"
+	platformgrpc "github.com/HuynhHoangPhuc/mcs-erp/internal/platform/grpc"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/history"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/openapi"
+	pkgmod "github.com/HuynhHoangPhuc/mcs-erp/pkg/module"
+	hrv1 "github.com/HuynhHoangPhuc/mcs-erp/proto/gen/hr/v1"
 )
 
 // Module implements pkg/module.Module for the HR (teachers/departments/availability) module.
@@ -22,6 +29,7 @@ type Module struct {
 	teacherRepo   domain.TeacherRepository
 	deptRepo      domain.DepartmentRepository
 	availRepo     domain.AvailabilityRepository
+	routes        []pkgmod.Route
 }
 
 // NewModule creates the HR module wired with concrete dependencies.
@@ -43,46 +51,105 @@ func (m *Module) AvailabilityRepo() domain.AvailabilityRepository { return m.ava
 
 func (m *Module) Name() string           { return "hr" }
 func (m *Module) Dependencies() []string { return []string{"core"} }
-func (m *Module) Migrate(ctx context.Context) error        { return nil }
+// Migrate applies the module's pending migrations to _template; tenants follow via database.TenantRunner.
+func (m *Module) Migrate(ctx context.Context) error {
+	return database.NewMigrator(m.pool).MigrateModule(ctx, m.Name())
+}
 func (m *Module) RegisterEvents(ctx context.Context) error { return nil }
 
+// RegisterGRPC exposes read access to teachers and availability (PermTeacherRead).
+func (m *Module) RegisterGRPC(s grpc.ServiceRegistrar) map[string]string {
+	hrv1.RegisterTeacherServiceServer(s, delivery.NewTeacherGRPCServer(m.teacherRepo, m.availRepo))
+	return platformgrpc.ServicePermissions(&hrv1.TeacherService_ServiceDesc, coredomain.PermTeacherRead)
+}
+
+// Routes describes the routes mounted by RegisterRoutes.
+func (m *Module) Routes() []pkgmod.Route { return m.routes }
+
 func (m *Module) RegisterRoutes(mux *http.ServeMux) {
 	teacherHandler := delivery.NewTeacherHandler(m.teacherRepo)
 	deptHandler := delivery.NewDepartmentHandler(m.deptRepo)
 	availHandler := delivery.NewAvailabilityHandler(m.availRepo, m.teacherRepo)
 
-	authMw := coredelivery.AuthMiddleware(m.authSvc)
-
-	teacherRead  := auth.RequirePermission(coredomain.PermTeacherRead)
-	teacherWrite := auth.RequirePermission(coredomain.PermTeacherWrite)
-	deptRead     := auth.RequirePermission(coredomain.PermDeptRead)
-	deptWrite    := auth.RequirePermission(coredomain.PermDeptWrite)
+	api := openapi.NewRouter(mux, m.Name(), coredelivery.ModuleAuthMiddleware(m.authSvc, m.Name()))
 
 	// Teacher routes
-	mux.Handle("POST /api/v1/teachers",
-		authMw(teacherWrite(http.HandlerFunc(teacherHandler.CreateTeacher))))
-	mux.Handle("GET /api/v1/teachers",
-		authMw(teacherRead(http.HandlerFunc(teacherHandler.ListTeachers))))
-	mux.Handle("GET /api/v1/teachers/{id}",
-		authMw(teacherRead(http.HandlerFunc(teacherHandler.GetTeacher))))
-	mux.Handle("PUT /api/v1/teachers/{id}",
-		authMw(teacherWrite(http.HandlerFunc(teacherHandler.UpdateTeacher))))
+	api.Handle(pkgmod.Route{
+		Method: "POST", Path: "/api/v1/teachers", Summary: "Create a teacher",
+		Permission: coredomain.PermTeacherWrite,
+		Request:    delivery.CreateTeacherRequest{}, Response: delivery.TeacherResponse{}, Status: http.StatusCreated,
+	}, teacherHandler.CreateTeacher)
+	api.Handle(pkgmod.Route{
+		Method: "GET", Path: "/api/v1/teachers", Summary: "List teachers",
+		Permission: coredomain.PermTeacherRead,
+		Query:      append(openapi.ListParams(domain.TeacherListSchema), pkgmod.Param{Name: "status", Description: "active or inactive; same as is_active"}),
+		Response:   listquery.Page[delivery.TeacherResponse]{},
+	}, teacherHandler.ListTeachers)
+	api.Handle(pkgmod.Route{
+		Method: "GET", Path: "/api/v1/teachers/{id}", Summary: "Get a teacher",
+		Permission: coredomain.PermTeacherRead,
+		Query:      []pkgmod.Param{openapi.AsOf},
+		Response:   delivery.TeacherResponse{},
+	}, teacherHandler.GetTeacher)
+	api.Handle(pkgmod.Route{
+		Method: "PUT", Path: "/api/v1/teachers/{id}", Summary: "Update a teacher",
+		Permission: coredomain.PermTeacherWrite,
+		Request:    delivery.UpdateTeacherRequest{}, Response: delivery.TeacherResponse{},
+	}, teacherHandler.UpdateTeacher)
+	api.Handle(pkgmod.Route{
+		Method: "GET", Path: "/api/v1/teachers/{id}/history", Summary: "List a teacher's versions",
+		Permission: coredomain.PermTeacherRead,
+		Response:   history.List[delivery.TeacherResponse]{},
+	}, teacherHandler.TeacherHistory)
 
 	// Availability routes (nested under teacher)
-	mux.Handle("GET /api/v1/teachers/{id}/availability",
-		authMw(teacherRead(http.HandlerFunc(availHandler.GetAvailability))))
-	mux.Handle("PUT /api/v1/teachers/{id}/availability",
-		authMw(teacherWrite(http.HandlerFunc(availHandler.SetAvailability))))
+	api.Handle(pkgmod.Route{
+		Method: "GET", Path: "/api/v1/teachers/{id}/availability", Summary: "Get a teacher's availability",
+		OperationID: "getTeacherAvailability",
+		Permission:  coredomain.PermTeacherRead,
+		Query:       []pkgmod.Param{openapi.AsOf},
+		Response:    delivery.AvailabilityResponse{},
+	}, availHandler.GetAvailability)
+	api.Handle(pkgmod.Route{
+		Method: "PUT", Path: "/api/v1/teachers/{id}/availability", Summary: "Replace a teacher's availability",
+		OperationID: "setTeacherAvailability",
+		Permission:  coredomain.PermTeacherWrite,
+		Request:     delivery.SetAvailabilityRequest{}, Response: delivery.SetAvailabilityResponse{},
+	}, availHandler.SetAvailability)
 
 	// Department routes
-	mux.Handle("POST /api/v1/departments",
-		authMw(deptWrite(http.HandlerFunc(deptHandler.CreateDepartment))))
-	mux.Handle("GET /api/v1/departments",
-		authMw(deptRead(http.HandlerFunc(deptHandler.ListDepartments))))
-	mux.Handle("GET /api/v1/departments/{id}",
-		authMw(deptRead(http.HandlerFunc(deptHandler.GetDepartment))))
-	mux.Handle("PUT /api/v1/departments/{id}",
-		authMw(deptWrite(http.HandlerFunc(deptHandler.UpdateDepartment))))
-	mux.Handle("DELETE /api/v1/departments/{id}",
-		authMw(deptWrite(http.HandlerFunc(deptHandler.DeleteDepartment))))
+	api.Handle(pkgmod.Route{
+		Method: "POST", Path: "/api/v1/departments", Summary: "Create a department",
+		Permission: coredomain.PermDeptWrite,
+		Request:    delivery.DepartmentRequest{}, Response: delivery.DepartmentResponse{}, Status: http.StatusCreated,
+	}, deptHandler.CreateDepartment)
+	api.Handle(pkgmod.Route{
+		Method: "GET", Path: "/api/v1/departments", Summary: "List departments",
+		Permission: coredomain.PermDeptRead,
+		Query:      openapi.ListParams(domain.DepartmentListSchema),
+		Response:   listquery.Page[delivery.DepartmentResponse]{},
+	}, deptHandler.ListDepartments)
+	api.Handle(pkgmod.Route{
+		Method: "GET", Path: "/api/v1/departments/{id}", Summary: "Get a department",
+		Permission: coredomain.PermDeptRead,
+		Query:      []pkgmod.Param{openapi.AsOf},
+		Response:   delivery.DepartmentResponse{},
+	}, deptHandler.GetDepartment)
+	api.Handle(pkgmod.Route{
+		Method: "PUT", Path: "/api/v1/departments/{id}", Summary: "Update a department",
+		Permission: coredomain.PermDeptWrite,
+		Request:    delivery.DepartmentRequest{}, Response: delivery.DepartmentResponse{},
+	}, deptHandler.UpdateDepartment)
+	api.Handle(pkgmod.Route{
+		Method: "GET", Path: "/api/v1/departments/{id}/history", Summary: "List a department's versions",
+		Permission: coredomain.PermDeptRead,
+		Response:   history.List[delivery.DepartmentResponse]{},
+	}, deptHandler.DepartmentHistory)
+	api.Handle(pkgmod.Route{
+		Method: "DELETE", Path: "/api/v1/departments/{id}", Summary: "Delete a department",
+		Permission: coredomain.PermDeptWrite,
+		Response:   map[string]string{},
+	}, deptHandler.DeleteDepartment)
+
+	m.routes = api.Routes()
 }
@@ -0,0 +1,99 @@
+//go:build integration
+
+package hr_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/problem"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/requestinfo"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/testutil"
+)
+
+func TestTeacherErrorsAreProblemDetails(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	schema := db.CreateTenantSchema(t)
+	admin := testutil.SeedAdmin(t, db.Pool, schema)
+	srv := testutil.TestServer(t, db.Pool)
+	defer srv.Close()
+
+	token := loginAndGetToken(t, srv.URL, admin.Email, admin.Password)
+
+	do := func(req *http.Request, expected int) problem.Details {
+		t.Helper()
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s: %v", req.Method, req.URL, err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != expected {
+			t.Fatalf("expected status %d, got %d", expected, resp.StatusCode)
+		}
+		if ct := resp.Header.Get("Content-Type"); ct != problem.ContentType {
+			t.Fatalf("expected Content-Type %s, got %q", problem.ContentType, ct)
+		}
+		var d problem.Details
+		if err := json.NewDecoder(resp.Body).Decode(&d); err != nil {
+			t.Fatalf("decode problem: %v", err)
+		}
+		return d
+	}
+
+	// Every violation is reported at once, per field.
+	req := mustAuthReq(t, http.MethodPost, srv.URL+"/api/v1/teachers", token, schema, jsonBody(t, map[string]any{
+		"name":          "",
+		"email":         "not-an-email",
+		"department_id": "nope",
+	}))
+	req.Header.Set(requestinfo.Header, "problem-test-1")
+	d := do(req, http.StatusBadRequest)
+	if d.Code != "validation_failed" || d.Type != problem.TypePrefix+"validation_failed" || d.Status != http.StatusBadRequest {
+		t.Fatalf("unexpected problem: %+v", d)
+	}
+	if d.RequestID != "problem-test-1" || d.Instance != "/api/v1/teachers" {
+		t.Fatalf("expected request_id and instance, got %+v", d)
+	}
+	got := map[string]string{}
+	for _, v := range d.Errors {
+		got[v.Field] = v.Code
+	}
+	want := map[string]string{"name": "required", "email": "invalid", "department_id": "invalid"}
+	for field, code := range want {
+		if got[field] != code {
+			t.Fatalf("expected %s violation %q, got %v", field, code, d.Errors)
+		}
+	}
+
+	// A value of the wrong JSON type names the field too.
+	d = do(mustAuthReq(t, http.MethodPost, srv.URL+"/api/v1/teachers", token, schema,
+		[]byte(`{"name": "Ada", "email": "ada@example.com", "qualifications": "math"}`)), http.StatusBadRequest)
+	if len(d.Errors) != 1 || d.Errors[0].Field != "qualifications" || d.Errors[0].Code != "invalid_type" {
+		t.Fatalf("expected qualifications invalid_type, got %+v", d.Errors)
+	}
+
+	// Malformed JSON is not a field problem.
+	d = do(mustAuthReq(t, http.MethodPost, srv.URL+"/api/v1/teachers", token, schema, []byte(`{"name":`)), http.StatusBadRequest)
+	if d.Code != "invalid_request" || len(d.Errors) != 0 {
+		t.Fatalf("expected invalid_request, got %+v", d)
+	}
+
+	// Path ids and missing resources.
+	d = do(mustAuthReq(t, http.MethodGet, srv.URL+"/api/v1/teachers/not-a-uuid", token, schema, nil), http.StatusBadRequest)
+	if d.Code != "validation_failed" || len(d.Errors) != 1 || d.Errors[0].Field != "id" {
+		t.Fatalf("expected id violation, got %+v", d)
+	}
+	d = do(mustAuthReq(t, http.MethodGet, srv.URL+"/api/v1/teachers/"+uuid.NewString(), token, schema, nil), http.StatusNotFound)
+	if d.Code != "not_found" || d.Detail != "teacher not found" {
+		t.Fatalf("expected not_found, got %+v", d)
+	}
+
+	// Middleware errors use the same format.
+	noPerm := testutil.GenerateTestToken(t, uuid.New(), schema, nil)
+	if d = do(mustAuthReq(t, http.MethodGet, srv.URL+"/api/v1/teachers", noPerm, schema, nil), http.StatusForbidden); d.Code != "forbidden" {
+		t.Fatalf("expected forbidden, got %+v", d)
+	}
+}
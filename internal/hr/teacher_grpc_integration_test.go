@@ -0,0 +1,75 @@
+//go:build integration
+
+package hr_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	coredomain "github.com/HuynhHoangPhuc/mcs-erp/internal/core/domain"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/testutil"
+	hrv1 "github.com/HuynhHoangPhuc/mcs-erp/proto/gen/hr/v1"
+)
+
+func TestTeacherGRPC_ReadAndPermissions(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	schema := db.CreateTenantSchema(t)
+	srv := testutil.TestServer(t, db.Pool)
+	defer srv.Close()
+
+	teacher := testutil.SeedTeacher(t, db.Pool, schema)
+	client := hrv1.NewTeacherServiceClient(testutil.TestGRPCClient(t, db.Pool))
+
+	readToken := testutil.GenerateTestToken(t, uuid.New(), schema, []string{coredomain.PermTeacherRead})
+	ctx := testutil.GRPCContext(context.Background(), readToken, schema)
+
+	got, err := client.GetTeacher(ctx, &hrv1.GetTeacherRequest{Id: teacher.ID.String()})
+	if err != nil {
+		t.Fatalf("get teacher: %v", err)
+	}
+	if got.GetTeacher().GetId() != teacher.ID.String() {
+		t.Fatalf("expected teacher %s, got %s", teacher.ID, got.GetTeacher().GetId())
+	}
+
+	list, err := client.ListTeachers(ctx, &hrv1.ListTeachersRequest{})
+	if err != nil {
+		t.Fatalf("list teachers: %v", err)
+	}
+	if list.GetTotal() < 1 {
+		t.Fatalf("expected at least 1 teacher, got %d", list.GetTotal())
+	}
+
+	avail, err := client.GetTeacherAvailability(ctx, &hrv1.GetTeacherAvailabilityRequest{TeacherId: teacher.ID.String()})
+	if err != nil {
+		t.Fatalf("get availability: %v", err)
+	}
+	if len(avail.GetSlots()) == 0 {
+		t.Fatal("expected seeded availability slot")
+	}
+
+	_, err = client.GetTeacher(ctx, &hrv1.GetTeacherRequest{Id: uuid.NewString()})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound for unknown teacher, got %v", err)
+	}
+
+	noPermToken := testutil.GenerateTestToken(t, uuid.New(), schema, nil)
+	_, err = client.GetTeacher(testutil.GRPCContext(context.Background(), noPermToken, schema), &hrv1.GetTeacherRequest{Id: teacher.ID.String()})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied without permission, got %v", err)
+	}
+
+	_, err = client.GetTeacher(testutil.GRPCContext(context.Background(), "not-a-jwt", schema), &hrv1.GetTeacherRequest{Id: teacher.ID.String()})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated for bad token, got %v", err)
+	}
+
+	otherSchema := db.CreateTenantSchema(t)
+	_, err = client.GetTeacher(testutil.GRPCContext(context.Background(), readToken, otherSchema), &hrv1.GetTeacherRequest{Id: teacher.ID.String()})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for cross-tenant token, got %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package audit
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/problem"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/validate"
+	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
+)
+
+// Handler serves the audit log query and export endpoints. Mount it behind
+// authentication and the audit read permission.
+type Handler struct {
+	store *Store
+}
+
+// NewHandler creates the audit HTTP handler.
+func NewHandler(store *Store) *Handler {
+	return &Handler{store: store}
+}
+
+// List handles GET /api/v1/audit with the listquery syntax over ListSchema
+// (limit defaults to 50, max 500).
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	spec, err := parseSpec(r.URL.Query())
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	page, err := h.store.List(r.Context(), spec)
+	if err != nil {
+		problem.Write(w, r, erptypes.Internal("failed to list audit log", err))
+		return
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+// Export handles GET /api/v1/audit/export?format=csv|jsonl with the same
+// filters as List, streaming every match oldest first.
+func (h *Handler) Export(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	spec, err := parseSpec(q)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	format := q.Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	var write func(Entry) error
+	var flush func() error
+	switch format {
+	case "csv":
+		cw := csv.NewWriter(w)
+		write = func(e Entry) error { return cw.Write(csvRecord(e)) }
+		flush = func() error { cw.Flush(); return cw.Error() }
+		w.Header().Set("Content-Type", "text/csv")
+		cw.Write(csvHeader)
+	case "jsonl":
+		enc := json.NewEncoder(w)
+		write = func(e Entry) error { return enc.Encode(e) }
+		flush = func() error { return nil }
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	default:
+		problem.Write(w, r, erptypes.Validation(erptypes.FieldViolation{
+			Field: "format", Code: validate.NotAllowed, Message: "must be one of csv, jsonl",
+		}))
+		return
+	}
+	w.Header().Set("Content-Disposition",
+		fmt.Sprintf(`attachment; filename="audit-%s.%s"`, time.Now().UTC().Format("20060102T150405Z"), format))
+
+	// Headers are already sent once rows stream, so a mid-export failure can
+	// only be logged; the truncated file lacks its trailing rows.
+	if err := h.store.Each(r.Context(), spec, write); err != nil {
+		slog.Error("export audit log failed", "error", err)
+		return
+	}
+	if err := flush(); err != nil {
+		slog.Error("export audit log failed", "error", err)
+	}
+}
+
+var csvHeader = []string{"id", "occurred_at", "tenant", "actor_id", "actor_email", "action",
+	"entity_type", "entity_id", "changes", "request_id", "client_ip"}
+
+func csvRecord(e Entry) []string {
+	actorID := ""
+	if e.ActorID != nil {
+		actorID = e.ActorID.String()
+	}
+	return []string{
+		strconv.FormatInt(e.ID, 10), e.OccurredAt.UTC().Format(time.RFC3339Nano), e.Tenant,
+		actorID, deref(e.ActorEmail), e.Action, e.EntityType, e.EntityID, string(e.Changes),
+		deref(e.RequestID), deref(e.ClientIP),
+	}
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// parseSpec parses the list query. from and to (RFC 3339) predate the
+// shared list syntax and mean occurred_at[gte] and occurred_at[lt].
+func parseSpec(q url.Values) (listquery.Spec, error) {
+	if v := q.Get("from"); v != "" {
+		q.Set("occurred_at[gte]", v)
+	}
+	if v := q.Get("to"); v != "" {
+		q.Set("occurred_at[lt]", v)
+	}
+	spec, err := listquery.Parse(q, ListSchema)
+	if err != nil {
+		return listquery.Spec{}, validate.Query(err)
+	}
+	return spec, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,138 @@
+// Package audit reads the per-tenant append-only audit_log. Rows are written by
+// the audit_row_change trigger (migrations/platform), never from Go, so every
+// write path is covered including ones added later.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/database"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
+)
+
+// Actions recorded by the trigger.
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// Entry is one recorded row change. Changes maps each changed column to
+// {"before": ..., "after": ...}; redacted columns only show {"redacted": true}.
+type Entry struct {
+	ID         int64           `json:"id"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Tenant     string          `json:"tenant"`
+	ActorID    *uuid.UUID      `json:"actor_id"`
+	ActorEmail *string         `json:"actor_email"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Changes    json.RawMessage `json:"changes"`
+	RequestID  *string         `json:"request_id"`
+	ClientIP   *string         `json:"client_ip"`
+}
+
+// ListSchema declares how the audit log can be filtered and sorted. Export
+// takes the same filters.
+var ListSchema = &listquery.Schema{
+	Fields: map[string]listquery.Field{
+		"id":          {Kind: listquery.Int, Filter: listquery.Eq | listquery.Range, Sort: true},
+		"occurred_at": {Kind: listquery.Time, Filter: listquery.Range, Sort: true},
+		"entity_type": {Kind: listquery.Text, Filter: listquery.Eq | listquery.In},
+		"entity_id":   {Kind: listquery.Text, Filter: listquery.Eq | listquery.In},
+		"actor_id":    {Kind: listquery.UUID, Filter: listquery.Eq | listquery.In},
+		"action":      {Kind: listquery.Text, Filter: listquery.Eq | listquery.In},
+		"request_id":  {Kind: listquery.Text, Filter: listquery.Eq},
+	},
+	Key:          "id",
+	DefaultSort:  []listquery.Sort{{Field: "id", Desc: true}},
+	DefaultLimit: 50,
+	MaxLimit:     500,
+}
+
+// Store queries the audit log of the tenant in the context.
+type Store struct {
+	pool *pgxpool.Pool
+}
+
+// NewStore creates an audit log reader.
+func NewStore(pool *pgxpool.Pool) *Store {
+	return &Store{pool: pool}
+}
+
+const entryColumns = `id, occurred_at, tenant, actor_id, actor_email, action, entity_type, entity_id, changes, request_id, client_ip`
+
+// scanEntry reads entryColumns, then the cursor column when extra is given.
+func scanEntry(row pgx.Row, extra ...any) (Entry, error) {
+	var e Entry
+	err := row.Scan(append([]any{&e.ID, &e.OccurredAt, &e.Tenant, &e.ActorID, &e.ActorEmail, &e.Action,
+		&e.EntityType, &e.EntityID, &e.Changes, &e.RequestID, &e.ClientIP}, extra...)...)
+	return e, err
+}
+
+// List returns a page of matching entries, newest first by default.
+func (s *Store) List(ctx context.Context, spec listquery.Spec) (listquery.Page[Entry], error) {
+	schema, err := tenant.FromContext(ctx)
+	if err != nil {
+		return listquery.Page[Entry]{}, err
+	}
+	q, err := listquery.Build(spec, ListSchema)
+	if err != nil {
+		return listquery.Page[Entry]{}, err
+	}
+
+	var page listquery.Page[Entry]
+	err = database.WithTenantTx(ctx, s.pool, schema, func(tx pgx.Tx) error {
+		page, err = listquery.Fetch(ctx, tx, q, entryColumns, "audit_log",
+			func(rows pgx.Rows, cursor *string) (Entry, error) {
+				return scanEntry(rows, cursor)
+			})
+		return err
+	})
+	if err != nil {
+		return listquery.Page[Entry]{}, fmt.Errorf("list audit log: %w", err)
+	}
+	return page, nil
+}
+
+// Each streams every entry matching the filters of spec, oldest first, to fn
+// without buffering the result set; its sort and paging are ignored. An
+// error from fn stops the scan and is returned.
+func (s *Store) Each(ctx context.Context, spec listquery.Spec, fn func(Entry) error) error {
+	schema, err := tenant.FromContext(ctx)
+	if err != nil {
+		return err
+	}
+	q, err := listquery.Build(listquery.Spec{Filters: spec.Filters}, ListSchema)
+	if err != nil {
+		return err
+	}
+	where, args := q.Where()
+
+	return database.WithTenantTx(ctx, s.pool, schema, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, fmt.Sprintf(`SELECT %s FROM audit_log%s ORDER BY id`, entryColumns, where), args...)
+		if err != nil {
+			return fmt.Errorf("export audit log: %w", err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			e, err := scanEntry(rows)
+			if err != nil {
+				return fmt.Errorf("scan audit entry: %w", err)
+			}
+			if err := fn(e); err != nil {
+				return err
+			}
+		}
+		return rows.Err()
+	})
+}
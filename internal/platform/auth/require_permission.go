@@ -4,6 +4,8 @@ import (
 	"net/http"
 
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/core/domain"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/problem"
+	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
 )
 
 // RequirePermission returns middleware that checks if the authenticated user
@@ -13,12 +15,12 @@ func RequirePermission(perm string) func(http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			claims, err := UserFromContext(r.Context())
 			if err != nil {
-				http.Error(w, `{"error":"unauthorized"}`, http.StatusUnauthorized)
+				problem.Write(w, r, erptypes.Unauthorized("unauthorized"))
 				return
 			}
 
 			if !domain.HasPermission(claims.Permissions, perm) {
-				http.Error(w, `{"error":"forbidden"}`, http.StatusForbidden)
+				problem.Write(w, r, erptypes.Forbidden("missing permission "+perm))
 				return
 			}
 
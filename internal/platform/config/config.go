@@ -1,84 +1,164 @@
+// Package config loads the server configuration in layers: defaults, then a
+// YAML file (-config or CONFIG_FILE), then environment variables, then
+// command-line flags. Each later layer overrides the earlier ones field by
+// field.
+//
+// Every setting has a YAML key (its path, e.g. http.request_timeout), an
+// environment variable (HTTP_REQUEST_TIMEOUT) and a flag named after the path
+// (-http.request_timeout). Secrets may instead be read from the file named by
+// <VAR>_FILE, e.g. JWT_SECRET_FILE, and are redacted by Print.
 package config
 
 import (
-	"fmt"
-	"os"
 	"time"
 )
 
-// Config holds all application configuration loaded from environment variables.
+// Config holds all application configuration.
 type Config struct {
-	Port        string
-	Env         string
-	DatabaseURL string
-	RedisURL    string
-	JWTSecret   string
-	JWTExpiry   time.Duration
-	LogLevel    string
-	GRPCPort    string
-
-	// LLM provider (primary)
-	LLMProvider string // LLM_PROVIDER: claude | openai | ollama
-	LLMModel    string // LLM_MODEL
-	LLMAPIKey   string // LLM_API_KEY
-
-	// LLM provider (fallback — optional)
-	LLMFallbackProvider string // LLM_FALLBACK_PROVIDER
-	LLMFallbackModel    string // LLM_FALLBACK_MODEL
-	LLMFallbackAPIKey   string // LLM_FALLBACK_API_KEY
-
-	// Ollama server URL (used when provider = ollama)
-	OllamaURL string // OLLAMA_URL, default http://localhost:11434
+	Env      string `yaml:"env" env:"ENV"`             // development enables dev-only behaviour
+	LogLevel string `yaml:"log_level" env:"LOG_LEVEL"` // debug | info | warn | error
+
+	// Bounds the whole shutdown sequence (SHUTDOWN_TIMEOUT, default 20s)
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
+
+	HTTP      HTTPConfig      `yaml:"http"`
+	GRPC      GRPCConfig      `yaml:"grpc"`
+	Database  DatabaseConfig  `yaml:"database"`
+	Redis     RedisConfig     `yaml:"redis"`
+	EventBus  EventBusConfig  `yaml:"eventbus"`
+	RateLimit RateLimitConfig `yaml:"ratelimit"`
+	Jobs      JobsConfig      `yaml:"jobs"`
+	Tracing   TracingConfig   `yaml:"tracing"`
+	Core      CoreConfig      `yaml:"core"`
+	Agent     AgentConfig     `yaml:"agent"`
+	Timetable TimetableConfig `yaml:"timetable"`
 }
 
-// Load reads configuration from environment variables with sensible defaults.
-// Returns error if required variables are missing.
-func Load() (*Config, error) {
-	cfg := &Config{
-		Port:        getEnv("PORT", "8080"),
-		Env:         getEnv("ENV", "development"),
-		DatabaseURL: os.Getenv("DATABASE_URL"),
-		RedisURL:    getEnv("REDIS_URL", "redis://localhost:6379/0"),
-		JWTSecret:   os.Getenv("JWT_SECRET"),
-		LogLevel:    getEnv("LOG_LEVEL", "info"),
-		GRPCPort:    getEnv("GRPC_PORT", "9090"),
-
-		LLMProvider: getEnv("LLM_PROVIDER", "ollama"),
-		LLMModel:    getEnv("LLM_MODEL", "llama3"),
-		LLMAPIKey:   os.Getenv("LLM_API_KEY"),
-
-		LLMFallbackProvider: os.Getenv("LLM_FALLBACK_PROVIDER"),
-		LLMFallbackModel:    os.Getenv("LLM_FALLBACK_MODEL"),
-		LLMFallbackAPIKey:   os.Getenv("LLM_FALLBACK_API_KEY"),
-
-		OllamaURL: getEnv("OLLAMA_URL", "http://localhost:11434"),
-	}
+// HTTPConfig configures the REST server.
+type HTTPConfig struct {
+	Port               string        `yaml:"port" env:"PORT"`
+	CORSAllowedOrigins []string      `yaml:"cors_allowed_origins" env:"CORS_ALLOWED_ORIGINS"` // comma-separated in env; may use {tenant}, e.g. https://{tenant}.erp.example.com
+	TrustedProxies     []string      `yaml:"trusted_proxies" env:"HTTP_TRUSTED_PROXIES"`      // CIDRs whose X-Forwarded-For is believed; comma-separated in env
+	RequestTimeout     time.Duration `yaml:"request_timeout" env:"HTTP_REQUEST_TIMEOUT"`      // routes may set their own
+	ReadTimeout        time.Duration `yaml:"read_timeout" env:"HTTP_READ_TIMEOUT"`            // reading the request, body included
+	IdleTimeout        time.Duration `yaml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT"`            // keep-alive connections
+	MaxBodyBytes       int64         `yaml:"max_body_bytes" env:"HTTP_MAX_BODY_BYTES"`
+	DrainGrace         time.Duration `yaml:"drain_grace" env:"HTTP_DRAIN_GRACE"` // in-flight requests may finish before being cancelled on shutdown
+}
 
-	if cfg.DatabaseURL == "" {
-		return nil, fmt.Errorf("DATABASE_URL is required")
-	}
-	if cfg.JWTSecret == "" {
-		return nil, fmt.Errorf("JWT_SECRET is required")
-	}
+// GRPCConfig configures the gRPC server.
+type GRPCConfig struct {
+	Port string `yaml:"port" env:"GRPC_PORT"`
+}
 
-	expiry := getEnv("JWT_EXPIRY", "24h")
-	d, err := time.ParseDuration(expiry)
-	if err != nil {
-		return nil, fmt.Errorf("invalid JWT_EXPIRY %q: %w", expiry, err)
-	}
-	cfg.JWTExpiry = d
+// DatabaseConfig configures the Postgres pool and tenant migrations.
+type DatabaseConfig struct {
+	URL      string `yaml:"url" env:"DATABASE_URL" secret:"true"`
+	MinConns int    `yaml:"min_conns" env:"DATABASE_MIN_CONNS"`
+	MaxConns int    `yaml:"max_conns" env:"DATABASE_MAX_CONNS"`
+	// Tenant schemas migrated in parallel at startup
+	MigrationConcurrency int `yaml:"migration_concurrency" env:"MIGRATION_CONCURRENCY"`
+}
 
-	return cfg, nil
+// RedisConfig locates Redis: agent message cache, rate limits and the redis event bus.
+type RedisConfig struct {
+	URL string `yaml:"url" env:"REDIS_URL" secret:"true"`
 }
 
-// IsDev returns true if running in development mode.
-func (c *Config) IsDev() bool {
-	return c.Env == "development"
+// EventBusConfig selects and tunes the event bus backend.
+type EventBusConfig struct {
+	Driver        string `yaml:"driver" env:"EVENTBUS_DRIVER"` // gochannel | postgres | redis
+	ConsumerGroup string `yaml:"consumer_group" env:"EVENTBUS_CONSUMER_GROUP"`
+	MaxRetries    int    `yaml:"max_retries" env:"EVENTBUS_MAX_RETRIES"`
+}
+
+// RateLimitConfig configures request rate limits; buckets live in Redis, else in memory.
+type RateLimitConfig struct {
+	Enabled bool `yaml:"enabled" env:"RATE_LIMIT_ENABLED"`
+}
+
+// JobsConfig tunes this replica's background job workers.
+type JobsConfig struct {
+	Workers      int           `yaml:"workers" env:"JOBS_WORKERS"`             // jobs run concurrently
+	PollInterval time.Duration `yaml:"poll_interval" env:"JOBS_POLL_INTERVAL"` // how often tenants are checked for due jobs
+	Lease        time.Duration `yaml:"lease" env:"JOBS_LEASE"`                 // a job is rerun this long after its worker stops responding
+	Retention    time.Duration `yaml:"retention" env:"JOBS_RETENTION"`         // finished jobs are kept this long
 }
 
-func getEnv(key, fallback string) string {
-	if v := os.Getenv(key); v != "" {
-		return v
+// TracingConfig configures tracing; the OTLP endpoint comes from the standard OTEL_EXPORTER_OTLP_ENDPOINT.
+type TracingConfig struct {
+	Exporter    string  `yaml:"exporter" env:"TRACE_EXPORTER"` // none | otlp | stdout | file
+	File        string  `yaml:"file" env:"TRACE_FILE"`         // file exporter output
+	SampleRatio float64 `yaml:"sample_ratio" env:"TRACE_SAMPLE_RATIO"`
+}
+
+// CoreConfig configures authentication and tenant onboarding.
+type CoreConfig struct {
+	JWTSecret     string        `yaml:"jwt_secret" env:"JWT_SECRET" secret:"true"`
+	AccessExpiry  time.Duration `yaml:"access_token_expiry" env:"JWT_EXPIRY"`
+	RefreshExpiry time.Duration `yaml:"refresh_token_expiry" env:"JWT_REFRESH_EXPIRY"`
+	// Platform operator token for tenant onboarding; empty disables it
+	PlatformAdminToken string `yaml:"platform_admin_token" env:"PLATFORM_ADMIN_TOKEN" secret:"true"`
+}
+
+// AgentConfig configures the LLM providers behind the AI agent.
+type AgentConfig struct {
+	Provider string `yaml:"provider" env:"LLM_PROVIDER"` // claude | openai | ollama
+	Model    string `yaml:"model" env:"LLM_MODEL"`
+	APIKey   string `yaml:"api_key" env:"LLM_API_KEY" secret:"true"`
+
+	// Optional fallback provider, used when the primary fails
+	FallbackProvider string `yaml:"fallback_provider" env:"LLM_FALLBACK_PROVIDER"`
+	FallbackModel    string `yaml:"fallback_model" env:"LLM_FALLBACK_MODEL"`
+	FallbackAPIKey   string `yaml:"fallback_api_key" env:"LLM_FALLBACK_API_KEY" secret:"true"`
+
+	// Ollama server URL (used when a provider is ollama)
+	OllamaURL string `yaml:"ollama_url" env:"OLLAMA_URL"`
+}
+
+// TimetableConfig tunes the schedule generator's simulated annealing.
+type TimetableConfig struct {
+	InitialTemperature float64 `yaml:"initial_temperature" env:"TIMETABLE_INITIAL_TEMPERATURE"`
+	CoolingRate        float64 `yaml:"cooling_rate" env:"TIMETABLE_COOLING_RATE"` // multiplicative factor per iteration
+	MinTemperature     float64 `yaml:"min_temperature" env:"TIMETABLE_MIN_TEMPERATURE"`
+	MaxIterations      int     `yaml:"max_iterations" env:"TIMETABLE_MAX_ITERATIONS"`
+	Workers            int     `yaml:"workers" env:"TIMETABLE_WORKERS"` // parallel annealers; 0 is one per CPU
+}
+
+// Defaults returns the configuration used where no layer sets a value.
+// DATABASE_URL and JWT_SECRET have no default.
+func Defaults() *Config {
+	return &Config{
+		Env:             "development",
+		LogLevel:        "info",
+		ShutdownTimeout: 20 * time.Second,
+		HTTP: HTTPConfig{
+			Port:           "8080",
+			RequestTimeout: 30 * time.Second,
+			ReadTimeout:    15 * time.Second,
+			IdleTimeout:    60 * time.Second,
+			MaxBodyBytes:   1 << 20,
+			DrainGrace:     10 * time.Second,
+		},
+		GRPC:      GRPCConfig{Port: "9090"},
+		Database:  DatabaseConfig{MinConns: 5, MaxConns: 25, MigrationConcurrency: 4},
+		Redis:     RedisConfig{URL: "redis://localhost:6379/0"},
+		EventBus:  EventBusConfig{Driver: "gochannel", ConsumerGroup: "mcs-erp", MaxRetries: 3},
+		RateLimit: RateLimitConfig{Enabled: true},
+		Jobs:      JobsConfig{Workers: 4, PollInterval: time.Second, Lease: 30 * time.Second, Retention: 7 * 24 * time.Hour},
+		Tracing:   TracingConfig{Exporter: "none", File: "traces.jsonl", SampleRatio: 1},
+		Core:      CoreConfig{AccessExpiry: 24 * time.Hour, RefreshExpiry: 7 * 24 * time.Hour},
+		Agent:     AgentConfig{Provider: "ollama", Model: "llama3", OllamaURL: "http://localhost:11434"},
+		Timetable: TimetableConfig{
+			InitialTemperature: 1000,
+			CoolingRate:        0.9995,
+			MinTemperature:     0.01,
+			MaxIterations:      500000,
+		},
 	}
-	return fallback
+}
+
+// IsDev returns true if running in development mode.
+func (c *Config) IsDev() bool {
+	return c.Env == "development"
 }
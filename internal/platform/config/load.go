@@ -0,0 +1,238 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv names the environment variable that points at the YAML file when
+// -config is not given.
+const FileEnv = "CONFIG_FILE"
+
+// Load resolves the configuration from args (command-line flags, without the
+// program name) and validates it. The error lists every problem found.
+func Load(args []string) (*Config, error) {
+	cfg, err := Resolve(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, errors.Join(err, cfg.Validate())
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Resolve applies the layers without validating the result. On error the
+// returned config holds every value that could be read, so it can still be
+// printed. A -h flag returns flag.ErrHelp after printing the flags.
+func Resolve(args []string) (*Config, error) {
+	cfg := Defaults()
+	settings := settingsOf(cfg)
+
+	fs, path := flagSet(settings)
+	if err := fs.Parse(args); err != nil {
+		return cfg, err
+	}
+	if fs.NArg() > 0 {
+		return cfg, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+
+	var errs []error
+	if *path == "" {
+		*path = os.Getenv(FileEnv)
+	}
+	if *path != "" {
+		if err := loadFile(cfg, *path); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, s := range settings {
+		if err := s.fromEnv(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	fs.Visit(func(f *flag.Flag) {
+		if v, ok := f.Value.(*flagValue); ok {
+			if err := v.s.parse(v.raw); err != nil {
+				errs = append(errs, fmt.Errorf("-%s: %w", f.Name, err))
+			}
+		}
+	})
+	return cfg, errors.Join(errs...)
+}
+
+// loadFile decodes the YAML file at path over cfg. Unknown keys are errors.
+func loadFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("config file: %w", err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// setting is one leaf of Config, addressed by its YAML path.
+type setting struct {
+	path   string // YAML path, also the flag name
+	env    string
+	secret bool
+	value  reflect.Value
+}
+
+// settingsOf lists the leaves of cfg in declaration order.
+func settingsOf(cfg *Config) []*setting {
+	var out []*setting
+	var walk func(v reflect.Value, prefix string)
+	walk = func(v reflect.Value, prefix string) {
+		t := v.Type()
+		for i := range t.NumField() {
+			f := t.Field(i)
+			path := prefix + strings.Split(f.Tag.Get("yaml"), ",")[0]
+			if f.Type.Kind() == reflect.Struct {
+				walk(v.Field(i), path+".")
+				continue
+			}
+			out = append(out, &setting{
+				path:   path,
+				env:    f.Tag.Get("env"),
+				secret: f.Tag.Get("secret") == "true",
+				value:  v.Field(i),
+			})
+		}
+	}
+	walk(reflect.ValueOf(cfg).Elem(), "")
+	return out
+}
+
+// fromEnv applies the setting's environment variable, or for a secret the
+// file named by <VAR>_FILE. Empty variables count as unset.
+func (s *setting) fromEnv() error {
+	raw, fromFile := os.Getenv(s.env), ""
+	if s.secret {
+		fromFile = os.Getenv(s.env + "_FILE")
+	}
+	switch {
+	case raw != "" && fromFile != "":
+		return fmt.Errorf("%s and %s_FILE are both set", s.env, s.env)
+	case fromFile != "":
+		data, err := os.ReadFile(fromFile)
+		if err != nil {
+			return fmt.Errorf("%s_FILE: %w", s.env, err)
+		}
+		raw = strings.TrimRight(string(data), "\r\n")
+	case raw == "":
+		return nil
+	}
+	if err := s.parse(raw); err != nil {
+		return fmt.Errorf("%s: %w", s.env, err)
+	}
+	return nil
+}
+
+// parse sets the setting from its text form: durations as "30s", lists
+// comma-separated.
+func (s *setting) parse(raw string) error {
+	v := s.value
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", raw)
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		v.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported setting type %s", v.Type())
+	}
+	return nil
+}
+
+// String formats the current value, as flag defaults are shown.
+func (s *setting) String() string {
+	if s.secret {
+		return ""
+	}
+	switch v := s.value.Interface().(type) {
+	case time.Duration:
+		return v.String()
+	case []string:
+		return strings.Join(v, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// flagSet declares -config and one flag per setting. Flag values are only
+// recorded while parsing; Resolve applies them after the env layer.
+func flagSet(settings []*setting) (*flag.FlagSet, *string) {
+	fs := flag.NewFlagSet("server", flag.ContinueOnError)
+	path := fs.String("config", "", "YAML config file (env "+FileEnv+")")
+	for _, s := range settings {
+		fs.Var(&flagValue{s: s}, s.path, "env "+s.env)
+	}
+	return fs, path
+}
+
+// flagValue holds a flag's text until Resolve applies it.
+type flagValue struct {
+	s   *setting
+	raw string
+}
+
+func (f *flagValue) String() string {
+	if f == nil || f.s == nil {
+		return ""
+	}
+	return f.s.String()
+}
+
+func (f *flagValue) Set(raw string) error {
+	f.raw = raw
+	return nil
+}
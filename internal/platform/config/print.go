@@ -0,0 +1,36 @@
+package config
+
+import (
+	"io"
+	"net/url"
+
+	"gopkg.in/yaml.v3"
+)
+
+// redacted replaces secret values in Print.
+const redacted = "[REDACTED]"
+
+// Print writes the configuration as YAML, in the config file's format, with
+// secrets redacted. URL secrets keep everything but the password, so the
+// database and Redis hosts stay visible.
+func (c *Config) Print(w io.Writer) error {
+	out := *c
+	for _, s := range settingsOf(&out) {
+		if s.secret && s.value.String() != "" {
+			s.value.SetString(redact(s.value.String()))
+		}
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&out); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func redact(secret string) string {
+	if u, err := url.Parse(secret); err == nil && u.Scheme != "" && u.Host != "" {
+		return u.Redacted()
+	}
+	return redacted
+}
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Validate checks the whole configuration and returns every problem at once,
+// each naming the setting by its YAML path and environment variable.
+func (c *Config) Validate() error {
+	v := &validator{env: map[string]string{}}
+	for _, s := range settingsOf(c) {
+		v.env[s.path] = s.env
+	}
+
+	v.oneOf("log_level", c.LogLevel, "debug", "info", "warn", "error")
+	v.positive("shutdown_timeout", c.ShutdownTimeout)
+
+	v.port("http.port", c.HTTP.Port)
+	v.positive("http.request_timeout", c.HTTP.RequestTimeout)
+	v.positive("http.read_timeout", c.HTTP.ReadTimeout)
+	v.positive("http.idle_timeout", c.HTTP.IdleTimeout)
+	v.positive("http.drain_grace", c.HTTP.DrainGrace)
+	v.check(c.HTTP.DrainGrace < c.ShutdownTimeout, "http.drain_grace", "must be shorter than shutdown_timeout")
+	v.check(c.HTTP.MaxBodyBytes > 0, "http.max_body_bytes", "must be positive")
+	for _, origin := range c.HTTP.CORSAllowedOrigins {
+		if u, err := url.Parse(origin); err != nil || u.Scheme == "" || u.Host == "" {
+			v.add("http.cors_allowed_origins", fmt.Sprintf("%q is not an origin (scheme://host)", origin))
+		}
+	}
+	for _, cidr := range c.HTTP.TrustedProxies {
+		if _, err := netip.ParsePrefix(strings.TrimSpace(cidr)); err != nil {
+			v.add("http.trusted_proxies", fmt.Sprintf("%q is not a CIDR (e.g. 10.0.0.0/8)", cidr))
+		}
+	}
+
+	v.port("grpc.port", c.GRPC.Port)
+
+	v.required("database.url", c.Database.URL)
+	v.check(c.Database.MinConns >= 0, "database.min_conns", "must not be negative")
+	v.check(c.Database.MaxConns >= 1, "database.max_conns", "must be at least 1")
+	v.check(c.Database.MinConns <= c.Database.MaxConns, "database.min_conns", "must not exceed max_conns")
+	v.check(c.Database.MigrationConcurrency >= 1, "database.migration_concurrency", "must be at least 1")
+
+	if c.Redis.URL != "" {
+		if u, err := url.Parse(c.Redis.URL); err != nil || !slices.Contains([]string{"redis", "rediss", "unix"}, u.Scheme) {
+			v.add("redis.url", "must be a redis://, rediss:// or unix:// URL")
+		}
+	}
+
+	v.oneOf("eventbus.driver", c.EventBus.Driver, "gochannel", "postgres", "redis")
+	v.check(c.EventBus.MaxRetries >= 0, "eventbus.max_retries", "must not be negative")
+	v.check(c.EventBus.Driver != "redis" || c.Redis.URL != "", "redis.url", "is required by the redis event bus")
+
+	v.check(c.Jobs.Workers >= 1, "jobs.workers", "must be at least 1")
+	v.positive("jobs.poll_interval", c.Jobs.PollInterval)
+	v.check(c.Jobs.Lease >= 3*time.Second, "jobs.lease", "must be at least 3s")
+	v.positive("jobs.retention", c.Jobs.Retention)
+
+	v.oneOf("tracing.exporter", c.Tracing.Exporter, "none", "otlp", "stdout", "file")
+	v.check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing.sample_ratio", "must be between 0 and 1")
+
+	v.required("core.jwt_secret", c.Core.JWTSecret)
+	v.positive("core.access_token_expiry", c.Core.AccessExpiry)
+	v.check(c.Core.RefreshExpiry > c.Core.AccessExpiry, "core.refresh_token_expiry", "must be longer than access_token_expiry")
+
+	providers := []string{"claude", "openai", "ollama"}
+	v.oneOf("agent.provider", c.Agent.Provider, providers...)
+	if c.Agent.FallbackProvider != "" {
+		v.oneOf("agent.fallback_provider", c.Agent.FallbackProvider, providers...)
+	}
+
+	v.check(c.Timetable.InitialTemperature > c.Timetable.MinTemperature, "timetable.initial_temperature", "must exceed min_temperature")
+	v.check(c.Timetable.MinTemperature > 0, "timetable.min_temperature", "must be positive")
+	v.check(c.Timetable.CoolingRate > 0 && c.Timetable.CoolingRate < 1, "timetable.cooling_rate", "must be between 0 and 1 (exclusive)")
+	v.check(c.Timetable.MaxIterations > 0, "timetable.max_iterations", "must be positive")
+	v.check(c.Timetable.Workers >= 0, "timetable.workers", "must not be negative")
+
+	return errors.Join(v.errs...)
+}
+
+// validator collects validation failures.
+type validator struct {
+	env  map[string]string // YAML path → environment variable
+	errs []error
+}
+
+func (v *validator) add(path, msg string) {
+	v.errs = append(v.errs, fmt.Errorf("%s (%s) %s", path, v.env[path], msg))
+}
+
+func (v *validator) check(ok bool, path, msg string) {
+	if !ok {
+		v.add(path, msg)
+	}
+}
+
+func (v *validator) required(path, value string) {
+	v.check(value != "", path, "is required")
+}
+
+func (v *validator) positive(path string, d time.Duration) {
+	v.check(d > 0, path, "must be positive")
+}
+
+func (v *validator) port(path, value string) {
+	n, err := strconv.Atoi(value)
+	v.check(err == nil && n > 0 && n < 65536, path, fmt.Sprintf("%q is not a port", value))
+}
+
+func (v *validator) oneOf(path, value string, allowed ...string) {
+	if !slices.Contains(allowed, value) {
+		v.add(path, fmt.Sprintf("%q must be one of %v", value, allowed))
+	}
+}
@@ -0,0 +1,102 @@
+//go:build integration
+
+package platform_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/config"
+)
+
+func TestConfig_LayersAndSecretFiles(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yaml")
+	yaml := "http:\n  port: \"9000\"\n  request_timeout: 45s\n  read_timeout: 20s\ndatabase:\n  max_conns: 40\n"
+	if err := os.WriteFile(file, []byte(yaml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	secret := filepath.Join(dir, "jwt_secret")
+	if err := os.WriteFile(secret, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(config.FileEnv, file)
+	t.Setenv("DATABASE_URL", "postgres://mcs:pw@db:5432/mcs_erp")
+	t.Setenv("JWT_SECRET", "")
+	t.Setenv("JWT_SECRET_FILE", secret)
+	t.Setenv("HTTP_REQUEST_TIMEOUT", "50s")
+	t.Setenv("PORT", "")
+
+	cfg, err := config.Load([]string{"-http.request_timeout=55s"})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	// default < file < env < flag
+	if cfg.HTTP.IdleTimeout != 60*time.Second {
+		t.Errorf("idle_timeout: expected default 60s, got %s", cfg.HTTP.IdleTimeout)
+	}
+	if cfg.HTTP.Port != "9000" || cfg.HTTP.ReadTimeout != 20*time.Second || cfg.Database.MaxConns != 40 {
+		t.Errorf("expected file values, got port %s, read_timeout %s, max_conns %d", cfg.HTTP.Port, cfg.HTTP.ReadTimeout, cfg.Database.MaxConns)
+	}
+	if cfg.HTTP.RequestTimeout != 55*time.Second {
+		t.Errorf("request_timeout: expected flag value 55s, got %s", cfg.HTTP.RequestTimeout)
+	}
+	if cfg.Core.JWTSecret != "from-file" {
+		t.Errorf("jwt_secret: expected JWT_SECRET_FILE contents, got %q", cfg.Core.JWTSecret)
+	}
+
+	// Print redacts secrets but keeps the hosts of URL secrets.
+	var out bytes.Buffer
+	if err := cfg.Print(&out); err != nil {
+		t.Fatalf("print: %v", err)
+	}
+	if strings.Contains(out.String(), "from-file") || strings.Contains(out.String(), ":pw@") {
+		t.Fatalf("secret leaked in:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "postgres://mcs:xxxxx@db:5432/mcs_erp") {
+		t.Fatalf("expected redacted database url in:\n%s", out.String())
+	}
+
+	// The printed config loads back as a config file.
+	if err := os.WriteFile(file, out.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := config.Resolve(nil); err != nil {
+		t.Fatalf("printed config does not load back: %v", err)
+	}
+}
+
+func TestConfig_AggregatesErrors(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(file, []byte("http:\n  prot: \"9000\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(config.FileEnv, file)
+	t.Setenv("DATABASE_URL", "")
+	t.Setenv("JWT_SECRET", "set")
+	t.Setenv("JWT_SECRET_FILE", file)
+	t.Setenv("TRACE_SAMPLE_RATIO", "2")
+	t.Setenv("EVENTBUS_DRIVER", "kafka")
+
+	_, err := config.Load([]string{"-http.max_body_bytes=lots"})
+	if err == nil {
+		t.Fatal("expected an invalid configuration")
+	}
+	for _, want := range []string{
+		"field prot not found",                        // unknown YAML key
+		"JWT_SECRET and JWT_SECRET_FILE are both set", // env
+		`-http.max_body_bytes: invalid integer "lots"`,
+		"database.url (DATABASE_URL) is required",
+		"eventbus.driver (EVENTBUS_DRIVER)",
+		"tracing.sample_ratio (TRACE_SAMPLE_RATIO) must be between 0 and 1",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in:\n%v", want, err)
+		}
+	}
+}
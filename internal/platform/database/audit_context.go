@@ -0,0 +1,29 @@
+package database
+
+import (
+	"context"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/auth"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/requestinfo"
+)
+
+// auditContext is what the audit_row_change trigger reads back through
+// current_setting('mcs.*'). Empty values are stored as NULL.
+type auditContext struct {
+	actorID    string
+	actorEmail string
+	requestID  string
+	clientIP   string
+}
+
+func auditContextFrom(ctx context.Context) auditContext {
+	var a auditContext
+	if c, err := auth.UserFromContext(ctx); err == nil {
+		a.actorID = c.UserID.String()
+		a.actorEmail = c.Email
+	}
+	info := requestinfo.FromContext(ctx)
+	a.requestID = info.ID
+	a.clientIP = info.ClientIP
+	return a
+}
@@ -0,0 +1,162 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DriftKind classifies a difference between a tenant schema and _template.
+type DriftKind string
+
+const (
+	DriftMissing DriftKind = "missing" // in _template, not in the tenant
+	DriftExtra   DriftKind = "extra"   // in the tenant, not in _template
+	DriftChanged DriftKind = "changed" // in both with different definitions
+)
+
+// DriftItem is one differing object, e.g. "column teachers.email".
+type DriftItem struct {
+	Object   string    `json:"object"`
+	Kind     DriftKind `json:"kind"`
+	Expected string    `json:"expected,omitempty"`
+	Actual   string    `json:"actual,omitempty"`
+}
+
+// SchemaDrift lists every difference found in one tenant schema.
+type SchemaDrift struct {
+	Schema string      `json:"schema"`
+	Items  []DriftItem `json:"items"`
+}
+
+// HasDrift reports whether the schema differs from _template.
+func (d *SchemaDrift) HasDrift() bool { return len(d.Items) > 0 }
+
+// DriftInspector compares tenant schemas against _template: tables, columns
+// (type, nullability, default), indexes and constraints.
+type DriftInspector struct {
+	pool *pgxpool.Pool
+}
+
+// NewDriftInspector creates a schema drift inspector.
+func NewDriftInspector(pool *pgxpool.Pool) *DriftInspector {
+	return &DriftInspector{pool: pool}
+}
+
+// Inspect compares one tenant schema against _template.
+func (i *DriftInspector) Inspect(ctx context.Context, schema string) (*SchemaDrift, error) {
+	template, err := i.snapshot(ctx, "_template")
+	if err != nil {
+		return nil, err
+	}
+	return i.compare(ctx, template, schema)
+}
+
+// InspectAll compares every live tenant schema against _template.
+func (i *DriftInspector) InspectAll(ctx context.Context) ([]*SchemaDrift, error) {
+	template, err := i.snapshot(ctx, "_template")
+	if err != nil {
+		return nil, err
+	}
+	schemas, err := ActiveTenantSchemas(ctx, i.pool)
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]*SchemaDrift, 0, len(schemas))
+	for _, schema := range schemas {
+		report, err := i.compare(ctx, template, schema)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+func (i *DriftInspector) compare(ctx context.Context, template map[string]string, schema string) (*SchemaDrift, error) {
+	actual, err := i.snapshot(ctx, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &SchemaDrift{Schema: schema, Items: []DriftItem{}}
+	for obj, want := range template {
+		got, ok := actual[obj]
+		switch {
+		case !ok:
+			report.Items = append(report.Items, DriftItem{Object: obj, Kind: DriftMissing, Expected: want})
+		case got != want:
+			report.Items = append(report.Items, DriftItem{Object: obj, Kind: DriftChanged, Expected: want, Actual: got})
+		}
+	}
+	for obj, got := range actual {
+		if _, ok := template[obj]; !ok {
+			report.Items = append(report.Items, DriftItem{Object: obj, Kind: DriftExtra, Actual: got})
+		}
+	}
+	sort.Slice(report.Items, func(a, b int) bool { return report.Items[a].Object < report.Items[b].Object })
+	return report, nil
+}
+
+// driftQueries describe a schema as (object, definition) rows. Definitions are
+// made schema-independent by stripSchema before comparison.
+var driftQueries = []string{
+	`SELECT 'table ' || table_name, table_type
+	 FROM information_schema.tables WHERE table_schema = $1`,
+
+	`SELECT 'column ' || c.relname || '.' || a.attname,
+	        format_type(a.atttypid, a.atttypmod)
+	        || CASE WHEN a.attnotnull THEN ' NOT NULL' ELSE '' END
+	        || COALESCE(' DEFAULT ' || pg_get_expr(d.adbin, d.adrelid), '')
+	 FROM pg_attribute a
+	 JOIN pg_class c ON c.oid = a.attrelid
+	 JOIN pg_namespace n ON n.oid = c.relnamespace
+	 LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+	 WHERE n.nspname = $1 AND c.relkind IN ('r', 'p') AND a.attnum > 0 AND NOT a.attisdropped`,
+
+	`SELECT 'index ' || indexname, indexdef
+	 FROM pg_indexes WHERE schemaname = $1`,
+
+	`SELECT 'constraint ' || c.relname || '.' || con.conname, pg_get_constraintdef(con.oid)
+	 FROM pg_constraint con
+	 JOIN pg_class c ON c.oid = con.conrelid
+	 JOIN pg_namespace n ON n.oid = c.relnamespace
+	 WHERE n.nspname = $1`,
+}
+
+func (i *DriftInspector) snapshot(ctx context.Context, schema string) (map[string]string, error) {
+	qualified := schemaQualifier(schema)
+	objects := map[string]string{}
+	for _, q := range driftQueries {
+		rows, err := i.pool.Query(ctx, q, schema)
+		if err != nil {
+			return nil, fmt.Errorf("inspect schema %s: %w", schema, err)
+		}
+		for rows.Next() {
+			var obj, def string
+			if err := rows.Scan(&obj, &def); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("scan schema %s: %w", schema, err)
+			}
+			objects[obj] = qualified.ReplaceAllString(def, "${1}")
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("inspect schema %s: %w", schema, err)
+		}
+	}
+	return objects, nil
+}
+
+// schemaQualifier matches schema as a qualifier ("schema." or, quoted,
+// "\"Schema\".") with the character before it in group 1, so that only whole
+// identifiers match: schema c must not turn public.x into publix.
+func schemaQualifier(schema string) *regexp.Regexp {
+	names := regexp.QuoteMeta(schema) + `|` + regexp.QuoteMeta(pgx.Identifier{schema}.Sanitize())
+	return regexp.MustCompile(`(^|[^\w$".])(?:` + names + `)\.`)
+}
@@ -3,9 +3,7 @@ package database
 import (
 	"context"
 	"fmt"
-	"log/slog"
 
-	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -24,9 +22,23 @@ func (m *Migrator) EnsureTemplateSchema(ctx context.Context) error {
 	return CreateSchema(ctx, m.pool, "_template")
 }
 
+// ProvisionSchema creates a tenant schema and applies every listed module's migrations to it.
+// Safe to call again on a partially provisioned schema: only pending steps run.
+func (m *Migrator) ProvisionSchema(ctx context.Context, schema string, modules ...string) error {
+	if err := CreateSchema(ctx, m.pool, schema); err != nil {
+		return fmt.Errorf("create schema %s: %w", schema, err)
+	}
+	return m.MigrateSchema(ctx, schema, modules...)
+}
+
 // ActiveTenantSchemas queries the public.tenants table for all active tenant schema names.
 func (m *Migrator) ActiveTenantSchemas(ctx context.Context) ([]string, error) {
-	rows, err := m.pool.Query(ctx, "SELECT schema_name FROM public.tenants WHERE is_active = true")
+	return ActiveTenantSchemas(ctx, m.pool)
+}
+
+// ActiveTenantSchemas returns the schema names of all active tenants.
+func ActiveTenantSchemas(ctx context.Context, pool *pgxpool.Pool) ([]string, error) {
+	rows, err := pool.Query(ctx, "SELECT schema_name FROM public.tenants WHERE is_active = true")
 	if err != nil {
 		return nil, fmt.Errorf("query tenant schemas: %w", err)
 	}
@@ -42,31 +54,3 @@ func (m *Migrator) ActiveTenantSchemas(ctx context.Context) ([]string, error) {
 	}
 	return schemas, rows.Err()
 }
-
-// MigrateAll applies the given SQL to _template and all active tenant schemas.
-func (m *Migrator) MigrateAll(ctx context.Context, sql string) error {
-	slog.Info("migrating _template schema")
-	if err := m.runSQLInSchema(ctx, "_template", sql); err != nil {
-		return fmt.Errorf("migrate _template: %w", err)
-	}
-
-	schemas, err := m.ActiveTenantSchemas(ctx)
-	if err != nil {
-		return err
-	}
-
-	for _, schema := range schemas {
-		slog.Info("migrating tenant schema", "schema", schema)
-		if err := m.runSQLInSchema(ctx, schema, sql); err != nil {
-			return fmt.Errorf("migrate %s: %w", schema, err)
-		}
-	}
-	return nil
-}
-
-func (m *Migrator) runSQLInSchema(ctx context.Context, schema string, sqlStr string) error {
-	return WithTenantTx(ctx, m.pool, schema, func(tx pgx.Tx) error {
-		_, err := tx.Exec(ctx, sqlStr)
-		return err
-	})
-}
@@ -11,14 +11,29 @@ import (
 
 var schemaNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
 
+// PoolSize bounds the connections a pool keeps open.
+type PoolSize struct {
+	MinConns int32
+	MaxConns int32
+}
+
+// DefaultPoolSize is the pool size NewPool uses.
+var DefaultPoolSize = PoolSize{MinConns: 5, MaxConns: 25}
+
 // NewPool creates a pgxpool connected to the given database URL.
 func NewPool(ctx context.Context, dbURL string) (*pgxpool.Pool, error) {
+	return NewPoolWithSize(ctx, dbURL, DefaultPoolSize)
+}
+
+// NewPoolWithSize is NewPool with an explicit pool size.
+func NewPoolWithSize(ctx context.Context, dbURL string, size PoolSize) (*pgxpool.Pool, error) {
 	cfg, err := pgxpool.ParseConfig(dbURL)
 	if err != nil {
 		return nil, fmt.Errorf("parse db config: %w", err)
 	}
-	cfg.MinConns = 5
-	cfg.MaxConns = 25
+	cfg.MinConns = size.MinConns
+	cfg.MaxConns = size.MaxConns
+	cfg.ConnConfig.Tracer = NewQueryTracer()
 
 	pool, err := pgxpool.NewWithConfig(ctx, cfg)
 	if err != nil {
@@ -43,6 +58,22 @@ func SetTenantSchema(ctx context.Context, tx pgx.Tx, schema string) error {
 	return err
 }
 
+// setTenantContext is SetTenantSchema plus the audit settings (see audit_context.go),
+// in one round trip.
+func setTenantContext(ctx context.Context, tx pgx.Tx, schema string) error {
+	if !schemaNameRegex.MatchString(schema) {
+		return fmt.Errorf("invalid schema name: %q", schema)
+	}
+	a := auditContextFrom(ctx)
+	_, err := tx.Exec(ctx, `SELECT set_config('search_path', $1, true),
+		set_config('mcs.actor_id', $2, true),
+		set_config('mcs.actor_email', $3, true),
+		set_config('mcs.request_id', $4, true),
+		set_config('mcs.client_ip', $5, true)`,
+		schema+", public", a.actorID, a.actorEmail, a.requestID, a.clientIP)
+	return err
+}
+
 // CreateSchema creates a tenant schema if it does not exist.
 func CreateSchema(ctx context.Context, pool *pgxpool.Pool, schema string) error {
 	if !schemaNameRegex.MatchString(schema) {
@@ -52,7 +83,9 @@ func CreateSchema(ctx context.Context, pool *pgxpool.Pool, schema string) error
 	return err
 }
 
-// WithTenantTx begins a transaction with the search_path set to the given tenant schema.
+// WithTenantTx begins a transaction with the search_path set to the given tenant
+// schema. The caller's identity and request metadata are set as transaction-local
+// settings so the audit triggers can attribute every row they record.
 func WithTenantTx(ctx context.Context, pool *pgxpool.Pool, schema string, fn func(tx pgx.Tx) error) error {
 	tx, err := pool.Begin(ctx)
 	if err != nil {
@@ -60,7 +93,7 @@ func WithTenantTx(ctx context.Context, pool *pgxpool.Pool, schema string, fn fun
 	}
 	defer tx.Rollback(ctx)
 
-	if err := SetTenantSchema(ctx, tx, schema); err != nil {
+	if err := setTenantContext(ctx, tx, schema); err != nil {
 		return fmt.Errorf("set tenant schema: %w", err)
 	}
 
@@ -0,0 +1,183 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TenantMigrationStatus is the outcome of the last migration run for a tenant.
+type TenantMigrationStatus string
+
+const (
+	TenantMigrationPending TenantMigrationStatus = "pending"
+	TenantMigrationApplied TenantMigrationStatus = "applied"
+	TenantMigrationFailed  TenantMigrationStatus = "failed"
+)
+
+// TenantMigrationState is one row of public.tenant_migration_status.
+type TenantMigrationState struct {
+	Schema    string                `json:"schema"`
+	Status    TenantMigrationStatus `json:"status"`
+	LastError string                `json:"last_error,omitempty"`
+	Attempts  int                   `json:"attempts"`
+	UpdatedAt time.Time             `json:"updated_at"`
+}
+
+// TenantRunResult is the outcome of migrating one tenant schema.
+type TenantRunResult struct {
+	Schema  string
+	Applied int
+	Err     error
+}
+
+// TenantRunReport summarises a run across tenants.
+type TenantRunReport struct {
+	Results []TenantRunResult
+}
+
+// Failed returns the schemas whose migration failed.
+func (r *TenantRunReport) Failed() []string {
+	var failed []string
+	for _, res := range r.Results {
+		if res.Err != nil {
+			failed = append(failed, res.Schema)
+		}
+	}
+	return failed
+}
+
+// TenantRunner brings every live tenant schema up to date with the module
+// migrations, several tenants at a time. A failing tenant does not stop the
+// run: it is recorded as failed (quarantined) and the rest carry on. Runs are
+// resumable because every step is recorded in the schema's schema_migrations.
+type TenantRunner struct {
+	pool        *pgxpool.Pool
+	migrator    *Migrator
+	modules     []string
+	concurrency int
+}
+
+// NewTenantRunner creates a runner applying modules, in order, with at most
+// concurrency tenants migrating at once.
+func NewTenantRunner(pool *pgxpool.Pool, modules []string, concurrency int) *TenantRunner {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &TenantRunner{pool: pool, migrator: NewMigrator(pool), modules: modules, concurrency: concurrency}
+}
+
+// Run migrates all active and suspended tenants. It only returns an error if
+// the tenant list cannot be read; per-tenant failures are in the report.
+func (r *TenantRunner) Run(ctx context.Context) (*TenantRunReport, error) {
+	schemas, err := ActiveTenantSchemas(ctx, r.pool)
+	if err != nil {
+		return nil, err
+	}
+	return r.RunSchemas(ctx, schemas), nil
+}
+
+// RunSchemas migrates the given tenant schemas with bounded parallelism.
+func (r *TenantRunner) RunSchemas(ctx context.Context, schemas []string) *TenantRunReport {
+	report := &TenantRunReport{Results: make([]TenantRunResult, len(schemas))}
+	sem := make(chan struct{}, r.concurrency)
+	var wg sync.WaitGroup
+
+	for i, schema := range schemas {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			report.Results[i] = TenantRunResult{Schema: schema, Err: ctx.Err()}
+			continue
+		}
+		wg.Add(1)
+		go func(i int, schema string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			report.Results[i] = r.migrateTenant(ctx, schema)
+		}(i, schema)
+	}
+	wg.Wait()
+
+	if failed := report.Failed(); len(failed) > 0 {
+		slog.Warn("tenant migrations failed; tenants quarantined", "count", len(failed), "schemas", failed)
+	}
+	return report
+}
+
+// Retry re-runs migrations for one tenant, lifting the quarantine on success.
+func (r *TenantRunner) Retry(ctx context.Context, schema string) TenantRunResult {
+	return r.migrateTenant(ctx, schema)
+}
+
+func (r *TenantRunner) migrateTenant(ctx context.Context, schema string) TenantRunResult {
+	res := TenantRunResult{Schema: schema}
+	if _, err := r.pool.Exec(ctx,
+		`INSERT INTO public.tenant_migration_status (schema_name, status) VALUES ($1, $2)
+		 ON CONFLICT (schema_name) DO NOTHING`,
+		schema, TenantMigrationPending,
+	); err != nil {
+		res.Err = fmt.Errorf("record migration status: %w", err)
+		return res
+	}
+
+	for _, module := range r.modules {
+		migs, err := LoadMigrations(module)
+		if err != nil {
+			res.Err = err
+			break
+		}
+		n, err := r.migrator.Up(ctx, schema, migs)
+		res.Applied += n
+		if err != nil {
+			res.Err = fmt.Errorf("migrate %s in %s: %w", module, schema, err)
+			break
+		}
+	}
+
+	// A cancelled run is interrupted, not failed: leave the status for the next run.
+	if ctx.Err() != nil {
+		if res.Err == nil {
+			res.Err = ctx.Err()
+		}
+		return res
+	}
+
+	status, lastError := TenantMigrationApplied, ""
+	if res.Err != nil {
+		status, lastError = TenantMigrationFailed, res.Err.Error()
+		slog.Error("tenant migration failed", "schema", schema, "error", res.Err)
+	} else if res.Applied > 0 {
+		slog.Info("applied tenant migrations", "schema", schema, "count", res.Applied)
+	}
+	if _, err := r.pool.Exec(ctx,
+		`UPDATE public.tenant_migration_status
+		 SET status = $2, last_error = $3, attempts = attempts + 1, updated_at = now()
+		 WHERE schema_name = $1`,
+		schema, status, lastError,
+	); err != nil && res.Err == nil {
+		res.Err = fmt.Errorf("record migration status: %w", err)
+	}
+	return res
+}
+
+// Statuses lists the recorded migration state of every tenant.
+func (r *TenantRunner) Statuses(ctx context.Context) ([]TenantMigrationState, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT schema_name, status, last_error, attempts, updated_at
+		 FROM public.tenant_migration_status ORDER BY schema_name`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query migration status: %w", err)
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (TenantMigrationState, error) {
+		var s TenantMigrationState
+		err := row.Scan(&s.Schema, &s.Status, &s.LastError, &s.Attempts, &s.UpdatedAt)
+		return s, err
+	})
+}
@@ -0,0 +1,62 @@
+package database
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tracing"
+)
+
+// NewQueryTracer returns a pgx tracer that emits a client span per query,
+// tagged with the tenant schema from ctx. Queries outside a trace (startup
+// migrations, background loops) are skipped rather than each becoming a root span.
+func NewQueryTracer() pgx.QueryTracer {
+	return queryTracer{}
+}
+
+type queryTracer struct{}
+
+func (queryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+
+	attrs := []attribute.KeyValue{
+		attribute.String("db.system.name", "postgresql"),
+		attribute.String("db.query.text", data.SQL),
+	}
+	if schema, err := tenant.FromContext(ctx); err == nil {
+		attrs = append(attrs, tracing.AttrTenant.String(schema))
+	}
+	ctx, _ = tracing.Tracer("mcs-erp/database").Start(ctx, "db "+operation(data.SQL),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	return ctx
+}
+
+func (queryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+	tracing.RecordError(span, data.Err)
+	span.SetAttributes(attribute.Int64("db.response.rows_affected", data.CommandTag.RowsAffected()))
+	span.End()
+}
+
+// operation returns the leading SQL keyword ("SELECT", "INSERT", ...).
+func operation(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return "query"
+	}
+	return strings.ToUpper(fields[0])
+}
+
+var _ pgx.QueryTracer = queryTracer{}
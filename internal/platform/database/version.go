@@ -0,0 +1,30 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
+)
+
+// VersionMismatch explains why an optimistic update
+//
+//	UPDATE <table> SET ..., <column> = <column> + 1 WHERE id = $1 AND <column> = $n
+//
+// matched no row: erptypes.ErrNotFound when the row is gone, otherwise an
+// error wrapping erptypes.ErrConflict with the expected and stored versions.
+// table and column must be constants, never user input.
+func VersionMismatch(ctx context.Context, tx pgx.Tx, table, column string, id any, expected int) error {
+	var current int
+	err := tx.QueryRow(ctx, "SELECT "+column+" FROM "+table+" WHERE id = $1", id).Scan(&current)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return erptypes.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("%s: expected %s %d, got %d: %w", table, column, expected, current, erptypes.ErrConflict)
+}
@@ -0,0 +1,261 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/migrations"
+)
+
+// Migration is one versioned, reversible schema change of a module.
+type Migration struct {
+	Module  string
+	Version int64
+	Name    string
+	Up      string
+	Down    string // empty when the step cannot be rolled back
+}
+
+// LoadMigrations reads a module's embedded migrations ordered by version.
+func LoadMigrations(module string) ([]Migration, error) {
+	return loadMigrations(migrations.FS, module, migrations.Dir(module))
+}
+
+func loadMigrations(fsys fs.FS, module, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("read migrations for %s: %w", module, err)
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		name := e.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(name, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+
+		base := strings.TrimSuffix(name, "."+direction+".sql")
+		prefix, desc, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %s/%s: expected <version>_<name>", module, name)
+		}
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s/%s: invalid version %q", module, name, prefix)
+		}
+
+		body, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s/%s: %w", module, name, err)
+		}
+
+		mig := byVersion[version]
+		if mig == nil {
+			mig = &Migration{Module: module, Version: version, Name: desc}
+			byVersion[version] = mig
+		} else if mig.Name != desc {
+			return nil, fmt.Errorf("migration %s version %d has conflicting names %q and %q", module, version, mig.Name, desc)
+		}
+		if direction == "up" {
+			mig.Up = string(body)
+		} else {
+			mig.Down = string(body)
+		}
+	}
+
+	result := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" {
+			return nil, fmt.Errorf("migration %s version %d has no up file", module, mig.Version)
+		}
+		result = append(result, *mig)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+	return result, nil
+}
+
+// MigratePublic applies pending migrations for the shared public schema.
+func (m *Migrator) MigratePublic(ctx context.Context) error {
+	migs, err := LoadMigrations(migrations.Public)
+	if err != nil {
+		return err
+	}
+	_, err = m.Up(ctx, "public", migs)
+	return err
+}
+
+// MigrateModule applies a module's pending migrations to _template. Tenant
+// schemas are brought up afterwards by TenantRunner, so one broken tenant
+// cannot block startup for the others.
+func (m *Migrator) MigrateModule(ctx context.Context, module string) error {
+	migs, err := LoadMigrations(module)
+	if err != nil {
+		return err
+	}
+	n, err := m.Up(ctx, "_template", migs)
+	if err != nil {
+		return fmt.Errorf("migrate %s in _template: %w", module, err)
+	}
+	if n > 0 {
+		slog.Info("applied migrations", "module", module, "schema", "_template", "count", n)
+	}
+	return nil
+}
+
+// MigrateSchema applies every listed module's pending migrations to one schema,
+// in the given order. Used to bring a freshly created tenant schema up to date.
+func (m *Migrator) MigrateSchema(ctx context.Context, schema string, modules ...string) error {
+	for _, module := range modules {
+		migs, err := LoadMigrations(module)
+		if err != nil {
+			return err
+		}
+		if _, err := m.Up(ctx, schema, migs); err != nil {
+			return fmt.Errorf("migrate %s in %s: %w", module, schema, err)
+		}
+	}
+	return nil
+}
+
+// Up applies the pending migrations to schema in version order and returns how many ran.
+// Each step runs in its own transaction together with its schema_migrations row,
+// under a per-schema advisory lock so concurrent replicas never apply a step twice.
+func (m *Migrator) Up(ctx context.Context, schema string, migs []Migration) (int, error) {
+	applied := 0
+	for _, mig := range migs {
+		ran := false
+		err := m.withMigrationLock(ctx, schema, func(tx pgx.Tx) error {
+			done, err := isApplied(ctx, tx, schema, mig.Module, mig.Version)
+			if err != nil || done {
+				return err
+			}
+			if _, err := tx.Exec(ctx, mig.Up); err != nil {
+				return fmt.Errorf("apply %s %06d_%s: %w", mig.Module, mig.Version, mig.Name, err)
+			}
+			_, err = tx.Exec(ctx,
+				`INSERT INTO `+migrationsTable(schema)+` (module, version, name) VALUES ($1, $2, $3)`,
+				mig.Module, mig.Version, mig.Name,
+			)
+			ran = err == nil
+			return err
+		})
+		if err != nil {
+			return applied, err
+		}
+		if ran {
+			applied++
+		}
+	}
+	return applied, nil
+}
+
+// Down rolls back the most recently applied steps of migs' module in schema,
+// newest first, and returns how many were reverted.
+func (m *Migrator) Down(ctx context.Context, schema string, migs []Migration, steps int) (int, error) {
+	if len(migs) == 0 || steps <= 0 {
+		return 0, nil
+	}
+	module := migs[0].Module
+
+	reverted := 0
+	for i := len(migs) - 1; i >= 0 && reverted < steps; i-- {
+		mig := migs[i]
+		ran := false
+		err := m.withMigrationLock(ctx, schema, func(tx pgx.Tx) error {
+			done, err := isApplied(ctx, tx, schema, module, mig.Version)
+			if err != nil || !done {
+				return err
+			}
+			if mig.Down == "" {
+				return fmt.Errorf("migration %s %06d_%s is irreversible", module, mig.Version, mig.Name)
+			}
+			if _, err := tx.Exec(ctx, mig.Down); err != nil {
+				return fmt.Errorf("revert %s %06d_%s: %w", module, mig.Version, mig.Name, err)
+			}
+			_, err = tx.Exec(ctx,
+				`DELETE FROM `+migrationsTable(schema)+` WHERE module = $1 AND version = $2`,
+				module, mig.Version,
+			)
+			ran = err == nil
+			return err
+		})
+		if err != nil {
+			return reverted, err
+		}
+		if ran {
+			reverted++
+		}
+	}
+	return reverted, nil
+}
+
+// AppliedVersions returns the versions of module recorded in schema, ascending.
+func (m *Migrator) AppliedVersions(ctx context.Context, schema, module string) ([]int64, error) {
+	var versions []int64
+	err := m.withMigrationLock(ctx, schema, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx,
+			`SELECT version FROM `+migrationsTable(schema)+` WHERE module = $1 ORDER BY version`, module,
+		)
+		if err != nil {
+			return err
+		}
+		versions, err = pgx.CollectRows(rows, pgx.RowTo[int64])
+		return err
+	})
+	return versions, err
+}
+
+// withMigrationLock runs fn in a tenant transaction holding the schema's
+// migration advisory lock, after making sure schema_migrations exists.
+func (m *Migrator) withMigrationLock(ctx context.Context, schema string, fn func(tx pgx.Tx) error) error {
+	if !schemaNameRegex.MatchString(schema) {
+		return fmt.Errorf("invalid schema name: %q", schema)
+	}
+	return WithTenantTx(ctx, m.pool, schema, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('schema_migrations:' || $1))`, schema); err != nil {
+			return fmt.Errorf("acquire migration lock: %w", err)
+		}
+		if _, err := tx.Exec(ctx, `CREATE TABLE IF NOT EXISTS `+migrationsTable(schema)+` (
+			module     VARCHAR(64)  NOT NULL,
+			version    BIGINT       NOT NULL,
+			name       VARCHAR(255) NOT NULL,
+			applied_at TIMESTAMPTZ  NOT NULL DEFAULT now(),
+			PRIMARY KEY (module, version)
+		)`); err != nil {
+			return fmt.Errorf("ensure schema_migrations: %w", err)
+		}
+		return fn(tx)
+	})
+}
+
+func isApplied(ctx context.Context, tx pgx.Tx, schema, module string, version int64) (bool, error) {
+	var exists bool
+	err := tx.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM `+migrationsTable(schema)+` WHERE module = $1 AND version = $2)`,
+		module, version,
+	).Scan(&exists)
+	return exists, err
+}
+
+// migrationsTable is always schema-qualified: with search_path "tenant, public"
+// an unqualified name would fall through to public.schema_migrations.
+func migrationsTable(schema string) string {
+	return pgx.Identifier{schema, "schema_migrations"}.Sanitize()
+}
@@ -0,0 +1,71 @@
+//go:build integration
+
+package platform_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/database"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/testutil"
+	"github.com/HuynhHoangPhuc/mcs-erp/migrations"
+)
+
+func TestDriftInspector_ReportsHandEditedSchema(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	ctx := context.Background()
+
+	migrator := database.NewMigrator(db.Pool)
+	if err := migrator.EnsureTemplateSchema(ctx); err != nil {
+		t.Fatalf("ensure _template: %v", err)
+	}
+	if err := migrator.MigrateSchema(ctx, "_template", migrations.TenantModules...); err != nil {
+		t.Fatalf("migrate _template: %v", err)
+	}
+
+	schema := db.CreateTenantSchema(t)
+	inspector := database.NewDriftInspector(db.Pool)
+
+	clean, err := inspector.Inspect(ctx, schema)
+	if err != nil {
+		t.Fatalf("inspect clean schema: %v", err)
+	}
+	if clean.HasDrift() {
+		t.Fatalf("expected freshly migrated schema to match _template, got %+v", clean.Items)
+	}
+
+	teachers := pgx.Identifier{schema, "teachers"}.Sanitize()
+	for _, stmt := range []string{
+		"ALTER TABLE " + teachers + " ALTER COLUMN name TYPE TEXT",
+		"DROP INDEX " + pgx.Identifier{schema, "idx_teachers_is_active"}.Sanitize(),
+		"CREATE INDEX idx_teachers_name ON " + teachers + " (name)",
+	} {
+		if _, err := db.Pool.Exec(ctx, stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+
+	drift, err := inspector.Inspect(ctx, schema)
+	if err != nil {
+		t.Fatalf("inspect edited schema: %v", err)
+	}
+	got := map[string]database.DriftKind{}
+	for _, item := range drift.Items {
+		got[item.Object] = item.Kind
+	}
+	want := map[string]database.DriftKind{
+		"column teachers.name":         database.DriftChanged,
+		"index idx_teachers_is_active": database.DriftMissing,
+		"index idx_teachers_name":      database.DriftExtra,
+	}
+	for obj, kind := range want {
+		if got[obj] != kind {
+			t.Fatalf("expected %s to be %s, got %q (all: %v)", obj, kind, got[obj], got)
+		}
+	}
+	if len(drift.Items) != len(want) {
+		t.Fatalf("expected %d differences, got %v", len(want), got)
+	}
+}
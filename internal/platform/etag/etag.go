@@ -0,0 +1,74 @@
+// Package etag carries aggregate versions over HTTP for optimistic
+// concurrency. Reads and writes of a versioned aggregate return its version
+// as a strong ETag; a client that sends it back in If-Match gets 412
+// Precondition Failed instead of overwriting a change it has not seen.
+//
+// Handlers check If-Match against the version they loaded, then the
+// repository's Update re-checks it in the UPDATE itself and returns
+// erptypes.ErrConflict when another writer got there first:
+//
+//	if !etag.Match(r, t.Version) { erptypes.PreconditionFailed(...) }
+//	... apply the change ...
+//	if err := repo.Update(ctx, t); errors.Is(err, erptypes.ErrConflict) { etag.Conflict(r, ...) }
+//	etag.Set(w, t.Version)
+//
+// If-Match is optional; writes without it still cannot be lost silently, they
+// fail with 409 when they race.
+package etag
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
+)
+
+// Header names.
+const (
+	Header        = "ETag"
+	IfMatchHeader = "If-Match"
+)
+
+// Of returns the entity tag for a version, quotes included, e.g. "3".
+func Of(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// Set writes the ETag header for version.
+func Set(w http.ResponseWriter, version int) {
+	w.Header().Set(Header, Of(version))
+}
+
+// Conditional reports whether the request carries If-Match.
+func Conditional(r *http.Request) bool {
+	return r.Header.Get(IfMatchHeader) != ""
+}
+
+// Match reports whether the request may modify an entity currently at
+// version: true when If-Match is absent, "*" or lists the entity's tag.
+// Weak tags (W/"3") never match, as If-Match uses strong comparison.
+func Match(r *http.Request, version int) bool {
+	if !Conditional(r) {
+		return true
+	}
+	want := Of(version)
+	for _, h := range r.Header.Values(IfMatchHeader) {
+		for _, tag := range strings.Split(h, ",") {
+			if tag = strings.TrimSpace(tag); tag == "*" || tag == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Conflict is the error for an erptypes.ErrConflict from a versioned Update:
+// precondition_failed (412) when the client made the request conditional,
+// conflict (409) otherwise.
+func Conflict(r *http.Request, msg string) *erptypes.Error {
+	if Conditional(r) {
+		return erptypes.PreconditionFailed(msg)
+	}
+	return erptypes.Conflict(msg)
+}
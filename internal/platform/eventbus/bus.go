@@ -1,45 +1,331 @@
 package eventbus
 
 import (
+	"context"
+	stdsql "database/sql"
+	"errors"
+	"fmt"
 	"log/slog"
+	"os"
+	"sync"
+	"time"
 
 	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill-redisstream/pkg/redisstream"
+	watermillsql "github.com/ThreeDotsLabs/watermill-sql/v3/pkg/sql"
 	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/message/router/middleware"
 	"github.com/ThreeDotsLabs/watermill/pubsub/gochannel"
+	"github.com/google/uuid"
+	_ "github.com/jackc/pgx/v5/stdlib" // registers the "pgx" database/sql driver
+	"github.com/redis/go-redis/v9"
 )
 
-// EventBus wraps Watermill's in-process GoChannel pub/sub.
+// Driver selects the transport backing the event bus.
+type Driver string
+
+const (
+	// DriverGoChannel keeps events in process memory. Events are lost on restart
+	// and are not shared between replicas; intended for tests and local development.
+	DriverGoChannel Driver = "gochannel"
+	// DriverPostgres stores events in Postgres tables with per-consumer-group offsets.
+	DriverPostgres Driver = "postgres"
+	// DriverRedis uses Redis Streams with XREADGROUP consumer groups.
+	DriverRedis Driver = "redis"
+)
+
+// PoisonTopic receives messages whose handler still fails after all retries.
+// The failure reason is stored in the middleware.ReasonForPoisonedKey metadata.
+const PoisonTopic = "eventbus.poison"
+
+// Config controls backend selection and delivery guarantees.
+type Config struct {
+	Driver        Driver
+	DatabaseURL   string // required for DriverPostgres
+	RedisURL      string // required for DriverRedis
+	ConsumerGroup string // prefix for per-handler consumer groups
+	MaxRetries    int
+	RetryInterval time.Duration
+}
+
+// EventBus wraps a Watermill publisher, subscriber and router for the configured driver.
 type EventBus struct {
+	cfg        Config
 	publisher  message.Publisher
 	subscriber message.Subscriber
 	router     *message.Router
+	logger     watermill.LoggerAdapter
+
+	// newSubscriber creates a subscriber bound to a consumer group. For the
+	// in-process driver every handler shares the single GoChannel subscriber.
+	newSubscriber func(group string) (message.Subscriber, error)
+
+	mu          sync.Mutex
+	subscribers []message.Subscriber
+	closers     []func() error
+
+	closeOnce sync.Once
+	closeErr  error
 }
 
-// New creates a new in-process event bus.
-func New() (*EventBus, error) {
+// New creates an event bus for cfg.Driver. An empty driver defaults to GoChannel.
+func New(cfg Config) (*EventBus, error) {
+	if cfg.Driver == "" {
+		cfg.Driver = DriverGoChannel
+	}
+	if cfg.ConsumerGroup == "" {
+		cfg.ConsumerGroup = "mcs-erp"
+	}
+	if cfg.RetryInterval <= 0 {
+		cfg.RetryInterval = 100 * time.Millisecond
+	}
+
 	logger := watermill.NewSlogLogger(slog.Default())
+	b := &EventBus{cfg: cfg, logger: logger}
 
-	ch := gochannel.NewGoChannel(gochannel.Config{
-		OutputChannelBuffer: 256,
-	}, logger)
+	var err error
+	switch cfg.Driver {
+	case DriverGoChannel:
+		err = b.initGoChannel()
+	case DriverPostgres:
+		err = b.initPostgres()
+	case DriverRedis:
+		err = b.initRedis()
+	default:
+		err = fmt.Errorf("unknown event bus driver %q", cfg.Driver)
+	}
+	if err != nil {
+		b.closeResources()
+		return nil, err
+	}
+	b.publisher = countingPublisher{Publisher: b.publisher}
 
 	router, err := message.NewRouter(message.RouterConfig{}, logger)
 	if err != nil {
+		b.closeResources()
+		return nil, err
+	}
+
+	// Order matters: the poison queue wraps retries so a message is only
+	// poisoned once every retry failed; metrics count every attempt; the
+	// recoverer turns panics into errors.
+	poison, err := middleware.PoisonQueue(b.publisher, PoisonTopic)
+	if err != nil {
+		b.closeResources()
 		return nil, err
 	}
+	retry := middleware.Retry{
+		MaxRetries:      cfg.MaxRetries,
+		InitialInterval: cfg.RetryInterval,
+		Multiplier:      2,
+		MaxInterval:     30 * time.Second,
+		Logger:          logger,
+	}
+	router.AddMiddleware(poison, retry.Middleware, consumeMetrics, middleware.Recoverer)
 
-	return &EventBus{
-		publisher:  ch,
-		subscriber: ch,
-		router:     router,
-	}, nil
+	b.router = router
+	return b, nil
+}
+
+func (b *EventBus) initGoChannel() error {
+	ch := gochannel.NewGoChannel(gochannel.Config{
+		OutputChannelBuffer: 256,
+	}, b.logger)
+
+	b.publisher = ch
+	b.subscriber = ch
+	b.newSubscriber = func(string) (message.Subscriber, error) { return ch, nil }
+	b.closers = append(b.closers, ch.Close)
+	return nil
+}
+
+func (b *EventBus) initPostgres() error {
+	if b.cfg.DatabaseURL == "" {
+		return errors.New("postgres event bus requires a database URL")
+	}
+	db, err := stdsql.Open("pgx", b.cfg.DatabaseURL)
+	if err != nil {
+		return fmt.Errorf("open event bus database: %w", err)
+	}
+	b.closers = append(b.closers, db.Close)
+
+	schema := watermillsql.DefaultPostgreSQLSchema{}
+	offsets := watermillsql.DefaultPostgreSQLOffsetsAdapter{}
+
+	pub, err := watermillsql.NewPublisher(db, watermillsql.PublisherConfig{
+		SchemaAdapter:        schema,
+		AutoInitializeSchema: true,
+	}, b.logger)
+	if err != nil {
+		return fmt.Errorf("create postgres publisher: %w", err)
+	}
+	b.publisher = pub
+
+	b.newSubscriber = func(group string) (message.Subscriber, error) {
+		return watermillsql.NewSubscriber(db, watermillsql.SubscriberConfig{
+			ConsumerGroup:    group,
+			SchemaAdapter:    schema,
+			OffsetsAdapter:   offsets,
+			InitializeSchema: true,
+		}, b.logger)
+	}
+	b.subscriber, err = b.subscriberFor(b.cfg.ConsumerGroup)
+	return err
+}
+
+func (b *EventBus) initRedis() error {
+	if b.cfg.RedisURL == "" {
+		return errors.New("redis event bus requires a redis URL")
+	}
+	opts, err := redis.ParseURL(b.cfg.RedisURL)
+	if err != nil {
+		return fmt.Errorf("parse redis URL: %w", err)
+	}
+	client := redis.NewClient(opts)
+	b.closers = append(b.closers, client.Close)
+
+	pub, err := redisstream.NewPublisher(redisstream.PublisherConfig{
+		Client: client,
+	}, b.logger)
+	if err != nil {
+		return fmt.Errorf("create redis publisher: %w", err)
+	}
+	b.publisher = pub
+
+	// Each replica is a distinct consumer inside the shared group, so Redis
+	// hands every stream entry to exactly one replica.
+	consumer := consumerName()
+	b.newSubscriber = func(group string) (message.Subscriber, error) {
+		return redisstream.NewSubscriber(redisstream.SubscriberConfig{
+			Client:        client,
+			ConsumerGroup: group,
+			Consumer:      consumer,
+		}, b.logger)
+	}
+	b.subscriber, err = b.subscriberFor(b.cfg.ConsumerGroup)
+	return err
+}
+
+// subscriberFor creates and tracks a subscriber for the given consumer group.
+func (b *EventBus) subscriberFor(group string) (message.Subscriber, error) {
+	sub, err := b.newSubscriber(group)
+	if err != nil {
+		return nil, fmt.Errorf("create subscriber for group %s: %w", group, err)
+	}
+	if b.cfg.Driver != DriverGoChannel {
+		b.mu.Lock()
+		b.subscribers = append(b.subscribers, sub)
+		b.mu.Unlock()
+	}
+	return sub, nil
+}
+
+// AddConsumer registers a handler for topic in its own consumer group,
+// named "<ConsumerGroup>.<name>". Every replica joins the same group, so each
+// event is handled once per consumer no matter how many replicas are running.
+func (b *EventBus) AddConsumer(name, topic string, handler message.NoPublishHandlerFunc) error {
+	sub, err := b.subscriberFor(b.cfg.ConsumerGroup + "." + name)
+	if err != nil {
+		return err
+	}
+	b.router.AddNoPublisherHandler(name, topic, sub, handler)
+	return nil
 }
 
 // Publisher returns the Watermill publisher.
 func (b *EventBus) Publisher() message.Publisher { return b.publisher }
 
-// Subscriber returns the Watermill subscriber.
+// Subscriber returns the Watermill subscriber bound to the default consumer group.
 func (b *EventBus) Subscriber() message.Subscriber { return b.subscriber }
 
 // Router returns the Watermill message router for adding handlers.
 func (b *EventBus) Router() *message.Router { return b.router }
+
+// Start runs the router in the background and returns once it is consuming.
+// Consumers must be added before Start. The router keeps running when ctx is
+// cancelled so shutdown can order it after the servers; Close stops it and
+// waits for in-flight handlers.
+func (b *EventBus) Start(ctx context.Context) error {
+	errCh := make(chan error, 1)
+	go func() {
+		if err := b.router.Run(context.WithoutCancel(ctx)); err != nil {
+			errCh <- err
+		}
+	}()
+	select {
+	case <-b.router.Running():
+		return nil
+	case err := <-errCh:
+		return fmt.Errorf("run event router: %w", err)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// CheckRunning reports an error while the router is not consuming events.
+func (b *EventBus) CheckRunning(context.Context) error {
+	if !b.router.IsRunning() {
+		return errors.New("event router is not running")
+	}
+	return nil
+}
+
+// Driver returns the configured backend.
+func (b *EventBus) Driver() Driver { return b.cfg.Driver }
+
+// Close stops the router, waiting for in-flight handlers, and releases
+// publishers, subscribers and connections. It is safe to call more than once.
+func (b *EventBus) Close() error {
+	b.closeOnce.Do(func() { b.closeErr = b.close() })
+	return b.closeErr
+}
+
+func (b *EventBus) close() error {
+	var errs []error
+	if b.router != nil {
+		if err := b.router.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("close router: %w", err))
+		}
+	}
+	if err := b.closeResources(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+func (b *EventBus) closeResources() error {
+	var errs []error
+
+	b.mu.Lock()
+	subs := b.subscribers
+	b.subscribers = nil
+	b.mu.Unlock()
+	for _, sub := range subs {
+		if err := sub.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if b.publisher != nil && b.cfg.Driver != DriverGoChannel {
+		if err := b.publisher.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// Closers run last: they own the connections the pub/sub above depend on.
+	for i := len(b.closers) - 1; i >= 0; i-- {
+		if err := b.closers[i](); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	b.closers = nil
+	return errors.Join(errs...)
+}
+
+func consumerName() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "mcs-erp"
+	}
+	return host + "-" + uuid.NewString()[:8]
+}
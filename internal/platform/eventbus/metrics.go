@@ -0,0 +1,27 @@
+package eventbus
+
+import (
+	"github.com/ThreeDotsLabs/watermill/message"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/metrics"
+)
+
+// countingPublisher counts published messages per topic.
+type countingPublisher struct {
+	message.Publisher
+}
+
+func (p countingPublisher) Publish(topic string, msgs ...*message.Message) error {
+	err := p.Publisher.Publish(topic, msgs...)
+	metrics.ObservePublish(topic, len(msgs), err)
+	return err
+}
+
+// consumeMetrics counts every handler attempt by handler, topic and outcome.
+func consumeMetrics(h message.HandlerFunc) message.HandlerFunc {
+	return func(msg *message.Message) ([]*message.Message, error) {
+		out, err := h(msg)
+		metrics.ObserveConsume(message.HandlerNameFromCtx(msg.Context()), message.SubscribeTopicFromCtx(msg.Context()), err)
+		return out, err
+	}
+}
@@ -23,3 +23,16 @@ func Publish(pub message.Publisher, topic string, event any) error {
 
 	return nil
 }
+
+// MetadataTenantID is the message metadata key carrying the tenant schema
+// the event originated from.
+const MetadataTenantID = "tenant_id"
+
+// TenantFromMessage returns the tenant schema stored in the message metadata.
+func TenantFromMessage(msg *message.Message) (string, error) {
+	schema := msg.Metadata.Get(MetadataTenantID)
+	if schema == "" {
+		return "", fmt.Errorf("message %s has no %s metadata", msg.UUID, MetadataTenantID)
+	}
+	return schema, nil
+}
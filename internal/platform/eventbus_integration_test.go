@@ -0,0 +1,65 @@
+//go:build integration
+
+package platform_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/message/router/middleware"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/eventbus"
+)
+
+func TestEventBus_FailingHandlerIsRetriedThenPoisoned(t *testing.T) {
+	bus, err := eventbus.New(eventbus.Config{
+		Driver:        eventbus.DriverGoChannel,
+		MaxRetries:    2,
+		RetryInterval: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("create event bus: %v", err)
+	}
+	defer bus.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	poisoned, err := bus.Subscriber().Subscribe(ctx, eventbus.PoisonTopic)
+	if err != nil {
+		t.Fatalf("subscribe poison topic: %v", err)
+	}
+
+	var calls atomic.Int32
+	if err := bus.AddConsumer("always-fails", "test.event", func(msg *message.Message) error {
+		calls.Add(1)
+		return errors.New("boom")
+	}); err != nil {
+		t.Fatalf("add consumer: %v", err)
+	}
+
+	go func() { _ = bus.Router().Run(ctx) }()
+	<-bus.Router().Running()
+
+	if err := eventbus.Publish(bus.Publisher(), "test.event", map[string]string{"hello": "world"}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	select {
+	case msg := <-poisoned:
+		msg.Ack()
+		if reason := msg.Metadata.Get(middleware.ReasonForPoisonedKey); reason == "" {
+			t.Fatal("expected poison reason metadata")
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for poisoned message")
+	}
+
+	if got := calls.Load(); got != 3 {
+		t.Fatalf("expected 1 attempt + 2 retries, got %d calls", got)
+	}
+}
@@ -0,0 +1,72 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
+)
+
+// Error converts a repository or service error into a gRPC status error.
+// Unexpected errors are logged and reported as Internal without details.
+func Error(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	case errors.Is(err, erptypes.ErrNotFound):
+		return status.Error(codes.NotFound, "not found")
+	case errors.Is(err, erptypes.ErrValidation):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, erptypes.ErrConflict):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, erptypes.ErrForbidden):
+		return status.Error(codes.PermissionDenied, "forbidden")
+	default:
+		slog.Error("grpc handler failed", "error", err)
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+// ParseUUID parses a required UUID request field, returning InvalidArgument if malformed.
+func ParseUUID(field, value string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, status.Errorf(codes.InvalidArgument, "invalid %s", field)
+	}
+	return id, nil
+}
+
+// ParseOptionalUUID parses an optional UUID field; empty yields nil.
+func ParseOptionalUUID(field, value string) (*uuid.UUID, error) {
+	if value == "" {
+		return nil, nil
+	}
+	id, err := ParseUUID(field, value)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// UUIDString renders an optional UUID as an empty string when nil.
+func UUIDString(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
+
+// PageLimit normalises a requested page size like the REST handlers: default 20, max 100.
+func PageLimit(limit int32) int {
+	if limit <= 0 || limit > 100 {
+		return 20
+	}
+	return int(limit)
+}
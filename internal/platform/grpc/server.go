@@ -3,14 +3,24 @@ package grpc
 import (
 	"log/slog"
 
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 )
 
-// NewServer creates a gRPC server with tenant and auth interceptors.
-func NewServer() *grpc.Server {
+// NewServer creates a gRPC server with tenant and auth interceptors for unary
+// and streaming calls, traced with OpenTelemetry. perms is
+// read on every call, so modules may fill it after the server is created but
+// before it starts serving.
+func NewServer(authn Authenticator, perms MethodPermissions) *grpc.Server {
 	srv := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
 		grpc.ChainUnaryInterceptor(
 			TenantUnaryInterceptor(),
+			AuthUnaryInterceptor(authn, perms),
+		),
+		grpc.ChainStreamInterceptor(
+			TenantStreamInterceptor(),
+			AuthStreamInterceptor(authn, perms),
 		),
 	)
 	slog.Info("gRPC server created")
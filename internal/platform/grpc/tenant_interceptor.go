@@ -2,7 +2,6 @@ package grpc
 
 import (
 	"context"
-	"regexp"
 	"strings"
 
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
@@ -12,33 +11,52 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-var validSchema = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
-
-// reservedSchemas that must never be used as tenant identifiers.
-var reservedSchemas = map[string]bool{
-	"public": true, "pg_catalog": true, "information_schema": true,
-	"pg_toast": true, "_template": true,
-}
-
 // TenantUnaryInterceptor extracts tenant from gRPC metadata and sets it in context.
 func TenantUnaryInterceptor() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
-		md, ok := metadata.FromIncomingContext(ctx)
-		if !ok {
-			return nil, status.Error(codes.InvalidArgument, "missing metadata")
+		ctx, err := withTenant(ctx)
+		if err != nil {
+			return nil, err
 		}
+		return handler(ctx, req)
+	}
+}
 
-		tenantIDs := md.Get("x-tenant-id")
-		if len(tenantIDs) == 0 || tenantIDs[0] == "" {
-			return nil, status.Error(codes.InvalidArgument, "missing x-tenant-id in metadata")
+// TenantStreamInterceptor is the streaming counterpart of TenantUnaryInterceptor.
+func TenantStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := withTenant(ss.Context())
+		if err != nil {
+			return err
 		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
 
-		schema := strings.ReplaceAll(tenantIDs[0], "-", "_")
-		if !validSchema.MatchString(schema) || reservedSchemas[schema] {
-			return nil, status.Error(codes.InvalidArgument, "invalid tenant identifier")
-		}
+func withTenant(ctx context.Context) (context.Context, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "missing metadata")
+	}
 
-		ctx = tenant.WithTenant(ctx, schema)
-		return handler(ctx, req)
+	tenantIDs := md.Get("x-tenant-id")
+	if len(tenantIDs) == 0 || tenantIDs[0] == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing x-tenant-id in metadata")
+	}
+
+	schema := strings.ReplaceAll(tenantIDs[0], "-", "_")
+	if tenant.ValidateSchema(schema) != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid tenant identifier")
 	}
+
+	return tenant.WithTenant(ctx, schema), nil
+}
+
+// contextStream overrides a ServerStream's context so values set by
+// interceptors reach the handler.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
 }
+
+func (s *contextStream) Context() context.Context { return s.ctx }
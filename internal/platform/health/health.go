@@ -0,0 +1,148 @@
+// Package health aggregates dependency checks into readiness and detail reports.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	pkgmod "github.com/HuynhHoangPhuc/mcs-erp/pkg/module"
+)
+
+// Status is the outcome of one check or of the whole report.
+type Status string
+
+const (
+	StatusOK       Status = "ok"
+	StatusDegraded Status = "degraded"
+	StatusFailed   Status = "failed"
+)
+
+// Result is the outcome of a single check.
+type Result struct {
+	Module     string `json:"module"`
+	Name       string `json:"name"`
+	Status     Status `json:"status"`
+	Critical   bool   `json:"critical"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// Report is the aggregated outcome: failed if any critical check failed,
+// degraded if any other check failed, ok otherwise.
+type Report struct {
+	Status Status   `json:"status"`
+	Checks []Result `json:"checks"`
+}
+
+type entry struct {
+	module string
+	check  pkgmod.HealthCheck
+}
+
+// Checker runs registered checks concurrently, each bounded by a timeout.
+type Checker struct {
+	timeout time.Duration
+
+	mu     sync.RWMutex
+	checks []entry
+}
+
+// NewChecker creates a checker whose checks each get at most timeout.
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+// Add registers checks contributed by module.
+func (c *Checker) Add(module string, checks ...pkgmod.HealthCheck) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, ch := range checks {
+		c.checks = append(c.checks, entry{module: module, check: ch})
+	}
+}
+
+// Run executes every check and aggregates the results in registration order.
+func (c *Checker) Run(ctx context.Context) Report {
+	c.mu.RLock()
+	checks := c.checks
+	c.mu.RUnlock()
+
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i, e := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.run(ctx, e)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: results}
+	for _, r := range results {
+		switch {
+		case r.Status == StatusFailed:
+			report.Status = StatusFailed
+		case r.Status == StatusDegraded && report.Status == StatusOK:
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, e entry) Result {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := e.check.Check(ctx)
+	res := Result{
+		Module:     e.module,
+		Name:       e.check.Name,
+		Status:     StatusOK,
+		Critical:   e.check.Critical,
+		DurationMS: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		res.Error = err.Error()
+		res.Status = StatusDegraded
+		if e.check.Critical {
+			res.Status = StatusFailed
+		}
+	}
+	return res
+}
+
+// ReadyHandler serves /readyz: 503 when a critical check fails, 200 otherwise.
+func (c *Checker) ReadyHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := c.Run(r.Context())
+		writeJSON(w, httpStatus(report.Status), map[string]Status{"status": report.Status})
+	})
+}
+
+// DetailsHandler serves /healthz/details with every check result. Error
+// messages are included, so mount it behind operator authentication.
+func (c *Checker) DetailsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := c.Run(r.Context())
+		writeJSON(w, httpStatus(report.Status), report)
+	})
+}
+
+func httpStatus(s Status) int {
+	if s == StatusFailed {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusOK
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
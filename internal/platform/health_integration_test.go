@@ -0,0 +1,105 @@
+//go:build integration
+
+package platform_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	coredelivery "github.com/HuynhHoangPhuc/mcs-erp/internal/core/delivery"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/health"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/testutil"
+	pkgmod "github.com/HuynhHoangPhuc/mcs-erp/pkg/module"
+)
+
+func TestHealth_ReadyzAndDetails(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	srv := testutil.TestServer(t, db.Pool)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/readyz")
+	if err != nil {
+		t.Fatalf("readyz: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected readyz 200, got %d", resp.StatusCode)
+	}
+
+	// Details carry error text, so they need the platform token.
+	resp, err = http.Get(srv.URL + "/healthz/details")
+	if err != nil {
+		t.Fatalf("healthz details: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected healthz details without a token to be 401, got %d", resp.StatusCode)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/healthz/details", nil)
+	req.Header.Set(coredelivery.PlatformTokenHeader, testutil.TestPlatformToken)
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("healthz details: %v", err)
+	}
+	defer resp.Body.Close()
+	var report health.Report
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		t.Fatalf("decode report: %v", err)
+	}
+
+	byName := map[string]health.Result{}
+	for _, r := range report.Checks {
+		byName[r.Module+"/"+r.Name] = r
+	}
+	for _, name := range []string{"platform/postgres", "core/tenant_migrations", "agent/llm_provider", "agent/redis_cache"} {
+		if _, ok := byName[name]; !ok {
+			t.Fatalf("expected check %s in report, got %+v", name, report.Checks)
+		}
+	}
+	if r := byName["platform/postgres"]; r.Status != health.StatusOK || !r.Critical {
+		t.Fatalf("expected critical postgres check ok, got %+v", r)
+	}
+	if r := byName["agent/llm_provider"]; r.Status != health.StatusOK {
+		t.Fatalf("expected llm provider ok, got %+v", r)
+	}
+}
+
+func TestHealth_CriticalFailureFailsReadiness(t *testing.T) {
+	checker := health.NewChecker(50 * time.Millisecond)
+	checker.Add("platform",
+		pkgmod.HealthCheck{Name: "ok", Critical: true, Check: func(context.Context) error { return nil }},
+		pkgmod.HealthCheck{Name: "cache", Check: func(context.Context) error { return errors.New("down") }},
+	)
+
+	rec := httptest.NewRecorder()
+	checker.ReadyHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("non-critical failure should keep readiness, got %d", rec.Code)
+	}
+	if report := checker.Run(context.Background()); report.Status != health.StatusDegraded {
+		t.Fatalf("expected degraded, got %s", report.Status)
+	}
+
+	checker.Add("platform", pkgmod.HealthCheck{Name: "slow", Critical: true, Check: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}})
+	rec = httptest.NewRecorder()
+	checker.DetailsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz/details", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("critical timeout should fail readiness, got %d", rec.Code)
+	}
+	var report health.Report
+	if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+		t.Fatalf("decode report: %v", err)
+	}
+	if report.Status != health.StatusFailed || report.Checks[2].Status != health.StatusFailed || report.Checks[1].Status != health.StatusDegraded {
+		t.Fatalf("unexpected report %+v", report)
+	}
+}
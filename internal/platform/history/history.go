@@ -0,0 +1,70 @@
+// Package history reads entity versions kept in the tenant's entity_history
+// table. Versions are written by the record_entity_version trigger
+// (migrations/platform), which modules attach to the tables they version; each
+// version holds the full row as JSON and the period [valid_from, valid_to) in
+// which it was current.
+//
+// Repositories query the past with the same column lists they use for the
+// live table by swapping the table name for AsOf or Versions, e.g.
+//
+//	"SELECT id, name FROM " + history.AsOf("teachers", 2) + " WHERE id = $1"
+package history
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Version is one state of an entity. ValidTo is nil while the version is
+// current; a deleted entity's last version has ValidTo set.
+type Version[T any] struct {
+	ValidFrom time.Time  `json:"valid_from"`
+	ValidTo   *time.Time `json:"valid_to"`
+	Data      T          `json:"data"`
+}
+
+// List is the body of history endpoints.
+type List[T any] struct {
+	Items []Version[T] `json:"items"`
+}
+
+// AsOf returns a FROM item standing in for table (same name, same columns)
+// holding the rows that were current at the timestamp bound to $param.
+// table must be a constant table name, never user input.
+func AsOf(table string, param int) string {
+	return fmt.Sprintf(`(SELECT r.* FROM entity_history h, jsonb_populate_record(NULL::%[1]s, h.data) r
+		WHERE h.entity_type = '%[1]s' AND h.valid @> $%[2]d::timestamptz) AS %[1]s`, table, param)
+}
+
+// Versions returns a FROM item with the table's columns plus valid_from and
+// valid_to, holding every version of the entity whose id (as text) is bound
+// to $param. table must be a constant table name, never user input.
+func Versions(table string, param int) string {
+	return fmt.Sprintf(`(SELECT lower(h.valid) AS valid_from, upper(h.valid) AS valid_to, r.*
+		FROM entity_history h, jsonb_populate_record(NULL::%[1]s, h.data) r
+		WHERE h.entity_type = '%[1]s' AND h.entity_id = $%[2]d) AS %[1]s`, table, param)
+}
+
+// Map converts the data of each version, e.g. to a handler's response shape.
+func Map[T, R any](versions []Version[T], f func(T) R) []Version[R] {
+	out := make([]Version[R], len(versions))
+	for i, v := range versions {
+		out[i] = Version[R]{ValidFrom: v.ValidFrom, ValidTo: v.ValidTo, Data: f(v.Data)}
+	}
+	return out
+}
+
+// ParseAsOf reads the as_of query parameter (RFC 3339). It returns nil when
+// the parameter is absent, meaning "current state".
+func ParseAsOf(q url.Values) (*time.Time, error) {
+	v := q.Get("as_of")
+	if v == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid as_of: use RFC 3339, e.g. 2025-03-01T08:00:00Z")
+	}
+	return &t, nil
+}
@@ -0,0 +1,198 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/auth"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/problem"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
+	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
+)
+
+// Header names.
+const (
+	Header         = "Idempotency-Key"
+	ReplayedHeader = "Idempotent-Replayed"
+)
+
+// maxStoredBody caps the response kept for replay; larger responses are not
+// stored and the key is released once they finish.
+const maxStoredBody = 1 << 20
+
+// notReplayed are response headers that belong to one exchange, not to the result.
+var notReplayed = []string{"Date", "X-Request-Id", "Content-Length"}
+
+// TokenValidator resolves a bearer token to its claims; core's AuthService implements it.
+type TokenValidator func(token string) (*auth.Claims, error)
+
+// Middleware honours Idempotency-Key on POST, PUT and PATCH requests from
+// authenticated users. The first request with a key runs normally and its
+// response is stored; a retry with the same method, path and body gets that
+// response again (with Idempotent-Replayed: true). Reusing a key for a
+// different request is 422; a retry while the first is still running is 409.
+// 5xx responses are not stored, so those requests can be retried.
+//
+// It must run after tenant resolution. Requests without a key, tenant or
+// valid token pass through untouched (authentication still rejects them).
+func Middleware(store *Store, validate TokenValidator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(Header)
+			if key == "" || !mutating(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if _, err := tenant.FromContext(r.Context()); err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			claims, err := validate(token)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !validKey(key) {
+				problem.Write(w, r, erptypes.InvalidRequest("Idempotency-Key must be 1-255 printable ASCII characters"))
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					problem.Write(w, r, erptypes.New(erptypes.CodeTooLarge, "request body too large"))
+					return
+				}
+				problem.Write(w, r, erptypes.InvalidRequest("failed to read request body"))
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			ctx := r.Context()
+			fp := fingerprint(r, body)
+			rec, err := store.Begin(ctx, claims.UserID, key, fp, r.Method, r.URL.Path)
+			if err != nil {
+				problem.Write(w, r, erptypes.Internal("internal error", fmt.Errorf("idempotency begin: %w", err)))
+				return
+			}
+			if rec != nil {
+				replay(w, r, rec, fp)
+				return
+			}
+
+			rw := &recorder{ResponseWriter: w}
+			// Store or release even if the client went away or the handler panicked.
+			saveCtx := context.WithoutCancel(ctx)
+			defer func() {
+				if rw.status >= 200 && rw.status < 500 && !rw.skip {
+					err := store.Complete(saveCtx, claims.UserID, key, rw.status, rw.header, rw.body.Bytes())
+					if err == nil {
+						return
+					}
+					slog.Error("idempotency complete failed", "error", err)
+				}
+				if err := store.Release(saveCtx, claims.UserID, key); err != nil {
+					slog.Error("idempotency release failed", "error", err)
+				}
+			}()
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
+
+func replay(w http.ResponseWriter, r *http.Request, rec *Record, fp string) {
+	switch {
+	case rec.Fingerprint != fp:
+		problem.Write(w, r, erptypes.New(erptypes.CodeUnprocessable, "Idempotency-Key was already used for a different request"))
+	case !rec.Completed:
+		problem.Write(w, r, erptypes.Conflict("a request with this Idempotency-Key is still in progress"))
+	default:
+		for k, v := range rec.Header {
+			w.Header()[k] = v
+		}
+		w.Header().Set(ReplayedHeader, "true")
+		w.WriteHeader(rec.Status)
+		w.Write(rec.Body)
+	}
+}
+
+func mutating(method string) bool {
+	return method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch
+}
+
+func validKey(key string) bool {
+	if len(key) > 255 {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x20 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// fingerprint identifies the request a key was first used for.
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.RequestURI()+"\n")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// recorder passes the response through while keeping a copy for replay.
+// Streamed (flushed) responses such as SSE are never stored.
+type recorder struct {
+	http.ResponseWriter
+	status int
+	header http.Header
+	body   bytes.Buffer
+	skip   bool
+}
+
+func (r *recorder) WriteHeader(code int) {
+	if r.status == 0 {
+		r.status = code
+		r.header = r.ResponseWriter.Header().Clone()
+		for _, h := range notReplayed {
+			r.header.Del(h)
+		}
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.WriteHeader(http.StatusOK)
+	}
+	if !r.skip {
+		if r.body.Len()+len(b) > maxStoredBody {
+			r.skip = true
+			r.body.Reset()
+		} else {
+			r.body.Write(b)
+		}
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *recorder) Flush() {
+	r.skip = true
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,145 @@
+// Package idempotency makes retried POST, PUT and PATCH requests safe: a
+// request carrying an Idempotency-Key header is executed once per tenant, user
+// and key, and retries get the stored response replayed.
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/database"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
+)
+
+// staleAfter is how long a key may stay in progress before it is presumed
+// abandoned (the server died mid-request) and may be claimed again.
+const staleAfter = 5 * time.Minute
+
+// Record is the stored state of a key that another request already claimed.
+type Record struct {
+	Fingerprint string
+	Completed   bool
+	Status      int
+	Header      http.Header
+	Body        []byte
+}
+
+// Store keeps idempotency keys in the tenant's idempotency_keys table.
+type Store struct {
+	pool *pgxpool.Pool
+	ttl  time.Duration
+}
+
+// NewStore creates a key store; completed responses are replayed for ttl.
+func NewStore(pool *pgxpool.Pool, ttl time.Duration) *Store {
+	return &Store{pool: pool, ttl: ttl}
+}
+
+// Begin claims key for the request. It returns nil when the caller now owns
+// the key and must finish with Complete or Release, otherwise the existing
+// record. Expired and abandoned keys are claimed afresh.
+func (s *Store) Begin(ctx context.Context, userID uuid.UUID, key, fingerprint, method, path string) (*Record, error) {
+	schema, err := tenant.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var rec *Record
+	err = database.WithTenantTx(ctx, s.pool, schema, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx,
+			`DELETE FROM idempotency_keys
+			 WHERE user_id = $1 AND (expires_at < now()
+			    OR (key = $2 AND status = 'in_progress' AND created_at < now() - make_interval(secs => $3)))`,
+			userID, key, staleAfter.Seconds(),
+		); err != nil {
+			return err
+		}
+
+		tag, err := tx.Exec(ctx,
+			`INSERT INTO idempotency_keys (user_id, key, fingerprint, method, path, expires_at)
+			 VALUES ($1, $2, $3, $4, $5, now() + make_interval(secs => $6))
+			 ON CONFLICT (user_id, key) DO NOTHING`,
+			userID, key, fingerprint, method, path, s.ttl.Seconds(),
+		)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 1 {
+			return nil
+		}
+
+		var (
+			r       Record
+			state   string
+			status  *int
+			headers []byte
+		)
+		if err := tx.QueryRow(ctx,
+			`SELECT fingerprint, status, response_status, response_headers, response_body
+			 FROM idempotency_keys WHERE user_id = $1 AND key = $2`,
+			userID, key,
+		).Scan(&r.Fingerprint, &state, &status, &headers, &r.Body); err != nil {
+			return err
+		}
+		r.Completed = state == "completed"
+		if status != nil {
+			r.Status = *status
+		}
+		if headers != nil {
+			if err := json.Unmarshal(headers, &r.Header); err != nil {
+				return fmt.Errorf("decode stored headers: %w", err)
+			}
+		}
+		rec = &r
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// The competing request released the key between our insert and select.
+			return s.Begin(ctx, userID, key, fingerprint, method, path)
+		}
+		return nil, fmt.Errorf("claim idempotency key: %w", err)
+	}
+	return rec, nil
+}
+
+// Complete stores the response for replay.
+func (s *Store) Complete(ctx context.Context, userID uuid.UUID, key string, status int, header http.Header, body []byte) error {
+	schema, err := tenant.FromContext(ctx)
+	if err != nil {
+		return err
+	}
+	headers, err := json.Marshal(header)
+	if err != nil {
+		return fmt.Errorf("encode headers: %w", err)
+	}
+	return database.WithTenantTx(ctx, s.pool, schema, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx,
+			`UPDATE idempotency_keys
+			 SET status = 'completed', response_status = $3, response_headers = $4, response_body = $5
+			 WHERE user_id = $1 AND key = $2`,
+			userID, key, status, headers, body,
+		)
+		return err
+	})
+}
+
+// Release forgets a key whose request failed, so the client may retry it.
+func (s *Store) Release(ctx context.Context, userID uuid.UUID, key string) error {
+	schema, err := tenant.FromContext(ctx)
+	if err != nil {
+		return err
+	}
+	return database.WithTenantTx(ctx, s.pool, schema, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `DELETE FROM idempotency_keys WHERE user_id = $1 AND key = $2`, userID, key)
+		return err
+	})
+}
@@ -0,0 +1,205 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/database"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
+)
+
+// Schedule is a parsed cron expression, evaluated in UTC.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64 // bit i set when value i matches
+	domAny, dowAny                bool   // field was *, for the day-of-month/day-of-week rule
+}
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseCron parses a standard five-field cron expression
+//
+//	minute hour day-of-month month day-of-week
+//
+// Each field is *, a number, a range a-b, a step */n or a-b/n, or a comma
+// separated list of those; day-of-week runs 0-6 from Sunday (7 is Sunday
+// too). As in cron, when both day fields are restricted either may match.
+// @hourly, @daily, @weekly, @monthly and @yearly are accepted.
+func ParseCron(spec string) (*Schedule, error) {
+	if d, ok := cronDescriptors[spec]; ok {
+		spec = d
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron %q: expected 5 fields, got %d", spec, len(fields))
+	}
+	var s Schedule
+	var err error
+	for i, f := range []struct {
+		dst      *uint64
+		min, max int
+	}{{&s.minute, 0, 59}, {&s.hour, 0, 23}, {&s.dom, 1, 31}, {&s.month, 1, 12}, {&s.dow, 0, 7}} {
+		if *f.dst, err = parseCronField(fields[i], f.min, f.max); err != nil {
+			return nil, fmt.Errorf("cron %q: field %d: %w", spec, i+1, err)
+		}
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	s.domAny, s.dowAny = fields[2] == "*", fields[4] == "*"
+	return &s, nil
+}
+
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, stepText, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepText)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid step %q", stepText)
+			}
+			step = n
+		}
+
+		lo, hi := min, max
+		if rng != "*" {
+			a, b, isRange := strings.Cut(rng, "-")
+			var err error
+			if lo, err = strconv.Atoi(a); err != nil {
+				return 0, fmt.Errorf("invalid value %q", a)
+			}
+			hi = lo
+			if isRange {
+				if hi, err = strconv.Atoi(b); err != nil {
+					return 0, fmt.Errorf("invalid value %q", b)
+				}
+			} else if hasStep {
+				hi = max // a/n runs from a to the end
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q is outside %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+// Next returns the first time after t the schedule matches, or the zero time
+// if it never does (e.g. February 30th).
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+	for limit := t.AddDate(5, 0, 0); t.Before(limit); {
+		switch {
+		case s.month&(1<<int(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+		case s.hour&(1<<t.Hour()) == 0:
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case s.minute&(1<<t.Minute()) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<t.Day()) != 0
+	dow := s.dow&(1<<int(t.Weekday())) != 0
+	if s.domAny || s.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// cronEntry is a kind enqueued on a schedule in every tenant.
+type cronEntry struct {
+	kind     string
+	spec     string
+	schedule *Schedule
+	payload  any
+}
+
+// Cron enqueues a job of kind with payload in every active tenant whenever
+// spec (see ParseCron) matches. Each occurrence is enqueued ahead of time
+// under a unique key, so replicas do not duplicate it. The kind must be
+// registered; call Cron before Start.
+func (q *Queue) Cron(kind, spec string, payload any) error {
+	s, err := ParseCron(spec)
+	if err != nil {
+		return err
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, ok := q.handlers[kind]; !ok {
+		return fmt.Errorf("cron: unknown job kind %q", kind)
+	}
+	q.crons = append(q.crons, &cronEntry{kind: kind, spec: spec, schedule: s, payload: payload})
+	return nil
+}
+
+// scheduleCrons enqueues the next occurrence of every cron entry in each tenant.
+func (q *Queue) scheduleCrons(ctx context.Context, schemas []string, now time.Time) {
+	q.mu.Lock()
+	crons := q.crons
+	q.mu.Unlock()
+	for _, c := range crons {
+		next := c.schedule.Next(now)
+		if next.IsZero() {
+			continue
+		}
+		key := fmt.Sprintf("cron:%s:%d", c.kind, next.Unix())
+		for _, schema := range schemas {
+			if _, err := q.Enqueue(tenant.WithTenant(ctx, schema), c.kind, c.payload, At(next), Unique(key)); err != nil && ctx.Err() == nil {
+				slog.Error("job queue: schedule cron failed", "schema", schema, "kind", c.kind, "cron", c.spec, "error", err)
+			}
+		}
+	}
+}
+
+// purgeKind deletes finished jobs older than Config.Retention, daily per tenant.
+const purgeKind = "jobs.purge"
+
+func registerPurge(q *Queue) {
+	Register(q, Definition[struct{}]{
+		Kind: purgeKind,
+		Run: func(ctx context.Context, _ *Job, _ struct{}) (any, error) {
+			schema, err := tenant.FromContext(ctx)
+			if err != nil {
+				return nil, err
+			}
+			var deleted int64
+			err = database.WithTenantTx(ctx, q.pool, schema, func(tx pgx.Tx) error {
+				tag, err := tx.Exec(ctx,
+					`DELETE FROM jobs WHERE finished_at < now() - make_interval(secs => $1)`,
+					q.cfg.Retention.Seconds())
+				deleted = tag.RowsAffected()
+				return err
+			})
+			return map[string]int64{"deleted": deleted}, err
+		},
+	})
+	if err := q.Cron(purgeKind, "@daily", struct{}{}); err != nil {
+		panic(err)
+	}
+}
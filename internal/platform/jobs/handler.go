@@ -0,0 +1,129 @@
+package jobs
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/auth"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/problem"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/validate"
+	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
+)
+
+// Path is where the job status API is mounted; WriteAccepted points clients
+// at Path/{id}.
+const Path = "/api/v1/jobs"
+
+// Handler serves the job status API. Users see and cancel the jobs they
+// submitted; holders of the read-all and cancel-all permissions those of
+// everyone in the tenant. Mount it behind authentication.
+type Handler struct {
+	queue     *Queue
+	readAll   string
+	cancelAll string
+}
+
+// NewHandler creates the job status handler.
+func NewHandler(queue *Queue, readAllPerm, cancelAllPerm string) *Handler {
+	return &Handler{queue: queue, readAll: readAllPerm, cancelAll: cancelAllPerm}
+}
+
+// List handles GET /api/v1/jobs with the listquery syntax over ListSchema.
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.UserFromContext(r.Context())
+	if err != nil {
+		problem.Write(w, r, erptypes.Unauthorized("unauthorized"))
+		return
+	}
+	spec, err := listquery.Parse(r.URL.Query(), ListSchema)
+	if err != nil {
+		problem.Write(w, r, validate.Query(err))
+		return
+	}
+	if !slices.Contains(claims.Permissions, h.readAll) {
+		spec = spec.Where("created_by", claims.UserID.String())
+	}
+
+	page, err := h.queue.List(r.Context(), spec)
+	if err != nil {
+		problem.Write(w, r, erptypes.Internal("failed to list jobs", err))
+		return
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+// Get handles GET /api/v1/jobs/{id}
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	job, ok := h.load(w, r, h.readAll)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
+
+// Cancel handles POST /api/v1/jobs/{id}/cancel. A running job stops at its
+// next heartbeat, so the response may still show it running with
+// cancel_requested set.
+func (h *Handler) Cancel(w http.ResponseWriter, r *http.Request) {
+	job, ok := h.load(w, r, h.cancelAll)
+	if !ok {
+		return
+	}
+	job, err := h.queue.Cancel(r.Context(), job.ID)
+	switch {
+	case errors.Is(err, erptypes.ErrNotFound):
+		problem.Write(w, r, erptypes.NotFound("job not found"))
+	case errors.Is(err, erptypes.ErrConflict):
+		problem.Write(w, r, err)
+	case err != nil:
+		problem.Write(w, r, erptypes.Internal("failed to cancel job", err))
+	default:
+		writeJSON(w, http.StatusOK, job)
+	}
+}
+
+// load returns the job named by the path if the caller submitted it or holds
+// perm. Other users' jobs are reported as not found.
+func (h *Handler) load(w http.ResponseWriter, r *http.Request, perm string) (*Job, bool) {
+	claims, err := auth.UserFromContext(r.Context())
+	if err != nil {
+		problem.Write(w, r, erptypes.Unauthorized("unauthorized"))
+		return nil, false
+	}
+	id, err := validate.PathUUID(r, "id")
+	if err != nil {
+		problem.Write(w, r, err)
+		return nil, false
+	}
+	job, err := h.queue.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, erptypes.ErrNotFound) {
+			problem.Write(w, r, erptypes.NotFound("job not found"))
+			return nil, false
+		}
+		problem.Write(w, r, erptypes.Internal("failed to load job", err))
+		return nil, false
+	}
+	own := job.CreatedBy != nil && *job.CreatedBy == claims.UserID
+	if !own && !slices.Contains(claims.Permissions, perm) {
+		problem.Write(w, r, erptypes.NotFound("job not found"))
+		return nil, false
+	}
+	return job, true
+}
+
+// WriteAccepted answers a request whose work was handed to the queue:
+// 202 Accepted with the job, and its status URL in Location.
+func WriteAccepted(w http.ResponseWriter, job *Job) {
+	w.Header().Set("Location", Path+"/"+job.ID.String())
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,181 @@
+// Package jobs is the platform's background job queue. Jobs live in each
+// tenant's jobs table (migrations/platform) and run on any replica: workers
+// claim due rows with FOR UPDATE SKIP LOCKED and hold a lease they renew while
+// the job runs, so a crashed replica's jobs are picked up again once the
+// lease expires.
+//
+// Modules Register a typed Definition per job kind and Enqueue work from
+// their handlers, answering 202 Accepted with WriteAccepted; clients follow
+// the job at /api/v1/jobs/{id}. Failed attempts are retried with exponential
+// backoff up to the definition's MaxAttempts; Cron enqueues a kind in every
+// tenant on a schedule. Delivery is at-least-once: a job may run again after
+// a crash, so handlers must be idempotent.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status is where a job is in its lifecycle.
+type Status string
+
+const (
+	StatusQueued    Status = "queued"  // waiting for run_at, or for a retry
+	StatusRunning   Status = "running" // claimed by a worker
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed" // out of attempts, or a permanent error
+	StatusCancelled Status = "cancelled"
+)
+
+// Finished reports whether the job will not run again.
+func (s Status) Finished() bool {
+	return s == StatusSucceeded || s == StatusFailed || s == StatusCancelled
+}
+
+// Job is one unit of background work and its outcome.
+type Job struct {
+	ID              uuid.UUID       `json:"id"`
+	Kind            string          `json:"kind"`
+	Status          Status          `json:"status"`
+	Payload         json.RawMessage `json:"payload"`
+	Result          json.RawMessage `json:"result,omitempty"` // set once succeeded
+	Error           *string         `json:"error,omitempty"`  // of the last failed attempt
+	Attempts        int             `json:"attempts"`
+	MaxAttempts     int             `json:"max_attempts"`
+	RunAt           time.Time       `json:"run_at"` // earliest start of the next attempt
+	CancelRequested bool            `json:"cancel_requested"`
+	CreatedBy       *uuid.UUID      `json:"created_by,omitempty"`
+	RequestID       *string         `json:"request_id,omitempty"`
+	CreatedAt       time.Time       `json:"created_at"`
+	StartedAt       *time.Time      `json:"started_at,omitempty"`
+	FinishedAt      *time.Time      `json:"finished_at,omitempty"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+
+	createdByEmail *string
+}
+
+// Definition describes how to run one kind of job with payload T.
+type Definition[T any] struct {
+	// Kind names the job, prefixed by the module, e.g. "timetable.generate".
+	Kind string
+	// MaxAttempts bounds the runs of one job, the first included (default 3).
+	MaxAttempts int
+	// Timeout bounds one attempt (default 10 minutes).
+	Timeout time.Duration
+	// Backoff is the delay before the next attempt after the given number of
+	// failed ones (default 10s doubling per attempt, at most an hour).
+	Backoff func(attempts int) time.Duration
+	// Run does the work. It runs with the submitter's tenant, user and
+	// request ID in ctx, and must return promptly once ctx is done: the job
+	// was cancelled, timed out or the server is stopping. The result is
+	// stored as JSON and returned by the job status API.
+	Run func(ctx context.Context, job *Job, payload T) (any, error)
+}
+
+// Defaults applied when a definition leaves them unset.
+const (
+	DefaultMaxAttempts = 3
+	DefaultTimeout     = 10 * time.Minute
+)
+
+// DefaultBackoff waits 10s after the first failure, doubling up to an hour.
+func DefaultBackoff(attempts int) time.Duration {
+	d := 10 * time.Second
+	for i := 1; i < attempts && d < time.Hour; i++ {
+		d *= 2
+	}
+	return min(d, time.Hour)
+}
+
+// handler is a Definition with its payload type erased.
+type handler struct {
+	maxAttempts int
+	timeout     time.Duration
+	backoff     func(int) time.Duration
+	run         func(ctx context.Context, job *Job) (any, error)
+}
+
+// Register adds a job kind to the queue. It panics if the kind is already
+// registered or has no Run, like http.ServeMux does for duplicate patterns:
+// both are wiring mistakes. Register before Start.
+func Register[T any](q *Queue, def Definition[T]) {
+	if def.Kind == "" || def.Run == nil {
+		panic("jobs: definition needs a Kind and Run")
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, dup := q.handlers[def.Kind]; dup {
+		panic(fmt.Sprintf("jobs: kind %q registered twice", def.Kind))
+	}
+	h := &handler{
+		maxAttempts: def.MaxAttempts,
+		timeout:     def.Timeout,
+		backoff:     def.Backoff,
+		run: func(ctx context.Context, job *Job) (any, error) {
+			var payload T
+			if err := json.Unmarshal(job.Payload, &payload); err != nil {
+				return nil, Permanent(fmt.Errorf("decode %s payload: %w", job.Kind, err))
+			}
+			return def.Run(ctx, job, payload)
+		},
+	}
+	if h.maxAttempts <= 0 {
+		h.maxAttempts = DefaultMaxAttempts
+	}
+	if h.timeout <= 0 {
+		h.timeout = DefaultTimeout
+	}
+	if h.backoff == nil {
+		h.backoff = DefaultBackoff
+	}
+	q.handlers[def.Kind] = h
+}
+
+// permanentError marks a failure that retrying cannot fix.
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so the job fails at once instead of being retried,
+// e.g. when the entity it works on no longer exists.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanentError{err}
+}
+
+// IsPermanent reports whether err was wrapped by Permanent.
+func IsPermanent(err error) bool {
+	var p permanentError
+	return errors.As(err, &p)
+}
+
+// Option adjusts a job at Enqueue.
+type Option func(*enqueueOptions)
+
+type enqueueOptions struct {
+	runAt       time.Time
+	maxAttempts int
+	uniqueKey   string
+}
+
+// At delays the first attempt until t.
+func At(t time.Time) Option { return func(o *enqueueOptions) { o.runAt = t } }
+
+// After delays the first attempt by d.
+func After(d time.Duration) Option { return At(time.Now().Add(d)) }
+
+// MaxAttempts overrides the definition's attempt limit for this job.
+func MaxAttempts(n int) Option { return func(o *enqueueOptions) { o.maxAttempts = n } }
+
+// Unique deduplicates submissions: while a job with the same key is queued or
+// running, Enqueue returns that job instead of adding another.
+func Unique(key string) Option { return func(o *enqueueOptions) { o.uniqueKey = key } }
@@ -0,0 +1,269 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/auth"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/database"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/requestinfo"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
+	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
+)
+
+// Config tunes the workers of one replica. Zero fields take the defaults.
+type Config struct {
+	Workers      int           // jobs run concurrently by this replica (default 4)
+	PollInterval time.Duration // how often tenants are checked for due jobs (default 1s)
+	Lease        time.Duration // a running job is reclaimed this long after its last heartbeat (default 30s)
+	Retention    time.Duration // finished jobs are purged after this long (default 7 days)
+}
+
+func (c Config) withDefaults() Config {
+	if c.Workers <= 0 {
+		c.Workers = 4
+	}
+	if c.PollInterval <= 0 {
+		c.PollInterval = time.Second
+	}
+	if c.Lease <= 0 {
+		c.Lease = 30 * time.Second
+	}
+	if c.Retention <= 0 {
+		c.Retention = 7 * 24 * time.Hour
+	}
+	return c
+}
+
+// ListSchema declares how jobs can be filtered and sorted.
+var ListSchema = &listquery.Schema{
+	Fields: map[string]listquery.Field{
+		"id":         {Kind: listquery.UUID, Filter: listquery.Eq | listquery.In, Sort: true},
+		"kind":       {Kind: listquery.Text, Filter: listquery.Eq | listquery.In},
+		"status":     {Kind: listquery.Text, Filter: listquery.Eq | listquery.In},
+		"created_by": {Kind: listquery.UUID, Filter: listquery.Eq},
+		"run_at":     {Kind: listquery.Time, Filter: listquery.Range, Sort: true},
+		"created_at": {Kind: listquery.Time, Filter: listquery.Range, Sort: true},
+	},
+	Key:         "id",
+	DefaultSort: []listquery.Sort{{Field: "created_at", Desc: true}},
+}
+
+const jobColumns = `id, kind, status, payload, result, last_error, attempts, max_attempts, run_at,
+	cancel_requested, created_by, created_by_email, request_id, created_at, started_at, finished_at, updated_at`
+
+// Queue submits jobs to the tenant's jobs table and runs the registered kinds.
+type Queue struct {
+	pool     *pgxpool.Pool
+	cfg      Config
+	workerID string
+
+	mu       sync.Mutex
+	handlers map[string]*handler
+	crons    []*cronEntry
+
+	wake    chan struct{} // nudges the dispatcher after a local Enqueue
+	slots   chan struct{} // one token per running job
+	stop    context.CancelCauseFunc
+	done    chan struct{}
+	running sync.WaitGroup
+}
+
+// NewQueue creates a queue. Register job kinds, then Start it.
+func NewQueue(pool *pgxpool.Pool, cfg Config) *Queue {
+	cfg = cfg.withDefaults()
+	q := &Queue{
+		pool:     pool,
+		cfg:      cfg,
+		workerID: workerID(),
+		handlers: map[string]*handler{},
+		wake:     make(chan struct{}, 1),
+		slots:    make(chan struct{}, cfg.Workers),
+	}
+	registerPurge(q)
+	return q
+}
+
+// Enqueue submits a job of a registered kind for the tenant in ctx. payload
+// is stored as JSON. The submitting user and request ID are recorded and
+// restored in the job's context. With Unique, an unfinished job holding the
+// same key is returned instead of a new one.
+func (q *Queue) Enqueue(ctx context.Context, kind string, payload any, opts ...Option) (*Job, error) {
+	schema, err := tenant.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	q.mu.Lock()
+	h, ok := q.handlers[kind]
+	q.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("enqueue: unknown job kind %q", kind)
+	}
+
+	o := enqueueOptions{maxAttempts: h.maxAttempts}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.maxAttempts <= 0 {
+		o.maxAttempts = h.maxAttempts
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("enqueue %s: encode payload: %w", kind, err)
+	}
+
+	var createdBy *uuid.UUID
+	var email, requestID, uniqueKey *string
+	if claims, err := auth.UserFromContext(ctx); err == nil {
+		createdBy, email = &claims.UserID, &claims.Email
+	}
+	if id := requestinfo.FromContext(ctx).ID; id != "" {
+		requestID = &id
+	}
+	if o.uniqueKey != "" {
+		uniqueKey = &o.uniqueKey
+	}
+	var runAt *time.Time
+	if !o.runAt.IsZero() {
+		runAt = &o.runAt
+	}
+
+	var job *Job
+	err = database.WithTenantTx(ctx, q.pool, schema, func(tx pgx.Tx) error {
+		// A unique job may finish between the insert and the lookup; try again then.
+		for range 3 {
+			job, err = scanJob(tx.QueryRow(ctx,
+				`INSERT INTO jobs (id, kind, payload, max_attempts, run_at, unique_key, created_by, created_by_email, request_id)
+				 VALUES ($1, $2, $3, $4, COALESCE($5, now()), $6, $7, $8, $9)
+				 ON CONFLICT (unique_key) WHERE unique_key IS NOT NULL AND status IN ('queued', 'running') DO NOTHING
+				 RETURNING `+jobColumns,
+				uuid.New(), kind, raw, o.maxAttempts, runAt, uniqueKey, createdBy, email, requestID,
+			))
+			if !errors.Is(err, pgx.ErrNoRows) {
+				return err
+			}
+			job, err = scanJob(tx.QueryRow(ctx,
+				`SELECT `+jobColumns+` FROM jobs WHERE unique_key = $1 AND status IN ('queued', 'running')`, o.uniqueKey))
+			if !errors.Is(err, pgx.ErrNoRows) {
+				return err
+			}
+		}
+		return fmt.Errorf("job %q keeps finishing while being submitted again", o.uniqueKey)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("enqueue %s: %w", kind, err)
+	}
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return job, nil
+}
+
+// Get returns a job of the tenant in ctx, or erptypes.ErrNotFound.
+func (q *Queue) Get(ctx context.Context, id uuid.UUID) (*Job, error) {
+	schema, err := tenant.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var job *Job
+	err = database.WithTenantTx(ctx, q.pool, schema, func(tx pgx.Tx) error {
+		job, err = scanJob(tx.QueryRow(ctx, `SELECT `+jobColumns+` FROM jobs WHERE id = $1`, id))
+		return err
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, erptypes.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get job: %w", err)
+	}
+	return job, nil
+}
+
+// List returns one page of the tenant's jobs.
+func (q *Queue) List(ctx context.Context, spec listquery.Spec) (listquery.Page[*Job], error) {
+	schema, err := tenant.FromContext(ctx)
+	if err != nil {
+		return listquery.Page[*Job]{}, err
+	}
+	lq, err := listquery.Build(spec, ListSchema)
+	if err != nil {
+		return listquery.Page[*Job]{}, err
+	}
+
+	var page listquery.Page[*Job]
+	err = database.WithTenantTx(ctx, q.pool, schema, func(tx pgx.Tx) error {
+		page, err = listquery.Fetch(ctx, tx, lq, jobColumns, "jobs",
+			func(rows pgx.Rows, cursor *string) (*Job, error) {
+				var j Job
+				return &j, rows.Scan(append(j.fields(), cursor)...)
+			})
+		return err
+	})
+	if err != nil {
+		return listquery.Page[*Job]{}, fmt.Errorf("list jobs: %w", err)
+	}
+	return page, nil
+}
+
+// Cancel stops a job of the tenant in ctx. A queued job is cancelled at once;
+// a running one is asked to stop, which its worker notices at the next
+// heartbeat. Returns erptypes.ErrNotFound, or erptypes.ErrConflict when the
+// job has already finished.
+func (q *Queue) Cancel(ctx context.Context, id uuid.UUID) (*Job, error) {
+	schema, err := tenant.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var job *Job
+	err = database.WithTenantTx(ctx, q.pool, schema, func(tx pgx.Tx) error {
+		job, err = scanJob(tx.QueryRow(ctx,
+			`UPDATE jobs SET
+			   status = CASE WHEN status = 'queued' THEN 'cancelled' ELSE status END,
+			   finished_at = CASE WHEN status = 'queued' THEN now() ELSE finished_at END,
+			   cancel_requested = true, updated_at = now()
+			 WHERE id = $1 AND status IN ('queued', 'running')
+			 RETURNING `+jobColumns, id))
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+		job, err = scanJob(tx.QueryRow(ctx, `SELECT `+jobColumns+` FROM jobs WHERE id = $1`, id))
+		if err != nil {
+			return err
+		}
+		return erptypes.Newf(erptypes.CodeConflict, "job already %s", job.Status)
+	})
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, erptypes.ErrNotFound
+	case errors.Is(err, erptypes.ErrConflict):
+		return job, err
+	case err != nil:
+		return nil, fmt.Errorf("cancel job: %w", err)
+	}
+	return job, nil
+}
+
+// fields are the scan targets for jobColumns.
+func (j *Job) fields() []any {
+	return []any{&j.ID, &j.Kind, &j.Status, &j.Payload, &j.Result, &j.Error, &j.Attempts, &j.MaxAttempts, &j.RunAt,
+		&j.CancelRequested, &j.CreatedBy, &j.createdByEmail, &j.RequestID, &j.CreatedAt, &j.StartedAt, &j.FinishedAt, &j.UpdatedAt}
+}
+
+func scanJob(row pgx.Row) (*Job, error) {
+	var j Job
+	if err := row.Scan(j.fields()...); err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
@@ -0,0 +1,370 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime/debug"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/auth"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/database"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/metrics"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/requestinfo"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
+)
+
+// Causes a running job's context is cancelled with.
+var (
+	// ErrCancelled is the cause once the job was cancelled through the API.
+	ErrCancelled = errors.New("job cancelled")
+	// errStopping requeues the job without counting the attempt.
+	errStopping = errors.New("job queue stopping")
+	// errLeaseLost means another worker reclaimed the job; its row is left alone.
+	errLeaseLost = errors.New("job lease lost")
+)
+
+// finishTimeout bounds recording an attempt's outcome after the job's own
+// context has ended.
+const finishTimeout = 10 * time.Second
+
+// Start runs the workers until Stop. Register job kinds and Cron schedules
+// before calling it.
+func (q *Queue) Start(ctx context.Context) error {
+	ctx, q.stop = context.WithCancelCause(context.WithoutCancel(ctx))
+	q.done = make(chan struct{})
+	go func() {
+		defer close(q.done)
+		q.loop(ctx)
+	}()
+	slog.Info("job queue started", "worker", q.workerID, "workers", q.cfg.Workers)
+	return nil
+}
+
+// Stop stops claiming jobs and cancels the running ones, which go back to the
+// queue without using up an attempt. It waits for them until ctx ends.
+func (q *Queue) Stop(ctx context.Context) error {
+	if q.stop == nil {
+		return nil
+	}
+	q.stop(errStopping)
+
+	stopped := make(chan struct{})
+	go func() {
+		<-q.done
+		q.running.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("job queue stop: %w", ctx.Err())
+	}
+}
+
+func (q *Queue) loop(ctx context.Context) {
+	ticker := time.NewTicker(q.cfg.PollInterval)
+	defer ticker.Stop()
+
+	var lastCron time.Time
+	for {
+		schemas, err := database.ActiveTenantSchemas(ctx, q.pool)
+		if err != nil && ctx.Err() == nil {
+			slog.Error("job queue: list tenants failed", "error", err)
+		}
+		if now := time.Now(); now.Truncate(time.Minute).After(lastCron) {
+			lastCron = now
+			q.scheduleCrons(ctx, schemas, now)
+			q.sweep(ctx, schemas)
+		}
+		q.dispatch(ctx, schemas)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-q.wake:
+		}
+	}
+}
+
+// dispatch claims due jobs, tenant by tenant, while workers are free. A
+// failing tenant is logged and skipped so it cannot block the others.
+func (q *Queue) dispatch(ctx context.Context, schemas []string) {
+	kinds := q.kinds()
+	for _, schema := range schemas {
+		for {
+			select {
+			case q.slots <- struct{}{}:
+			default:
+				return // every worker is busy
+			}
+			job, err := q.claim(ctx, schema, kinds)
+			if job == nil {
+				<-q.slots
+				if err != nil && ctx.Err() == nil {
+					slog.Error("job queue: claim failed", "schema", schema, "error", err)
+				}
+				break
+			}
+			q.running.Add(1)
+			go func() {
+				defer func() {
+					<-q.slots
+					q.running.Done()
+				}()
+				q.execute(ctx, schema, job)
+			}()
+		}
+	}
+}
+
+func (q *Queue) kinds() []string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	kinds := make([]string, 0, len(q.handlers))
+	for k := range q.handlers {
+		kinds = append(kinds, k)
+	}
+	return kinds
+}
+
+// claim leases the next due job this replica can run: a queued one whose
+// run_at has passed, or a running one whose worker stopped renewing its lease
+// and that has attempts left; never one asked to cancel. Returns nil when
+// there is none.
+func (q *Queue) claim(ctx context.Context, schema string, kinds []string) (*Job, error) {
+	var job *Job
+	err := database.WithTenantTx(ctx, q.pool, schema, func(tx pgx.Tx) error {
+		var err error
+		job, err = scanJob(tx.QueryRow(ctx,
+			`UPDATE jobs SET
+			   status = 'running', attempts = attempts + 1,
+			   last_error = CASE WHEN status = 'running' THEN 'lease expired: worker stopped responding' ELSE last_error END,
+			   locked_by = $1, locked_until = now() + make_interval(secs => $2),
+			   started_at = COALESCE(started_at, now()), updated_at = now()
+			 WHERE id = (
+			   SELECT id FROM jobs
+			   WHERE kind = ANY($3) AND (
+			         (status = 'queued' AND run_at <= now() AND NOT cancel_requested)
+			      OR (status = 'running' AND locked_until < now() AND attempts < max_attempts AND NOT cancel_requested))
+			   ORDER BY run_at
+			   LIMIT 1
+			   FOR UPDATE SKIP LOCKED)
+			 RETURNING `+jobColumns,
+			q.workerID, q.cfg.Lease.Seconds(), kinds,
+		))
+		return err
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return job, err
+}
+
+// sweep finishes running jobs whose lease expired and that claim will not
+// pick up again: out of attempts (failed) or cancelled meanwhile.
+func (q *Queue) sweep(ctx context.Context, schemas []string) {
+	for _, schema := range schemas {
+		err := database.WithTenantTx(ctx, q.pool, schema, func(tx pgx.Tx) error {
+			_, err := tx.Exec(ctx,
+				`UPDATE jobs SET
+				   status = CASE WHEN cancel_requested THEN 'cancelled' ELSE 'failed' END,
+				   last_error = CASE WHEN cancel_requested THEN last_error ELSE 'lease expired: worker stopped responding' END,
+				   locked_by = NULL, locked_until = NULL, finished_at = now(), updated_at = now()
+				 WHERE status = 'running' AND locked_until < now() AND (attempts >= max_attempts OR cancel_requested)`)
+			return err
+		})
+		if err != nil && ctx.Err() == nil {
+			slog.Error("job queue: sweep failed", "schema", schema, "error", err)
+		}
+	}
+}
+
+// execute runs one claimed attempt and records its outcome.
+func (q *Queue) execute(ctx context.Context, schema string, job *Job) {
+	q.mu.Lock()
+	h := q.handlers[job.Kind]
+	q.mu.Unlock()
+
+	runCtx, cancel := context.WithCancelCause(jobContext(ctx, schema, job))
+	defer cancel(nil)
+	runCtx, cancelTimeout := context.WithTimeoutCause(runCtx, h.timeout,
+		fmt.Errorf("attempt timed out after %s", h.timeout))
+	defer cancelTimeout()
+
+	heartbeatDone := make(chan struct{})
+	go func() {
+		defer close(heartbeatDone)
+		q.heartbeat(runCtx, cancel, schema, job.ID)
+	}()
+
+	start := time.Now()
+	result, err := call(runCtx, h, job)
+	if err != nil && runCtx.Err() != nil {
+		err = context.Cause(runCtx)
+	}
+	cancel(nil)
+	<-heartbeatDone
+
+	outcome, ferr := q.finish(ctx, schema, job, h, result, err)
+	if ferr != nil {
+		slog.ErrorContext(runCtx, "job queue: record outcome failed", "job_id", job.ID, "kind", job.Kind, "error", ferr)
+		return
+	}
+	if outcome != "" {
+		metrics.ObserveJob(job.Kind, outcome, time.Since(start))
+	}
+	attrs := []any{"job_id", job.ID, "kind", job.Kind, "attempt", job.Attempts, "outcome", outcome, "duration", time.Since(start)}
+	if outcome == string(StatusFailed) || outcome == "retried" {
+		slog.WarnContext(runCtx, "job attempt failed", append(attrs, "error", err)...)
+		return
+	}
+	slog.InfoContext(runCtx, "job attempt finished", attrs...)
+}
+
+// jobContext carries the tenant, submitting user and request ID of the job,
+// so repositories resolve the tenant, audit rows name the user and logs link
+// back to the request that submitted it.
+func jobContext(ctx context.Context, schema string, job *Job) context.Context {
+	ctx = tenant.WithTenant(ctx, schema)
+	info := requestinfo.Info{ID: "job:" + job.ID.String(), Tenant: schema}
+	if job.RequestID != nil {
+		info.ID = *job.RequestID
+	}
+	if job.CreatedBy != nil {
+		claims := &auth.Claims{UserID: *job.CreatedBy, TenantID: schema}
+		if job.createdByEmail != nil {
+			claims.Email = *job.createdByEmail
+		}
+		ctx = auth.WithUser(ctx, claims)
+		info.UserID = job.CreatedBy.String()
+	}
+	return requestinfo.WithInfo(ctx, info)
+}
+
+// call runs the handler, turning a panic into a permanent failure.
+func call(ctx context.Context, h *handler, job *Job) (result any, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			slog.ErrorContext(ctx, "job panicked", "job_id", job.ID, "kind", job.Kind, "panic", p, "stack", string(debug.Stack()))
+			err = Permanent(fmt.Errorf("panic: %v", p))
+		}
+	}()
+	return h.run(ctx, job)
+}
+
+// heartbeat renews the lease until ctx ends, cancelling the job when it is
+// cancelled through the API or another worker has taken it over.
+func (q *Queue) heartbeat(ctx context.Context, cancel context.CancelCauseFunc, schema string, id uuid.UUID) {
+	ticker := time.NewTicker(max(q.cfg.Lease/3, 10*time.Millisecond))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		var cancelRequested bool
+		err := database.WithTenantTx(ctx, q.pool, schema, func(tx pgx.Tx) error {
+			return tx.QueryRow(ctx,
+				`UPDATE jobs SET locked_until = now() + make_interval(secs => $3), updated_at = now()
+				 WHERE id = $1 AND locked_by = $2 AND status = 'running'
+				 RETURNING cancel_requested`,
+				id, q.workerID, q.cfg.Lease.Seconds(),
+			).Scan(&cancelRequested)
+		})
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			cancel(errLeaseLost)
+			return
+		case err != nil:
+			if ctx.Err() == nil {
+				slog.WarnContext(ctx, "job queue: heartbeat failed", "job_id", id, "error", err)
+			}
+		case cancelRequested:
+			cancel(ErrCancelled)
+			return
+		}
+	}
+}
+
+// finish records the attempt's outcome and returns it as a metric label; ""
+// when the row was left alone. A job that would be queued again is cancelled
+// instead if a cancel was requested during the attempt, since the heartbeat
+// that would have stopped it may not have come round.
+func (q *Queue) finish(ctx context.Context, schema string, job *Job, h *handler, result any, runErr error) (string, error) {
+	if errors.Is(runErr, errLeaseLost) {
+		return "", nil
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), finishTimeout)
+	defer cancel()
+
+	var (
+		outcome string
+		sql     string
+		args    = []any{job.ID, q.workerID}
+	)
+	switch {
+	case runErr == nil:
+		raw, err := json.Marshal(result)
+		if err != nil {
+			return q.finish(ctx, schema, job, h, nil, Permanent(fmt.Errorf("encode result: %w", err)))
+		}
+		outcome = string(StatusSucceeded)
+		sql = `status = 'succeeded', result = $3, last_error = NULL, finished_at = now()`
+		args = append(args, raw)
+	case errors.Is(runErr, ErrCancelled):
+		outcome = string(StatusCancelled)
+		sql = `status = 'cancelled', finished_at = now()`
+	case errors.Is(runErr, errStopping):
+		// Not the job's fault: run it again without using up an attempt.
+		outcome = "requeued"
+		sql = requeue + `, attempts = attempts - 1, run_at = now()`
+	case IsPermanent(runErr) || job.Attempts >= job.MaxAttempts:
+		outcome = string(StatusFailed)
+		sql = `status = 'failed', last_error = $3, finished_at = now()`
+		args = append(args, runErr.Error())
+	default:
+		outcome = "retried"
+		sql = requeue + `, last_error = $3, run_at = now() + make_interval(secs => $4)`
+		args = append(args, runErr.Error(), h.backoff(job.Attempts).Seconds())
+	}
+
+	var status Status
+	err := database.WithTenantTx(ctx, q.pool, schema, func(tx pgx.Tx) error {
+		return tx.QueryRow(ctx,
+			`UPDATE jobs SET `+sql+`, locked_by = NULL, locked_until = NULL, updated_at = now()
+			 WHERE id = $1 AND locked_by = $2 AND status = 'running'
+			 RETURNING status`, args...).Scan(&status)
+	})
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return "", nil
+	case err != nil:
+		return "", err
+	case status == StatusCancelled:
+		return string(StatusCancelled), nil
+	}
+	return outcome, nil
+}
+
+// requeue queues a job again, or cancels it if that was asked for meanwhile.
+const requeue = `status = CASE WHEN cancel_requested THEN 'cancelled' ELSE 'queued' END,
+	finished_at = CASE WHEN cancel_requested THEN now() ELSE finished_at END`
+
+// workerID identifies this replica's leases, e.g. in locked_by.
+func workerID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), uuid.NewString()[:8])
+}
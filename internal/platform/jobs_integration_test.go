@@ -0,0 +1,314 @@
+//go:build integration
+
+package platform_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/auth"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/jobs"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/testutil"
+	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
+)
+
+type echoPayload struct {
+	Text string `json:"text"`
+}
+
+// startQueue starts q and stops it when the test ends, before the tenant
+// schema is dropped.
+func startQueue(t *testing.T, q *jobs.Queue) {
+	t.Helper()
+	if err := q.Start(context.Background()); err != nil {
+		t.Fatalf("start queue: %v", err)
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := q.Stop(ctx); err != nil {
+			t.Errorf("stop queue: %v", err)
+		}
+	})
+}
+
+func waitForStatus(t *testing.T, ctx context.Context, q *jobs.Queue, id uuid.UUID, want jobs.Status) *jobs.Job {
+	t.Helper()
+	deadline := time.Now().Add(15 * time.Second)
+	for {
+		job, err := q.Get(ctx, id)
+		if err != nil {
+			t.Fatalf("get job: %v", err)
+		}
+		if job.Status == want {
+			return job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job %s: expected %s, still %s (attempts %d, error %v)", id, want, job.Status, job.Attempts, job.Error)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestJobs_RetriesUntilSuccessWithSubmitterContext(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	schema := db.CreateTenantSchema(t)
+
+	var runs atomic.Int32
+	var sawTenant, sawUser atomic.Value
+	q := jobs.NewQueue(db.Pool, jobs.Config{PollInterval: 20 * time.Millisecond})
+	jobs.Register(q, jobs.Definition[echoPayload]{
+		Kind:    "test.flaky",
+		Backoff: func(int) time.Duration { return 0 },
+		Run: func(ctx context.Context, _ *jobs.Job, p echoPayload) (any, error) {
+			if s, err := tenant.FromContext(ctx); err == nil {
+				sawTenant.Store(s)
+			}
+			if c, err := auth.UserFromContext(ctx); err == nil {
+				sawUser.Store(c.UserID)
+			}
+			if runs.Add(1) == 1 {
+				return nil, errors.New("transient failure")
+			}
+			return map[string]string{"echo": p.Text}, nil
+		},
+	})
+	jobs.Register(q, jobs.Definition[echoPayload]{
+		Kind: "test.broken",
+		Run: func(context.Context, *jobs.Job, echoPayload) (any, error) {
+			return nil, jobs.Permanent(errors.New("cannot ever work"))
+		},
+	})
+	startQueue(t, q)
+
+	userID := uuid.New()
+	ctx := auth.WithUser(tenant.WithTenant(context.Background(), schema), &auth.Claims{UserID: userID, Email: "jobs@example.com"})
+	job, err := q.Enqueue(ctx, "test.flaky", echoPayload{Text: "hello"})
+	if err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	if job.Status != jobs.StatusQueued || job.CreatedBy == nil || *job.CreatedBy != userID {
+		t.Fatalf("expected a queued job created by %s, got %+v", userID, job)
+	}
+
+	done := waitForStatus(t, ctx, q, job.ID, jobs.StatusSucceeded)
+	if done.Attempts != 2 || string(done.Result) != `{"echo":"hello"}` {
+		t.Fatalf("expected success on attempt 2 with the echo result, got attempts %d result %s", done.Attempts, done.Result)
+	}
+	if sawTenant.Load() != schema || sawUser.Load() != userID {
+		t.Fatalf("job ran as tenant %v user %v, expected %s %s", sawTenant.Load(), sawUser.Load(), schema, userID)
+	}
+
+	// A permanent error fails the job without retrying.
+	broken, err := q.Enqueue(ctx, "test.broken", echoPayload{})
+	if err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	failed := waitForStatus(t, ctx, q, broken.ID, jobs.StatusFailed)
+	if failed.Attempts != 1 || failed.Error == nil || !strings.Contains(*failed.Error, "cannot ever work") {
+		t.Fatalf("expected one failed attempt with its error, got attempts %d error %v", failed.Attempts, failed.Error)
+	}
+
+	if _, err := q.Enqueue(ctx, "test.unknown", nil); err == nil {
+		t.Fatal("expected enqueueing an unregistered kind to fail")
+	}
+}
+
+func TestJobs_UniqueScheduledAndCancel(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	schema := db.CreateTenantSchema(t)
+	ctx := tenant.WithTenant(context.Background(), schema)
+
+	started := make(chan struct{}, 1)
+	q := jobs.NewQueue(db.Pool, jobs.Config{PollInterval: 20 * time.Millisecond, Lease: 300 * time.Millisecond})
+	jobs.Register(q, jobs.Definition[echoPayload]{
+		Kind: "test.block",
+		Run: func(ctx context.Context, _ *jobs.Job, _ echoPayload) (any, error) {
+			started <- struct{}{}
+			<-ctx.Done()
+			return nil, context.Cause(ctx)
+		},
+	})
+	startQueue(t, q)
+
+	// Scheduled for later, and deduplicated while unfinished.
+	later, err := q.Enqueue(ctx, "test.block", echoPayload{}, jobs.After(time.Hour), jobs.Unique("later"))
+	if err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	dup, err := q.Enqueue(ctx, "test.block", echoPayload{}, jobs.Unique("later"))
+	if err != nil {
+		t.Fatalf("enqueue duplicate: %v", err)
+	}
+	if dup.ID != later.ID {
+		t.Fatalf("expected the unique job %s back, got %s", later.ID, dup.ID)
+	}
+
+	// Cancelling a queued job finishes it at once; a finished job cannot be cancelled.
+	cancelled, err := q.Cancel(ctx, later.ID)
+	if err != nil || cancelled.Status != jobs.StatusCancelled {
+		t.Fatalf("cancel queued job: status %v, err %v", cancelled, err)
+	}
+	if _, err := q.Cancel(ctx, later.ID); !errors.Is(err, erptypes.ErrConflict) {
+		t.Fatalf("expected conflict cancelling a finished job, got %v", err)
+	}
+	if _, err := q.Cancel(ctx, uuid.New()); !errors.Is(err, erptypes.ErrNotFound) {
+		t.Fatalf("expected not found for an unknown job, got %v", err)
+	}
+
+	// A running job is cancelled through its context at the next heartbeat.
+	running, err := q.Enqueue(ctx, "test.block", echoPayload{})
+	if err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	select {
+	case <-started:
+	case <-time.After(10 * time.Second):
+		t.Fatal("job did not start")
+	}
+	if job, err := q.Cancel(ctx, running.ID); err != nil || !job.CancelRequested {
+		t.Fatalf("cancel running job: %+v, %v", job, err)
+	}
+	waitForStatus(t, ctx, q, running.ID, jobs.StatusCancelled)
+
+	page, err := q.List(ctx, listquery.Spec{}.Where("status", string(jobs.StatusCancelled)))
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if page.Total != 2 {
+		t.Fatalf("expected 2 cancelled jobs, got %d", page.Total)
+	}
+}
+
+func TestJobs_CancelDuringFailingAttempt(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	schema := db.CreateTenantSchema(t)
+	ctx := tenant.WithTenant(context.Background(), schema)
+
+	// The lease is long enough that no heartbeat sees the cancel before the
+	// attempt fails.
+	started, release := make(chan struct{}, 1), make(chan struct{})
+	var runs atomic.Int32
+	q := jobs.NewQueue(db.Pool, jobs.Config{PollInterval: 20 * time.Millisecond, Lease: time.Minute})
+	jobs.Register(q, jobs.Definition[echoPayload]{
+		Kind:    "test.failing",
+		Backoff: func(int) time.Duration { return 0 },
+		Run: func(context.Context, *jobs.Job, echoPayload) (any, error) {
+			runs.Add(1)
+			started <- struct{}{}
+			<-release
+			return nil, errors.New("transient failure")
+		},
+	})
+	startQueue(t, q)
+
+	job, err := q.Enqueue(ctx, "test.failing", echoPayload{})
+	if err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	select {
+	case <-started:
+	case <-time.After(10 * time.Second):
+		t.Fatal("job did not start")
+	}
+	if job, err := q.Cancel(ctx, job.ID); err != nil || !job.CancelRequested {
+		t.Fatalf("cancel running job: %+v, %v", job, err)
+	}
+	close(release)
+
+	// The attempt's failure would queue a retry; the cancel wins instead.
+	done := waitForStatus(t, ctx, q, job.ID, jobs.StatusCancelled)
+	if done.FinishedAt == nil {
+		t.Fatal("expected the cancelled job to have finished_at set")
+	}
+	time.Sleep(200 * time.Millisecond)
+	if n := runs.Load(); n != 1 {
+		t.Fatalf("expected one attempt, got %d", n)
+	}
+}
+
+func TestJobs_StopRequeuesRunningJobs(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	schema := db.CreateTenantSchema(t)
+	ctx := tenant.WithTenant(context.Background(), schema)
+
+	started := make(chan struct{}, 1)
+	q := jobs.NewQueue(db.Pool, jobs.Config{PollInterval: 20 * time.Millisecond})
+	jobs.Register(q, jobs.Definition[echoPayload]{
+		Kind: "test.block",
+		Run: func(ctx context.Context, _ *jobs.Job, _ echoPayload) (any, error) {
+			started <- struct{}{}
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	})
+	if err := q.Start(context.Background()); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	job, err := q.Enqueue(ctx, "test.block", echoPayload{})
+	if err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	select {
+	case <-started:
+	case <-time.After(10 * time.Second):
+		t.Fatal("job did not start")
+	}
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := q.Stop(stopCtx); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	got, err := q.Get(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Status != jobs.StatusQueued || got.Attempts != 0 {
+		t.Fatalf("expected the job back in the queue without a used attempt, got %s after %d attempts", got.Status, got.Attempts)
+	}
+}
+
+func TestJobs_CronSchedule(t *testing.T) {
+	at := func(s string) time.Time {
+		tm, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tm
+	}
+	for _, tc := range []struct {
+		spec, after, want string
+	}{
+		{"*/15 * * * *", "2026-03-01T10:07:30Z", "2026-03-01T10:15:00Z"},
+		{"@hourly", "2026-03-01T10:00:00Z", "2026-03-01T11:00:00Z"},
+		{"@daily", "2026-12-31T23:59:00Z", "2027-01-01T00:00:00Z"},
+		{"30 2 * * 1-5", "2026-10-16T03:00:00Z", "2026-10-19T02:30:00Z"}, // Friday → Monday
+		{"0 9 1,15 * *", "2026-02-02T00:00:00Z", "2026-02-15T09:00:00Z"},
+		{"0 0 13 * 5", "2026-10-17T00:00:00Z", "2026-10-23T00:00:00Z"}, // 13th or Friday
+		{"0 0 29 2 *", "2026-03-01T00:00:00Z", "2028-02-29T00:00:00Z"},
+		{"0 12 * * 7", "2026-10-17T00:00:00Z", "2026-10-18T12:00:00Z"}, // 7 is Sunday
+	} {
+		s, err := jobs.ParseCron(tc.spec)
+		if err != nil {
+			t.Fatalf("parse %q: %v", tc.spec, err)
+		}
+		if got := s.Next(at(tc.after)); !got.Equal(at(tc.want)) {
+			t.Errorf("%q after %s: expected %s, got %s", tc.spec, tc.after, tc.want, got.Format(time.RFC3339))
+		}
+	}
+
+	for _, spec := range []string{"* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "x * * * *"} {
+		if _, err := jobs.ParseCron(spec); err == nil {
+			t.Errorf("expected %q to be rejected", spec)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+//go:build integration
+
+package platform_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/eventbus"
+	platformmod "github.com/HuynhHoangPhuc/mcs-erp/internal/platform/module"
+)
+
+// lifecycleModule records Start/Stop calls into a shared log.
+type lifecycleModule struct {
+	name     string
+	deps     []string
+	log      *[]string
+	startErr error
+}
+
+func (m *lifecycleModule) Name() string                         { return m.name }
+func (m *lifecycleModule) Dependencies() []string               { return m.deps }
+func (m *lifecycleModule) RegisterRoutes(*http.ServeMux)        {}
+func (m *lifecycleModule) RegisterEvents(context.Context) error { return nil }
+func (m *lifecycleModule) Migrate(context.Context) error        { return nil }
+func (m *lifecycleModule) Start(context.Context) error {
+	*m.log = append(*m.log, "start "+m.name)
+	return m.startErr
+}
+func (m *lifecycleModule) Stop(context.Context) error {
+	*m.log = append(*m.log, "stop "+m.name)
+	return nil
+}
+
+func lifecycleRegistry(t *testing.T, log *[]string, failing string) *platformmod.Registry {
+	t.Helper()
+	reg := platformmod.NewRegistry()
+	for _, m := range []*lifecycleModule{
+		{name: "core", log: log},
+		{name: "hr", deps: []string{"core"}, log: log},
+		{name: "timetable", deps: []string{"hr"}, log: log},
+	} {
+		if m.name == failing {
+			m.startErr = errors.New("boom")
+		}
+		if err := reg.Register(m); err != nil {
+			t.Fatalf("register %s: %v", m.name, err)
+		}
+	}
+	return reg
+}
+
+func TestLifecycle_StartInOrderStopInReverse(t *testing.T) {
+	var log []string
+	reg := lifecycleRegistry(t, &log, "")
+
+	if err := platformmod.Start(context.Background(), reg); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := platformmod.Stop(context.Background(), reg); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+
+	want := []string{"start core", "start hr", "start timetable", "stop timetable", "stop hr", "stop core"}
+	if !reflect.DeepEqual(log, want) {
+		t.Fatalf("expected %v, got %v", want, log)
+	}
+}
+
+func TestLifecycle_FailedStartStopsStartedModules(t *testing.T) {
+	var log []string
+	reg := lifecycleRegistry(t, &log, "timetable")
+
+	if err := platformmod.Start(context.Background(), reg); err == nil {
+		t.Fatal("expected start error")
+	}
+
+	want := []string{"start core", "start hr", "start timetable", "stop hr", "stop core"}
+	if !reflect.DeepEqual(log, want) {
+		t.Fatalf("expected %v, got %v", want, log)
+	}
+}
+
+func TestEventBus_CloseWaitsForInFlightHandler(t *testing.T) {
+	bus, err := eventbus.New(eventbus.Config{Driver: eventbus.DriverGoChannel})
+	if err != nil {
+		t.Fatalf("create bus: %v", err)
+	}
+
+	started := make(chan struct{})
+	var finished atomic.Bool
+	if err := bus.AddConsumer("slow", "test.slow", func(*message.Message) error {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		finished.Store(true)
+		return nil
+	}); err != nil {
+		t.Fatalf("add consumer: %v", err)
+	}
+
+	// A cancelled start context must not stop the router; only Close does.
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := bus.Start(ctx); err != nil {
+		t.Fatalf("start bus: %v", err)
+	}
+	cancel()
+	if err := bus.CheckRunning(context.Background()); err != nil {
+		t.Fatalf("router should keep running after start ctx is cancelled: %v", err)
+	}
+
+	if err := eventbus.Publish(bus.Publisher(), "test.slow", map[string]string{"k": "v"}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	<-started
+
+	if err := bus.Close(); err != nil {
+		t.Fatalf("close bus: %v", err)
+	}
+	if !finished.Load() {
+		t.Fatal("Close returned before the in-flight handler finished")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatalf("second close should be a no-op: %v", err)
+	}
+}
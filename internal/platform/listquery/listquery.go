@@ -0,0 +1,323 @@
+// Package listquery is the query language shared by list endpoints: typed
+// filters, multi-field sort and opaque cursor (keyset) pagination.
+//
+//	GET /api/v1/rooms?building=A&capacity[gte]=30&equipment[contains]=projector&sort=-capacity,code&limit=20
+//
+// A module declares the fields of each listable entity in a Schema next to its
+// repository interface. Handlers Parse the query string against the schema
+// (errors are client errors), repositories Build SQL from the resulting Spec
+// and return a Page carrying total and next_cursor.
+package listquery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalid wraps every error caused by a malformed list query.
+var ErrInvalid = errors.New("invalid list query")
+
+// Limits applied when a schema does not set its own.
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// Kind is the type of a field's values.
+type Kind uint8
+
+const (
+	Text Kind = iota
+	Int
+	Bool
+	Time // RFC 3339
+	UUID
+)
+
+func (k Kind) sqlType() string {
+	switch k {
+	case Int:
+		return "bigint"
+	case Bool:
+		return "boolean"
+	case Time:
+		return "timestamptz"
+	case UUID:
+		return "uuid"
+	default:
+		return "text"
+	}
+}
+
+// parse converts a query or cursor value to the Go value bound for the column.
+func (k Kind) parse(v string) (any, error) {
+	switch k {
+	case Int:
+		return strconv.ParseInt(v, 10, 64)
+	case Bool:
+		return strconv.ParseBool(v)
+	case Time:
+		return time.Parse(time.RFC3339, v)
+	case UUID:
+		return uuid.Parse(v)
+	default:
+		return v, nil
+	}
+}
+
+// parseAll converts values to a typed slice for array parameters.
+func (k Kind) parseAll(vs []string) (any, error) {
+	var (
+		ints  []int64
+		bools []bool
+		times []time.Time
+		ids   []uuid.UUID
+	)
+	for _, v := range vs {
+		x, err := k.parse(v)
+		if err != nil {
+			return nil, err
+		}
+		switch x := x.(type) {
+		case int64:
+			ints = append(ints, x)
+		case bool:
+			bools = append(bools, x)
+		case time.Time:
+			times = append(times, x)
+		case uuid.UUID:
+			ids = append(ids, x)
+		}
+	}
+	switch k {
+	case Int:
+		return ints, nil
+	case Bool:
+		return bools, nil
+	case Time:
+		return times, nil
+	case UUID:
+		return ids, nil
+	default:
+		return vs, nil
+	}
+}
+
+// Ops is the set of filter operators a field accepts.
+type Ops uint8
+
+const (
+	Eq       Ops = 1 << iota // field=v or field[eq]=v
+	In                       // field[in]=a,b
+	Range                    // field[gt|gte|lt|lte]=v
+	Contains                 // field[contains]=v: substring (Text) or all of a,b (Array)
+)
+
+var opSets = map[string]Ops{
+	"eq": Eq, "in": In, "gt": Range, "gte": Range, "lt": Range, "lte": Range, "contains": Contains,
+}
+
+// Field describes one filterable and/or sortable field of a list.
+type Field struct {
+	Column string // SQL expression; defaults to the field name. Must be constant.
+	Kind   Kind
+	Array  bool // column is an array of Kind: eq tests membership, in overlap
+	Filter Ops
+	Sort   bool // sortable; the column must be NOT NULL
+}
+
+// Sort orders a list by one field.
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// Schema lists the fields of one list endpoint.
+type Schema struct {
+	Fields map[string]Field
+	// Key is a unique, NOT NULL, sortable field appended to every sort so that
+	// the order (and therefore the cursor) is total, e.g. "id".
+	Key          string
+	DefaultSort  []Sort
+	DefaultLimit int // DefaultLimit when zero
+	MaxLimit     int // MaxLimit when zero
+}
+
+// With returns a copy of the schema with one more field, e.g. a scope column
+// that only the repository filters on and clients must not see.
+func (s *Schema) With(name string, f Field) *Schema {
+	c := *s
+	c.Fields = make(map[string]Field, len(s.Fields)+1)
+	for k, v := range s.Fields {
+		c.Fields[k] = v
+	}
+	c.Fields[name] = f
+	return &c
+}
+
+func (s *Schema) limits() (def, max int) {
+	def, max = s.DefaultLimit, s.MaxLimit
+	if def == 0 {
+		def = DefaultLimit
+	}
+	if max == 0 {
+		max = MaxLimit
+	}
+	return def, max
+}
+
+func (s *Schema) column(name string) string {
+	if c := s.Fields[name].Column; c != "" {
+		return c
+	}
+	return name
+}
+
+// Filter restricts a list to rows whose field matches Values under Op.
+// Operators other than in and array contains take exactly one value.
+type Filter struct {
+	Field  string
+	Op     string
+	Values []string
+}
+
+// Spec is a parsed list request. The zero Spec lists the first page in the
+// schema's default order.
+type Spec struct {
+	Filters []Filter
+	Sort    []Sort // DefaultSort when empty
+	Limit   int    // DefaultLimit when zero; clamped to MaxLimit
+	Offset  int    // page by position instead of cursor (kept for table UIs)
+	Cursor  string // next_cursor of the previous page
+}
+
+// Where returns a copy of the spec with an extra equality filter, for
+// callers that scope a list themselves (e.g. to the current user).
+func (sp Spec) Where(field, value string) Spec {
+	sp.Filters = append(slices.Clip(sp.Filters), Filter{Field: field, Op: "eq", Values: []string{value}})
+	return sp
+}
+
+// reserved query parameters that are not filters.
+var reserved = map[string]bool{"sort": true, "limit": true, "offset": true, "cursor": true}
+
+// Parse reads a list request from query parameters:
+//
+//	field=v | field[op]=v   filters (ANDed); in and array contains take a,b,c
+//	sort=-created_at,name   sort fields, "-" for descending
+//	limit=20                page size (clamped to the schema maximum)
+//	cursor=<next_cursor>    continue after the previous page
+//	offset=40               or skip rows instead of using a cursor
+//
+// Plain parameters that are not fields of the schema are ignored; everything
+// else that does not fit the schema is an error wrapping ErrInvalid.
+func Parse(q url.Values, s *Schema) (Spec, error) {
+	var sp Spec
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return Spec{}, fmt.Errorf("%w: limit must be a positive integer", ErrInvalid)
+		}
+		sp.Limit = n
+	}
+	if v := q.Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return Spec{}, fmt.Errorf("%w: offset must be a non-negative integer", ErrInvalid)
+		}
+		sp.Offset = n
+	}
+	sp.Cursor = q.Get("cursor")
+	if v := q.Get("sort"); v != "" {
+		for _, f := range strings.Split(v, ",") {
+			f = strings.TrimSpace(f)
+			name, desc := strings.CutPrefix(f, "-")
+			sp.Sort = append(sp.Sort, Sort{Field: name, Desc: desc})
+		}
+	}
+
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		if reserved[k] {
+			continue
+		}
+		name, op := k, "eq"
+		if i := strings.IndexByte(k, '['); i > 0 && strings.HasSuffix(k, "]") {
+			name, op = k[:i], k[i+1:len(k)-1]
+		} else if _, ok := s.Fields[k]; !ok {
+			continue
+		}
+		f, ok := s.Fields[name]
+		if !ok {
+			return Spec{}, fmt.Errorf("%w: unknown filter field %q", ErrInvalid, name)
+		}
+		for _, raw := range q[k] {
+			values := []string{raw}
+			if op == "in" || (op == "contains" && f.Array) {
+				values = splitList(raw)
+			}
+			sp.Filters = append(sp.Filters, Filter{Field: name, Op: op, Values: values})
+		}
+	}
+
+	// Building validates everything, including the cursor.
+	if _, err := Build(sp, s); err != nil {
+		return Spec{}, err
+	}
+	return sp, nil
+}
+
+func splitList(raw string) []string {
+	var out []string
+	for _, v := range strings.Split(raw, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// Page is one page of a list. NextCursor is empty on the last page.
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	Total      int    `json:"total"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// Map converts the items of a page, e.g. to a handler's response shape.
+func Map[T, R any](p Page[T], f func(T) R) Page[R] {
+	items := make([]R, len(p.Items))
+	for i, it := range p.Items {
+		items[i] = f(it)
+	}
+	return Page[R]{Items: items, Total: p.Total, NextCursor: p.NextCursor}
+}
+
+// All reads every page of a list by following cursors, for internal callers
+// that need the whole set (spec must not use Offset).
+func All[T any](ctx context.Context, spec Spec, list func(context.Context, Spec) (Page[T], error)) ([]T, error) {
+	var all []T
+	for {
+		page, err := list(ctx, spec)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page.Items...)
+		if page.NextCursor == "" {
+			return all, nil
+		}
+		spec.Cursor = page.NextCursor
+	}
+}
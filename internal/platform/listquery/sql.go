@@ -0,0 +1,310 @@
+package listquery
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Query is a Spec translated to SQL for one schema. Values are always bound
+// as parameters; only the schema's constant column expressions are spliced in.
+//
+// Repositories usually run it with Fetch.
+type Query struct {
+	where   []string
+	args    []any // filter arguments, then cursor arguments
+	nfilter int   // number of filter arguments
+	after   string
+	order   string
+	cursor  string // SQL expression producing the row's cursor values
+	sig     string
+	limit   int
+	offset  int
+}
+
+// cursorData is what an opaque cursor encodes: the sort it belongs to and
+// the sort values of the last row of the previous page.
+type cursorData struct {
+	Sort   string            `json:"s"`
+	Values []json.RawMessage `json:"v"`
+}
+
+// Build validates spec against s and translates it to SQL. Errors wrap ErrInvalid.
+func Build(sp Spec, s *Schema) (*Query, error) {
+	q := &Query{}
+
+	def, max := s.limits()
+	q.limit = sp.Limit
+	if q.limit <= 0 {
+		q.limit = def
+	}
+	q.limit = min(q.limit, max)
+	if sp.Offset < 0 {
+		return nil, fmt.Errorf("%w: offset must be a non-negative integer", ErrInvalid)
+	}
+	if sp.Offset > 0 && sp.Cursor != "" {
+		return nil, fmt.Errorf("%w: use either cursor or offset", ErrInvalid)
+	}
+	q.offset = sp.Offset
+
+	for _, f := range sp.Filters {
+		if err := q.addFilter(s, f); err != nil {
+			return nil, err
+		}
+	}
+
+	q.nfilter = len(q.args)
+
+	sorts, err := effectiveSort(sp.Sort, s)
+	if err != nil {
+		return nil, err
+	}
+	var order, cols, sig []string
+	for _, so := range sorts {
+		col := s.column(so.Field)
+		dir, name := "ASC", so.Field
+		if so.Desc {
+			dir, name = "DESC", "-"+so.Field
+		}
+		order = append(order, col+" "+dir)
+		cols = append(cols, col)
+		sig = append(sig, name)
+	}
+	q.order = strings.Join(order, ", ")
+	q.cursor = "jsonb_build_array(" + strings.Join(cols, ", ") + ")::text"
+	q.sig = strings.Join(sig, ",")
+
+	if sp.Cursor != "" {
+		if err := q.addAfter(s, sorts, sp.Cursor); err != nil {
+			return nil, err
+		}
+	}
+	return q, nil
+}
+
+func effectiveSort(requested []Sort, s *Schema) ([]Sort, error) {
+	sorts := requested
+	if len(sorts) == 0 {
+		sorts = s.DefaultSort
+	}
+	seen := map[string]bool{}
+	for _, so := range sorts {
+		if f, ok := s.Fields[so.Field]; !ok || !f.Sort {
+			return nil, fmt.Errorf("%w: cannot sort by %q", ErrInvalid, so.Field)
+		}
+		if seen[so.Field] {
+			return nil, fmt.Errorf("%w: duplicate sort field %q", ErrInvalid, so.Field)
+		}
+		seen[so.Field] = true
+	}
+	if !seen[s.Key] {
+		sorts = append(sorts[:len(sorts):len(sorts)], Sort{Field: s.Key})
+	}
+	return sorts, nil
+}
+
+func (q *Query) param(v any, kind Kind, array bool) string {
+	q.args = append(q.args, v)
+	t := kind.sqlType()
+	if array {
+		t += "[]"
+	}
+	return fmt.Sprintf("$%d::%s", len(q.args), t)
+}
+
+func (q *Query) addFilter(s *Schema, f Filter) error {
+	field, ok := s.Fields[f.Field]
+	if !ok {
+		return fmt.Errorf("%w: unknown filter field %q", ErrInvalid, f.Field)
+	}
+	set, ok := opSets[f.Op]
+	if !ok || field.Filter&set == 0 || (field.Array && set == Range) {
+		return fmt.Errorf("%w: field %q does not support %q", ErrInvalid, f.Field, f.Op)
+	}
+	multi := f.Op == "in" || (f.Op == "contains" && field.Array)
+	if len(f.Values) == 0 || (!multi && len(f.Values) != 1) {
+		return fmt.Errorf("%w: wrong number of values for %s[%s]", ErrInvalid, f.Field, f.Op)
+	}
+	kind := field.Kind
+	if f.Op == "contains" && !field.Array {
+		kind = Text // substring of the column's text form
+	}
+	var value any
+	var err error
+	if multi {
+		value, err = kind.parseAll(f.Values)
+	} else {
+		value, err = kind.parse(f.Values[0])
+	}
+	if err != nil {
+		return fmt.Errorf("%w: invalid value for %s", ErrInvalid, f.Field)
+	}
+
+	col := s.column(f.Field)
+	var cond string
+	switch {
+	case field.Array && f.Op == "eq":
+		cond = q.param(value, field.Kind, false) + " = ANY(" + col + ")"
+	case field.Array && f.Op == "in":
+		cond = col + " && " + q.param(value, field.Kind, true)
+	case field.Array: // contains
+		cond = col + " @> " + q.param(value, field.Kind, true)
+	case f.Op == "eq":
+		cond = col + " = " + q.param(value, field.Kind, false)
+	case f.Op == "in":
+		cond = col + " = ANY(" + q.param(value, field.Kind, true) + ")"
+	case f.Op == "contains":
+		cond = col + "::text ILIKE " + q.param("%"+escapeLike(f.Values[0])+"%", Text, false)
+	default:
+		op := map[string]string{"gt": ">", "gte": ">=", "lt": "<", "lte": "<="}[f.Op]
+		cond = col + " " + op + " " + q.param(value, field.Kind, false)
+	}
+	q.where = append(q.where, cond)
+	return nil
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// addAfter restricts the list to rows after the cursor position in sort order:
+// (a > $1) OR (a = $1 AND b < $2) OR ... with < for descending fields.
+func (q *Query) addAfter(s *Schema, sorts []Sort, cursor string) error {
+	invalid := fmt.Errorf("%w: malformed cursor", ErrInvalid)
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return invalid
+	}
+	var c cursorData
+	if err := json.Unmarshal(raw, &c); err != nil || len(c.Values) != len(sorts) {
+		return invalid
+	}
+	if c.Sort != q.sig {
+		return fmt.Errorf("%w: cursor belongs to a different sort", ErrInvalid)
+	}
+
+	params := make([]string, len(sorts))
+	for i, so := range sorts {
+		kind := s.Fields[so.Field].Kind
+		text, err := cursorValue(c.Values[i])
+		if err != nil {
+			return invalid
+		}
+		v, err := kind.parse(text)
+		if err != nil {
+			return invalid
+		}
+		params[i] = q.param(v, kind, false)
+	}
+	ors := make([]string, len(sorts))
+	for i, so := range sorts {
+		var ands []string
+		for j := range i {
+			ands = append(ands, s.column(sorts[j].Field)+" = "+params[j])
+		}
+		op := ">"
+		if so.Desc {
+			op = "<"
+		}
+		ands = append(ands, s.column(so.Field)+" "+op+" "+params[i])
+		ors[i] = "(" + strings.Join(ands, " AND ") + ")"
+	}
+	q.after = "(" + strings.Join(ors, " OR ") + ")"
+	return nil
+}
+
+// cursorValue turns a JSON scalar from jsonb_build_array into its text form.
+func cursorValue(raw json.RawMessage) (string, error) {
+	if bytes.HasPrefix(raw, []byte(`"`)) {
+		var s string
+		err := json.Unmarshal(raw, &s)
+		return s, err
+	}
+	if string(raw) == "null" {
+		return "", fmt.Errorf("null cursor value")
+	}
+	return string(raw), nil
+}
+
+func whereClause(conds []string) string {
+	if len(conds) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(conds, " AND ")
+}
+
+// Count returns the query counting every row that matches the filters.
+func (q *Query) Count(from string) (string, []any) {
+	where, args := q.Where()
+	return "SELECT COUNT(*) FROM " + from + where, args
+}
+
+// Where returns the filters alone as " WHERE ..." (empty without filters)
+// and their arguments, for reading every match rather than a page, e.g. an
+// export.
+func (q *Query) Where() (string, []any) {
+	return whereClause(q.where), q.args[:q.nfilter:q.nfilter]
+}
+
+// Select returns the query for the page: columns followed by the cursor
+// column, filtered, sorted and limited to one row more than the page so
+// Fetch can tell whether another page follows.
+func (q *Query) Select(columns, from string) (string, []any) {
+	conds := q.where
+	if q.after != "" {
+		conds = append(conds[:len(conds):len(conds)], q.after)
+	}
+	sql := fmt.Sprintf("SELECT %s, %s FROM %s%s ORDER BY %s LIMIT %d",
+		columns, q.cursor, from, whereClause(conds), q.order, q.limit+1)
+	if q.offset > 0 {
+		sql += fmt.Sprintf(" OFFSET %d", q.offset)
+	}
+	return sql, q.args
+}
+
+// Fetch runs q in tx: it counts the rows matching the filters and reads one
+// page of columns from from. scan reads the current row's columns followed by
+// cursor, e.g. rows.Scan(&r.ID, &r.Name, cursor).
+func Fetch[T any](ctx context.Context, tx pgx.Tx, q *Query, columns, from string,
+	scan func(rows pgx.Rows, cursor *string) (T, error)) (Page[T], error) {
+	var total int
+	countSQL, args := q.Count(from)
+	if err := tx.QueryRow(ctx, countSQL, args...).Scan(&total); err != nil {
+		return Page[T]{}, err
+	}
+
+	selectSQL, args := q.Select(columns, from)
+	rows, err := tx.Query(ctx, selectSQL, args...)
+	if err != nil {
+		return Page[T]{}, err
+	}
+	defer rows.Close()
+
+	page := Page[T]{Items: []T{}, Total: total}
+	var cursor string
+	for rows.Next() {
+		if len(page.Items) == q.limit {
+			// The extra row only tells that another page follows.
+			data, _ := json.Marshal(cursorData{Sort: q.sig, Values: rawValues(cursor)})
+			page.NextCursor = base64.RawURLEncoding.EncodeToString(data)
+			break
+		}
+		item, err := scan(rows, &cursor)
+		if err != nil {
+			return Page[T]{}, err
+		}
+		page.Items = append(page.Items, item)
+	}
+	return page, rows.Err()
+}
+
+func rawValues(cursor string) []json.RawMessage {
+	var vs []json.RawMessage
+	_ = json.Unmarshal([]byte(cursor), &vs)
+	return vs
+}
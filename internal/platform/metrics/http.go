@@ -0,0 +1,81 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
+)
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "HTTP requests by route pattern, status and tenant.",
+	}, []string{"route", "status", "tenant"})
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by route pattern, status and tenant.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "status", "tenant"})
+)
+
+// HTTPMiddleware records request counts and latency. It must wrap the
+// ServeMux directly: the route label is the matched pattern (e.g.
+// "GET /api/v1/teachers/{id}"), which the mux sets on the request it is
+// given. Unmatched requests share the "unmatched" route; requests without a
+// tenant (health, login, platform) use tenant "none".
+func HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		route := r.Pattern
+		if route == "" {
+			route = "unmatched"
+		}
+		schema, err := tenant.FromContext(r.Context())
+		if err != nil {
+			schema = "none"
+		}
+		labels := prometheus.Labels{"route": route, "status": strconv.Itoa(rec.status), "tenant": schema}
+		httpRequests.With(labels).Inc()
+		httpDuration.With(labels).Observe(time.Since(start).Seconds())
+	})
+}
+
+// statusRecorder captures the response status while keeping streaming
+// (http.Flusher) working for SSE handlers.
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	if !r.wroteHeader {
+		r.status = code
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter { return r.ResponseWriter }
@@ -0,0 +1,180 @@
+// Package metrics exposes Prometheus instrumentation for the HTTP API,
+// database pool, scheduler, AI agent and event bus on GET /metrics.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "mcs"
+
+// Outcome label values.
+const (
+	OutcomeOK        = "ok"
+	OutcomeError     = "error"
+	OutcomeCancelled = "cancelled"
+	OutcomeDenied    = "denied"
+	OutcomeNotFound  = "not_found"
+)
+
+// Registry holds every mcs-erp collector plus Go runtime and process metrics.
+var Registry = prometheus.NewRegistry()
+
+var (
+	schedulerDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "scheduler",
+		Name:      "run_duration_seconds",
+		Help:      "Schedule generation wall time by outcome.",
+		Buckets:   []float64{0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+	}, []string{"outcome"})
+	schedulerIterations = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "scheduler",
+		Name:      "iterations",
+		Help:      "Annealing iterations summed over all workers of a completed run.",
+		Buckets:   prometheus.ExponentialBuckets(1000, 4, 8),
+	})
+	schedulerFinalCost = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "scheduler",
+		Name:      "final_cost",
+		Help:      "Cost of the best schedule of a completed run (hard violations weigh 10000).",
+		Buckets:   []float64{0, 1, 5, 10, 25, 50, 100, 1000, 10000, 100000},
+	})
+
+	llmDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "llm",
+		Name:      "request_duration_seconds",
+		Help:      "LLM GenerateContent latency by provider and outcome.",
+		Buckets:   []float64{0.25, 0.5, 1, 2, 5, 10, 20, 30, 60},
+	}, []string{"provider", "outcome"})
+	llmTokens = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "llm",
+		Name:      "tokens_total",
+		Help:      "Tokens reported by the LLM provider, by type (prompt, completion).",
+	}, []string{"provider", "type"})
+	agentToolCalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "agent",
+		Name:      "tool_calls_total",
+		Help:      "Agent tool calls by tool and outcome (ok, error, denied, not_found).",
+	}, []string{"tool", "outcome"})
+
+	eventsPublished = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "eventbus",
+		Name:      "published_total",
+		Help:      "Messages published to the event bus by topic and outcome.",
+	}, []string{"topic", "outcome"})
+	eventsConsumed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "eventbus",
+		Name:      "consumed_total",
+		Help:      "Handler attempts by handler, topic and outcome; retries count separately.",
+	}, []string{"handler", "topic", "outcome"})
+
+	rateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "ratelimit",
+		Name:      "rejected_total",
+		Help:      "Requests rejected by the rate limiter by route class and scope.",
+	}, []string{"class", "scope"})
+
+	jobDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "jobs",
+		Name:      "run_duration_seconds",
+		Help:      "Background job attempt wall time by kind and outcome (succeeded, retried, failed, cancelled, requeued).",
+		Buckets:   []float64{0.1, 0.5, 1, 5, 15, 60, 300, 900, 3600},
+	}, []string{"kind", "outcome"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests, httpDuration,
+		schedulerDuration, schedulerIterations, schedulerFinalCost,
+		llmDuration, llmTokens, agentToolCalls,
+		eventsPublished, eventsConsumed,
+		rateLimited,
+		jobDuration,
+	)
+}
+
+// Handler serves the registry in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// ObserveSchedulerRun records one schedule generation. iterations and
+// finalCost are only recorded for successful runs.
+func ObserveSchedulerRun(d time.Duration, iterations, finalCost int, err error) {
+	outcome := outcomeOf(err)
+	schedulerDuration.WithLabelValues(outcome).Observe(d.Seconds())
+	if outcome == OutcomeOK {
+		schedulerIterations.Observe(float64(iterations))
+		schedulerFinalCost.Observe(float64(finalCost))
+	}
+}
+
+// ObserveLLMCall records one LLM request.
+func ObserveLLMCall(provider string, d time.Duration, err error) {
+	llmDuration.WithLabelValues(provider, outcomeOf(err)).Observe(d.Seconds())
+}
+
+// AddLLMTokens counts tokens reported by the provider.
+func AddLLMTokens(provider string, prompt, completion int) {
+	if prompt > 0 {
+		llmTokens.WithLabelValues(provider, "prompt").Add(float64(prompt))
+	}
+	if completion > 0 {
+		llmTokens.WithLabelValues(provider, "completion").Add(float64(completion))
+	}
+}
+
+// ObserveToolCall counts one agent tool call with an Outcome* value.
+func ObserveToolCall(tool, outcome string) {
+	agentToolCalls.WithLabelValues(tool, outcome).Inc()
+}
+
+// ObservePublish counts n messages published to topic.
+func ObservePublish(topic string, n int, err error) {
+	eventsPublished.WithLabelValues(topic, outcomeOf(err)).Add(float64(n))
+}
+
+// ObserveConsume counts one handler attempt.
+func ObserveConsume(handler, topic string, err error) {
+	eventsConsumed.WithLabelValues(handler, topic, outcomeOf(err)).Inc()
+}
+
+// ObserveRateLimited counts one request rejected by the rate limiter.
+func ObserveRateLimited(class, scope string) {
+	rateLimited.WithLabelValues(class, scope).Inc()
+}
+
+// ObserveJob records one attempt of a background job.
+func ObserveJob(kind, outcome string, d time.Duration) {
+	jobDuration.WithLabelValues(kind, outcome).Observe(d.Seconds())
+}
+
+func outcomeOf(err error) string {
+	switch {
+	case err == nil:
+		return OutcomeOK
+	case errors.Is(err, context.Canceled):
+		return OutcomeCancelled
+	default:
+		return OutcomeError
+	}
+}
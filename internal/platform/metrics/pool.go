@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// RegisterPool exposes pgxpool statistics for pool. Call once per pool.
+func RegisterPool(pool *pgxpool.Pool) error {
+	return Registry.Register(&poolCollector{pool: pool})
+}
+
+var (
+	poolAcquiredConns    = poolDesc("acquired_conns", "Connections currently checked out.")
+	poolIdleConns        = poolDesc("idle_conns", "Idle connections in the pool.")
+	poolConstructing     = poolDesc("constructing_conns", "Connections being established.")
+	poolTotalConns       = poolDesc("total_conns", "Total connections in the pool.")
+	poolMaxConns         = poolDesc("max_conns", "Configured maximum pool size.")
+	poolAcquires         = poolDesc("acquires_total", "Successful connection acquires.")
+	poolAcquireSeconds   = poolDesc("acquire_duration_seconds_total", "Total time spent waiting to acquire connections.")
+	poolEmptyAcquires    = poolDesc("empty_acquires_total", "Acquires that had to wait because the pool was empty.")
+	poolCanceledAcquires = poolDesc("canceled_acquires_total", "Acquires cancelled by their context.")
+)
+
+func poolDesc(name, help string) *prometheus.Desc {
+	return prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", name), help, nil, nil)
+}
+
+// poolCollector reads pgxpool.Stat on every scrape.
+type poolCollector struct {
+	pool *pgxpool.Pool
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{
+		poolAcquiredConns, poolIdleConns, poolConstructing, poolTotalConns, poolMaxConns,
+		poolAcquires, poolAcquireSeconds, poolEmptyAcquires, poolCanceledAcquires,
+	} {
+		ch <- d
+	}
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.pool.Stat()
+	ch <- prometheus.MustNewConstMetric(poolAcquiredConns, prometheus.GaugeValue, float64(s.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(poolIdleConns, prometheus.GaugeValue, float64(s.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(poolConstructing, prometheus.GaugeValue, float64(s.ConstructingConns()))
+	ch <- prometheus.MustNewConstMetric(poolTotalConns, prometheus.GaugeValue, float64(s.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(poolMaxConns, prometheus.GaugeValue, float64(s.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(poolAcquires, prometheus.CounterValue, float64(s.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolAcquireSeconds, prometheus.CounterValue, s.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(poolEmptyAcquires, prometheus.CounterValue, float64(s.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolCanceledAcquires, prometheus.CounterValue, float64(s.CanceledAcquireCount()))
+}
@@ -0,0 +1,66 @@
+//go:build integration
+
+package platform_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	coredomain "github.com/HuynhHoangPhuc/mcs-erp/internal/core/domain"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/metrics"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/testutil"
+)
+
+func TestMetrics_ReportsRoutesByTenant(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	schema := db.CreateTenantSchema(t)
+	srv := testutil.TestServer(t, db.Pool)
+	defer srv.Close()
+
+	if err := metrics.RegisterPool(db.Pool); err != nil {
+		t.Fatalf("register pool metrics: %v", err)
+	}
+
+	token := testutil.GenerateTestToken(t, uuid.New(), schema, []string{coredomain.PermTeacherRead})
+	req, err := testutil.AuthenticatedRequest(http.MethodGet, srv.URL+"/api/v1/teachers/"+uuid.NewString(), token, schema, nil)
+	if err != nil {
+		t.Fatalf("build request: %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("get teacher: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown teacher, got %d", resp.StatusCode)
+	}
+
+	// /metrics is public: no token or tenant header.
+	resp, err = http.Get(srv.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("get /metrics: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 from /metrics, got %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read /metrics: %v", err)
+	}
+
+	for _, want := range []string{
+		`mcs_http_requests_total{route="GET /api/v1/teachers/{id}",status="404",tenant="` + schema + `"} 1`,
+		`mcs_http_request_duration_seconds_bucket{route="GET /api/v1/teachers/{id}",status="404",tenant="` + schema + `"`,
+		`mcs_db_pool_total_conns`,
+		`go_goroutines`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Fatalf("expected /metrics to contain %q", want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/requestinfo"
+)
+
+// probePaths are polled by orchestrators and scrapers; their requests are
+// logged at debug level only.
+var probePaths = map[string]bool{"/healthz": true, "/readyz": true, "/metrics": true}
+
+// AccessLog logs one structured line per request once it completes: method,
+// path, status, bytes, duration, client IP and user agent, plus the request
+// ID, tenant and user from requestinfo (set by the tenant and auth middleware
+// further in). It must sit inside requestinfo.Middleware. Server errors are
+// logged at warn level.
+func AccessLog(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &recorder{ResponseWriter: w}
+			defer func() {
+				status := rec.status
+				if status == 0 {
+					// Nothing written: net/http sends an empty 200.
+					status = http.StatusOK
+				}
+				level := slog.LevelInfo
+				switch {
+				case status >= 500:
+					level = slog.LevelWarn
+				case probePaths[r.URL.Path]:
+					level = slog.LevelDebug
+				}
+				info := requestinfo.FromContext(r.Context())
+				logger.LogAttrs(r.Context(), level, "http request",
+					slog.String("method", r.Method),
+					slog.String("path", r.URL.Path),
+					slog.Int("status", status),
+					slog.Int64("bytes", rec.bytes),
+					slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+					slog.String("client_ip", info.ClientIP),
+					slog.String("user_agent", r.UserAgent()),
+					slog.String("request_id", info.ID),
+					slog.String("tenant", info.Tenant),
+					slog.String("user_id", info.UserID),
+				)
+			}()
+			next.ServeHTTP(rec, r)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/etag"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/idempotency"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/problem"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/ratelimit"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/requestinfo"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
+	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
+)
+
+// TenantPlaceholder in an allowed origin stands for one subdomain label
+// naming a tenant, as in tenant.Resolve: faculty-a is tenant faculty_a.
+const TenantPlaceholder = "{tenant}"
+
+// CORSConfig configures CORS.
+type CORSConfig struct {
+	// Origins allowed to call the API: exact origins
+	// ("http://localhost:5173") or templates with TenantPlaceholder
+	// ("https://{tenant}.erp.example.com"). "*" allows any origin.
+	Origins []string
+	// CheckTenant accepts a tenant matched by a template (it must exist and
+	// be active); nil accepts any well-formed schema name.
+	CheckTenant func(ctx context.Context, schema string) error
+	// MaxAge is how long browsers may cache a preflight; default 10 minutes.
+	MaxAge time.Duration
+}
+
+// Methods, request headers and response headers a cross-origin client uses.
+var (
+	corsMethods        = "GET, POST, PUT, PATCH, DELETE"
+	corsRequestHeaders = strings.Join([]string{
+		"Authorization", "Content-Type", "X-Tenant-ID", requestinfo.Header,
+		idempotency.Header, etag.IfMatchHeader,
+	}, ", ")
+	corsExposedHeaders = strings.Join([]string{
+		requestinfo.Header, etag.Header, idempotency.ReplayedHeader,
+		ratelimit.LimitHeader, ratelimit.RemainingHeader, ratelimit.ResetHeader,
+		ratelimit.PolicyHeader, ratelimit.RetryAfterHeader,
+		"Content-Disposition", "X-Conversation-ID",
+	}, ", ")
+)
+
+// CORS answers preflight requests and marks responses readable by allowed
+// origins. A template origin only serves its own tenant: when the request
+// names another one (by subdomain or X-Tenant-ID) the origin is refused, so
+// one tenant's site cannot read another tenant's data through a user's
+// browser. Bearer tokens are not cookies, so credentials are not allowed.
+func CORS(cfg CORSConfig) func(http.Handler) http.Handler {
+	var exact []string
+	var templates []*regexp.Regexp
+	for _, o := range cfg.Origins {
+		o = strings.ToLower(strings.TrimSuffix(o, "/"))
+		prefix, suffix, ok := strings.Cut(o, TenantPlaceholder)
+		if !ok {
+			exact = append(exact, o)
+			continue
+		}
+		templates = append(templates, regexp.MustCompile(`^`+regexp.QuoteMeta(prefix)+`([a-z0-9-]+)`+regexp.QuoteMeta(suffix)+`$`))
+	}
+	maxAge := cfg.MaxAge
+	if maxAge == 0 {
+		maxAge = 10 * time.Minute
+	}
+
+	allowed := func(r *http.Request, origin string) bool {
+		origin = strings.ToLower(origin)
+		for _, o := range exact {
+			if o == "*" || o == origin {
+				return true
+			}
+		}
+		for _, t := range templates {
+			m := t.FindStringSubmatch(origin)
+			if m == nil {
+				continue
+			}
+			schema := strings.ReplaceAll(m[1], "-", "_")
+			if tenant.ValidateSchema(schema) != nil {
+				continue
+			}
+			if named, err := tenant.Resolve(r); err == nil && named != schema {
+				continue
+			}
+			if cfg.CheckTenant == nil || cfg.CheckTenant(r.Context(), schema) == nil {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			h := w.Header()
+			h.Add("Vary", "Origin")
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+			if !allowed(r, origin) {
+				if preflight {
+					problem.Write(w, r, erptypes.Forbidden("origin not allowed"))
+					return
+				}
+				// Served, but the browser will not let the page read it.
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h.Set("Access-Control-Allow-Origin", origin)
+			if preflight {
+				h.Add("Vary", "Access-Control-Request-Method")
+				h.Add("Vary", "Access-Control-Request-Headers")
+				h.Set("Access-Control-Allow-Methods", corsMethods)
+				h.Set("Access-Control-Allow-Headers", corsRequestHeaders)
+				h.Set("Access-Control-Max-Age", strconv.Itoa(int(maxAge.Seconds())))
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			h.Set("Access-Control-Expose-Headers", corsExposedHeaders)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
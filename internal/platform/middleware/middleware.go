@@ -0,0 +1,54 @@
+// Package middleware holds the HTTP middleware cmd/server wraps around the
+// mux: access logging, panic recovery, CORS and per-route timeouts. Request
+// IDs come from requestinfo, tenant resolution from tenant, metrics and
+// spans from metrics and tracing; Chain puts them in order.
+package middleware
+
+import "net/http"
+
+// Chain wraps h in mws, the first outermost:
+//
+//	Chain(mux, requestinfo.Middleware(proxies), AccessLog(logger), Recover)
+//
+// serves a request through requestinfo, then AccessLog, then Recover.
+func Chain(h http.Handler, mws ...func(http.Handler) http.Handler) http.Handler {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	return h
+}
+
+// recorder captures the status and size of a response while keeping
+// streaming (http.Flusher) and http.ResponseController working.
+type recorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (r *recorder) WriteHeader(code int) {
+	if r.status == 0 {
+		r.status = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += int64(n)
+	return n, err
+}
+
+func (r *recorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (r *recorder) Unwrap() http.ResponseWriter { return r.ResponseWriter }
+
+// written reports whether the response has started.
+func (r *recorder) written() bool { return r.status != 0 }
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/problem"
+	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
+)
+
+// Recover turns a panicking handler into a 500 problem response, logged with
+// the panic value and stack, instead of a dropped connection. A panic after
+// the response has started can only be logged; the client sees a truncated
+// body. http.ErrAbortHandler is re-panicked, as net/http expects.
+func Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &recorder{ResponseWriter: w}
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+			err := erptypes.Internal("internal error", fmt.Errorf("panic: %v\n%s", v, debug.Stack()))
+			if rec.written() {
+				problem.Log(r, err)
+				return
+			}
+			problem.Write(rec, r, err)
+		}()
+		next.ServeHTTP(rec, r)
+	})
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/problem"
+	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
+	pkgmod "github.com/HuynhHoangPhuc/mcs-erp/pkg/module"
+)
+
+// writeGrace is how long past its timeout a request may still write, so the
+// handler can report the timeout itself.
+const writeGrace = 5 * time.Second
+
+// Timeout bounds every request by its route's Timeout (pkg/module.Route), or
+// def for routes without one and for requests no route matches: the request
+// context gets that deadline and the connection's write deadline follows it,
+// replacing the server's WriteTimeout. Routes with a negative Timeout (event
+// streams) run unbounded. A handler that returns without a response after
+// the deadline gets a 503 problem; handlers that report the context error
+// themselves get the same through problem.Write.
+//
+// mux is the mux serving routes; it is only asked which pattern matches.
+func Timeout(mux *http.ServeMux, def time.Duration, routes []pkgmod.Route) func(http.Handler) http.Handler {
+	byPattern := make(map[string]time.Duration, len(routes))
+	for _, rt := range routes {
+		if rt.Timeout != 0 {
+			byPattern[rt.Method+" "+rt.Path] = rt.Timeout
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			d := def
+			if _, pattern := mux.Handler(r); pattern != "" {
+				if t, ok := byPattern[pattern]; ok {
+					d = t
+				}
+			}
+			rc := http.NewResponseController(w)
+			if d <= 0 {
+				_ = rc.SetWriteDeadline(time.Time{})
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+			_ = rc.SetWriteDeadline(time.Now().Add(d + writeGrace))
+			rec := &recorder{ResponseWriter: w}
+			next.ServeHTTP(rec, r.WithContext(ctx))
+			if !rec.written() && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				problem.Write(rec, r.WithContext(ctx), erptypes.New(erptypes.CodeUnavailable, "request timed out"))
+			}
+		})
+	}
+}
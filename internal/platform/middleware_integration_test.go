@@ -0,0 +1,149 @@
+//go:build integration
+
+package platform_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/middleware"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/problem"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/requestinfo"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/testutil"
+	pkgmod "github.com/HuynhHoangPhuc/mcs-erp/pkg/module"
+)
+
+func TestMiddleware_CORSPerTenantOrigin(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	schema := db.CreateTenantSchema(t)
+	other := db.CreateTenantSchema(t)
+	srv := testutil.TestServer(t, db.Pool)
+	defer srv.Close()
+
+	tenantOrigin := "http://" + strings.ReplaceAll(schema, "_", "-") + ".app.test"
+	do := func(method, origin, tenantID string) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(method, srv.URL+"/api/v1/teachers", nil)
+		req.Header.Set("Origin", origin)
+		if method == http.MethodOptions {
+			req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+			req.Header.Set("Access-Control-Request-Headers", "authorization, x-tenant-id")
+		}
+		if tenantID != "" {
+			req.Header.Set("X-Tenant-ID", tenantID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s from %s: %v", method, origin, err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	// Preflights carry no tenant header; the origin alone decides.
+	for _, origin := range []string{"http://localhost:5173", tenantOrigin} {
+		resp := do(http.MethodOptions, origin, "")
+		if resp.StatusCode != http.StatusNoContent || resp.Header.Get("Access-Control-Allow-Origin") != origin {
+			t.Fatalf("preflight from %s: status %d, allow-origin %q", origin, resp.StatusCode, resp.Header.Get("Access-Control-Allow-Origin"))
+		}
+		if !strings.Contains(resp.Header.Get("Access-Control-Allow-Headers"), "X-Tenant-ID") {
+			t.Fatalf("expected X-Tenant-ID in allowed headers, got %q", resp.Header.Get("Access-Control-Allow-Headers"))
+		}
+	}
+	for _, origin := range []string{"http://evil.test", "http://no-such-tenant.app.test"} {
+		if resp := do(http.MethodOptions, origin, ""); resp.StatusCode != http.StatusForbidden || resp.Header.Get("Access-Control-Allow-Origin") != "" {
+			t.Fatalf("expected preflight from %s refused, got %d", origin, resp.StatusCode)
+		}
+	}
+
+	// A tenant's origin may read its own tenant's responses, not another's.
+	if resp := do(http.MethodGet, tenantOrigin, schema); resp.Header.Get("Access-Control-Allow-Origin") != tenantOrigin {
+		t.Fatal("expected own tenant readable from tenant origin")
+	}
+	if resp := do(http.MethodGet, tenantOrigin, other); resp.Header.Get("Access-Control-Allow-Origin") != "" {
+		t.Fatal("expected other tenant unreadable from tenant origin")
+	}
+}
+
+func TestMiddleware_RecoverTimeoutAndAccessLog(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /panic", func(http.ResponseWriter, *http.Request) { panic("boom") })
+	slow := func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+			problem.Write(w, r, r.Context().Err())
+		case <-time.After(300 * time.Millisecond):
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}
+	mux.HandleFunc("GET /slow", slow)
+	mux.HandleFunc("GET /slow-allowed", slow)
+	mux.HandleFunc("GET /whoami", func(w http.ResponseWriter, r *http.Request) {
+		requestinfo.SetUser(r.Context(), "user-1")
+		w.WriteHeader(http.StatusNoContent)
+	})
+	routes := []pkgmod.Route{{Method: "GET", Path: "/slow-allowed", Timeout: time.Second}}
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+	srv := httptest.NewServer(middleware.Chain(mux,
+		requestinfo.Middleware(nil),
+		middleware.AccessLog(logger),
+		middleware.Recover,
+		middleware.Timeout(mux, 100*time.Millisecond, routes),
+		tenant.Middleware,
+	))
+	defer srv.Close()
+
+	get := func(path string) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+		req.Header.Set("X-Tenant-ID", "faculty_a")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("get %s: %v", path, err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	// A panic is a 500 problem, not a dropped connection.
+	resp := get("/panic")
+	if resp.StatusCode != http.StatusInternalServerError || resp.Header.Get("Content-Type") != problem.ContentType {
+		t.Fatalf("expected 500 problem after panic, got %d %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	// The default timeout cuts /slow; /slow-allowed has its own.
+	if resp := get("/slow"); resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 after the default timeout, got %d", resp.StatusCode)
+	}
+	if resp := get("/slow-allowed"); resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("expected route timeout to allow /slow-allowed, got %d", resp.StatusCode)
+	}
+
+	// The access log has the request ID, tenant and user.
+	resp = get("/whoami")
+	var entry map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		var e map[string]any
+		if err := json.Unmarshal([]byte(line), &e); err == nil && e["msg"] == "http request" && e["path"] == "/whoami" {
+			entry = e
+		}
+	}
+	if entry == nil {
+		t.Fatalf("no access log line for /whoami in:\n%s", logs.String())
+	}
+	if entry["request_id"] != resp.Header.Get(requestinfo.Header) || entry["tenant"] != "faculty_a" || entry["user_id"] != "user-1" {
+		t.Fatalf("unexpected access log entry: %v", entry)
+	}
+	if entry["status"] != float64(http.StatusNoContent) {
+		t.Fatalf("expected status 204 in access log, got %v", entry["status"])
+	}
+}
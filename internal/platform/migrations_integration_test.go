@@ -0,0 +1,85 @@
+//go:build integration
+
+package platform_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/database"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/testutil"
+	"github.com/HuynhHoangPhuc/mcs-erp/migrations"
+)
+
+func TestMigrator_AppliesOnlyPendingStepsAndRollsBack(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	schema := db.CreateTenantSchema(t)
+	ctx := context.Background()
+	migrator := database.NewMigrator(db.Pool)
+
+	// CreateTenantSchema already applied everything: a second pass is a no-op.
+	for _, module := range migrations.TenantModules {
+		migs, err := database.LoadMigrations(module)
+		if err != nil {
+			t.Fatalf("load %s migrations: %v", module, err)
+		}
+		n, err := migrator.Up(ctx, schema, migs)
+		if err != nil {
+			t.Fatalf("re-apply %s: %v", module, err)
+		}
+		if n != 0 {
+			t.Fatalf("expected no pending %s migrations, applied %d", module, n)
+		}
+	}
+
+	hrMigs, err := database.LoadMigrations("hr")
+	if err != nil {
+		t.Fatalf("load hr migrations: %v", err)
+	}
+
+	n, err := migrator.Down(ctx, schema, hrMigs, 1)
+	if err != nil {
+		t.Fatalf("roll back hr: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("expected 1 reverted step, got %d", n)
+	}
+	if tableExists(t, db, schema, "teacher_availability") {
+		t.Fatal("expected teacher_availability to be dropped by rollback")
+	}
+	if !tableExists(t, db, schema, "teachers") {
+		t.Fatal("expected teachers to survive a single-step rollback")
+	}
+
+	applied, err := migrator.AppliedVersions(ctx, schema, "hr")
+	if err != nil {
+		t.Fatalf("applied versions: %v", err)
+	}
+	if len(applied) != len(hrMigs)-1 {
+		t.Fatalf("expected %d applied hr versions, got %v", len(hrMigs)-1, applied)
+	}
+
+	n, err = migrator.Up(ctx, schema, hrMigs)
+	if err != nil {
+		t.Fatalf("re-apply hr: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("expected only the reverted step to be re-applied, got %d", n)
+	}
+	if !tableExists(t, db, schema, "teacher_availability") {
+		t.Fatal("expected teacher_availability to be recreated")
+	}
+}
+
+func tableExists(t *testing.T, db *testutil.TestDB, schema, table string) bool {
+	t.Helper()
+	var exists bool
+	err := db.Pool.QueryRow(context.Background(),
+		`SELECT EXISTS (SELECT 1 FROM information_schema.tables WHERE table_schema = $1 AND table_name = $2)`,
+		schema, table,
+	).Scan(&exists)
+	if err != nil {
+		t.Fatalf("check table %s.%s: %v", schema, table, err)
+	}
+	return exists
+}
@@ -2,9 +2,15 @@ package module
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+
+	"google.golang.org/grpc"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/health"
+	pkgmod "github.com/HuynhHoangPhuc/mcs-erp/pkg/module"
 )
 
 // Bootstrap resolves module order and initializes each module in sequence:
@@ -33,3 +39,105 @@ func Bootstrap(ctx context.Context, reg *Registry, mux *http.ServeMux) error {
 
 	return nil
 }
+
+// Start calls Start on every module implementing pkg/module.Starter, in
+// dependency order. If one fails, the modules already started are stopped in
+// reverse order and the error is returned.
+func Start(ctx context.Context, reg *Registry) error {
+	modules, err := reg.ResolveOrder()
+	if err != nil {
+		return fmt.Errorf("resolve module order: %w", err)
+	}
+	for i, m := range modules {
+		s, ok := m.(pkgmod.Starter)
+		if !ok {
+			continue
+		}
+		if err := s.Start(ctx); err != nil {
+			if stopErr := stopModules(context.WithoutCancel(ctx), modules[:i]); stopErr != nil {
+				slog.Error("stop modules after failed start", "error", stopErr)
+			}
+			return fmt.Errorf("start module %q: %w", m.Name(), err)
+		}
+		slog.Info("module started", "module", m.Name())
+	}
+	return nil
+}
+
+// Stop calls Stop on every module implementing pkg/module.Stopper, in reverse
+// dependency order. Every module is stopped even if an earlier one fails; the
+// errors are joined.
+func Stop(ctx context.Context, reg *Registry) error {
+	modules, err := reg.ResolveOrder()
+	if err != nil {
+		return fmt.Errorf("resolve module order: %w", err)
+	}
+	return stopModules(ctx, modules)
+}
+
+func stopModules(ctx context.Context, modules []pkgmod.Module) error {
+	var errs []error
+	for i := len(modules) - 1; i >= 0; i-- {
+		s, ok := modules[i].(pkgmod.Stopper)
+		if !ok {
+			continue
+		}
+		if err := s.Stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("stop module %q: %w", modules[i].Name(), err))
+			continue
+		}
+		slog.Info("module stopped", "module", modules[i].Name())
+	}
+	return errors.Join(errs...)
+}
+
+// RegisterGRPC registers the gRPC services of every module implementing
+// pkg/module.GRPCRegistrar and records their method permissions in perms.
+func RegisterGRPC(reg *Registry, s grpc.ServiceRegistrar, perms map[string]string) error {
+	modules, err := reg.ResolveOrder()
+	if err != nil {
+		return fmt.Errorf("resolve module order: %w", err)
+	}
+	for _, m := range modules {
+		r, ok := m.(pkgmod.GRPCRegistrar)
+		if !ok {
+			continue
+		}
+		for method, perm := range r.RegisterGRPC(s) {
+			perms[method] = perm
+		}
+		slog.Info("grpc services registered", "module", m.Name())
+	}
+	return nil
+}
+
+// RegisterHealthChecks adds the checks of every module implementing
+// pkg/module.HealthChecker to checker.
+func RegisterHealthChecks(reg *Registry, checker *health.Checker) error {
+	modules, err := reg.ResolveOrder()
+	if err != nil {
+		return fmt.Errorf("resolve module order: %w", err)
+	}
+	for _, m := range modules {
+		if hc, ok := m.(pkgmod.HealthChecker); ok {
+			checker.Add(m.Name(), hc.HealthChecks()...)
+		}
+	}
+	return nil
+}
+
+// Routes returns the REST routes of every module implementing
+// pkg/module.RouteDescriber, in dependency order. Call it after Bootstrap.
+func Routes(reg *Registry) ([]pkgmod.Route, error) {
+	modules, err := reg.ResolveOrder()
+	if err != nil {
+		return nil, fmt.Errorf("resolve module order: %w", err)
+	}
+	var routes []pkgmod.Route
+	for _, m := range modules {
+		if rd, ok := m.(pkgmod.RouteDescriber); ok {
+			routes = append(routes, rd.Routes()...)
+		}
+	}
+	return routes, nil
+}
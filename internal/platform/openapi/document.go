@@ -0,0 +1,268 @@
+// Package openapi describes the REST API as an OpenAPI 3.1 document built at
+// runtime from the routes modules register (see Router), and serves it
+// filtered to what the caller may use:
+//
+//	GET /api/v1/openapi.json
+//
+// Anonymous callers see the public routes; a tenant user also sees the
+// routes of the tenant's enabled modules their permissions allow; the
+// platform operator sees the platform routes.
+package openapi
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/problem"
+	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
+	pkgmod "github.com/HuynhHoangPhuc/mcs-erp/pkg/module"
+)
+
+// Path is where the document is served.
+const Path = "/api/v1/openapi.json"
+
+// Version is the OpenAPI version of the document.
+const Version = "3.1.0"
+
+// Info identifies the API.
+type Info struct {
+	Title   string
+	Version string
+}
+
+// Filter decides which routes a caller may see.
+type Filter func(pkgmod.Route) bool
+
+// Document holds the operations of every route; Build selects a caller's.
+type Document struct {
+	info    Info
+	ops     []operation
+	schemas *components
+}
+
+type operation struct {
+	route pkgmod.Route
+	body  map[string]any
+	refs  map[string]bool // component schemas the operation uses
+}
+
+// NewDocument describes routes. Operation ids are made unique by prefixing
+// the module name to the ones that clash. Named response types become
+// component schemas, prefixed by their module when two modules use the same
+// name.
+func NewDocument(info Info, routes []pkgmod.Route) *Document {
+	d := newDocument(info, routes, nil)
+	if len(d.schemas.clashes) > 0 {
+		// Describe again now that every clashing name is known.
+		d = newDocument(info, routes, d.schemas.clashes)
+	}
+	return d
+}
+
+func newDocument(info Info, routes []pkgmod.Route, qualify map[string]bool) *Document {
+	count := map[string]int{}
+	for _, r := range routes {
+		count[r.OperationID]++
+	}
+	d := &Document{info: info, schemas: newComponents(qualify)}
+	d.schemas.define("Problem", SchemaOf(problemBody{}))
+	g := &generator{components: d.schemas}
+	for _, r := range routes {
+		if r.OperationID == "" || count[r.OperationID] > 1 {
+			r.OperationID = r.Module + "." + cmpOr(r.OperationID, strings.ToLower(r.Method)+strings.ReplaceAll(r.Path, "/", "_"))
+		}
+		refs := map[string]bool{}
+		d.ops = append(d.ops, operation{route: r, body: describe(r, g, refs), refs: refs})
+	}
+	return d
+}
+
+// Build returns the document with the routes allow accepts; nil allows all.
+func (d *Document) Build(allow Filter) map[string]any {
+	paths := map[string]map[string]any{}
+	used := map[string]bool{"Problem": true}
+	for _, op := range d.ops {
+		if allow != nil && !allow(op.route) {
+			continue
+		}
+		for name := range op.refs {
+			used[name] = true
+		}
+		item := paths[op.route.Path]
+		if item == nil {
+			item = map[string]any{}
+			paths[op.route.Path] = item
+		}
+		item[strings.ToLower(op.route.Method)] = op.body
+	}
+	d.schemas.closure(used)
+	schemas := map[string]any{}
+	for name := range used {
+		schemas[name] = d.schemas.schemas[name]
+	}
+
+	components := maps.Clone(sharedComponents)
+	components["schemas"] = schemas
+	return map[string]any{
+		"openapi": Version,
+		"info": map[string]any{
+			"title":   d.info.Title,
+			"version": d.info.Version,
+		},
+		"paths":      paths,
+		"components": components,
+	}
+}
+
+// Handler serves the document, filtered by the Filter filter returns for the
+// request.
+func (d *Document) Handler(filter func(*http.Request) Filter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "private, no-cache")
+		json.NewEncoder(w).Encode(d.Build(filter(r)))
+	})
+}
+
+var pathParam = regexp.MustCompile(`\{([A-Za-z0-9_]+)(\.\.\.)?\}`)
+
+// describe builds the operation object of a route, recording the component
+// schemas it references in refs.
+func describe(r pkgmod.Route, g *generator, refs map[string]bool) map[string]any {
+	op := map[string]any{
+		"operationId": r.OperationID,
+		"tags":        []string{r.Module},
+	}
+	if r.Summary != "" {
+		op["summary"] = r.Summary
+	}
+
+	var params []any
+	for _, m := range pathParam.FindAllStringSubmatch(r.Path, -1) {
+		s := Schema{"type": "string"}
+		if m[1] == "id" || strings.HasSuffix(m[1], "Id") {
+			s["format"] = "uuid"
+		}
+		params = append(params, map[string]any{"name": m[1], "in": "path", "required": true, "schema": s})
+	}
+	for _, p := range r.Query {
+		s := Schema{"type": cmpOr(p.Type, "string")}
+		if p.Format != "" {
+			s["format"] = p.Format
+		}
+		param := map[string]any{"name": p.Name, "in": "query", "schema": s}
+		if p.Description != "" {
+			param["description"] = p.Description
+		}
+		if p.Required {
+			param["required"] = true
+		}
+		params = append(params, param)
+	}
+
+	switch r.Access {
+	case pkgmod.AccessPlatform:
+		op["security"] = []any{map[string]any{"platformToken": []string{}}}
+	case pkgmod.AccessPublic:
+		op["security"] = []any{}
+		params = append(params, map[string]any{"$ref": "#/components/parameters/TenantID"})
+	default:
+		op["security"] = []any{map[string]any{"bearerAuth": []string{}}}
+		params = append(params, map[string]any{"$ref": "#/components/parameters/TenantID"})
+		if r.Permission != "" {
+			op["x-permission"] = r.Permission
+		}
+	}
+	if len(params) > 0 {
+		op["parameters"] = params
+	}
+
+	if r.Request != nil {
+		op["requestBody"] = map[string]any{
+			"required": true,
+			"content":  map[string]any{"application/json": map[string]any{"schema": requestSchema(SchemaOf(r.Request))}},
+		}
+	}
+
+	status := r.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	ok := map[string]any{"description": http.StatusText(status)}
+	switch {
+	case r.Produces != "":
+		ok["content"] = map[string]any{r.Produces: map[string]any{"schema": Schema{"type": "string"}}}
+	case r.Response != nil:
+		ok["content"] = map[string]any{"application/json": map[string]any{"schema": g.schema(reflect.TypeOf(r.Response), refs)}}
+	}
+	op["responses"] = map[string]any{
+		strconv.Itoa(status): ok,
+		"default":            map[string]any{"$ref": "#/components/responses/Problem"},
+	}
+	return op
+}
+
+// requestSchema drops "required" from a request body schema: which fields a
+// request needs is checked by its Validate method and reported per field.
+func requestSchema(s Schema) Schema {
+	delete(s, "required")
+	if props, ok := s["properties"].(Schema); ok {
+		for k, p := range props {
+			if ps, ok := p.(Schema); ok {
+				props[k] = requestSchema(ps)
+			}
+		}
+	}
+	if items, ok := s["items"].(Schema); ok {
+		s["items"] = requestSchema(items)
+	}
+	return s
+}
+
+// problemBody is problem.Details as it is encoded (Meta adds members).
+type problemBody struct {
+	Type      string                    `json:"type"`
+	Title     string                    `json:"title"`
+	Status    int                       `json:"status"`
+	Detail    string                    `json:"detail,omitempty"`
+	Instance  string                    `json:"instance,omitempty"`
+	Code      erptypes.Code             `json:"code"`
+	Errors    []erptypes.FieldViolation `json:"errors,omitempty"`
+	RequestID string                    `json:"request_id,omitempty"`
+}
+
+// sharedComponents are the components every document carries; the schemas
+// are added per document.
+var sharedComponents = map[string]any{
+	"securitySchemes": map[string]any{
+		"bearerAuth":    map[string]any{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+		"platformToken": map[string]any{"type": "apiKey", "in": "header", "name": "X-Platform-Token"},
+	},
+	"parameters": map[string]any{
+		"TenantID": map[string]any{
+			"name": "X-Tenant-ID", "in": "header",
+			"description": "Tenant schema, unless the host's subdomain names it",
+			"schema":      Schema{"type": "string"},
+		},
+	},
+	"responses": map[string]any{
+		"Problem": map[string]any{
+			"description": "Error, as RFC 7807 problem details",
+			"content": map[string]any{
+				problem.ContentType: map[string]any{"schema": map[string]any{"$ref": "#/components/schemas/Problem"}},
+			},
+		},
+	},
+}
+
+func cmpOr(v, def string) string {
+	if v == "" {
+		return def
+	}
+	return v
+}
@@ -0,0 +1,73 @@
+package openapi
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
+	pkgmod "github.com/HuynhHoangPhuc/mcs-erp/pkg/module"
+)
+
+// AsOf is the as_of parameter of endpoints that read an entity at a point in
+// time (see the history package).
+var AsOf = pkgmod.Param{Name: "as_of", Type: "string", Format: "date-time", Description: "Read the entity as it was at this time"}
+
+// ListParams describes the query parameters listquery.Parse accepts for s:
+// limit, offset, cursor, sort and one parameter per filter operator.
+func ListParams(s *listquery.Schema) []pkgmod.Param {
+	names := make([]string, 0, len(s.Fields))
+	var sortable []string
+	for name, f := range s.Fields {
+		names = append(names, name)
+		if f.Sort {
+			sortable = append(sortable, name)
+		}
+	}
+	slices.Sort(names)
+	slices.Sort(sortable)
+
+	params := []pkgmod.Param{
+		{Name: "limit", Type: "integer", Description: "Page size"},
+		{Name: "offset", Type: "integer", Description: "Rows to skip, instead of cursor"},
+		{Name: "cursor", Type: "string", Description: "next_cursor of the previous page"},
+		{Name: "sort", Type: "string", Description: "Comma-separated fields, - for descending: " + strings.Join(sortable, ", ")},
+	}
+	for _, name := range names {
+		f := s.Fields[name]
+		typ, format := kindType(f.Kind)
+		if f.Filter&listquery.Eq != 0 {
+			params = append(params, pkgmod.Param{Name: name, Type: typ, Format: format})
+		}
+		if f.Filter&listquery.In != 0 {
+			params = append(params, pkgmod.Param{Name: name + "[in]", Type: "string", Description: "Comma-separated values"})
+		}
+		if f.Filter&listquery.Range != 0 {
+			for _, op := range []string{"gt", "gte", "lt", "lte"} {
+				params = append(params, pkgmod.Param{Name: name + "[" + op + "]", Type: typ, Format: format})
+			}
+		}
+		if f.Filter&listquery.Contains != 0 {
+			p := pkgmod.Param{Name: name + "[contains]", Type: "string", Description: "Substring"}
+			if f.Array {
+				p.Description = "Comma-separated values, all required"
+			}
+			params = append(params, p)
+		}
+	}
+	return params
+}
+
+func kindType(k listquery.Kind) (typ, format string) {
+	switch k {
+	case listquery.Int:
+		return "integer", ""
+	case listquery.Bool:
+		return "boolean", ""
+	case listquery.Time:
+		return "string", "date-time"
+	case listquery.UUID:
+		return "string", "uuid"
+	default:
+		return "string", ""
+	}
+}
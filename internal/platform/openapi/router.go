@@ -0,0 +1,87 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/auth"
+	pkgmod "github.com/HuynhHoangPhuc/mcs-erp/pkg/module"
+)
+
+// Router mounts a module's routes on a mux and records them for the OpenAPI
+// document, so a route cannot be served without being described (or the
+// other way round). The access rules are applied here too:
+//
+//	api := openapi.NewRouter(mux, m.Name(), authMw)
+//	api.Handle(pkgmod.Route{
+//		Method: "POST", Path: "/api/v1/teachers", Summary: "Create a teacher",
+//		Permission: coredomain.PermTeacherWrite,
+//		Request: delivery.CreateTeacherRequest{}, Response: delivery.TeacherResponse{}, Status: http.StatusCreated,
+//	}, teacherHandler.CreateTeacher)
+//	m.routes = api.Routes()
+type Router struct {
+	mux      *http.ServeMux
+	module   string
+	user     func(http.Handler) http.Handler
+	platform func(http.Handler) http.Handler
+	routes   []pkgmod.Route
+}
+
+// NewRouter returns a router for module's routes. user authenticates
+// AccessUser routes; their Permission is checked after it.
+func NewRouter(mux *http.ServeMux, module string, user func(http.Handler) http.Handler) *Router {
+	return &Router{mux: mux, module: module, user: user}
+}
+
+// WithPlatform sets the middleware guarding AccessPlatform routes.
+func (rt *Router) WithPlatform(mw func(http.Handler) http.Handler) *Router {
+	rt.platform = mw
+	return rt
+}
+
+// Handle mounts h for route and records it. The operation id defaults to the
+// handler's method name.
+func (rt *Router) Handle(route pkgmod.Route, h http.HandlerFunc) {
+	route.Module = rt.module
+	if route.OperationID == "" {
+		route.OperationID = funcName(h)
+	}
+
+	var handler http.Handler = h
+	switch route.Access {
+	case pkgmod.AccessPublic:
+	case pkgmod.AccessPlatform:
+		if rt.platform == nil {
+			panic("openapi: platform route " + route.Path + " without platform middleware")
+		}
+		handler = rt.platform(handler)
+	default:
+		if route.Permission != "" {
+			handler = auth.RequirePermission(route.Permission)(handler)
+		}
+		handler = rt.user(handler)
+	}
+	rt.mux.Handle(route.Method+" "+route.Path, handler)
+	rt.routes = append(rt.routes, route)
+}
+
+// Routes returns the routes mounted so far.
+func (rt *Router) Routes() []pkgmod.Route {
+	return rt.routes
+}
+
+// funcName is the method name of a method value, lower camel case
+// ("createTeacher" for h.CreateTeacher), or "" for other functions.
+func funcName(h http.HandlerFunc) string {
+	name := runtime.FuncForPC(reflect.ValueOf(h).Pointer()).Name()
+	name = strings.TrimSuffix(name, "-fm")
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+	if name == "" || strings.HasPrefix(name, "func") {
+		return ""
+	}
+	return strings.ToLower(name[:1]) + name[1:]
+}
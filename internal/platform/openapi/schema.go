@@ -0,0 +1,264 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// Schema is a JSON Schema (2020-12, as used by OpenAPI 3.1) object.
+type Schema map[string]any
+
+var (
+	timeType    = reflect.TypeFor[time.Time]()
+	uuidType    = reflect.TypeFor[uuid.UUID]()
+	rawType     = reflect.TypeFor[json.RawMessage]()
+	marshalType = reflect.TypeFor[json.Marshaler]()
+)
+
+// SchemaOf derives the schema of v's JSON encoding from its Go type, inline:
+// structs by their json tags (fields without omitempty are required), pointer
+// fields as nullable, time.Time and uuid.UUID as formatted strings. Types with
+// their own MarshalJSON are described as any value. A Schema is returned as is.
+func SchemaOf(v any) Schema {
+	if v == nil {
+		return nil
+	}
+	if s, ok := v.(Schema); ok {
+		return s
+	}
+	return (&generator{}).schema(reflect.TypeOf(v), nil)
+}
+
+// generator derives schemas. With components set, named struct types are
+// defined once in components and referenced; otherwise everything is inline.
+type generator struct {
+	components *components
+	seen       map[reflect.Type]bool // inline structs being expanded
+}
+
+// components holds the named schemas of a document and which other names
+// each one references, so a filtered document can carry just what it uses.
+type components struct {
+	schemas map[string]Schema
+	refs    map[string]map[string]bool
+	names   map[reflect.Type]string
+
+	// Go names used by more than one type; those types are all qualified by
+	// their module. clashes is filled as types are seen, qualify is given.
+	owners  map[string]reflect.Type
+	clashes map[string]bool
+	qualify map[string]bool
+}
+
+func newComponents(qualify map[string]bool) *components {
+	return &components{
+		schemas: map[string]Schema{},
+		refs:    map[string]map[string]bool{},
+		names:   map[reflect.Type]string{},
+		owners:  map[string]reflect.Type{},
+		clashes: map[string]bool{},
+		qualify: qualify,
+	}
+}
+
+// define adds s under name (used for schemas not derived from a Go type).
+func (c *components) define(name string, s Schema) {
+	c.schemas[name] = s
+	c.refs[name] = map[string]bool{}
+}
+
+// closure adds to names every schema they reference, transitively.
+func (c *components) closure(names map[string]bool) {
+	queue := make([]string, 0, len(names))
+	for n := range names {
+		queue = append(queue, n)
+	}
+	for len(queue) > 0 {
+		n := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		for dep := range c.refs[n] {
+			if !names[dep] {
+				names[dep] = true
+				queue = append(queue, dep)
+			}
+		}
+	}
+}
+
+// schema returns the schema of t, recording referenced component names in
+// refs (when components are in use).
+func (g *generator) schema(t reflect.Type, refs map[string]bool) Schema {
+	switch t {
+	case timeType:
+		return Schema{"type": "string", "format": "date-time"}
+	case uuidType:
+		return Schema{"type": "string", "format": "uuid"}
+	case rawType:
+		return Schema{}
+	}
+	if t.Kind() != reflect.Pointer && t.Implements(marshalType) {
+		return Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.schema(t.Elem(), refs)
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Schema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+	case reflect.String:
+		return Schema{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return Schema{"type": "string", "contentEncoding": "base64"}
+		}
+		return Schema{"type": "array", "items": g.schema(t.Elem(), refs)}
+	case reflect.Map:
+		return Schema{"type": "object", "additionalProperties": g.schema(t.Elem(), refs)}
+	case reflect.Struct:
+		if g.components != nil && t.Name() != "" {
+			name := g.component(t)
+			if refs != nil {
+				refs[name] = true
+			}
+			return Schema{"$ref": "#/components/schemas/" + name}
+		}
+		if g.seen[t] {
+			return Schema{"type": "object"} // recursive type
+		}
+		if g.seen == nil {
+			g.seen = map[reflect.Type]bool{}
+		}
+		g.seen[t] = true
+		defer delete(g.seen, t)
+		return g.object(t, refs)
+	default:
+		return Schema{}
+	}
+}
+
+// component defines named struct t in the components (once) and returns its
+// name.
+func (g *generator) component(t reflect.Type) string {
+	c := g.components
+	if name, ok := c.names[t]; ok {
+		return name
+	}
+	name := typeName(t)
+	if owner, ok := c.owners[name]; ok && owner != t {
+		c.clashes[name] = true
+	}
+	c.owners[name] = t
+	if c.qualify[name] {
+		name = pkgName(t.PkgPath()) + name
+	}
+	c.names[t] = name
+	c.schemas[name] = Schema{} // placeholder for recursive types
+	refs := map[string]bool{}
+	c.schemas[name] = g.object(t, refs)
+	c.refs[name] = refs
+	return name
+}
+
+func (g *generator) object(t reflect.Type, refs map[string]bool) Schema {
+	props := Schema{}
+	var required []string
+	g.addFields(t, props, &required, refs)
+	s := Schema{"type": "object", "properties": props}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+// addFields adds the JSON fields of struct t, flattening embedded structs as
+// encoding/json does.
+func (g *generator) addFields(t reflect.Type, props Schema, required *[]string, refs map[string]bool) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				g.addFields(ft, props, required, refs)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		s := g.schema(f.Type, refs)
+		if f.Type.Kind() == reflect.Pointer {
+			s = nullable(s)
+		}
+		props[name] = s
+		if !strings.Contains(opts, "omitempty") && !strings.Contains(opts, "omitzero") {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// nullable allows null besides s.
+func nullable(s Schema) Schema {
+	if typ, ok := s["type"].(string); ok {
+		s["type"] = []string{typ, "null"}
+		return s
+	}
+	if _, ok := s["$ref"]; ok {
+		return Schema{"anyOf": []Schema{s, {"type": "null"}}}
+	}
+	return s
+}
+
+// typeName is the component name of a named type: its Go name, with the
+// type arguments of generic types appended ("PageTeacherResponse" for
+// listquery.Page[delivery.TeacherResponse]).
+func typeName(t reflect.Type) string {
+	name, args, generic := strings.Cut(t.Name(), "[")
+	if !generic {
+		return name
+	}
+	for _, arg := range strings.Split(strings.TrimSuffix(args, "]"), ",") {
+		arg = strings.TrimLeft(arg, "*[]")
+		if i := strings.LastIndexByte(arg, '.'); i >= 0 {
+			arg = arg[i+1:]
+		}
+		name += arg
+	}
+	return name
+}
+
+// pkgName qualifies a clashing component name by the module owning the type:
+// "room" for .../internal/room/delivery.
+func pkgName(path string) string {
+	parts := strings.Split(path, "/")
+	name := parts[len(parts)-1]
+	for i, p := range parts {
+		if p == "internal" && i+1 < len(parts) {
+			name = parts[i+1]
+			break
+		}
+	}
+	r := []rune(name)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
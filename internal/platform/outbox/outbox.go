@@ -0,0 +1,34 @@
+// Package outbox implements the transactional outbox pattern.
+// Repositories write domain events into the tenant's outbox table inside the
+// same transaction as the entity change; the Relay later forwards committed
+// rows to the event bus, so events survive process crashes. The outbox table
+// itself is created by the "platform" migrations.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Write marshals the event to JSON and appends it to the outbox table.
+// MUST be called with a transaction opened by database.WithTenantTx so the
+// row lands in the tenant schema and commits atomically with the change.
+func Write(ctx context.Context, tx pgx.Tx, topic string, event any) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal outbox event: %w", err)
+	}
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO outbox (id, topic, payload) VALUES ($1, $2, $3)`,
+		uuid.New(), topic, payload,
+	)
+	if err != nil {
+		return fmt.Errorf("insert outbox event %s: %w", topic, err)
+	}
+	return nil
+}
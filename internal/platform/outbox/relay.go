@@ -0,0 +1,156 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/database"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/eventbus"
+)
+
+const (
+	defaultPollInterval = time.Second
+	defaultBatchSize    = 100
+	// publishedRetention is how long delivered rows are kept for troubleshooting.
+	publishedRetention = 7 * 24 * time.Hour
+)
+
+// Relay polls every active tenant's outbox and forwards pending rows to the publisher.
+// Rows are claimed with FOR UPDATE SKIP LOCKED so several replicas can relay concurrently.
+// Delivery is at-least-once: consumers must tolerate the occasional duplicate.
+type Relay struct {
+	pool      *pgxpool.Pool
+	publisher message.Publisher
+	interval  time.Duration
+	batchSize int
+}
+
+// NewRelay creates an outbox relay publishing to the given Watermill publisher.
+func NewRelay(pool *pgxpool.Pool, publisher message.Publisher) *Relay {
+	return &Relay{
+		pool:      pool,
+		publisher: publisher,
+		interval:  defaultPollInterval,
+		batchSize: defaultBatchSize,
+	}
+}
+
+// Run relays pending events until ctx is cancelled.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.RelayOnce(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("outbox relay failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayOnce forwards one batch per tenant schema and returns the number of published events.
+// A failing tenant is logged and skipped so it cannot block the others.
+func (r *Relay) RelayOnce(ctx context.Context) (int, error) {
+	schemas, err := database.ActiveTenantSchemas(ctx, r.pool)
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for _, schema := range schemas {
+		n, err := r.relaySchema(ctx, schema)
+		if err != nil {
+			slog.Error("outbox relay schema failed", "schema", schema, "error", err)
+			continue
+		}
+		total += n
+	}
+	return total, nil
+}
+
+type pendingEvent struct {
+	id      uuid.UUID
+	topic   string
+	payload []byte
+}
+
+// relaySchema publishes pending rows in creation order. Publishing stops at the
+// first failure so per-tenant ordering is preserved; the row is retried next tick.
+func (r *Relay) relaySchema(ctx context.Context, schema string) (int, error) {
+	published := 0
+
+	err := database.WithTenantTx(ctx, r.pool, schema, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx,
+			`SELECT id, topic, payload FROM outbox
+			 WHERE published_at IS NULL
+			 ORDER BY created_at
+			 LIMIT $1
+			 FOR UPDATE SKIP LOCKED`,
+			r.batchSize,
+		)
+		if err != nil {
+			return fmt.Errorf("select pending events: %w", err)
+		}
+
+		var pending []pendingEvent
+		for rows.Next() {
+			var e pendingEvent
+			if err := rows.Scan(&e.id, &e.topic, &e.payload); err != nil {
+				rows.Close()
+				return fmt.Errorf("scan pending event: %w", err)
+			}
+			pending = append(pending, e)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		var done []uuid.UUID
+		for _, e := range pending {
+			msg := message.NewMessage(e.id.String(), e.payload)
+			msg.Metadata.Set(eventbus.MetadataTenantID, schema)
+
+			if err := r.publisher.Publish(e.topic, msg); err != nil {
+				if _, uerr := tx.Exec(ctx,
+					`UPDATE outbox SET attempts = attempts + 1, last_error = $2 WHERE id = $1`,
+					e.id, err.Error(),
+				); uerr != nil {
+					return fmt.Errorf("record publish failure: %w", uerr)
+				}
+				slog.Warn("outbox publish failed", "schema", schema, "topic", e.topic, "error", err)
+				break
+			}
+			done = append(done, e.id)
+		}
+
+		if len(done) > 0 {
+			if _, err := tx.Exec(ctx,
+				`UPDATE outbox SET published_at = now(), attempts = attempts + 1 WHERE id = ANY($1)`,
+				done,
+			); err != nil {
+				return fmt.Errorf("mark events published: %w", err)
+			}
+		}
+		published = len(done)
+
+		_, err = tx.Exec(ctx,
+			`DELETE FROM outbox WHERE published_at < $1`,
+			time.Now().Add(-publishedRetention),
+		)
+		return err
+	})
+	return published, err
+}
@@ -0,0 +1,85 @@
+//go:build integration
+
+package platform_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/pubsub/gochannel"
+	"github.com/google/uuid"
+
+	hrdomain "github.com/HuynhHoangPhuc/mcs-erp/internal/hr/domain"
+	hrinfra "github.com/HuynhHoangPhuc/mcs-erp/internal/hr/infrastructure"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/eventbus"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/outbox"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/testutil"
+)
+
+func TestOutbox_RelayPublishesCommittedEvents(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	schema := db.CreateTenantSchema(t)
+	_ = testutil.SeedAdmin(t, db.Pool, schema)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	pubSub := gochannel.NewGoChannel(gochannel.Config{Persistent: true}, watermill.NopLogger{})
+	defer pubSub.Close()
+
+	messages, err := pubSub.Subscribe(ctx, hrdomain.TopicTeacherCreated)
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	now := time.Now()
+	teacher := &hrdomain.Teacher{
+		ID:             uuid.New(),
+		Name:           "Outbox Teacher",
+		Email:          "outbox_" + uuid.NewString() + "@example.com",
+		Qualifications: []string{},
+		IsActive:       true,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	repo := hrinfra.NewPostgresTeacherRepo(db.Pool)
+	if err := repo.Save(tenant.WithTenant(ctx, schema), teacher); err != nil {
+		t.Fatalf("save teacher: %v", err)
+	}
+
+	relay := outbox.NewRelay(db.Pool, pubSub)
+	if _, err := relay.RelayOnce(ctx); err != nil {
+		t.Fatalf("relay once: %v", err)
+	}
+
+	select {
+	case msg := <-messages:
+		msg.Ack()
+		gotTenant, err := eventbus.TenantFromMessage(msg)
+		if err != nil || gotTenant != schema {
+			t.Fatalf("expected tenant metadata %q, got %q (%v)", schema, gotTenant, err)
+		}
+		var event hrdomain.TeacherCreated
+		if err := json.Unmarshal(msg.Payload, &event); err != nil {
+			t.Fatalf("decode event: %v", err)
+		}
+		if event.TeacherID != teacher.ID {
+			t.Fatalf("expected teacher id %s, got %s", teacher.ID, event.TeacherID)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for relayed event")
+	}
+
+	// A second pass must not republish rows already marked as delivered.
+	n, err := relay.RelayOnce(ctx)
+	if err != nil {
+		t.Fatalf("second relay: %v", err)
+	}
+	if n != 0 {
+		t.Fatalf("expected no pending events after relay, got %d", n)
+	}
+}
@@ -0,0 +1,117 @@
+// Package problem writes errors as RFC 7807 problem details
+// (application/problem+json), the error format of every HTTP endpoint:
+//
+//	HTTP/1.1 400 Bad Request
+//	Content-Type: application/problem+json
+//
+//	{
+//	  "type": "urn:mcs-erp:problem:validation_failed",
+//	  "title": "Bad Request",
+//	  "status": 400,
+//	  "detail": "request validation failed",
+//	  "instance": "/api/v1/teachers",
+//	  "code": "validation_failed",
+//	  "errors": [{"field": "email", "code": "required", "message": "is required"}],
+//	  "request_id": "3f0c..."
+//	}
+//
+// code (an erptypes.Code) and errors[].code are stable; clients switch on
+// them. Meta of an *erptypes.Error is added as further members.
+package problem
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/requestinfo"
+	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
+)
+
+// ContentType is the media type of problem details.
+const ContentType = "application/problem+json"
+
+// TypePrefix prefixes the code to form the problem type URI.
+const TypePrefix = "urn:mcs-erp:problem:"
+
+// Details is the JSON body of a problem response.
+type Details struct {
+	Type      string                    `json:"type"`
+	Title     string                    `json:"title"`
+	Status    int                       `json:"status"`
+	Detail    string                    `json:"detail,omitempty"`
+	Instance  string                    `json:"instance,omitempty"`
+	Code      erptypes.Code             `json:"code"`
+	Errors    []erptypes.FieldViolation `json:"errors,omitempty"`
+	RequestID string                    `json:"request_id,omitempty"`
+	Meta      map[string]any            `json:"-"`
+}
+
+// MarshalJSON adds Meta as top-level members; it cannot shadow the standard ones.
+func (d Details) MarshalJSON() ([]byte, error) {
+	type plain Details
+	if len(d.Meta) == 0 {
+		return json.Marshal(plain(d))
+	}
+	raw, err := json.Marshal(plain(d))
+	if err != nil {
+		return nil, err
+	}
+	var members map[string]any
+	if err := json.Unmarshal(raw, &members); err != nil {
+		return nil, err
+	}
+	for k, v := range d.Meta {
+		if _, taken := members[k]; !taken {
+			members[k] = v
+		}
+	}
+	return json.Marshal(members)
+}
+
+// From builds the problem details for err as served for r. Errors that are
+// not an *erptypes.Error and do not wrap a standard one are internal errors:
+// their text is never shown. A request that ran out of time (its route
+// timeout) is unavailable rather than an internal error.
+func From(r *http.Request, err error) Details {
+	var e *erptypes.Error
+	if !errors.As(err, &e) && errors.Is(err, context.DeadlineExceeded) && r.Context().Err() != nil {
+		e = erptypes.New(erptypes.CodeUnavailable, "request timed out").WithCause(err)
+	} else {
+		e = erptypes.AsError(err)
+	}
+	status := e.HTTPStatus()
+	return Details{
+		Type:      TypePrefix + string(e.Code),
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    e.Message,
+		Instance:  r.URL.Path,
+		Code:      e.Code,
+		Errors:    e.Violations,
+		RequestID: requestinfo.FromContext(r.Context()).ID,
+		Meta:      e.Meta,
+	}
+}
+
+// Write writes err as a problem response. Server errors are logged with
+// their cause (and, through requestinfo.LogHandler, the request ID).
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	d := From(r, err)
+	if d.Status >= 500 {
+		Log(r, err)
+	}
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(d.Status)
+	json.NewEncoder(w).Encode(d)
+}
+
+// Log logs a server error for r without writing a response, for failures
+// after the response has started.
+func Log(r *http.Request, err error) {
+	d := From(r, err)
+	slog.ErrorContext(r.Context(), "request failed",
+		"method", r.Method, "path", r.URL.Path, "status", d.Status, "error", err)
+}
@@ -0,0 +1,39 @@
+package ratelimit
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// fallbackLogEvery bounds how often a failing primary store is logged.
+const fallbackLogEvery = time.Minute
+
+// Fallback uses primary and, whenever it fails, secondary: with a Redis
+// primary and a memory secondary, limits stay enforced (per replica) while
+// Redis is down instead of failing every request.
+func Fallback(primary, secondary Store) Store {
+	return &fallback{primary: primary, secondary: secondary}
+}
+
+type fallback struct {
+	primary, secondary Store
+
+	mu     sync.Mutex
+	logged time.Time
+}
+
+func (f *fallback) Take(ctx context.Context, buckets []Bucket) ([]Result, error) {
+	results, err := f.primary.Take(ctx, buckets)
+	if err == nil {
+		return results, nil
+	}
+	f.mu.Lock()
+	if time.Since(f.logged) >= fallbackLogEvery {
+		f.logged = time.Now()
+		slog.WarnContext(ctx, "rate limit store failed, using fallback", "error", err)
+	}
+	f.mu.Unlock()
+	return f.secondary.Take(ctx, buckets)
+}
@@ -0,0 +1,54 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepEvery is how often the memory store drops buckets that are full again.
+const sweepEvery = time.Minute
+
+// MemoryStore keeps buckets in this process: limits are per replica.
+type MemoryStore struct {
+	now func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]time.Time // key → theoretical arrival time
+	lastSweep time.Time
+}
+
+// NewMemoryStore returns an empty memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{now: time.Now, buckets: map[string]time.Time{}}
+}
+
+// Take implements Store.
+func (s *MemoryStore) Take(_ context.Context, buckets []Bucket) ([]Result, error) {
+	now := s.now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastSweep) >= sweepEvery {
+		for k, tat := range s.buckets {
+			if !tat.After(now) {
+				delete(s.buckets, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	results := make([]Result, len(buckets))
+	tats := make([]time.Time, len(buckets))
+	allowed := true
+	for i, b := range buckets {
+		results[i], tats[i] = gcra(now, s.buckets[b.Key], b.Rate)
+		allowed = allowed && results[i].Allowed
+	}
+	if allowed {
+		for i, b := range buckets {
+			s.buckets[b.Key] = tats[i]
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,157 @@
+package ratelimit
+
+import (
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/auth"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/metrics"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/problem"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/requestinfo"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
+	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
+	pkgmod "github.com/HuynhHoangPhuc/mcs-erp/pkg/module"
+)
+
+// Response headers (draft-ietf-httpapi-ratelimit-headers).
+const (
+	LimitHeader      = "RateLimit-Limit"
+	RemainingHeader  = "RateLimit-Remaining"
+	ResetHeader      = "RateLimit-Reset"
+	PolicyHeader     = "RateLimit-Policy"
+	RetryAfterHeader = "Retry-After"
+)
+
+// TokenValidator resolves a bearer token to its claims; core's AuthService implements it.
+type TokenValidator func(token string) (*auth.Claims, error)
+
+// Config configures Middleware.
+type Config struct {
+	Store Store
+	Rules []Rule
+	// Mux serves the routes; it is only asked which pattern a request
+	// matches, to find the route's class in Routes.
+	Mux    *http.ServeMux
+	Routes []pkgmod.Route
+	// Users identifies the user of ScopeUser rules from the bearer token.
+	Users TokenValidator
+}
+
+// Middleware limits /api/ requests by the rules of their route's class. The
+// strictest rule's state is reported in RateLimit-* headers; a request any
+// rule denies is 429 rate_limited with Retry-After and takes no tokens from
+// the other rules. It must run after tenant resolution. If the store fails the request is let through.
+func Middleware(cfg Config) func(http.Handler) http.Handler {
+	classes := map[string]string{}
+	for _, rt := range cfg.Routes {
+		classes[rt.Method+" "+rt.Path] = rt.RateClass
+	}
+	rules := map[string][]Rule{}
+	for _, rule := range cfg.Rules {
+		rules[rule.Class] = append(rules[rule.Class], rule)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !strings.HasPrefix(r.URL.Path, "/api/") {
+				next.ServeHTTP(w, r)
+				return
+			}
+			_, pattern := cfg.Mux.Handler(r)
+			class := classes[pattern]
+			if len(rules[class]) == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var (
+				applied []Rule
+				buckets []Bucket
+			)
+			for _, rule := range rules[class] {
+				if id := identify(r, rule.Scope, cfg.Users); id != "" {
+					applied = append(applied, rule)
+					buckets = append(buckets, Bucket{Key: className(class) + ":" + string(rule.Scope) + ":" + id, Rate: rule.Rate})
+				}
+			}
+			if len(buckets) == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+			results, err := cfg.Store.Take(r.Context(), buckets)
+			if err != nil {
+				slog.ErrorContext(r.Context(), "rate limit check failed", "class", className(class), "error", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var (
+				tightest Result
+				policy   Rate
+				denied   *Rule
+			)
+			for i, rule := range applied {
+				res := results[i]
+				if !res.Allowed {
+					if denied == nil || res.RetryAfter > tightest.RetryAfter {
+						tightest, policy, denied = res, rule.Rate, &rule
+					}
+					continue
+				}
+				if denied == nil && (policy.Limit == 0 || res.Remaining < tightest.Remaining) {
+					tightest, policy = res, rule.Rate
+				}
+			}
+			h := w.Header()
+			h.Set(LimitHeader, strconv.Itoa(tightest.Limit))
+			h.Set(RemainingHeader, strconv.Itoa(tightest.Remaining))
+			h.Set(ResetHeader, seconds(tightest.Reset))
+			h.Set(PolicyHeader, strconv.Itoa(policy.Limit)+";w="+seconds(policy.Period))
+			if denied != nil {
+				metrics.ObserveRateLimited(className(class), string(denied.Scope))
+				retry := seconds(tightest.RetryAfter)
+				h.Set(RetryAfterHeader, retry)
+				problem.Write(w, r, erptypes.Newf(erptypes.CodeRateLimited, "too many requests, retry in %ss", retry))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// identify returns the id a request is counted under for scope, or "" when
+// the scope does not apply.
+func identify(r *http.Request, scope Scope, users TokenValidator) string {
+	switch scope {
+	case ScopeTenant:
+		schema, _ := tenant.FromContext(r.Context())
+		return schema
+	case ScopeUser:
+		if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && users != nil {
+			if claims, err := users(token); err == nil {
+				return claims.TenantID + ":" + claims.UserID.String()
+			}
+		}
+	}
+	ip := requestinfo.FromContext(r.Context()).ClientIP
+	if ip == "" {
+		ip = requestinfo.ClientIP(r, nil)
+	}
+	return "ip:" + ip
+}
+
+func className(class string) string {
+	if class == pkgmod.RateDefault {
+		return "default"
+	}
+	return class
+}
+
+// seconds formats d as whole seconds, rounded up.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,101 @@
+// Package ratelimit limits API requests per client IP, user and tenant, by
+// route class (pkg/module.Route.RateClass). Limits are token buckets (GCRA):
+// a rate of 10 per minute allows bursts of 10 and then one request every 6s.
+// Buckets live in Redis so every replica shares them; when Redis is not
+// configured or fails, a per-process memory store takes over.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	pkgmod "github.com/HuynhHoangPhuc/mcs-erp/pkg/module"
+)
+
+// Rate is Limit requests per Period, with bursts of up to Limit.
+type Rate struct {
+	Limit  int
+	Period time.Duration
+}
+
+// PerMinute returns a rate of n requests per minute.
+func PerMinute(n int) Rate { return Rate{Limit: n, Period: time.Minute} }
+
+// interval is the time one request's token takes to come back.
+func (r Rate) interval() time.Duration { return r.Period / time.Duration(r.Limit) }
+
+func (r Rate) String() string { return fmt.Sprintf("%d/%s", r.Limit, r.Period) }
+
+// Bucket names the bucket of key, limited to Rate.
+type Bucket struct {
+	Key  string
+	Rate Rate
+}
+
+// Result is the outcome of taking a token from one bucket.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int           // tokens left after this request
+	RetryAfter time.Duration // when denied, until the next token
+	Reset      time.Duration // until the bucket is full again
+}
+
+// Store keeps token buckets.
+type Store interface {
+	// Take takes a token from every bucket, or from none when any of them is
+	// empty, so a request one rule denies does not drain the others. Results
+	// are in the order of buckets.
+	Take(ctx context.Context, buckets []Bucket) ([]Result, error)
+}
+
+// Scope is what a rule counts requests by.
+type Scope string
+
+const (
+	ScopeIP     Scope = "ip"     // the client IP
+	ScopeUser   Scope = "user"   // the bearer token's user; the client IP when anonymous
+	ScopeTenant Scope = "tenant" // the resolved tenant; the rule is skipped without one
+)
+
+// Rule limits the routes of a class per scope. Every rule of a class must
+// allow a request.
+type Rule struct {
+	Class string // a pkg/module Rate* class
+	Scope Scope
+	Rate  Rate
+}
+
+// DefaultRules are the limits the server applies.
+func DefaultRules() []Rule {
+	return []Rule{
+		{Class: pkgmod.RateDefault, Scope: ScopeUser, Rate: PerMinute(600)},
+		{Class: pkgmod.RateAuth, Scope: ScopeIP, Rate: PerMinute(10)},
+		{Class: pkgmod.RateLLM, Scope: ScopeUser, Rate: PerMinute(10)},
+		{Class: pkgmod.RateLLM, Scope: ScopeTenant, Rate: PerMinute(60)},
+		{Class: pkgmod.RateCompute, Scope: ScopeTenant, Rate: PerMinute(6)},
+	}
+}
+
+// gcra applies one request to a bucket whose theoretical arrival time is
+// tat (zero for a new bucket), returning the result and the new tat. The
+// Redis store runs the same algorithm in Lua.
+func gcra(now, tat time.Time, rate Rate) (Result, time.Time) {
+	interval := rate.interval()
+	burst := time.Duration(rate.Limit) * interval
+	if tat.Before(now) {
+		tat = now
+	}
+	next := tat.Add(interval)
+	res := Result{Limit: rate.Limit}
+	if allowAt := next.Add(-burst); allowAt.After(now) {
+		res.RetryAfter = allowAt.Sub(now)
+		res.Reset = tat.Sub(now)
+		return res, tat
+	}
+	res.Allowed = true
+	res.Remaining = int((burst - next.Sub(now)) / interval)
+	res.Reset = next.Sub(now)
+	return res, next
+}
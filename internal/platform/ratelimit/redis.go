@@ -0,0 +1,99 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// keyPrefix namespaces the buckets in Redis.
+const keyPrefix = "ratelimit:"
+
+// takeScript is gcra in Lua, on Redis' clock so replicas agree. Each bucket
+// holds the theoretical arrival time in microseconds and expires when full;
+// none is updated unless every one allows the request.
+//
+// KEYS[i] bucket; ARGV[2i-1] its interval µs, ARGV[2i] its limit.
+// Returns {allowed, remaining, retry_after µs, reset µs} per bucket, flattened.
+var takeScript = redis.NewScript(`
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+
+local out, nexts, allowed = {}, {}, true
+for i, key in ipairs(KEYS) do
+  local interval = tonumber(ARGV[2 * i - 1])
+  local burst = interval * tonumber(ARGV[2 * i])
+  local tat = tonumber(redis.call('GET', key) or now)
+  if tat < now then tat = now end
+  local next = tat + interval
+  if next - burst > now then
+    allowed = false
+    for _, v in ipairs({0, 0, next - burst - now, tat - now}) do table.insert(out, v) end
+  else
+    nexts[i] = next
+    for _, v in ipairs({1, math.floor((burst - (next - now)) / interval), 0, next - now}) do table.insert(out, v) end
+  end
+end
+
+if allowed then
+  for i, key in ipairs(KEYS) do
+    redis.call('SET', key, string.format('%.0f', nexts[i]), 'PX', math.ceil((nexts[i] - now) / 1000))
+  end
+end
+return out
+`)
+
+// RedisStore keeps buckets in Redis, shared by every replica.
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore connects to Redis at url.
+func NewRedisStore(url string) (*RedisStore, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("ratelimit: parse redis url: %w", err)
+	}
+	return &RedisStore{client: redis.NewClient(opts)}, nil
+}
+
+// Take implements Store.
+func (s *RedisStore) Take(ctx context.Context, buckets []Bucket) ([]Result, error) {
+	keys := make([]string, len(buckets))
+	args := make([]any, 0, 2*len(buckets))
+	for i, b := range buckets {
+		keys[i] = keyPrefix + b.Key
+		args = append(args, b.Rate.interval().Microseconds(), b.Rate.Limit)
+	}
+	vals, err := takeScript.Run(ctx, s.client, keys, args...).Int64Slice()
+	if err != nil {
+		return nil, fmt.Errorf("ratelimit: take %v: %w", keys, err)
+	}
+	if len(vals) != 4*len(buckets) {
+		return nil, fmt.Errorf("ratelimit: take %v: got %d values for %d buckets", keys, len(vals), len(buckets))
+	}
+	results := make([]Result, len(buckets))
+	for i, b := range buckets {
+		v := vals[4*i:]
+		results[i] = Result{
+			Allowed:    v[0] == 1,
+			Limit:      b.Rate.Limit,
+			Remaining:  int(v[1]),
+			RetryAfter: time.Duration(v[2]) * time.Microsecond,
+			Reset:      time.Duration(v[3]) * time.Microsecond,
+		}
+	}
+	return results, nil
+}
+
+// Ping checks the Redis connection.
+func (s *RedisStore) Ping(ctx context.Context) error {
+	return s.client.Ping(ctx).Err()
+}
+
+// Close closes the Redis connection.
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
@@ -0,0 +1,157 @@
+//go:build integration
+
+package platform_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/middleware"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/problem"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/ratelimit"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/requestinfo"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/testutil"
+	pkgmod "github.com/HuynhHoangPhuc/mcs-erp/pkg/module"
+)
+
+// rateLimitServer serves a login route (auth class, 3/min per IP), a generate
+// route (compute class, 2/min per tenant), a chat route (llm class, 2/min per
+// user and 3/min per tenant; users are anonymous, so counted by IP) and an
+// unlimited route. Loopback is
+// a trusted proxy, so X-Forwarded-For names the client.
+func rateLimitServer(t *testing.T, store ratelimit.Store) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	ok := func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) }
+	mux.HandleFunc("POST /api/v1/auth/login", ok)
+	mux.HandleFunc("POST /api/v1/timetable/generate", ok)
+	mux.HandleFunc("POST /api/v1/agent/chat", ok)
+	mux.HandleFunc("GET /api/v1/teachers", ok)
+	routes := []pkgmod.Route{
+		{Method: "POST", Path: "/api/v1/auth/login", RateClass: pkgmod.RateAuth},
+		{Method: "POST", Path: "/api/v1/timetable/generate", RateClass: pkgmod.RateCompute},
+		{Method: "POST", Path: "/api/v1/agent/chat", RateClass: pkgmod.RateLLM},
+		{Method: "GET", Path: "/api/v1/teachers"},
+	}
+	rules := []ratelimit.Rule{
+		{Class: pkgmod.RateAuth, Scope: ratelimit.ScopeIP, Rate: ratelimit.PerMinute(3)},
+		{Class: pkgmod.RateCompute, Scope: ratelimit.ScopeTenant, Rate: ratelimit.PerMinute(2)},
+		{Class: pkgmod.RateLLM, Scope: ratelimit.ScopeUser, Rate: ratelimit.PerMinute(2)},
+		{Class: pkgmod.RateLLM, Scope: ratelimit.ScopeTenant, Rate: ratelimit.PerMinute(3)},
+	}
+	proxies, err := requestinfo.ParseProxies([]string{"127.0.0.0/8", "::1/128"})
+	if err != nil {
+		t.Fatalf("parse proxies: %v", err)
+	}
+	srv := httptest.NewServer(middleware.Chain(mux,
+		requestinfo.Middleware(proxies),
+		tenant.Middleware,
+		ratelimit.Middleware(ratelimit.Config{Store: store, Rules: rules, Mux: mux, Routes: routes}),
+	))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRateLimit_MemoryStore(t *testing.T) {
+	testRateLimit(t, ratelimit.NewMemoryStore())
+}
+
+func TestRateLimit_RedisStore(t *testing.T) {
+	store, err := ratelimit.NewRedisStore(testutil.TestRedis(t))
+	if err != nil {
+		t.Fatalf("new redis store: %v", err)
+	}
+	defer store.Close()
+	if err := store.Ping(context.Background()); err != nil {
+		t.Fatalf("ping redis: %v", err)
+	}
+	testRateLimit(t, store)
+}
+
+func testRateLimit(t *testing.T, store ratelimit.Store) {
+	// Tenants are unique per run so Redis buckets from earlier runs do not count.
+	run := strconv.FormatInt(time.Now().UnixNano(), 36)
+	srv := rateLimitServer(t, store)
+	do := func(method, path, tenantID, ip string) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(method, srv.URL+path, nil)
+		req.Header.Set("X-Tenant-ID", tenantID)
+		req.Header.Set("X-Forwarded-For", ip)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s: %v", method, path, err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	// Logins are counted per client IP, across tenants. The client IP is the
+	// hop our proxy appended: a client rotating a spoofed leftmost hop stays
+	// in one bucket.
+	ip := "203.0.113." + strconv.Itoa(int(time.Now().UnixNano()%250)+1)
+	spoofed := func(i int) string { return "192.0.2." + strconv.Itoa(i+1) + ", " + ip }
+	for i := range 3 {
+		resp := do(http.MethodPost, "/api/v1/auth/login", "t_"+run+strconv.Itoa(i), spoofed(i))
+		if resp.StatusCode != http.StatusNoContent {
+			t.Fatalf("login %d: expected 204, got %d", i+1, resp.StatusCode)
+		}
+		if got := resp.Header.Get(ratelimit.RemainingHeader); got != strconv.Itoa(2-i) {
+			t.Fatalf("login %d: expected %d remaining, got %q", i+1, 2-i, got)
+		}
+	}
+	resp := do(http.MethodPost, "/api/v1/auth/login", "t_"+run+"x", spoofed(3))
+	if resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("Content-Type") != problem.ContentType {
+		t.Fatalf("expected 429 problem on the 4th login, got %d %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	if retry, _ := strconv.Atoi(resp.Header.Get(ratelimit.RetryAfterHeader)); retry < 1 || retry > 20 {
+		t.Fatalf("expected Retry-After of about 20s, got %q", resp.Header.Get(ratelimit.RetryAfterHeader))
+	}
+	if got := resp.Header.Get(ratelimit.PolicyHeader); got != "3;w=60" {
+		t.Fatalf("expected policy 3;w=60, got %q", got)
+	}
+
+	// Generate is counted per tenant: one tenant's burst leaves another alone.
+	busy, quiet := "t_"+run+"busy", "t_"+run+"quiet"
+	for i := range 2 {
+		if resp := do(http.MethodPost, "/api/v1/timetable/generate", busy, "198.51.100."+strconv.Itoa(i+1)); resp.StatusCode != http.StatusNoContent {
+			t.Fatalf("generate %d: expected 204, got %d", i+1, resp.StatusCode)
+		}
+	}
+	if resp := do(http.MethodPost, "/api/v1/timetable/generate", busy, "198.51.100.9"); resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected busy tenant limited, got %d", resp.StatusCode)
+	}
+	if resp := do(http.MethodPost, "/api/v1/timetable/generate", quiet, "198.51.100.9"); resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("expected quiet tenant allowed, got %d", resp.StatusCode)
+	}
+
+	// A throttled user's denied requests leave the tenant's tokens to others.
+	n := int(time.Now().UnixNano()%100) + 100
+	chatty, other := "198.51.100."+strconv.Itoa(n), "198.51.100."+strconv.Itoa(n+1)
+	for i := range 2 {
+		if resp := do(http.MethodPost, "/api/v1/agent/chat", quiet, chatty); resp.StatusCode != http.StatusNoContent {
+			t.Fatalf("chat %d: expected 204, got %d", i+1, resp.StatusCode)
+		}
+	}
+	for range 3 {
+		if resp := do(http.MethodPost, "/api/v1/agent/chat", quiet, chatty); resp.StatusCode != http.StatusTooManyRequests {
+			t.Fatalf("expected the chatty user limited, got %d", resp.StatusCode)
+		}
+	}
+	resp = do(http.MethodPost, "/api/v1/agent/chat", quiet, other)
+	if resp.StatusCode != http.StatusNoContent || resp.Header.Get(ratelimit.RemainingHeader) != "0" {
+		t.Fatalf("expected the tenant's last token for another user, got %d with %q remaining", resp.StatusCode, resp.Header.Get(ratelimit.RemainingHeader))
+	}
+	if resp := do(http.MethodPost, "/api/v1/agent/chat", quiet, other); resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected the tenant limited once its tokens are used, got %d", resp.StatusCode)
+	}
+
+	// Routes of a class without rules carry no headers.
+	if resp := do(http.MethodGet, "/api/v1/teachers", busy, ip); resp.StatusCode != http.StatusNoContent || resp.Header.Get(ratelimit.LimitHeader) != "" {
+		t.Fatalf("expected unlimited route untouched, got %d %q", resp.StatusCode, resp.Header.Get(ratelimit.LimitHeader))
+	}
+}
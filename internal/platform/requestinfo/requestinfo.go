@@ -0,0 +1,180 @@
+// Package requestinfo carries per-request metadata (request ID, client IP,
+// tenant and user) through the context so the audit log and logs can
+// attribute changes.
+package requestinfo
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Header is the request ID header accepted from clients and echoed back.
+const Header = "X-Request-ID"
+
+// validID bounds client-supplied request IDs so they are safe to log and store.
+var validID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// Info is the metadata recorded for one request. Tenant and UserID are
+// filled in by the tenant and auth middleware once they are known.
+type Info struct {
+	ID       string
+	ClientIP string
+	Tenant   string
+	UserID   string
+}
+
+type ctxKey struct{}
+
+// WithInfo stores request metadata in the context.
+func WithInfo(ctx context.Context, info Info) context.Context {
+	return context.WithValue(ctx, ctxKey{}, &info)
+}
+
+// FromContext returns the request metadata, or the zero Info outside a request.
+func FromContext(ctx context.Context) Info {
+	if info, ok := ctx.Value(ctxKey{}).(*Info); ok {
+		return *info
+	}
+	return Info{}
+}
+
+// SetTenant records the request's tenant. It updates the Info stored by
+// WithInfo in place, so middleware further out (the access log) sees it too.
+// It must be called before the handler starts goroutines that read the Info.
+func SetTenant(ctx context.Context, schema string) {
+	if info, ok := ctx.Value(ctxKey{}).(*Info); ok {
+		info.Tenant = schema
+	}
+}
+
+// SetUser records the authenticated user, like SetTenant.
+func SetUser(ctx context.Context, userID string) {
+	if info, ok := ctx.Value(ctxKey{}).(*Info); ok {
+		info.UserID = userID
+	}
+}
+
+// NewID returns a fresh request ID.
+func NewID() string { return uuid.NewString() }
+
+// NormalizeID returns id when it is a well-formed client request ID, otherwise a new one.
+func NormalizeID(id string) string {
+	if validID.MatchString(id) {
+		return id
+	}
+	return NewID()
+}
+
+// Middleware assigns every request an ID (reusing a well-formed X-Request-ID),
+// echoes it in the response and records the client IP, believing
+// X-Forwarded-For only as far as it was written by trustedProxies.
+func Middleware(trustedProxies []netip.Prefix) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			info := Info{ID: NormalizeID(r.Header.Get(Header)), ClientIP: ClientIP(r, trustedProxies)}
+			w.Header().Set(Header, info.ID)
+			next.ServeHTTP(w, r.WithContext(WithInfo(r.Context(), info)))
+		})
+	}
+}
+
+// ParseProxies parses trusted proxy CIDRs, e.g. 10.0.0.0/8, for Middleware.
+func ParseProxies(cidrs []string) ([]netip.Prefix, error) {
+	proxies := make([]netip.Prefix, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		p, err := netip.ParsePrefix(strings.TrimSpace(cidr))
+		if err != nil {
+			return nil, fmt.Errorf("requestinfo: trusted proxy %q: %w", cidr, err)
+		}
+		proxies = append(proxies, p.Masked())
+	}
+	return proxies, nil
+}
+
+// LogHandler adds request_id, tenant and user_id to records logged with a
+// request context (slog.InfoContext(r.Context(), ...)) that do not carry a
+// request_id already.
+func LogHandler(h slog.Handler) slog.Handler {
+	return logHandler{h}
+}
+
+type logHandler struct{ slog.Handler }
+
+func (h logHandler) Handle(ctx context.Context, rec slog.Record) error {
+	if info, ok := ctx.Value(ctxKey{}).(*Info); ok && !hasAttr(rec, "request_id") {
+		rec.AddAttrs(slog.String("request_id", info.ID))
+		if info.Tenant != "" {
+			rec.AddAttrs(slog.String("tenant", info.Tenant))
+		}
+		if info.UserID != "" {
+			rec.AddAttrs(slog.String("user_id", info.UserID))
+		}
+	}
+	return h.Handler.Handle(ctx, rec)
+}
+
+func hasAttr(rec slog.Record, key string) bool {
+	found := false
+	rec.Attrs(func(a slog.Attr) bool {
+		found = a.Key == key
+		return !found
+	})
+	return found
+}
+
+func (h logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return logHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h logHandler) WithGroup(name string) slog.Handler {
+	return logHandler{h.Handler.WithGroup(name)}
+}
+
+// ClientIP returns the caller's address: the direct peer, or when the peer is
+// one of trustedProxies, the nearest X-Forwarded-For hop that is not. Hops
+// are walked from the right because only the ones our proxies appended can be
+// believed; anything further left is whatever the client sent, and trusting
+// it would let a client pick its rate limit bucket and its audit log IP.
+func ClientIP(r *http.Request, trustedProxies []netip.Prefix) string {
+	peer := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(peer); err == nil {
+		peer = host
+	}
+	addr, err := netip.ParseAddr(peer)
+	if err != nil || !trusted(addr, trustedProxies) {
+		return peer
+	}
+	hops := r.Header.Values("X-Forwarded-For")
+	for i := len(hops) - 1; i >= 0; i-- {
+		fields := strings.Split(hops[i], ",")
+		for j := len(fields) - 1; j >= 0; j-- {
+			hop, err := netip.ParseAddr(strings.TrimSpace(fields[j]))
+			if err != nil {
+				return addr.String() // a malformed hop ends what can be believed
+			}
+			addr = hop.Unmap()
+			if !trusted(addr, trustedProxies) {
+				return addr.String()
+			}
+		}
+	}
+	return addr.String()
+}
+
+func trusted(addr netip.Addr, proxies []netip.Prefix) bool {
+	addr = addr.Unmap()
+	for _, p := range proxies {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
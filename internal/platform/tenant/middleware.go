@@ -1,34 +1,56 @@
 package tenant
 
 import (
-	"encoding/json"
+	"fmt"
 	"net/http"
 	"strings"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/problem"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/requestinfo"
+	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
 )
 
 // publicPaths are routes that skip tenant resolution.
-var publicPaths = []string{"/healthz", "/api/v1/auth/login", "/api/v1/auth/register"}
+var publicPaths = []string{"/healthz", "/readyz", "/metrics", "/api/v1/auth/login", "/api/v1/auth/register", "/api/v1/platform/", "/api/v1/openapi.json"}
 
 // Middleware resolves the tenant from the request and injects it into context.
-// Public paths (healthz, login) skip tenant resolution.
+// Public paths (healthz, readyz, metrics, login) skip tenant resolution.
 func Middleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		for _, p := range publicPaths {
-			if strings.HasPrefix(r.URL.Path, p) {
-				next.ServeHTTP(w, r)
+	return MiddlewareWithDirectory(nil)(next)
+}
+
+// MiddlewareWithDirectory is Middleware that also rejects tenants the directory
+// reports as unknown, suspended, archived or still provisioning.
+func MiddlewareWithDirectory(dir *Directory) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, p := range publicPaths {
+				if strings.HasPrefix(r.URL.Path, p) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			schema, err := Resolve(r)
+			if err != nil {
+				problem.Write(w, r, erptypes.InvalidRequest("tenant resolution failed: "+err.Error()))
 				return
 			}
-		}
-
-		schema, err := Resolve(r)
-		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadRequest)
-			json.NewEncoder(w).Encode(map[string]string{"error": "tenant resolution failed: " + err.Error()})
-			return
-		}
-
-		ctx := WithTenant(r.Context(), schema)
-		next.ServeHTTP(w, r.WithContext(ctx))
-	})
+
+			if dir != nil {
+				if err := dir.Check(r.Context(), schema); err != nil {
+					if e := Problem(err); e != nil {
+						problem.Write(w, r, e)
+					} else {
+						problem.Write(w, r, erptypes.Internal("tenant status check failed", fmt.Errorf("tenant %s: %w", schema, err)))
+					}
+					return
+				}
+			}
+
+			requestinfo.SetTenant(r.Context(), schema)
+			ctx := WithTenant(r.Context(), schema)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
 }
@@ -23,7 +23,7 @@ func Resolve(r *http.Request) (string, error) {
 	if len(parts) >= 3 {
 		sub := parts[0]
 		schema := strings.ReplaceAll(sub, "-", "_")
-		if validSchema.MatchString(schema) {
+		if ValidateSchema(schema) == nil {
 			return schema, nil
 		}
 	}
@@ -31,7 +31,7 @@ func Resolve(r *http.Request) (string, error) {
 	// Fallback: X-Tenant-ID header
 	if tid := r.Header.Get("X-Tenant-ID"); tid != "" {
 		schema := strings.ReplaceAll(tid, "-", "_")
-		if validSchema.MatchString(schema) {
+		if ValidateSchema(schema) == nil {
 			return schema, nil
 		}
 		return "", fmt.Errorf("invalid tenant ID: %q", tid)
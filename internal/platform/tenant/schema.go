@@ -0,0 +1,32 @@
+package tenant
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxSchemaLength is Postgres' identifier limit (NAMEDATALEN - 1).
+const maxSchemaLength = 63
+
+// reservedSchemas must never be used as tenant identifiers.
+var reservedSchemas = map[string]bool{
+	"public": true, "pg_catalog": true, "information_schema": true,
+	"pg_toast": true, "_template": true,
+}
+
+// NormalizeSchema maps a tenant identifier (subdomain, header, slug) to its schema name.
+func NormalizeSchema(id string) string {
+	return strings.ToLower(strings.ReplaceAll(id, "-", "_"))
+}
+
+// ValidateSchema reports whether schema is usable as a tenant schema name:
+// a valid identifier, not reserved, and not in the pg_ system namespace.
+func ValidateSchema(schema string) error {
+	if !validSchema.MatchString(schema) || len(schema) > maxSchemaLength {
+		return fmt.Errorf("invalid tenant schema name: %q", schema)
+	}
+	if reservedSchemas[strings.ToLower(schema)] || strings.HasPrefix(strings.ToLower(schema), "pg_") {
+		return fmt.Errorf("reserved tenant schema name: %q", schema)
+	}
+	return nil
+}
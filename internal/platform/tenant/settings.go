@@ -0,0 +1,56 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// CoreModule is always enabled: it owns authentication and the settings themselves.
+const CoreModule = "core"
+
+// ErrModuleDisabled is returned when a tenant calls a module it has not enabled.
+var ErrModuleDisabled = errors.New("module is not enabled for this tenant")
+
+// Settings are a tenant's module switches and feature flags, stored in
+// public.tenant_settings. Modules default to enabled and flags to off, so a
+// tenant without settings gets every module and no experimental features.
+type Settings struct {
+	Modules  map[string]bool `json:"modules"`
+	Features map[string]bool `json:"features"`
+}
+
+// ModuleEnabled reports whether the module may be used by the tenant.
+func (s Settings) ModuleEnabled(module string) bool {
+	if module == CoreModule {
+		return true
+	}
+	enabled, ok := s.Modules[module]
+	return !ok || enabled
+}
+
+// FeatureEnabled reports whether the feature flag is switched on.
+func (s Settings) FeatureEnabled(flag string) bool {
+	return s.Features[flag]
+}
+
+// Settings returns the tenant's module switches and feature flags, or ErrUnknownTenant.
+func (d *Directory) Settings(ctx context.Context, schema string) (Settings, error) {
+	e, err := d.lookup(ctx, schema)
+	if err != nil {
+		return Settings{}, err
+	}
+	return e.settings, nil
+}
+
+// CheckModule returns ErrModuleDisabled when the tenant has switched module off.
+func (d *Directory) CheckModule(ctx context.Context, schema, module string) error {
+	s, err := d.Settings(ctx, schema)
+	if err != nil {
+		return err
+	}
+	if !s.ModuleEnabled(module) {
+		return fmt.Errorf("%w: %s", ErrModuleDisabled, module)
+	}
+	return nil
+}
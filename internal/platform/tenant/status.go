@@ -0,0 +1,154 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
+)
+
+// Status is the lifecycle state stored in public.tenants.status.
+type Status string
+
+const (
+	StatusProvisioning Status = "provisioning"
+	StatusActive       Status = "active"
+	StatusSuspended    Status = "suspended"
+	StatusArchived     Status = "archived"
+)
+
+// Errors returned when a tenant may not be served.
+var (
+	ErrUnknownTenant = errors.New("tenant not found")
+	ErrNotReady      = errors.New("tenant is still being provisioned")
+	ErrSuspended     = errors.New("tenant is suspended")
+	ErrArchived      = errors.New("tenant is archived")
+	ErrMaintenance   = errors.New("tenant is under maintenance, please try again later")
+)
+
+// Err returns nil for an active tenant, otherwise the error explaining why it is unavailable.
+func (s Status) Err() error {
+	switch s {
+	case StatusActive:
+		return nil
+	case StatusProvisioning:
+		return ErrNotReady
+	case StatusSuspended:
+		return ErrSuspended
+	case StatusArchived:
+		return ErrArchived
+	default:
+		return fmt.Errorf("tenant has unknown status %q", s)
+	}
+}
+
+// Problem maps a tenant availability error to the client-facing error, or nil
+// if err is unrelated.
+func Problem(err error) *erptypes.Error {
+	var code erptypes.Code
+	switch {
+	case errors.Is(err, ErrUnknownTenant):
+		code = erptypes.CodeNotFound
+	case errors.Is(err, ErrSuspended), errors.Is(err, ErrModuleDisabled):
+		code = erptypes.CodeForbidden
+	case errors.Is(err, ErrArchived):
+		code = erptypes.CodeGone
+	case errors.Is(err, ErrNotReady), errors.Is(err, ErrMaintenance):
+		code = erptypes.CodeUnavailable
+	default:
+		return nil
+	}
+	return erptypes.New(code, err.Error())
+}
+
+// Directory answers "may this tenant be served?" from public.tenants and
+// public.tenant_migration_status (tenants whose migrations failed are quarantined),
+// and which modules and features it has from public.tenant_settings.
+// Lookups are cached briefly; lifecycle changes call Invalidate so the local
+// replica sees them at once and others within the TTL.
+type Directory struct {
+	pool *pgxpool.Pool
+	ttl  time.Duration
+
+	mu      sync.RWMutex
+	entries map[string]directoryEntry
+}
+
+type directoryEntry struct {
+	status      Status
+	quarantined bool
+	settings    Settings
+	expires     time.Time
+}
+
+// NewDirectory creates a tenant directory with the given cache TTL.
+func NewDirectory(pool *pgxpool.Pool, ttl time.Duration) *Directory {
+	return &Directory{pool: pool, ttl: ttl, entries: map[string]directoryEntry{}}
+}
+
+// Status returns the tenant's lifecycle state, or ErrUnknownTenant.
+func (d *Directory) Status(ctx context.Context, schema string) (Status, error) {
+	e, err := d.lookup(ctx, schema)
+	if err != nil {
+		return "", err
+	}
+	return e.status, nil
+}
+
+// Check returns nil when the tenant exists, is active and not quarantined.
+func (d *Directory) Check(ctx context.Context, schema string) error {
+	e, err := d.lookup(ctx, schema)
+	if err != nil {
+		return err
+	}
+	if err := e.status.Err(); err != nil {
+		return err
+	}
+	if e.quarantined {
+		return ErrMaintenance
+	}
+	return nil
+}
+
+func (d *Directory) lookup(ctx context.Context, schema string) (directoryEntry, error) {
+	d.mu.RLock()
+	e, ok := d.entries[schema]
+	d.mu.RUnlock()
+	if ok && time.Now().Before(e.expires) {
+		return e, nil
+	}
+
+	err := d.pool.QueryRow(ctx,
+		`SELECT t.status, COALESCE(m.status = 'failed', false),
+		        COALESCE(s.modules, '{}'), COALESCE(s.features, '{}')
+		 FROM public.tenants t
+		 LEFT JOIN public.tenant_migration_status m ON m.schema_name = t.schema_name
+		 LEFT JOIN public.tenant_settings s ON s.schema_name = t.schema_name
+		 WHERE t.schema_name = $1`, schema,
+	).Scan(&e.status, &e.quarantined, &e.settings.Modules, &e.settings.Features)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return e, ErrUnknownTenant
+		}
+		return e, fmt.Errorf("lookup tenant status: %w", err)
+	}
+
+	e.expires = time.Now().Add(d.ttl)
+	d.mu.Lock()
+	d.entries[schema] = e
+	d.mu.Unlock()
+	return e, nil
+}
+
+// Invalidate drops the cached state and settings for schema.
+func (d *Directory) Invalidate(schema string) {
+	d.mu.Lock()
+	delete(d.entries, schema)
+	d.mu.Unlock()
+}
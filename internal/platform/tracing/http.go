@@ -0,0 +1,39 @@
+package tracing
+
+import (
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
+)
+
+// HTTPHandler starts a server span per request, continuing any incoming W3C
+// trace context. Wrap it outermost so every middleware runs inside the span.
+func HTTPHandler(next http.Handler) http.Handler {
+	return otelhttp.NewHandler(next, "http.request")
+}
+
+// RouteMiddleware renames the request span after the matched mux pattern
+// (e.g. "GET /api/v1/teachers/{id}") and tags the tenant. Like
+// metrics.HTTPMiddleware it must wrap the ServeMux directly.
+func RouteMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r)
+
+		span := trace.SpanFromContext(r.Context())
+		if r.Pattern != "" {
+			span.SetName(r.Pattern)
+			route := r.Pattern
+			if i := strings.IndexByte(route, ' '); i >= 0 {
+				route = route[i+1:]
+			}
+			span.SetAttributes(attrHTTPRoute.String(route))
+		}
+		if schema, err := tenant.FromContext(r.Context()); err == nil {
+			span.SetAttributes(AttrTenant.String(schema))
+		}
+	})
+}
@@ -0,0 +1,127 @@
+// Package tracing configures OpenTelemetry distributed tracing.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ServiceName identifies this process in exported traces.
+const ServiceName = "mcs-erp"
+
+// Span attributes shared across modules.
+const (
+	AttrTenant = attribute.Key("mcs.tenant")
+	AttrUserID = attribute.Key("enduser.id")
+
+	attrHTTPRoute = attribute.Key("http.route")
+)
+
+// Exporter selects where spans are sent.
+type Exporter string
+
+const (
+	// ExporterNone disables tracing; spans are created but never recorded.
+	ExporterNone Exporter = "none"
+	// ExporterOTLP sends spans over OTLP/gRPC. The endpoint and headers come
+	// from the standard OTEL_EXPORTER_OTLP_* environment variables.
+	ExporterOTLP Exporter = "otlp"
+	// ExporterStdout pretty-prints spans to stdout for local runs.
+	ExporterStdout Exporter = "stdout"
+	// ExporterFile appends spans as JSON lines to Config.File.
+	ExporterFile Exporter = "file"
+)
+
+// Config controls tracing setup.
+type Config struct {
+	Exporter    Exporter
+	File        string  // target for ExporterFile
+	SampleRatio float64 // fraction of new traces recorded; parents' decisions are honoured
+}
+
+// Setup installs the global tracer provider and W3C trace-context propagator.
+// The returned function flushes pending spans and must be called on shutdown.
+func Setup(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if cfg.Exporter == "" || cfg.Exporter == ExporterNone {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var (
+		exporter sdktrace.SpanExporter
+		closer   io.Closer
+		err      error
+	)
+	switch cfg.Exporter {
+	case ExporterOTLP:
+		exporter, err = otlptracegrpc.New(ctx)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case ExporterFile:
+		var f *os.File
+		f, err = os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("open trace file: %w", err)
+		}
+		closer = f
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(f))
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", cfg.Exporter)
+	}
+	if err != nil {
+		if closer != nil {
+			closer.Close()
+		}
+		return nil, fmt.Errorf("create %s trace exporter: %w", cfg.Exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(ServiceName)))
+	if err != nil {
+		return nil, fmt.Errorf("build trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closer != nil {
+			if cerr := closer.Close(); err == nil {
+				err = cerr
+			}
+		}
+		return err
+	}, nil
+}
+
+// Tracer returns a named tracer from the global provider.
+func Tracer(name string) trace.Tracer {
+	return otel.Tracer(name)
+}
+
+// RecordError marks span as failed with err; nil is a no-op.
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,81 @@
+//go:build integration
+
+package platform_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	coredomain "github.com/HuynhHoangPhuc/mcs-erp/internal/core/domain"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tracing"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/testutil"
+)
+
+func TestTracing_HTTPRouteSpanWithTenantQueries(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prev)
+		_ = provider.Shutdown(context.Background())
+	})
+
+	db := testutil.NewTestDB(t)
+	schema := db.CreateTenantSchema(t)
+	srv := testutil.TestServer(t, db.Pool)
+	defer srv.Close()
+
+	userID := uuid.New()
+	token := testutil.GenerateTestToken(t, userID, schema, []string{coredomain.PermTeacherRead})
+	req, err := testutil.AuthenticatedRequest(http.MethodGet, srv.URL+"/api/v1/teachers", token, schema, nil)
+	if err != nil {
+		t.Fatalf("build request: %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("list teachers: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var root sdktrace.ReadOnlySpan
+	for _, s := range recorder.Ended() {
+		if s.Name() == "GET /api/v1/teachers" {
+			root = s
+		}
+	}
+	if root == nil {
+		t.Fatal("expected a server span named after the route pattern")
+	}
+	attrs := map[string]string{}
+	for _, kv := range root.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	if attrs[string(tracing.AttrTenant)] != schema || attrs[string(tracing.AttrUserID)] != userID.String() {
+		t.Fatalf("expected tenant and user attributes on route span, got %v", attrs)
+	}
+
+	queries := 0
+	for _, s := range recorder.Ended() {
+		if s.Parent().SpanID() != root.SpanContext().SpanID() || s.Parent().TraceID() != root.SpanContext().TraceID() {
+			continue
+		}
+		for _, kv := range s.Attributes() {
+			if kv.Key == tracing.AttrTenant && kv.Value.AsString() == schema {
+				queries++
+			}
+		}
+	}
+	if queries == 0 {
+		t.Fatal("expected tenant-tagged query spans under the route span")
+	}
+}
@@ -0,0 +1,197 @@
+// Package validate is the request-validation layer of the HTTP handlers. A
+// request body implements Request; DecodeJSON reads it and runs its checks,
+// collecting every field violation into one erptypes validation error:
+//
+//	func (req *createRoomRequest) Validate(v *validate.Validator) {
+//		v.Required("code", req.Code)
+//		v.Min("capacity", req.Capacity, 1)
+//	}
+//
+//	var req createRoomRequest
+//	if err := validate.DecodeJSON(r, &req); err != nil {
+//		problem.Write(w, r, err)
+//		return
+//	}
+//
+// Validate may also store parsed values (ids, times) in unexported fields so
+// the handler does not parse twice.
+package validate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
+)
+
+// Violation codes (erptypes.FieldViolation.Code).
+const (
+	Required    = "required"     // missing or blank
+	Invalid     = "invalid"      // wrong format, e.g. not a UUID or RFC 3339 time
+	InvalidType = "invalid_type" // JSON value of the wrong type
+	TooShort    = "too_short"
+	TooLong     = "too_long"
+	OutOfRange  = "out_of_range"
+	NotAllowed  = "not_allowed" // not one of the accepted values
+)
+
+// Request is a request body that validates itself.
+type Request interface {
+	Validate(v *Validator)
+}
+
+// Validator collects field violations. The zero value is ready to use.
+type Validator struct {
+	violations []erptypes.FieldViolation
+}
+
+// Add records a violation.
+func (v *Validator) Add(field, code, message string) {
+	v.violations = append(v.violations, erptypes.FieldViolation{Field: field, Code: code, Message: message})
+}
+
+// Check records a violation unless ok, and returns ok.
+func (v *Validator) Check(ok bool, field, code, message string) bool {
+	if !ok {
+		v.Add(field, code, message)
+	}
+	return ok
+}
+
+// Has reports whether field already has a violation, to skip checks that
+// depend on it.
+func (v *Validator) Has(field string) bool {
+	return slices.ContainsFunc(v.violations, func(f erptypes.FieldViolation) bool { return f.Field == field })
+}
+
+// Required checks that value is not blank.
+func (v *Validator) Required(field, value string) bool {
+	return v.Check(strings.TrimSpace(value) != "", field, Required, "is required")
+}
+
+// MaxLen checks that value has at most max characters.
+func (v *Validator) MaxLen(field, value string, max int) bool {
+	return v.Check(utf8.RuneCountInString(value) <= max, field, TooLong, fmt.Sprintf("must be at most %d characters", max))
+}
+
+// Email checks that a non-empty value is an email address.
+func (v *Validator) Email(field, value string) bool {
+	if value == "" {
+		return true
+	}
+	addr, err := mail.ParseAddress(value)
+	return v.Check(err == nil && addr.Address == value, field, Invalid, "must be an email address")
+}
+
+// Min checks value >= min.
+func (v *Validator) Min(field string, value, min int) bool {
+	return v.Check(value >= min, field, OutOfRange, fmt.Sprintf("must be at least %d", min))
+}
+
+// Range checks min <= value <= max.
+func (v *Validator) Range(field string, value, min, max int) bool {
+	return v.Check(value >= min && value <= max, field, OutOfRange, fmt.Sprintf("must be between %d and %d", min, max))
+}
+
+// OneOf checks that value is one of allowed.
+func (v *Validator) OneOf(field, value string, allowed ...string) bool {
+	return v.Check(slices.Contains(allowed, value), field, NotAllowed, "must be one of "+strings.Join(allowed, ", "))
+}
+
+// UUID parses a required UUID.
+func (v *Validator) UUID(field, value string) uuid.UUID {
+	if !v.Required(field, value) {
+		return uuid.Nil
+	}
+	id, err := uuid.Parse(value)
+	v.Check(err == nil, field, Invalid, "must be a UUID")
+	return id
+}
+
+// OptionalUUID parses a UUID that may be empty (nil).
+func (v *Validator) OptionalUUID(field, value string) *uuid.UUID {
+	if value == "" {
+		return nil
+	}
+	id, err := uuid.Parse(value)
+	if !v.Check(err == nil, field, Invalid, "must be a UUID") {
+		return nil
+	}
+	return &id
+}
+
+// Time parses a required RFC 3339 timestamp.
+func (v *Validator) Time(field, value string) time.Time {
+	if !v.Required(field, value) {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	v.Check(err == nil, field, Invalid, "must be an RFC 3339 time, e.g. 2025-09-01T00:00:00Z")
+	return t
+}
+
+// Violations returns the violations recorded so far.
+func (v *Validator) Violations() []erptypes.FieldViolation {
+	return v.violations
+}
+
+// Err returns nil, or a validation error carrying every violation.
+func (v *Validator) Err() error {
+	if len(v.violations) == 0 {
+		return nil
+	}
+	return erptypes.Validation(v.violations...)
+}
+
+// DecodeJSON reads the request body into dst and, if dst is a Request,
+// validates it. Unreadable bodies are invalid_request (or payload_too_large);
+// values of the wrong JSON type and failed checks are validation_failed.
+func DecodeJSON(r *http.Request, dst any) error {
+	if err := json.NewDecoder(r.Body).Decode(dst); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		var tooLarge *http.MaxBytesError
+		switch {
+		case errors.As(err, &tooLarge):
+			return erptypes.New(erptypes.CodeTooLarge, "request body too large")
+		case errors.As(err, &typeErr) && typeErr.Field != "":
+			return erptypes.Validation(erptypes.FieldViolation{
+				Field: typeErr.Field, Code: InvalidType, Message: "must not be a JSON " + typeErr.Value,
+			})
+		case errors.Is(err, io.EOF):
+			return erptypes.InvalidRequest("request body is required")
+		default:
+			return erptypes.InvalidRequest("invalid request body")
+		}
+	}
+	if req, ok := dst.(Request); ok {
+		var v Validator
+		req.Validate(&v)
+		return v.Err()
+	}
+	return nil
+}
+
+// PathUUID parses the named path parameter as a UUID.
+func PathUUID(r *http.Request, name string) (uuid.UUID, error) {
+	id, err := uuid.Parse(r.PathValue(name))
+	if err != nil {
+		return uuid.Nil, erptypes.Validation(erptypes.FieldViolation{Field: name, Code: Invalid, Message: "must be a UUID"})
+	}
+	return id, nil
+}
+
+// Query wraps an error from parsing query parameters (list queries, as_of)
+// as invalid_request, keeping its message.
+func Query(err error) error {
+	return erptypes.InvalidRequest(err.Error())
+}
@@ -1,12 +1,16 @@
 package delivery
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/google/uuid"
 
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/history"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/problem"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/validate"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/room/domain"
 	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
 )
@@ -22,98 +26,112 @@ func NewAvailabilityHandler(roomRepo domain.RoomRepository, availRepo domain.Roo
 	return &AvailabilityHandler{roomRepo: roomRepo, availRepo: availRepo}
 }
 
-// slotRequest is a single slot entry in the PUT body.
-type slotRequest struct {
+// Slot is a single day+period slot of a room's week.
+type Slot struct {
 	Day         int  `json:"day"`    // 0-6
 	Period      int  `json:"period"` // 1-10
 	IsAvailable bool `json:"is_available"`
 }
 
-// slotResponse is a single slot entry in the GET response.
-type slotResponse struct {
-	Day         int  `json:"day"`
-	Period      int  `json:"period"`
-	IsAvailable bool `json:"is_available"`
+// SetAvailabilityRequest is the PUT body; it replaces every slot.
+type SetAvailabilityRequest struct {
+	Slots []Slot `json:"slots"`
+}
+
+func (req *SetAvailabilityRequest) Validate(v *validate.Validator) {
+	for i, s := range req.Slots {
+		v.Range(fmt.Sprintf("slots[%d].day", i), s.Day, 0, 6)
+		v.Range(fmt.Sprintf("slots[%d].period", i), s.Period, 1, 10)
+	}
+}
+
+// AvailabilityResponse is a room's weekly availability.
+type AvailabilityResponse struct {
+	RoomID uuid.UUID `json:"room_id"`
+	Slots  []Slot    `json:"slots"`
 }
 
-// GetAvailability handles GET /api/v1/rooms/{id}/availability
+// GetAvailability handles GET /api/v1/rooms/{id}/availability[?as_of=<RFC 3339>]
 func (h *AvailabilityHandler) GetAvailability(w http.ResponseWriter, r *http.Request) {
-	roomID, err := uuid.Parse(r.PathValue("id"))
+	roomID, err := validate.PathUUID(r, "id")
 	if err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid room id"})
+		problem.Write(w, r, err)
+		return
+	}
+	asOf, err := history.ParseAsOf(r.URL.Query())
+	if err != nil {
+		problem.Write(w, r, validate.Query(err))
 		return
 	}
 
-	// Verify the room exists
-	if _, err := h.roomRepo.FindByID(r.Context(), roomID); err != nil {
+	// Verify the room exists (at that time, for as_of)
+	findRoom, getAvail := h.roomRepo.FindByID, h.availRepo.GetByRoomID
+	if asOf != nil {
+		findRoom = func(ctx context.Context, id uuid.UUID) (*domain.Room, error) {
+			return h.roomRepo.FindByIDAsOf(ctx, id, *asOf)
+		}
+		getAvail = func(ctx context.Context, id uuid.UUID) (domain.RoomAvailability, error) {
+			return h.availRepo.GetByRoomIDAsOf(ctx, id, *asOf)
+		}
+	}
+	if _, err := findRoom(r.Context(), roomID); err != nil {
 		if errors.Is(err, erptypes.ErrNotFound) {
-			writeJSON(w, http.StatusNotFound, map[string]string{"error": "room not found"})
+			problem.Write(w, r, erptypes.NotFound("room not found"))
 			return
 		}
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get room"})
+		problem.Write(w, r, erptypes.Internal("failed to get room", err))
 		return
 	}
 
-	avail, err := h.availRepo.GetByRoomID(r.Context(), roomID)
+	avail, err := getAvail(r.Context(), roomID)
 	if err != nil {
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get availability"})
+		problem.Write(w, r, erptypes.Internal("failed to get availability", err))
 		return
 	}
 
-	slots := make([]slotResponse, 0, len(avail))
+	slots := make([]Slot, 0, len(avail))
 	for slot, isAvailable := range avail {
-		slots = append(slots, slotResponse{
+		slots = append(slots, Slot{
 			Day:         slot.Day,
 			Period:      slot.Period,
 			IsAvailable: isAvailable,
 		})
 	}
 
-	writeJSON(w, http.StatusOK, map[string]any{"room_id": roomID, "slots": slots})
+	writeJSON(w, http.StatusOK, AvailabilityResponse{RoomID: roomID, Slots: slots})
 }
 
 // SetAvailability handles PUT /api/v1/rooms/{id}/availability
 func (h *AvailabilityHandler) SetAvailability(w http.ResponseWriter, r *http.Request) {
-	roomID, err := uuid.Parse(r.PathValue("id"))
+	roomID, err := validate.PathUUID(r, "id")
 	if err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid room id"})
+		problem.Write(w, r, err)
 		return
 	}
 
 	// Verify the room exists
 	if _, err := h.roomRepo.FindByID(r.Context(), roomID); err != nil {
 		if errors.Is(err, erptypes.ErrNotFound) {
-			writeJSON(w, http.StatusNotFound, map[string]string{"error": "room not found"})
+			problem.Write(w, r, erptypes.NotFound("room not found"))
 			return
 		}
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get room"})
+		problem.Write(w, r, erptypes.Internal("failed to get room", err))
 		return
 	}
 
-	var body struct {
-		Slots []slotRequest `json:"slots"`
-	}
-	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	var body SetAvailabilityRequest
+	if err := validate.DecodeJSON(r, &body); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
-	// Validate and build the availability map
 	avail := make(domain.RoomAvailability, len(body.Slots))
 	for _, s := range body.Slots {
-		if s.Day < 0 || s.Day > 6 {
-			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "day must be between 0 and 6"})
-			return
-		}
-		if s.Period < 1 || s.Period > 10 {
-			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "period must be between 1 and 10"})
-			return
-		}
 		avail[domain.WeeklySlot{Day: s.Day, Period: s.Period}] = s.IsAvailable
 	}
 
 	if err := h.availRepo.SetSlots(r.Context(), roomID, avail); err != nil {
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to set availability"})
+		problem.Write(w, r, erptypes.Internal("failed to set availability", err))
 		return
 	}
 
@@ -0,0 +1,101 @@
+package delivery
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	platformgrpc "github.com/HuynhHoangPhuc/mcs-erp/internal/platform/grpc"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/room/domain"
+	roomv1 "github.com/HuynhHoangPhuc/mcs-erp/proto/gen/room/v1"
+)
+
+// RoomGRPCServer implements roomv1.RoomServiceServer over the room repositories.
+type RoomGRPCServer struct {
+	roomv1.UnimplementedRoomServiceServer
+	rooms domain.RoomRepository
+	avail domain.RoomAvailabilityRepository
+}
+
+func NewRoomGRPCServer(rooms domain.RoomRepository, avail domain.RoomAvailabilityRepository) *RoomGRPCServer {
+	return &RoomGRPCServer{rooms: rooms, avail: avail}
+}
+
+func (s *RoomGRPCServer) GetRoom(ctx context.Context, req *roomv1.GetRoomRequest) (*roomv1.GetRoomResponse, error) {
+	id, err := platformgrpc.ParseUUID("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	room, err := s.rooms.FindByID(ctx, id)
+	if err != nil {
+		return nil, platformgrpc.Error(err)
+	}
+	return &roomv1.GetRoomResponse{Room: roomToProto(room)}, nil
+}
+
+func (s *RoomGRPCServer) ListRooms(ctx context.Context, req *roomv1.ListRoomsRequest) (*roomv1.ListRoomsResponse, error) {
+	spec := listquery.Spec{Limit: listquery.MaxLimit}
+	if b := req.GetBuilding(); b != "" {
+		spec = spec.Where("building", b)
+	}
+	if c := req.GetMinCapacity(); c > 0 {
+		spec.Filters = append(spec.Filters, listquery.Filter{Field: "capacity", Op: "gte", Values: []string{strconv.Itoa(int(c))}})
+	}
+	if eq := req.GetEquipment(); len(eq) > 0 {
+		spec.Filters = append(spec.Filters, listquery.Filter{Field: "equipment", Op: "contains", Values: eq})
+	}
+	rooms, err := listquery.All(ctx, spec, s.rooms.List)
+	if err != nil {
+		return nil, platformgrpc.Error(err)
+	}
+	resp := &roomv1.ListRoomsResponse{Rooms: make([]*roomv1.Room, 0, len(rooms))}
+	for _, room := range rooms {
+		resp.Rooms = append(resp.Rooms, roomToProto(room))
+	}
+	return resp, nil
+}
+
+func (s *RoomGRPCServer) GetRoomAvailability(ctx context.Context, req *roomv1.GetRoomAvailabilityRequest) (*roomv1.GetRoomAvailabilityResponse, error) {
+	id, err := platformgrpc.ParseUUID("room_id", req.GetRoomId())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.rooms.FindByID(ctx, id); err != nil {
+		return nil, platformgrpc.Error(err)
+	}
+	avail, err := s.avail.GetByRoomID(ctx, id)
+	if err != nil {
+		return nil, platformgrpc.Error(err)
+	}
+
+	resp := &roomv1.GetRoomAvailabilityResponse{RoomId: id.String(), Slots: make([]*roomv1.AvailabilitySlot, 0, len(avail))}
+	for slot, ok := range avail {
+		resp.Slots = append(resp.Slots, &roomv1.AvailabilitySlot{Day: int32(slot.Day), Period: int32(slot.Period), IsAvailable: ok})
+	}
+	// Map iteration is random; keep responses stable.
+	sort.Slice(resp.Slots, func(i, j int) bool {
+		a, b := resp.Slots[i], resp.Slots[j]
+		return a.Day < b.Day || (a.Day == b.Day && a.Period < b.Period)
+	})
+	return resp, nil
+}
+
+func roomToProto(r *domain.Room) *roomv1.Room {
+	return &roomv1.Room{
+		Id:        r.ID.String(),
+		Name:      r.Name,
+		Code:      r.Code,
+		Building:  r.Building,
+		Floor:     int32(r.Floor),
+		Capacity:  int32(r.Capacity),
+		Equipment: r.Equipment,
+		IsActive:  r.IsActive,
+		CreatedAt: timestamppb.New(r.CreatedAt),
+		UpdatedAt: timestamppb.New(r.UpdatedAt),
+	}
+}
+
+var _ roomv1.RoomServiceServer = (*RoomGRPCServer)(nil)
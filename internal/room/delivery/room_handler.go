@@ -1,15 +1,17 @@
 package delivery
 
 import (
-	"encoding/json"
 	"errors"
 	"net/http"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/etag"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/history"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/problem"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/validate"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/room/domain"
 	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
 )
@@ -24,7 +26,8 @@ func NewRoomHandler(roomRepo domain.RoomRepository) *RoomHandler {
 	return &RoomHandler{roomRepo: roomRepo}
 }
 
-type createRoomRequest struct {
+// CreateRoomRequest is the body of POST /api/v1/rooms.
+type CreateRoomRequest struct {
 	Name      string   `json:"name"`
 	Code      string   `json:"code"`
 	Building  string   `json:"building"`
@@ -33,18 +36,20 @@ type createRoomRequest struct {
 	Equipment []string `json:"equipment"`
 }
 
-type updateRoomRequest struct {
-	Name      string   `json:"name"`
-	Code      string   `json:"code"`
-	Building  string   `json:"building"`
-	Floor     int      `json:"floor"`
-	Capacity  int      `json:"capacity"`
-	Equipment []string `json:"equipment"`
-	IsActive  *bool    `json:"is_active"`
+func (req *CreateRoomRequest) Validate(v *validate.Validator) {
+	v.Required("name", req.Name)
+	v.Required("code", req.Code)
+	v.Min("capacity", req.Capacity, 1)
 }
 
-// roomResponse is the standard JSON representation of a Room.
-type roomResponse struct {
+// UpdateRoomRequest is the body of PUT /api/v1/rooms/{id}.
+type UpdateRoomRequest struct {
+	CreateRoomRequest
+	IsActive *bool `json:"is_active"`
+}
+
+// RoomResponse is the standard JSON representation of a Room.
+type RoomResponse struct {
 	ID        uuid.UUID `json:"id"`
 	Name      string    `json:"name"`
 	Code      string    `json:"code"`
@@ -57,12 +62,12 @@ type roomResponse struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
-func toRoomResponse(r *domain.Room) roomResponse {
+func toRoomResponse(r *domain.Room) RoomResponse {
 	eq := r.Equipment
 	if eq == nil {
 		eq = []string{}
 	}
-	return roomResponse{
+	return RoomResponse{
 		ID:        r.ID,
 		Name:      r.Name,
 		Code:      r.Code,
@@ -78,18 +83,9 @@ func toRoomResponse(r *domain.Room) roomResponse {
 
 // CreateRoom handles POST /api/v1/rooms
 func (h *RoomHandler) CreateRoom(w http.ResponseWriter, r *http.Request) {
-	var req createRoomRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
-		return
-	}
-
-	if req.Name == "" || req.Code == "" {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "name and code are required"})
-		return
-	}
-	if req.Capacity <= 0 {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "capacity must be positive"})
+	var req CreateRoomRequest
+	if err := validate.DecodeJSON(r, &req); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -109,13 +105,14 @@ func (h *RoomHandler) CreateRoom(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.roomRepo.Save(r.Context(), room); err != nil {
 		if errors.Is(err, erptypes.ErrConflict) {
-			writeJSON(w, http.StatusConflict, map[string]string{"error": "room code already exists"})
+			problem.Write(w, r, erptypes.Conflict("room code already exists"))
 			return
 		}
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to create room"})
+		problem.Write(w, r, erptypes.Internal("failed to create room", err))
 		return
 	}
 
+	etag.Set(w, room.Version)
 	writeJSON(w, http.StatusCreated, toRoomResponse(room))
 }
 
@@ -123,94 +120,108 @@ func (h *RoomHandler) CreateRoom(w http.ResponseWriter, r *http.Request) {
 // Query params: building, min_capacity, equipment (comma-separated)
 func (h *RoomHandler) ListRooms(w http.ResponseWriter, r *http.Request) {
 	q := r.URL.Query()
-
-	filter := domain.ListFilter{
-		Building: q.Get("building"),
+	// min_capacity and equipment=a,b (all of) predate the shared list syntax.
+	if v := q.Get("min_capacity"); v != "" {
+		q.Set("capacity[gte]", v)
 	}
-
-	if mc := q.Get("min_capacity"); mc != "" {
-		n, err := strconv.Atoi(mc)
-		if err != nil || n < 0 {
-			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "min_capacity must be a non-negative integer"})
-			return
-		}
-		filter.MinCapacity = n
+	if v := q.Get("equipment"); v != "" {
+		q.Del("equipment")
+		q.Set("equipment[contains]", v)
 	}
-
-	if eq := q.Get("equipment"); eq != "" {
-		for _, item := range strings.Split(eq, ",") {
-			item = strings.TrimSpace(item)
-			if item != "" {
-				filter.Equipment = append(filter.Equipment, item)
-			}
-		}
-	}
-
-	rooms, err := h.roomRepo.List(r.Context(), filter)
+	spec, err := listquery.Parse(q, domain.RoomListSchema)
 	if err != nil {
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to list rooms"})
+		problem.Write(w, r, validate.Query(err))
 		return
 	}
 
-	items := make([]roomResponse, len(rooms))
-	for i, room := range rooms {
-		items[i] = toRoomResponse(room)
+	page, err := h.roomRepo.List(r.Context(), spec)
+	if err != nil {
+		problem.Write(w, r, erptypes.Internal("failed to list rooms", err))
+		return
 	}
-
-	writeJSON(w, http.StatusOK, map[string]any{"items": items, "total": len(items)})
+	writeJSON(w, http.StatusOK, listquery.Map(page, toRoomResponse))
 }
 
-// GetRoom handles GET /api/v1/rooms/{id}
+// GetRoom handles GET /api/v1/rooms/{id}[?as_of=<RFC 3339>]
 func (h *RoomHandler) GetRoom(w http.ResponseWriter, r *http.Request) {
-	id, err := uuid.Parse(r.PathValue("id"))
+	id, err := validate.PathUUID(r, "id")
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	asOf, err := history.ParseAsOf(r.URL.Query())
 	if err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid room id"})
+		problem.Write(w, r, validate.Query(err))
 		return
 	}
 
-	room, err := h.roomRepo.FindByID(r.Context(), id)
+	var room *domain.Room
+	if asOf != nil {
+		room, err = h.roomRepo.FindByIDAsOf(r.Context(), id, *asOf)
+	} else {
+		room, err = h.roomRepo.FindByID(r.Context(), id)
+	}
 	if err != nil {
 		if errors.Is(err, erptypes.ErrNotFound) {
-			writeJSON(w, http.StatusNotFound, map[string]string{"error": "room not found"})
+			problem.Write(w, r, erptypes.NotFound("room not found"))
 			return
 		}
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get room"})
+		problem.Write(w, r, erptypes.Internal("failed to get room", err))
 		return
 	}
 
+	if asOf == nil {
+		etag.Set(w, room.Version)
+	}
 	writeJSON(w, http.StatusOK, toRoomResponse(room))
 }
 
-// UpdateRoom handles PUT /api/v1/rooms/{id}
-func (h *RoomHandler) UpdateRoom(w http.ResponseWriter, r *http.Request) {
-	id, err := uuid.Parse(r.PathValue("id"))
+// RoomHistory handles GET /api/v1/rooms/{id}/history
+func (h *RoomHandler) RoomHistory(w http.ResponseWriter, r *http.Request) {
+	id, err := validate.PathUUID(r, "id")
 	if err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid room id"})
+		problem.Write(w, r, err)
 		return
 	}
 
-	var req updateRoomRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	versions, err := h.roomRepo.History(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, erptypes.ErrNotFound) {
+			problem.Write(w, r, erptypes.NotFound("room not found"))
+			return
+		}
+		problem.Write(w, r, erptypes.Internal("failed to get room history", err))
 		return
 	}
 
-	if req.Name == "" || req.Code == "" {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "name and code are required"})
+	writeJSON(w, http.StatusOK, history.List[RoomResponse]{Items: history.Map(versions, toRoomResponse)})
+}
+
+// UpdateRoom handles PUT /api/v1/rooms/{id}
+func (h *RoomHandler) UpdateRoom(w http.ResponseWriter, r *http.Request) {
+	id, err := validate.PathUUID(r, "id")
+	if err != nil {
+		problem.Write(w, r, err)
 		return
 	}
-	if req.Capacity <= 0 {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "capacity must be positive"})
+
+	var req UpdateRoomRequest
+	if err := validate.DecodeJSON(r, &req); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
 	room, err := h.roomRepo.FindByID(r.Context(), id)
 	if err != nil {
 		if errors.Is(err, erptypes.ErrNotFound) {
-			writeJSON(w, http.StatusNotFound, map[string]string{"error": "room not found"})
+			problem.Write(w, r, erptypes.NotFound("room not found"))
 			return
 		}
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get room"})
+		problem.Write(w, r, erptypes.Internal("failed to get room", err))
+		return
+	}
+	if !etag.Match(r, room.Version) {
+		problem.Write(w, r, erptypes.PreconditionFailed("room was modified; reload and retry"))
 		return
 	}
 
@@ -225,9 +236,17 @@ func (h *RoomHandler) UpdateRoom(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.roomRepo.Update(r.Context(), room); err != nil {
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to update room"})
+		switch {
+		case errors.Is(err, erptypes.ErrNotFound):
+			problem.Write(w, r, erptypes.NotFound("room not found"))
+		case errors.Is(err, erptypes.ErrConflict):
+			problem.Write(w, r, etag.Conflict(r, "room was modified; reload and retry"))
+		default:
+			problem.Write(w, r, erptypes.Internal("failed to update room", err))
+		}
 		return
 	}
 
+	etag.Set(w, room.Version)
 	writeJSON(w, http.StatusOK, toRoomResponse(room))
 }
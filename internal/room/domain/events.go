@@ -6,6 +6,13 @@ import (
 	"github.com/google/uuid"
 )
 
+// Event topics published through the transactional outbox.
+const (
+	TopicRoomCreated             = "room.room.created"
+	TopicRoomUpdated             = "room.room.updated"
+	TopicRoomAvailabilityUpdated = "room.availability.updated"
+)
+
 // RoomCreated is published when a new room is created.
 type RoomCreated struct {
 	RoomID    uuid.UUID `json:"room_id"`
@@ -2,24 +2,46 @@ package domain
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/history"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
 )
 
-// ListFilter holds optional filters for listing rooms.
-type ListFilter struct {
-	Building    string   // filter by building name (empty = no filter)
-	MinCapacity int      // minimum seat capacity (0 = no filter)
-	Equipment   []string // must have all listed equipment (nil = no filter)
+// RoomListSchema declares how rooms can be filtered and sorted.
+var RoomListSchema = &listquery.Schema{
+	Fields: map[string]listquery.Field{
+		"id":         {Kind: listquery.UUID, Filter: listquery.Eq | listquery.In, Sort: true},
+		"name":       {Kind: listquery.Text, Filter: listquery.Eq | listquery.Contains, Sort: true},
+		"code":       {Kind: listquery.Text, Filter: listquery.Eq | listquery.In | listquery.Contains, Sort: true},
+		"building":   {Kind: listquery.Text, Filter: listquery.Eq | listquery.In | listquery.Contains, Sort: true},
+		"floor":      {Kind: listquery.Int, Filter: listquery.Eq | listquery.In | listquery.Range, Sort: true},
+		"capacity":   {Kind: listquery.Int, Filter: listquery.Eq | listquery.Range, Sort: true},
+		"equipment":  {Kind: listquery.Text, Array: true, Filter: listquery.Eq | listquery.In | listquery.Contains},
+		"is_active":  {Kind: listquery.Bool, Filter: listquery.Eq},
+		"created_at": {Kind: listquery.Time, Filter: listquery.Range, Sort: true},
+		"updated_at": {Kind: listquery.Time, Filter: listquery.Range, Sort: true},
+	},
+	Key:         "id",
+	DefaultSort: []listquery.Sort{{Field: "code"}},
 }
 
 // RoomRepository defines persistence operations for Room entities.
 type RoomRepository interface {
 	FindByID(ctx context.Context, id uuid.UUID) (*Room, error)
+	// FindByIDAsOf returns the room as it was at the given time.
+	FindByIDAsOf(ctx context.Context, id uuid.UUID, at time.Time) (*Room, error)
+	// History returns every version of the room, oldest first.
+	History(ctx context.Context, id uuid.UUID) ([]history.Version[*Room], error)
 	FindByCode(ctx context.Context, code string) (*Room, error)
+	// Save inserts the room; a duplicate code is erptypes.ErrConflict.
 	Save(ctx context.Context, room *Room) error
+	// Update stores the room if it is still at room.Version and bumps the
+	// version; otherwise it returns erptypes.ErrConflict.
 	Update(ctx context.Context, room *Room) error
-	List(ctx context.Context, filter ListFilter) ([]*Room, error)
+	List(ctx context.Context, spec listquery.Spec) (listquery.Page[*Room], error)
 }
 
 // RoomAvailabilityRepository persists weekly slot availability for rooms.
@@ -27,6 +49,8 @@ type RoomAvailabilityRepository interface {
 	// GetByRoomID returns the full availability map for a room.
 	// Returns an empty map (not error) when no slots are configured.
 	GetByRoomID(ctx context.Context, roomID uuid.UUID) (RoomAvailability, error)
+	// GetByRoomIDAsOf returns the availability stored for the room at the given time.
+	GetByRoomIDAsOf(ctx context.Context, roomID uuid.UUID, at time.Time) (RoomAvailability, error)
 
 	// SetSlots replaces all availability records for the given room.
 	SetSlots(ctx context.Context, roomID uuid.UUID, avail RoomAvailability) error
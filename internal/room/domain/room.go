@@ -16,6 +16,7 @@ type Room struct {
 	Capacity  int
 	Equipment []string
 	IsActive  bool
+	Version   int // optimistic lock, bumped by every update
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
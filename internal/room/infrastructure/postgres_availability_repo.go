@@ -3,12 +3,15 @@ package infrastructure
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/database"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/history"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/outbox"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/room/domain"
 )
@@ -28,6 +31,15 @@ func (r *PostgresAvailabilityRepo) schema(ctx context.Context) (string, error) {
 }
 
 func (r *PostgresAvailabilityRepo) GetByRoomID(ctx context.Context, roomID uuid.UUID) (domain.RoomAvailability, error) {
+	return r.getByRoomID(ctx, "room_availability", roomID)
+}
+
+// GetByRoomIDAsOf returns the availability map stored for a room at the given time.
+func (r *PostgresAvailabilityRepo) GetByRoomIDAsOf(ctx context.Context, roomID uuid.UUID, at time.Time) (domain.RoomAvailability, error) {
+	return r.getByRoomID(ctx, history.AsOf("room_availability", 2), roomID, at)
+}
+
+func (r *PostgresAvailabilityRepo) getByRoomID(ctx context.Context, from string, args ...any) (domain.RoomAvailability, error) {
 	schema, err := r.schema(ctx)
 	if err != nil {
 		return nil, err
@@ -36,8 +48,8 @@ func (r *PostgresAvailabilityRepo) GetByRoomID(ctx context.Context, roomID uuid.
 	avail := make(domain.RoomAvailability)
 	err = database.WithTenantTx(ctx, r.pool, schema, func(tx pgx.Tx) error {
 		rows, err := tx.Query(ctx,
-			`SELECT day, period, is_available FROM room_availability WHERE room_id = $1`,
-			roomID,
+			`SELECT day, period, is_available FROM `+from+` WHERE room_id = $1`,
+			args...,
 		)
 		if err != nil {
 			return err
@@ -83,7 +95,10 @@ func (r *PostgresAvailabilityRepo) SetSlots(ctx context.Context, roomID uuid.UUI
 				return err
 			}
 		}
-		return nil
+		return outbox.Write(ctx, tx, domain.TopicRoomAvailabilityUpdated, domain.RoomAvailabilityUpdated{
+			RoomID:     roomID,
+			OccurredAt: time.Now(),
+		})
 	})
 }
 
@@ -2,14 +2,18 @@ package infrastructure
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/database"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/history"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/outbox"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/room/domain"
 	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
@@ -30,6 +34,15 @@ func (r *PostgresRoomRepo) schema(ctx context.Context) (string, error) {
 }
 
 func (r *PostgresRoomRepo) FindByID(ctx context.Context, id uuid.UUID) (*domain.Room, error) {
+	return r.findByID(ctx, "rooms", id)
+}
+
+// FindByIDAsOf returns the room as it was at the given time.
+func (r *PostgresRoomRepo) FindByIDAsOf(ctx context.Context, id uuid.UUID, at time.Time) (*domain.Room, error) {
+	return r.findByID(ctx, history.AsOf("rooms", 2), id, at)
+}
+
+func (r *PostgresRoomRepo) findByID(ctx context.Context, from string, args ...any) (*domain.Room, error) {
 	schema, err := r.schema(ctx)
 	if err != nil {
 		return nil, err
@@ -38,17 +51,17 @@ func (r *PostgresRoomRepo) FindByID(ctx context.Context, id uuid.UUID) (*domain.
 	var room domain.Room
 	err = database.WithTenantTx(ctx, r.pool, schema, func(tx pgx.Tx) error {
 		return tx.QueryRow(ctx,
-			`SELECT id, name, code, building, floor, capacity, equipment, is_active, created_at, updated_at
-			 FROM rooms WHERE id = $1`,
-			id,
+			`SELECT id, name, code, building, floor, capacity, equipment, is_active, version, created_at, updated_at
+			 FROM `+from+` WHERE id = $1`,
+			args...,
 		).Scan(
 			&room.ID, &room.Name, &room.Code, &room.Building,
 			&room.Floor, &room.Capacity, &room.Equipment,
-			&room.IsActive, &room.CreatedAt, &room.UpdatedAt,
+			&room.IsActive, &room.Version, &room.CreatedAt, &room.UpdatedAt,
 		)
 	})
 	if err != nil {
-		if err == pgx.ErrNoRows {
+		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, erptypes.ErrNotFound
 		}
 		return nil, fmt.Errorf("find room by id: %w", err)
@@ -65,17 +78,17 @@ func (r *PostgresRoomRepo) FindByCode(ctx context.Context, code string) (*domain
 	var room domain.Room
 	err = database.WithTenantTx(ctx, r.pool, schema, func(tx pgx.Tx) error {
 		return tx.QueryRow(ctx,
-			`SELECT id, name, code, building, floor, capacity, equipment, is_active, created_at, updated_at
+			`SELECT id, name, code, building, floor, capacity, equipment, is_active, version, created_at, updated_at
 			 FROM rooms WHERE code = $1`,
 			code,
 		).Scan(
 			&room.ID, &room.Name, &room.Code, &room.Building,
 			&room.Floor, &room.Capacity, &room.Equipment,
-			&room.IsActive, &room.CreatedAt, &room.UpdatedAt,
+			&room.IsActive, &room.Version, &room.CreatedAt, &room.UpdatedAt,
 		)
 	})
 	if err != nil {
-		if err == pgx.ErrNoRows {
+		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, erptypes.ErrNotFound
 		}
 		return nil, fmt.Errorf("find room by code: %w", err)
@@ -90,13 +103,26 @@ func (r *PostgresRoomRepo) Save(ctx context.Context, room *domain.Room) error {
 	}
 
 	return database.WithTenantTx(ctx, r.pool, schema, func(tx pgx.Tx) error {
-		_, err := tx.Exec(ctx,
+		err := tx.QueryRow(ctx,
 			`INSERT INTO rooms (id, name, code, building, floor, capacity, equipment, is_active, created_at, updated_at)
-			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			 RETURNING version`,
 			room.ID, room.Name, room.Code, room.Building, room.Floor,
 			room.Capacity, room.Equipment, room.IsActive, room.CreatedAt, room.UpdatedAt,
-		)
-		return err
+		).Scan(&room.Version)
+		if database.IsUniqueViolation(err) {
+			return fmt.Errorf("room code %q: %w", room.Code, erptypes.ErrConflict)
+		}
+		if err != nil {
+			return err
+		}
+		return outbox.Write(ctx, tx, domain.TopicRoomCreated, domain.RoomCreated{
+			RoomID:     room.ID,
+			Code:       room.Code,
+			Name:       room.Name,
+			Capacity:   room.Capacity,
+			OccurredAt: time.Now(),
+		})
 	})
 }
 
@@ -107,28 +133,75 @@ func (r *PostgresRoomRepo) Update(ctx context.Context, room *domain.Room) error
 	}
 
 	return database.WithTenantTx(ctx, r.pool, schema, func(tx pgx.Tx) error {
-		_, err := tx.Exec(ctx,
+		err := tx.QueryRow(ctx,
 			`UPDATE rooms
 			 SET name = $2, code = $3, building = $4, floor = $5,
-			     capacity = $6, equipment = $7, is_active = $8, updated_at = now()
-			 WHERE id = $1`,
+			     capacity = $6, equipment = $7, is_active = $8,
+			     version = version + 1, updated_at = now()
+			 WHERE id = $1 AND version = $9
+			 RETURNING version, updated_at`,
 			room.ID, room.Name, room.Code, room.Building, room.Floor,
-			room.Capacity, room.Equipment, room.IsActive,
-		)
+			room.Capacity, room.Equipment, room.IsActive, room.Version,
+		).Scan(&room.Version, &room.UpdatedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return database.VersionMismatch(ctx, tx, "rooms", "version", room.ID, room.Version)
+		}
+		if err != nil {
+			return err
+		}
+		return outbox.Write(ctx, tx, domain.TopicRoomUpdated, domain.RoomUpdated{
+			RoomID:     room.ID,
+			Code:       room.Code,
+			OccurredAt: time.Now(),
+		})
+	})
+}
+
+func (r *PostgresRoomRepo) List(ctx context.Context, spec listquery.Spec) (listquery.Page[*domain.Room], error) {
+	schema, err := r.schema(ctx)
+	if err != nil {
+		return listquery.Page[*domain.Room]{}, err
+	}
+	q, err := listquery.Build(spec, domain.RoomListSchema)
+	if err != nil {
+		return listquery.Page[*domain.Room]{}, err
+	}
+
+	var page listquery.Page[*domain.Room]
+	err = database.WithTenantTx(ctx, r.pool, schema, func(tx pgx.Tx) error {
+		page, err = listquery.Fetch(ctx, tx, q,
+			"id, name, code, building, floor, capacity, equipment, is_active, version, created_at, updated_at", "rooms",
+			func(rows pgx.Rows, cursor *string) (*domain.Room, error) {
+				var room domain.Room
+				return &room, rows.Scan(
+					&room.ID, &room.Name, &room.Code, &room.Building,
+					&room.Floor, &room.Capacity, &room.Equipment,
+					&room.IsActive, &room.Version, &room.CreatedAt, &room.UpdatedAt, cursor,
+				)
+			})
 		return err
 	})
+	if err != nil {
+		return listquery.Page[*domain.Room]{}, fmt.Errorf("list rooms: %w", err)
+	}
+	return page, nil
 }
 
-func (r *PostgresRoomRepo) List(ctx context.Context, filter domain.ListFilter) ([]*domain.Room, error) {
+// History returns every version of the room, oldest first, or
+// erptypes.ErrNotFound when it never existed.
+func (r *PostgresRoomRepo) History(ctx context.Context, id uuid.UUID) ([]history.Version[*domain.Room], error) {
 	schema, err := r.schema(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	var rooms []*domain.Room
+	var versions []history.Version[*domain.Room]
 	err = database.WithTenantTx(ctx, r.pool, schema, func(tx pgx.Tx) error {
-		query, args := buildListQuery(filter)
-		rows, err := tx.Query(ctx, query, args...)
+		rows, err := tx.Query(ctx,
+			`SELECT valid_from, valid_to, id, name, code, building, floor, capacity, equipment, is_active, created_at, updated_at
+			 FROM `+history.Versions("rooms", 1)+` ORDER BY valid_from`,
+			id.String(),
+		)
 		if err != nil {
 			return err
 		}
@@ -136,58 +209,22 @@ func (r *PostgresRoomRepo) List(ctx context.Context, filter domain.ListFilter) (
 
 		for rows.Next() {
 			var room domain.Room
-			if err := rows.Scan(
-				&room.ID, &room.Name, &room.Code, &room.Building,
-				&room.Floor, &room.Capacity, &room.Equipment,
-				&room.IsActive, &room.CreatedAt, &room.UpdatedAt,
-			); err != nil {
+			v := history.Version[*domain.Room]{Data: &room}
+			if err := rows.Scan(&v.ValidFrom, &v.ValidTo, &room.ID, &room.Name, &room.Code, &room.Building,
+				&room.Floor, &room.Capacity, &room.Equipment, &room.IsActive, &room.CreatedAt, &room.UpdatedAt); err != nil {
 				return err
 			}
-			rooms = append(rooms, &room)
+			versions = append(versions, v)
 		}
 		return rows.Err()
 	})
 	if err != nil {
-		return nil, fmt.Errorf("list rooms: %w", err)
+		return nil, fmt.Errorf("room history: %w", err)
 	}
-	return rooms, nil
-}
-
-// buildListQuery constructs a parameterized SELECT with optional WHERE clauses.
-func buildListQuery(filter domain.ListFilter) (string, []any) {
-	base := `SELECT id, name, code, building, floor, capacity, equipment, is_active, created_at, updated_at
-	         FROM rooms`
-
-	var clauses []string
-	var args []any
-	idx := 1
-
-	if filter.Building != "" {
-		clauses = append(clauses, fmt.Sprintf("building = $%d", idx))
-		args = append(args, filter.Building)
-		idx++
+	if len(versions) == 0 {
+		return nil, erptypes.ErrNotFound
 	}
-
-	if filter.MinCapacity > 0 {
-		clauses = append(clauses, fmt.Sprintf("capacity >= $%d", idx))
-		args = append(args, filter.MinCapacity)
-		idx++
-	}
-
-	// equipment filter: room must contain ALL requested items
-	for _, eq := range filter.Equipment {
-		clauses = append(clauses, fmt.Sprintf("$%d = ANY(equipment)", idx))
-		args = append(args, eq)
-		idx++
-	}
-
-	query := base
-	if len(clauses) > 0 {
-		query += " WHERE " + strings.Join(clauses, " AND ")
-	}
-	query += " ORDER BY code ASC"
-
-	return query, args
+	return versions, nil
 }
 
 // Ensure interface compliance.
@@ -0,0 +1,93 @@
+//go:build integration
+
+package room_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/testutil"
+)
+
+func TestRoomListQuery_FilterSortAndCursor(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	schema := db.CreateTenantSchema(t)
+	admin := testutil.SeedAdmin(t, db.Pool, schema)
+	srv := testutil.TestServer(t, db.Pool)
+	defer srv.Close()
+
+	token := loginAndGetToken(t, srv.URL, admin.Email, admin.Password)
+	rooms := []struct {
+		code      string
+		capacity  int
+		equipment []string
+	}{
+		{"L-1", 30, []string{"projector"}},
+		{"L-2", 60, []string{"projector", "audio"}},
+		{"L-3", 60, []string{"audio"}},
+		{"L-4", 90, []string{"projector", "audio"}},
+		{"L-5", 10, nil},
+	}
+	for _, r := range rooms {
+		_ = getJSON(t, mustAuthReq(t, http.MethodPost, srv.URL+"/api/v1/rooms", token, schema, jsonBody(t, map[string]any{
+			"name": "Lab " + r.code, "code": r.code, "building": "Lab", "floor": 1,
+			"capacity": r.capacity, "equipment": r.equipment,
+		})), http.StatusCreated)
+	}
+
+	list := func(q url.Values, status int) map[string]any {
+		t.Helper()
+		return getJSON(t, mustAuthReq(t, http.MethodGet, srv.URL+"/api/v1/rooms?"+q.Encode(), token, schema, nil), status)
+	}
+	codes := func(page map[string]any) []string {
+		var out []string
+		for _, it := range page["items"].([]any) {
+			out = append(out, fmt.Sprint(it.(map[string]any)["code"]))
+		}
+		return out
+	}
+
+	// Rooms with >= 30 seats, largest first, ties by code, two per page.
+	q := url.Values{"capacity[gte]": {"30"}, "sort": {"-capacity,code"}, "limit": {"2"}}
+	var got []string
+	for pages := 0; ; pages++ {
+		page := list(q, http.StatusOK)
+		if page["total"].(float64) != 4 {
+			t.Fatalf("expected total 4 on every page, got %v", page["total"])
+		}
+		got = append(got, codes(page)...)
+		next, _ := page["next_cursor"].(string)
+		if next == "" {
+			if pages != 1 {
+				t.Fatalf("expected 2 pages, got %d", pages+1)
+			}
+			break
+		}
+		q.Set("cursor", next)
+	}
+	if fmt.Sprint(got) != "[L-4 L-2 L-3 L-1]" {
+		t.Fatalf("unexpected order across pages: %v", got)
+	}
+
+	// Array all-of and in filters, plus the legacy equipment parameter.
+	if c := codes(list(url.Values{"equipment[contains]": {"projector,audio"}, "sort": {"code"}}, http.StatusOK)); fmt.Sprint(c) != "[L-2 L-4]" {
+		t.Fatalf("unexpected equipment[contains] result: %v", c)
+	}
+	if c := codes(list(url.Values{"equipment": {"audio,projector"}, "sort": {"code"}}, http.StatusOK)); fmt.Sprint(c) != "[L-2 L-4]" {
+		t.Fatalf("unexpected legacy equipment result: %v", c)
+	}
+	if c := codes(list(url.Values{"code[in]": {"L-5,L-1"}}, http.StatusOK)); fmt.Sprint(c) != "[L-1 L-5]" {
+		t.Fatalf("unexpected code[in] result: %v", c)
+	}
+
+	// A cursor only continues the sort it was issued for.
+	first := list(url.Values{"sort": {"code"}, "limit": {"1"}}, http.StatusOK)
+	_ = list(url.Values{"sort": {"-code"}, "cursor": {first["next_cursor"].(string)}}, http.StatusBadRequest)
+
+	_ = list(url.Values{"sort": {"equipment"}}, http.StatusBadRequest)
+	_ = list(url.Values{"capacity[gte]": {"many"}}, http.StatusBadRequest)
+	_ = list(url.Values{"colour[eq]": {"red"}}, http.StatusBadRequest)
+	_ = list(url.Values{"cursor": {"not-a-cursor"}}, http.StatusBadRequest)
+}
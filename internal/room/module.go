@@ -5,15 +5,21 @@ import (
 	"net/http"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	"google.golang.org/grpc"
 
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/core/application/services"
 	coredel "github.com/HuynhHoangPhuc/mcs-erp/internal/core/delivery"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/core/domain"
-	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/auth"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/database"
+	platformgrpc "github.com/HuynhHoangPhuc/mcs-erp/internal/platform/grpc"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/history"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/openapi"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/room/delivery"
 	roomdomain "github.com/HuynhHoangPhuc/mcs-erp/internal/room/domain"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/room/infrastructure"
+	pkgmod "github.com/HuynhHoangPhuc/mcs-erp/pkg/module"
+	roomv1 "github.com/HuynhHoangPhuc/mcs-erp/proto/gen/room/v1"
 )
 
 // Module implements pkg/module.Module for the room module.
@@ -22,6 +28,7 @@ type Module struct {
 	authSvc   *services.AuthService
 	roomRepo  *infrastructure.PostgresRoomRepo
 	availRepo *infrastructure.PostgresAvailabilityRepo
+	routes    []pkgmod.Route
 }
 
 // NewModule creates the room module wired with concrete dependencies.
@@ -43,62 +50,61 @@ func (m *Module) RoomAvailabilityRepo() roomdomain.RoomAvailabilityRepository {
 func (m *Module) Name() string          { return "room" }
 func (m *Module) Dependencies() []string { return []string{"core"} }
 
-// Migrate runs room table migrations across all active tenant schemas.
+// Migrate applies the module's pending migrations to _template; tenants follow via database.TenantRunner.
 func (m *Module) Migrate(ctx context.Context) error {
-	migrator := database.NewMigrator(m.pool)
-
-	if err := migrator.MigrateAll(ctx, sqlCreateRoomsTable); err != nil {
-		return err
-	}
-	return migrator.MigrateAll(ctx, sqlCreateRoomAvailabilityTable)
+	return database.NewMigrator(m.pool).MigrateModule(ctx, m.Name())
 }
 
 func (m *Module) RegisterEvents(_ context.Context) error { return nil }
 
+// RegisterGRPC exposes read access to rooms and availability (PermRoomRead).
+func (m *Module) RegisterGRPC(s grpc.ServiceRegistrar) map[string]string {
+	roomv1.RegisterRoomServiceServer(s, delivery.NewRoomGRPCServer(m.roomRepo, m.availRepo))
+	return platformgrpc.ServicePermissions(&roomv1.RoomService_ServiceDesc, domain.PermRoomRead)
+}
+
+// Routes describes the routes mounted by RegisterRoutes.
+func (m *Module) Routes() []pkgmod.Route { return m.routes }
+
 func (m *Module) RegisterRoutes(mux *http.ServeMux) {
 	roomHandler := delivery.NewRoomHandler(m.roomRepo)
 	availHandler := delivery.NewAvailabilityHandler(m.roomRepo, m.availRepo)
 
-	authMw := coredel.AuthMiddleware(m.authSvc)
-	readPerm := auth.RequirePermission(domain.PermRoomRead)
-	writePerm := auth.RequirePermission(domain.PermRoomWrite)
-
-	mux.Handle("POST /api/v1/rooms", authMw(writePerm(http.HandlerFunc(roomHandler.CreateRoom))))
-	mux.Handle("GET /api/v1/rooms", authMw(readPerm(http.HandlerFunc(roomHandler.ListRooms))))
-	mux.Handle("GET /api/v1/rooms/{id}", authMw(readPerm(http.HandlerFunc(roomHandler.GetRoom))))
-	mux.Handle("PUT /api/v1/rooms/{id}", authMw(writePerm(http.HandlerFunc(roomHandler.UpdateRoom))))
-
-	mux.Handle("GET /api/v1/rooms/{id}/availability", authMw(readPerm(http.HandlerFunc(availHandler.GetAvailability))))
-	mux.Handle("PUT /api/v1/rooms/{id}/availability", authMw(writePerm(http.HandlerFunc(availHandler.SetAvailability))))
+	api := openapi.NewRouter(mux, m.Name(), coredel.ModuleAuthMiddleware(m.authSvc, m.Name()))
+	readPerm, writePerm := domain.PermRoomRead, domain.PermRoomWrite
+
+	api.Handle(pkgmod.Route{
+		Method: "POST", Path: "/api/v1/rooms", Summary: "Create a room", Permission: writePerm,
+		Request: delivery.CreateRoomRequest{}, Response: delivery.RoomResponse{}, Status: http.StatusCreated,
+	}, roomHandler.CreateRoom)
+	api.Handle(pkgmod.Route{
+		Method: "GET", Path: "/api/v1/rooms", Summary: "List rooms", Permission: readPerm,
+		Query: openapi.ListParams(roomdomain.RoomListSchema), Response: listquery.Page[delivery.RoomResponse]{},
+	}, roomHandler.ListRooms)
+	api.Handle(pkgmod.Route{
+		Method: "GET", Path: "/api/v1/rooms/{id}", Summary: "Get a room", Permission: readPerm,
+		Query: []pkgmod.Param{openapi.AsOf}, Response: delivery.RoomResponse{},
+	}, roomHandler.GetRoom)
+	api.Handle(pkgmod.Route{
+		Method: "PUT", Path: "/api/v1/rooms/{id}", Summary: "Update a room", Permission: writePerm,
+		Request: delivery.UpdateRoomRequest{}, Response: delivery.RoomResponse{},
+	}, roomHandler.UpdateRoom)
+	api.Handle(pkgmod.Route{
+		Method: "GET", Path: "/api/v1/rooms/{id}/history", Summary: "List a room's versions", Permission: readPerm,
+		Response: history.List[delivery.RoomResponse]{},
+	}, roomHandler.RoomHistory)
+
+	api.Handle(pkgmod.Route{
+		Method: "GET", Path: "/api/v1/rooms/{id}/availability", Summary: "Get a room's availability", Permission: readPerm,
+		OperationID: "getRoomAvailability",
+		Query:       []pkgmod.Param{openapi.AsOf}, Response: delivery.AvailabilityResponse{},
+	}, availHandler.GetAvailability)
+	api.Handle(pkgmod.Route{
+		Method: "PUT", Path: "/api/v1/rooms/{id}/availability", Summary: "Replace a room's availability", Permission: writePerm,
+		OperationID: "setRoomAvailability",
+		Request:     delivery.SetAvailabilityRequest{}, Response: map[string]string{},
+	}, availHandler.SetAvailability)
+
+	m.routes = api.Routes()
 }
 
-// sqlCreateRoomsTable is the DDL for the rooms table.
-const sqlCreateRoomsTable = `
-CREATE TABLE IF NOT EXISTS rooms (
-    id        UUID PRIMARY KEY DEFAULT gen_random_uuid(),
-    name      VARCHAR(255) NOT NULL,
-    code      VARCHAR(50)  NOT NULL,
-    building  VARCHAR(255) NOT NULL DEFAULT '',
-    floor     INTEGER      NOT NULL DEFAULT 0,
-    capacity  INTEGER      NOT NULL CHECK (capacity > 0),
-    equipment TEXT[]       NOT NULL DEFAULT '{}',
-    is_active BOOLEAN      NOT NULL DEFAULT true,
-    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
-    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
-    CONSTRAINT rooms_code_unique UNIQUE (code)
-);
-CREATE INDEX IF NOT EXISTS idx_rooms_building ON rooms(building);
-CREATE INDEX IF NOT EXISTS idx_rooms_capacity ON rooms(capacity);
-`
-
-// sqlCreateRoomAvailabilityTable is the DDL for the room_availability table.
-const sqlCreateRoomAvailabilityTable = `
-CREATE TABLE IF NOT EXISTS room_availability (
-    room_id      UUID    NOT NULL REFERENCES rooms(id) ON DELETE CASCADE,
-    day          SMALLINT NOT NULL CHECK (day >= 0 AND day <= 6),
-    period       SMALLINT NOT NULL CHECK (period >= 1 AND period <= 10),
-    is_available BOOLEAN  NOT NULL DEFAULT true,
-    CONSTRAINT room_availability_unique UNIQUE (room_id, day, period)
-);
-CREATE INDEX IF NOT EXISTS idx_room_availability_room_id ON room_availability(room_id);
-`
@@ -44,8 +44,8 @@ func TestRoomCRUDAndAvailability(t *testing.T) {
 		"floor":     1,
 		"capacity":  50,
 		"equipment": []string{"projector"},
-	})), http.StatusInternalServerError)
-	if fmt.Sprintf("%v", dupResp["error"]) == "" {
+	})), http.StatusConflict)
+	if dupResp["code"] != "conflict" {
 		t.Fatalf("expected duplicate room create error payload")
 	}
 
@@ -1,13 +1,15 @@
 package delivery
 
 import (
-	"encoding/json"
 	"errors"
 	"net/http"
 	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/problem"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/validate"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/subject/domain"
 	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
 )
@@ -22,21 +24,21 @@ func NewCategoryHandler(categoryRepo domain.CategoryRepository) *CategoryHandler
 	return &CategoryHandler{categoryRepo: categoryRepo}
 }
 
-type createCategoryRequest struct {
+// CreateCategoryRequest is the body of POST /api/v1/categories.
+type CreateCategoryRequest struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 }
 
+func (req *CreateCategoryRequest) Validate(v *validate.Validator) {
+	v.Required("name", req.Name)
+}
+
 // CreateCategory handles POST /api/v1/categories
 func (h *CategoryHandler) CreateCategory(w http.ResponseWriter, r *http.Request) {
-	var req createCategoryRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
-		return
-	}
-
-	if req.Name == "" {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "name is required"})
+	var req CreateCategoryRequest
+	if err := validate.DecodeJSON(r, &req); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -48,54 +50,58 @@ func (h *CategoryHandler) CreateCategory(w http.ResponseWriter, r *http.Request)
 	}
 
 	if err := h.categoryRepo.Save(r.Context(), c); err != nil {
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to create category"})
+		problem.Write(w, r, erptypes.Internal("failed to create category", err))
 		return
 	}
 
-	writeJSON(w, http.StatusCreated, categoryResponse(c))
+	writeJSON(w, http.StatusCreated, toCategoryResponse(c))
 }
 
 // ListCategories handles GET /api/v1/categories
 func (h *CategoryHandler) ListCategories(w http.ResponseWriter, r *http.Request) {
-	categories, err := h.categoryRepo.List(r.Context())
+	spec, err := listquery.Parse(r.URL.Query(), domain.CategoryListSchema)
 	if err != nil {
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to list categories"})
+		problem.Write(w, r, validate.Query(err))
 		return
 	}
 
-	items := make([]map[string]any, len(categories))
-	for i, c := range categories {
-		items[i] = categoryResponse(c)
+	page, err := h.categoryRepo.List(r.Context(), spec)
+	if err != nil {
+		problem.Write(w, r, erptypes.Internal("failed to list categories", err))
+		return
 	}
-	writeJSON(w, http.StatusOK, map[string]any{"items": items})
+	writeJSON(w, http.StatusOK, listquery.Map(page, toCategoryResponse))
 }
 
 // GetCategory handles GET /api/v1/categories/{id}
 func (h *CategoryHandler) GetCategory(w http.ResponseWriter, r *http.Request) {
-	id, err := uuid.Parse(r.PathValue("id"))
+	id, err := validate.PathUUID(r, "id")
 	if err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid category id"})
+		problem.Write(w, r, err)
 		return
 	}
 
 	c, err := h.categoryRepo.FindByID(r.Context(), id)
 	if err != nil {
 		if errors.Is(err, erptypes.ErrNotFound) {
-			writeJSON(w, http.StatusNotFound, map[string]string{"error": "category not found"})
+			problem.Write(w, r, erptypes.NotFound("category not found"))
 			return
 		}
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get category"})
+		problem.Write(w, r, erptypes.Internal("failed to get category", err))
 		return
 	}
 
-	writeJSON(w, http.StatusOK, categoryResponse(c))
+	writeJSON(w, http.StatusOK, toCategoryResponse(c))
 }
 
-func categoryResponse(c *domain.Category) map[string]any {
-	return map[string]any{
-		"id":          c.ID,
-		"name":        c.Name,
-		"description": c.Description,
-		"created_at":  c.CreatedAt,
-	}
+// CategoryResponse is the JSON representation of a Category.
+type CategoryResponse struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func toCategoryResponse(c *domain.Category) CategoryResponse {
+	return CategoryResponse{ID: c.ID, Name: c.Name, Description: c.Description, CreatedAt: c.CreatedAt}
 }
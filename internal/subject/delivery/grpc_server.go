@@ -0,0 +1,109 @@
+package delivery
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	platformgrpc "github.com/HuynhHoangPhuc/mcs-erp/internal/platform/grpc"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/subject/domain"
+	subjectv1 "github.com/HuynhHoangPhuc/mcs-erp/proto/gen/subject/v1"
+)
+
+// SubjectGRPCServer implements subjectv1.SubjectServiceServer over the subject repositories.
+type SubjectGRPCServer struct {
+	subjectv1.UnimplementedSubjectServiceServer
+	subjects domain.SubjectRepository
+	prereqs  domain.PrerequisiteRepository
+}
+
+func NewSubjectGRPCServer(subjects domain.SubjectRepository, prereqs domain.PrerequisiteRepository) *SubjectGRPCServer {
+	return &SubjectGRPCServer{subjects: subjects, prereqs: prereqs}
+}
+
+func (s *SubjectGRPCServer) GetSubject(ctx context.Context, req *subjectv1.GetSubjectRequest) (*subjectv1.GetSubjectResponse, error) {
+	id, err := platformgrpc.ParseUUID("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	sub, err := s.subjects.FindByID(ctx, id)
+	if err != nil {
+		return nil, platformgrpc.Error(err)
+	}
+	return &subjectv1.GetSubjectResponse{Subject: subjectToProto(sub)}, nil
+}
+
+func (s *SubjectGRPCServer) ListSubjects(ctx context.Context, req *subjectv1.ListSubjectsRequest) (*subjectv1.ListSubjectsResponse, error) {
+	categoryID, err := platformgrpc.ParseOptionalUUID("category_id", req.GetCategoryId())
+	if err != nil {
+		return nil, err
+	}
+	spec := listquery.Spec{Offset: int(req.GetOffset()), Limit: platformgrpc.PageLimit(req.GetLimit())}
+	if categoryID != nil {
+		spec = spec.Where("category_id", categoryID.String())
+	}
+	page, err := s.subjects.List(ctx, spec)
+	if err != nil {
+		return nil, platformgrpc.Error(err)
+	}
+
+	resp := &subjectv1.ListSubjectsResponse{Subjects: make([]*subjectv1.Subject, 0, len(page.Items)), Total: int32(page.Total)}
+	for _, sub := range page.Items {
+		resp.Subjects = append(resp.Subjects, subjectToProto(sub))
+	}
+	return resp, nil
+}
+
+func (s *SubjectGRPCServer) GetPrerequisites(ctx context.Context, req *subjectv1.GetPrerequisitesRequest) (*subjectv1.GetPrerequisitesResponse, error) {
+	id, err := platformgrpc.ParseUUID("subject_id", req.GetSubjectId())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.subjects.FindByID(ctx, id); err != nil {
+		return nil, platformgrpc.Error(err)
+	}
+	edges, err := s.prereqs.GetEdges(ctx, id)
+	if err != nil {
+		return nil, platformgrpc.Error(err)
+	}
+	version, err := s.prereqs.GetVersion(ctx, id)
+	if err != nil {
+		return nil, platformgrpc.Error(err)
+	}
+
+	resp := &subjectv1.GetPrerequisitesResponse{SubjectId: id.String(), PrerequisiteIds: make([]string, 0, len(edges)), Version: int32(version)}
+	for _, e := range edges {
+		resp.PrerequisiteIds = append(resp.PrerequisiteIds, e.PrerequisiteID.String())
+	}
+	return resp, nil
+}
+
+func (s *SubjectGRPCServer) GetPrerequisiteGraph(ctx context.Context, _ *subjectv1.GetPrerequisiteGraphRequest) (*subjectv1.GetPrerequisiteGraphResponse, error) {
+	edges, err := s.prereqs.GetAllEdges(ctx)
+	if err != nil {
+		return nil, platformgrpc.Error(err)
+	}
+	resp := &subjectv1.GetPrerequisiteGraphResponse{Edges: make([]*subjectv1.PrerequisiteEdge, 0, len(edges))}
+	for _, e := range edges {
+		resp.Edges = append(resp.Edges, &subjectv1.PrerequisiteEdge{SubjectId: e.SubjectID.String(), PrerequisiteId: e.PrerequisiteID.String()})
+	}
+	return resp, nil
+}
+
+func subjectToProto(s *domain.Subject) *subjectv1.Subject {
+	return &subjectv1.Subject{
+		Id:           s.ID.String(),
+		Name:         s.Name,
+		Code:         s.Code,
+		Description:  s.Description,
+		CategoryId:   platformgrpc.UUIDString(s.CategoryID),
+		Credits:      int32(s.Credits),
+		HoursPerWeek: int32(s.HoursPerWeek),
+		IsActive:     s.IsActive,
+		CreatedAt:    timestamppb.New(s.CreatedAt),
+		UpdatedAt:    timestamppb.New(s.UpdatedAt),
+	}
+}
+
+var _ subjectv1.SubjectServiceServer = (*SubjectGRPCServer)(nil)
@@ -1,12 +1,13 @@
 package delivery
 
 import (
-	"encoding/json"
 	"errors"
 	"net/http"
 
 	"github.com/google/uuid"
 
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/problem"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/validate"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/subject/domain"
 	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
 )
@@ -23,7 +24,8 @@ func NewPrerequisiteHandler(prereqRepo domain.PrerequisiteRepository, subjectRep
 	return &PrerequisiteHandler{prereqRepo: prereqRepo, subjectRepo: subjectRepo}
 }
 
-type addPrerequisiteRequest struct {
+// AddPrerequisiteRequest is the body of POST /api/v1/subjects/{id}/prerequisites.
+type AddPrerequisiteRequest struct {
 	PrerequisiteID uuid.UUID `json:"prerequisite_id"`
 	// ExpectedVersion is the caller's known version for optimistic locking.
 	// If omitted the server will read the current version and use it
@@ -31,6 +33,29 @@ type addPrerequisiteRequest struct {
 	ExpectedVersion *int `json:"expected_version"`
 }
 
+func (req *AddPrerequisiteRequest) Validate(v *validate.Validator) {
+	v.Check(req.PrerequisiteID != uuid.Nil, "prerequisite_id", validate.Required, "is required")
+}
+
+// PrerequisiteEdge says that SubjectID requires PrerequisiteID.
+type PrerequisiteEdge struct {
+	SubjectID      uuid.UUID `json:"subject_id"`
+	PrerequisiteID uuid.UUID `json:"prerequisite_id"`
+}
+
+// PrerequisitesResponse lists a subject's direct prerequisites. Version is
+// the subject's prerequisite version, for expected_version.
+type PrerequisitesResponse struct {
+	Items   []PrerequisiteEdge `json:"items"`
+	Version int                `json:"version"`
+}
+
+// PrerequisiteChainResponse lists a subject's transitive prerequisites.
+type PrerequisiteChainResponse struct {
+	SubjectID uuid.UUID   `json:"subject_id"`
+	Chain     []uuid.UUID `json:"chain"`
+}
+
 // AddPrerequisite handles POST /api/v1/subjects/{id}/prerequisites
 // Cycle detection steps:
 //  1. Load all existing edges from DB
@@ -38,56 +63,51 @@ type addPrerequisiteRequest struct {
 //  3. Check if new edge creates a cycle
 //  4. If safe, persist with optimistic locking
 func (h *PrerequisiteHandler) AddPrerequisite(w http.ResponseWriter, r *http.Request) {
-	subjectID, err := uuid.Parse(r.PathValue("id"))
+	subjectID, err := validate.PathUUID(r, "id")
 	if err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid subject id"})
-		return
-	}
-
-	var req addPrerequisiteRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		problem.Write(w, r, err)
 		return
 	}
 
-	if req.PrerequisiteID == uuid.Nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "prerequisite_id is required"})
+	var req AddPrerequisiteRequest
+	if err := validate.DecodeJSON(r, &req); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
 	if subjectID == req.PrerequisiteID {
-		writeJSON(w, http.StatusConflict, map[string]string{"error": "a subject cannot be its own prerequisite"})
+		problem.Write(w, r, erptypes.Conflict("a subject cannot be its own prerequisite"))
 		return
 	}
 
 	// Verify both subjects exist.
 	if _, err := h.subjectRepo.FindByID(r.Context(), subjectID); err != nil {
 		if errors.Is(err, erptypes.ErrNotFound) {
-			writeJSON(w, http.StatusNotFound, map[string]string{"error": "subject not found"})
+			problem.Write(w, r, erptypes.NotFound("subject not found"))
 			return
 		}
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to verify subject"})
+		problem.Write(w, r, erptypes.Internal("failed to verify subject", err))
 		return
 	}
 	if _, err := h.subjectRepo.FindByID(r.Context(), req.PrerequisiteID); err != nil {
 		if errors.Is(err, erptypes.ErrNotFound) {
-			writeJSON(w, http.StatusNotFound, map[string]string{"error": "prerequisite subject not found"})
+			problem.Write(w, r, erptypes.NotFound("prerequisite subject not found"))
 			return
 		}
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to verify prerequisite subject"})
+		problem.Write(w, r, erptypes.Internal("failed to verify prerequisite subject", err))
 		return
 	}
 
 	// Load all edges and build in-memory graph for cycle detection.
 	allEdges, err := h.prereqRepo.GetAllEdges(r.Context())
 	if err != nil {
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to load prerequisite graph"})
+		problem.Write(w, r, erptypes.Internal("failed to load prerequisite graph", err))
 		return
 	}
 
 	graph := domain.NewGraph(allEdges)
 	if domain.HasCycle(graph, subjectID, req.PrerequisiteID) {
-		writeJSON(w, http.StatusConflict, map[string]string{"error": "adding this prerequisite would create a cycle"})
+		problem.Write(w, r, erptypes.Conflict("adding this prerequisite would create a cycle"))
 		return
 	}
 
@@ -98,37 +118,34 @@ func (h *PrerequisiteHandler) AddPrerequisite(w http.ResponseWriter, r *http.Req
 	} else {
 		expectedVersion, err = h.prereqRepo.GetVersion(r.Context(), subjectID)
 		if err != nil {
-			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get version"})
+			problem.Write(w, r, erptypes.Internal("failed to get version", err))
 			return
 		}
 	}
 
 	if err := h.prereqRepo.AddEdge(r.Context(), subjectID, req.PrerequisiteID, expectedVersion); err != nil {
 		if errors.Is(err, erptypes.ErrConflict) {
-			writeJSON(w, http.StatusConflict, map[string]string{"error": "concurrent modification detected, please retry"})
+			problem.Write(w, r, erptypes.Conflict("concurrent modification detected, please retry"))
 			return
 		}
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to add prerequisite"})
+		problem.Write(w, r, erptypes.Internal("failed to add prerequisite", err))
 		return
 	}
 
-	writeJSON(w, http.StatusCreated, map[string]any{
-		"subject_id":      subjectID,
-		"prerequisite_id": req.PrerequisiteID,
-	})
+	writeJSON(w, http.StatusCreated, PrerequisiteEdge{SubjectID: subjectID, PrerequisiteID: req.PrerequisiteID})
 }
 
 // RemovePrerequisite handles DELETE /api/v1/subjects/{id}/prerequisites/{prereqId}
 func (h *PrerequisiteHandler) RemovePrerequisite(w http.ResponseWriter, r *http.Request) {
-	subjectID, err := uuid.Parse(r.PathValue("id"))
+	subjectID, err := validate.PathUUID(r, "id")
 	if err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid subject id"})
+		problem.Write(w, r, err)
 		return
 	}
 
-	prereqID, err := uuid.Parse(r.PathValue("prereqId"))
+	prereqID, err := validate.PathUUID(r, "prereqId")
 	if err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid prerequisite id"})
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -136,27 +153,29 @@ func (h *PrerequisiteHandler) RemovePrerequisite(w http.ResponseWriter, r *http.
 	expectedVersion := 0
 	if v := r.URL.Query().Get("expected_version"); v != "" {
 		if _, err := parseIntParam(v, &expectedVersion); err != nil {
-			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid expected_version"})
+			problem.Write(w, r, erptypes.Validation(erptypes.FieldViolation{
+				Field: "expected_version", Code: validate.Invalid, Message: "must be an integer",
+			}))
 			return
 		}
 	} else {
 		expectedVersion, err = h.prereqRepo.GetVersion(r.Context(), subjectID)
 		if err != nil {
-			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get version"})
+			problem.Write(w, r, erptypes.Internal("failed to get version", err))
 			return
 		}
 	}
 
 	if err := h.prereqRepo.RemoveEdge(r.Context(), subjectID, prereqID, expectedVersion); err != nil {
 		if errors.Is(err, erptypes.ErrNotFound) {
-			writeJSON(w, http.StatusNotFound, map[string]string{"error": "prerequisite edge not found"})
+			problem.Write(w, r, erptypes.NotFound("prerequisite edge not found"))
 			return
 		}
 		if errors.Is(err, erptypes.ErrConflict) {
-			writeJSON(w, http.StatusConflict, map[string]string{"error": "concurrent modification detected, please retry"})
+			problem.Write(w, r, erptypes.Conflict("concurrent modification detected, please retry"))
 			return
 		}
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to remove prerequisite"})
+		problem.Write(w, r, erptypes.Internal("failed to remove prerequisite", err))
 		return
 	}
 
@@ -165,24 +184,21 @@ func (h *PrerequisiteHandler) RemovePrerequisite(w http.ResponseWriter, r *http.
 
 // ListPrerequisites handles GET /api/v1/subjects/{id}/prerequisites
 func (h *PrerequisiteHandler) ListPrerequisites(w http.ResponseWriter, r *http.Request) {
-	subjectID, err := uuid.Parse(r.PathValue("id"))
+	subjectID, err := validate.PathUUID(r, "id")
 	if err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid subject id"})
+		problem.Write(w, r, err)
 		return
 	}
 
 	edges, err := h.prereqRepo.GetEdges(r.Context(), subjectID)
 	if err != nil {
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to list prerequisites"})
+		problem.Write(w, r, erptypes.Internal("failed to list prerequisites", err))
 		return
 	}
 
-	items := make([]map[string]any, len(edges))
+	items := make([]PrerequisiteEdge, len(edges))
 	for i, e := range edges {
-		items[i] = map[string]any{
-			"subject_id":      e.SubjectID,
-			"prerequisite_id": e.PrerequisiteID,
-		}
+		items[i] = PrerequisiteEdge{SubjectID: e.SubjectID, PrerequisiteID: e.PrerequisiteID}
 	}
 
 	version := 0
@@ -190,24 +206,21 @@ func (h *PrerequisiteHandler) ListPrerequisites(w http.ResponseWriter, r *http.R
 		version = edges[0].Version
 	}
 
-	writeJSON(w, http.StatusOK, map[string]any{
-		"items":   items,
-		"version": version,
-	})
+	writeJSON(w, http.StatusOK, PrerequisitesResponse{Items: items, Version: version})
 }
 
 // GetPrerequisiteChain handles GET /api/v1/subjects/{id}/prerequisite-chain
 // Returns all transitive prerequisites (full DAG reachability).
 func (h *PrerequisiteHandler) GetPrerequisiteChain(w http.ResponseWriter, r *http.Request) {
-	subjectID, err := uuid.Parse(r.PathValue("id"))
+	subjectID, err := validate.PathUUID(r, "id")
 	if err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid subject id"})
+		problem.Write(w, r, err)
 		return
 	}
 
 	allEdges, err := h.prereqRepo.GetAllEdges(r.Context())
 	if err != nil {
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to load prerequisite graph"})
+		problem.Write(w, r, erptypes.Internal("failed to load prerequisite graph", err))
 		return
 	}
 
@@ -217,10 +230,7 @@ func (h *PrerequisiteHandler) GetPrerequisiteChain(w http.ResponseWriter, r *htt
 	ids := make([]uuid.UUID, len(chain))
 	copy(ids, chain)
 
-	writeJSON(w, http.StatusOK, map[string]any{
-		"subject_id": subjectID,
-		"chain":      ids,
-	})
+	writeJSON(w, http.StatusOK, PrerequisiteChainResponse{SubjectID: subjectID, Chain: ids})
 }
 
 // parseIntParam parses a string into an int pointer target. Returns error on failure.
@@ -1,14 +1,17 @@
 package delivery
 
 import (
-	"encoding/json"
 	"errors"
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/etag"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/history"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/problem"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/validate"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/subject/domain"
 	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
 )
@@ -23,7 +26,8 @@ func NewSubjectHandler(subjectRepo domain.SubjectRepository) *SubjectHandler {
 	return &SubjectHandler{subjectRepo: subjectRepo}
 }
 
-type createSubjectRequest struct {
+// CreateSubjectRequest is the body of POST /api/v1/subjects.
+type CreateSubjectRequest struct {
 	Name         string     `json:"name"`
 	Code         string     `json:"code"`
 	Description  string     `json:"description"`
@@ -32,26 +36,24 @@ type createSubjectRequest struct {
 	HoursPerWeek int        `json:"hours_per_week"`
 }
 
-type updateSubjectRequest struct {
-	Name         string     `json:"name"`
-	Code         string     `json:"code"`
-	Description  string     `json:"description"`
-	CategoryID   *uuid.UUID `json:"category_id"`
-	Credits      int        `json:"credits"`
-	HoursPerWeek int        `json:"hours_per_week"`
-	IsActive     bool       `json:"is_active"`
+func (req *CreateSubjectRequest) Validate(v *validate.Validator) {
+	v.Required("name", req.Name)
+	v.Required("code", req.Code)
+	v.Min("credits", req.Credits, 0)
+	v.Min("hours_per_week", req.HoursPerWeek, 0)
+}
+
+// UpdateSubjectRequest is the body of PUT /api/v1/subjects/{id}.
+type UpdateSubjectRequest struct {
+	CreateSubjectRequest
+	IsActive bool `json:"is_active"`
 }
 
 // CreateSubject handles POST /api/v1/subjects
 func (h *SubjectHandler) CreateSubject(w http.ResponseWriter, r *http.Request) {
-	var req createSubjectRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
-		return
-	}
-
-	if req.Name == "" || req.Code == "" {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "name and code are required"})
+	var req CreateSubjectRequest
+	if err := validate.DecodeJSON(r, &req); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -70,93 +72,110 @@ func (h *SubjectHandler) CreateSubject(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.subjectRepo.Save(r.Context(), s); err != nil {
-		writeJSON(w, http.StatusConflict, map[string]string{"error": "subject code already exists or save failed"})
+		problem.Write(w, r, erptypes.Conflict("subject code already exists or save failed"))
 		return
 	}
 
-	writeJSON(w, http.StatusCreated, subjectResponse(s))
+	etag.Set(w, s.Version)
+	writeJSON(w, http.StatusCreated, toSubjectResponse(s))
 }
 
-// ListSubjects handles GET /api/v1/subjects
-// Optional query params: offset, limit, category_id
+// ListSubjects handles GET /api/v1/subjects (shared list query parameters).
 func (h *SubjectHandler) ListSubjects(w http.ResponseWriter, r *http.Request) {
-	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
-	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-	if limit <= 0 || limit > 100 {
-		limit = 20
-	}
-
-	categoryIDStr := r.URL.Query().Get("category_id")
-	if categoryIDStr != "" {
-		catID, err := uuid.Parse(categoryIDStr)
-		if err != nil {
-			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid category_id"})
-			return
-		}
-		subjects, total, err := h.subjectRepo.ListByCategory(r.Context(), catID, offset, limit)
-		if err != nil {
-			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to list subjects"})
-			return
-		}
-		writeJSON(w, http.StatusOK, map[string]any{"items": subjectList(subjects), "total": total})
+	spec, err := listquery.Parse(r.URL.Query(), domain.SubjectListSchema)
+	if err != nil {
+		problem.Write(w, r, validate.Query(err))
 		return
 	}
 
-	subjects, total, err := h.subjectRepo.List(r.Context(), offset, limit)
+	page, err := h.subjectRepo.List(r.Context(), spec)
 	if err != nil {
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to list subjects"})
+		problem.Write(w, r, erptypes.Internal("failed to list subjects", err))
 		return
 	}
-	writeJSON(w, http.StatusOK, map[string]any{"items": subjectList(subjects), "total": total})
+	writeJSON(w, http.StatusOK, listquery.Map(page, toSubjectResponse))
 }
 
-// GetSubject handles GET /api/v1/subjects/{id}
+// GetSubject handles GET /api/v1/subjects/{id}[?as_of=<RFC 3339>]
 func (h *SubjectHandler) GetSubject(w http.ResponseWriter, r *http.Request) {
-	id, err := uuid.Parse(r.PathValue("id"))
+	id, err := validate.PathUUID(r, "id")
 	if err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid subject id"})
+		problem.Write(w, r, err)
+		return
+	}
+	asOf, err := history.ParseAsOf(r.URL.Query())
+	if err != nil {
+		problem.Write(w, r, validate.Query(err))
 		return
 	}
 
-	s, err := h.subjectRepo.FindByID(r.Context(), id)
+	var s *domain.Subject
+	if asOf != nil {
+		s, err = h.subjectRepo.FindByIDAsOf(r.Context(), id, *asOf)
+	} else {
+		s, err = h.subjectRepo.FindByID(r.Context(), id)
+	}
 	if err != nil {
 		if errors.Is(err, erptypes.ErrNotFound) {
-			writeJSON(w, http.StatusNotFound, map[string]string{"error": "subject not found"})
+			problem.Write(w, r, erptypes.NotFound("subject not found"))
 			return
 		}
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get subject"})
+		problem.Write(w, r, erptypes.Internal("failed to get subject", err))
 		return
 	}
 
-	writeJSON(w, http.StatusOK, subjectResponse(s))
+	if asOf == nil {
+		etag.Set(w, s.Version)
+	}
+	writeJSON(w, http.StatusOK, toSubjectResponse(s))
 }
 
-// UpdateSubject handles PUT /api/v1/subjects/{id}
-func (h *SubjectHandler) UpdateSubject(w http.ResponseWriter, r *http.Request) {
-	id, err := uuid.Parse(r.PathValue("id"))
+// SubjectHistory handles GET /api/v1/subjects/{id}/history
+func (h *SubjectHandler) SubjectHistory(w http.ResponseWriter, r *http.Request) {
+	id, err := validate.PathUUID(r, "id")
 	if err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid subject id"})
+		problem.Write(w, r, err)
 		return
 	}
 
-	var req updateSubjectRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	versions, err := h.subjectRepo.History(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, erptypes.ErrNotFound) {
+			problem.Write(w, r, erptypes.NotFound("subject not found"))
+			return
+		}
+		problem.Write(w, r, erptypes.Internal("failed to get subject history", err))
 		return
 	}
 
-	if req.Name == "" || req.Code == "" {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "name and code are required"})
+	writeJSON(w, http.StatusOK, history.List[SubjectResponse]{Items: history.Map(versions, toSubjectResponse)})
+}
+
+// UpdateSubject handles PUT /api/v1/subjects/{id}
+func (h *SubjectHandler) UpdateSubject(w http.ResponseWriter, r *http.Request) {
+	id, err := validate.PathUUID(r, "id")
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	var req UpdateSubjectRequest
+	if err := validate.DecodeJSON(r, &req); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
 	s, err := h.subjectRepo.FindByID(r.Context(), id)
 	if err != nil {
 		if errors.Is(err, erptypes.ErrNotFound) {
-			writeJSON(w, http.StatusNotFound, map[string]string{"error": "subject not found"})
+			problem.Write(w, r, erptypes.NotFound("subject not found"))
 			return
 		}
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get subject"})
+		problem.Write(w, r, erptypes.Internal("failed to get subject", err))
+		return
+	}
+	if !etag.Match(r, s.Version) {
+		problem.Write(w, r, erptypes.PreconditionFailed("subject was modified; reload and retry"))
 		return
 	}
 
@@ -169,33 +188,46 @@ func (h *SubjectHandler) UpdateSubject(w http.ResponseWriter, r *http.Request) {
 	s.IsActive = req.IsActive
 
 	if err := h.subjectRepo.Update(r.Context(), s); err != nil {
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to update subject"})
+		switch {
+		case errors.Is(err, erptypes.ErrNotFound):
+			problem.Write(w, r, erptypes.NotFound("subject not found"))
+		case errors.Is(err, erptypes.ErrConflict):
+			problem.Write(w, r, etag.Conflict(r, "subject was modified; reload and retry"))
+		default:
+			problem.Write(w, r, erptypes.Internal("failed to update subject", err))
+		}
 		return
 	}
 
-	writeJSON(w, http.StatusOK, subjectResponse(s))
+	etag.Set(w, s.Version)
+	writeJSON(w, http.StatusOK, toSubjectResponse(s))
 }
 
-// subjectResponse converts a Subject to a map for JSON serialization.
-func subjectResponse(s *domain.Subject) map[string]any {
-	return map[string]any{
-		"id":             s.ID,
-		"name":           s.Name,
-		"code":           s.Code,
-		"description":    s.Description,
-		"category_id":    s.CategoryID,
-		"credits":        s.Credits,
-		"hours_per_week": s.HoursPerWeek,
-		"is_active":      s.IsActive,
-		"created_at":     s.CreatedAt,
-		"updated_at":     s.UpdatedAt,
-	}
+// SubjectResponse is the JSON representation of a Subject.
+type SubjectResponse struct {
+	ID           uuid.UUID  `json:"id"`
+	Name         string     `json:"name"`
+	Code         string     `json:"code"`
+	Description  string     `json:"description"`
+	CategoryID   *uuid.UUID `json:"category_id"`
+	Credits      int        `json:"credits"`
+	HoursPerWeek int        `json:"hours_per_week"`
+	IsActive     bool       `json:"is_active"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
 }
 
-func subjectList(subjects []*domain.Subject) []map[string]any {
-	items := make([]map[string]any, len(subjects))
-	for i, s := range subjects {
-		items[i] = subjectResponse(s)
+func toSubjectResponse(s *domain.Subject) SubjectResponse {
+	return SubjectResponse{
+		ID:           s.ID,
+		Name:         s.Name,
+		Code:         s.Code,
+		Description:  s.Description,
+		CategoryID:   s.CategoryID,
+		Credits:      s.Credits,
+		HoursPerWeek: s.HoursPerWeek,
+		IsActive:     s.IsActive,
+		CreatedAt:    s.CreatedAt,
+		UpdatedAt:    s.UpdatedAt,
 	}
-	return items
 }
@@ -6,6 +6,13 @@ import (
 	"github.com/google/uuid"
 )
 
+// Event topics published through the transactional outbox.
+const (
+	TopicSubjectCreated      = "subject.subject.created"
+	TopicPrerequisiteAdded   = "subject.prerequisite.added"
+	TopicPrerequisiteRemoved = "subject.prerequisite.removed"
+)
+
 // SubjectCreated is published when a new subject is successfully persisted.
 type SubjectCreated struct {
 	SubjectID  uuid.UUID
@@ -2,25 +2,62 @@ package domain
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/history"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
 )
 
+// SubjectListSchema declares how subjects can be filtered and sorted.
+var SubjectListSchema = &listquery.Schema{
+	Fields: map[string]listquery.Field{
+		"id":             {Kind: listquery.UUID, Filter: listquery.Eq | listquery.In, Sort: true},
+		"name":           {Kind: listquery.Text, Filter: listquery.Eq | listquery.Contains, Sort: true},
+		"code":           {Kind: listquery.Text, Filter: listquery.Eq | listquery.In | listquery.Contains, Sort: true},
+		"category_id":    {Kind: listquery.UUID, Filter: listquery.Eq | listquery.In},
+		"credits":        {Kind: listquery.Int, Filter: listquery.Eq | listquery.Range, Sort: true},
+		"hours_per_week": {Kind: listquery.Int, Filter: listquery.Eq | listquery.Range, Sort: true},
+		"is_active":      {Kind: listquery.Bool, Filter: listquery.Eq},
+		"created_at":     {Kind: listquery.Time, Filter: listquery.Range, Sort: true},
+		"updated_at":     {Kind: listquery.Time, Filter: listquery.Range, Sort: true},
+	},
+	Key:         "id",
+	DefaultSort: []listquery.Sort{{Field: "created_at", Desc: true}},
+}
+
+// CategoryListSchema declares how subject categories can be filtered and sorted.
+var CategoryListSchema = &listquery.Schema{
+	Fields: map[string]listquery.Field{
+		"id":         {Kind: listquery.UUID, Filter: listquery.Eq | listquery.In, Sort: true},
+		"name":       {Kind: listquery.Text, Filter: listquery.Eq | listquery.Contains, Sort: true},
+		"created_at": {Kind: listquery.Time, Filter: listquery.Range, Sort: true},
+	},
+	Key:         "id",
+	DefaultSort: []listquery.Sort{{Field: "name"}},
+}
+
 // SubjectRepository defines persistence operations for Subject entities.
 type SubjectRepository interface {
 	Save(ctx context.Context, subject *Subject) error
 	FindByID(ctx context.Context, id uuid.UUID) (*Subject, error)
+	// FindByIDAsOf returns the subject as it was at the given time.
+	FindByIDAsOf(ctx context.Context, id uuid.UUID, at time.Time) (*Subject, error)
+	// History returns every version of the subject, oldest first.
+	History(ctx context.Context, id uuid.UUID) ([]history.Version[*Subject], error)
 	FindByCode(ctx context.Context, code string) (*Subject, error)
+	// Update stores the subject if it is still at subject.Version and bumps
+	// the version; otherwise it returns erptypes.ErrConflict.
 	Update(ctx context.Context, subject *Subject) error
-	List(ctx context.Context, offset, limit int) ([]*Subject, int, error)
-	ListByCategory(ctx context.Context, categoryID uuid.UUID, offset, limit int) ([]*Subject, int, error)
+	List(ctx context.Context, spec listquery.Spec) (listquery.Page[*Subject], error)
 }
 
 // CategoryRepository defines persistence operations for Category entities.
 type CategoryRepository interface {
 	Save(ctx context.Context, category *Category) error
 	FindByID(ctx context.Context, id uuid.UUID) (*Category, error)
-	List(ctx context.Context) ([]*Category, error)
+	List(ctx context.Context, spec listquery.Spec) (listquery.Page[*Category], error)
 }
 
 // PrerequisiteRepository manages the prerequisite edge set with optimistic locking.
@@ -16,6 +16,7 @@ type Subject struct {
 	Credits      int
 	HoursPerWeek int
 	IsActive     bool
+	Version      int // optimistic lock, bumped by every update
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
 }
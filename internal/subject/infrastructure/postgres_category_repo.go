@@ -2,6 +2,7 @@ package infrastructure
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/google/uuid"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/subject/domain"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/database"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
 	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
 )
@@ -60,7 +62,7 @@ func (r *PostgresCategoryRepo) FindByID(ctx context.Context, id uuid.UUID) (*dom
 		).Scan(&c.ID, &c.Name, &c.Description, &c.CreatedAt)
 	})
 	if err != nil {
-		if err == pgx.ErrNoRows {
+		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, erptypes.ErrNotFound
 		}
 		return nil, fmt.Errorf("find category by id: %w", err)
@@ -68,38 +70,30 @@ func (r *PostgresCategoryRepo) FindByID(ctx context.Context, id uuid.UUID) (*dom
 	return &c, nil
 }
 
-// List returns all categories ordered by name.
-func (r *PostgresCategoryRepo) List(ctx context.Context) ([]*domain.Category, error) {
+// List returns one page of categories matching spec.
+func (r *PostgresCategoryRepo) List(ctx context.Context, spec listquery.Spec) (listquery.Page[*domain.Category], error) {
 	schema, err := r.schema(ctx)
 	if err != nil {
-		return nil, err
+		return listquery.Page[*domain.Category]{}, err
+	}
+	q, err := listquery.Build(spec, domain.CategoryListSchema)
+	if err != nil {
+		return listquery.Page[*domain.Category]{}, err
 	}
 
-	var categories []*domain.Category
-
+	var page listquery.Page[*domain.Category]
 	err = database.WithTenantTx(ctx, r.pool, schema, func(tx pgx.Tx) error {
-		rows, err := tx.Query(ctx,
-			`SELECT id, name, description, created_at FROM subject_categories ORDER BY name ASC`,
-		)
-		if err != nil {
-			return err
-		}
-		defer rows.Close()
-
-		for rows.Next() {
-			var c domain.Category
-			if err := rows.Scan(&c.ID, &c.Name, &c.Description, &c.CreatedAt); err != nil {
-				return err
-			}
-			categories = append(categories, &c)
-		}
-		return rows.Err()
+		page, err = listquery.Fetch(ctx, tx, q, "id, name, description, created_at", "subject_categories",
+			func(rows pgx.Rows, cursor *string) (*domain.Category, error) {
+				var c domain.Category
+				return &c, rows.Scan(&c.ID, &c.Name, &c.Description, &c.CreatedAt, cursor)
+			})
+		return err
 	})
 	if err != nil {
-		return nil, fmt.Errorf("list categories: %w", err)
+		return listquery.Page[*domain.Category]{}, fmt.Errorf("list categories: %w", err)
 	}
-	return categories, nil
+	return page, nil
 }
 
-// Ensure interface compliance.
 var _ domain.CategoryRepository = (*PostgresCategoryRepo)(nil)
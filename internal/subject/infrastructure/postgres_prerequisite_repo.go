@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/subject/domain"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/database"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/outbox"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
 	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
 )
@@ -174,7 +176,14 @@ func (r *PostgresPrerequisiteRepo) AddEdge(ctx context.Context, subjectID, prere
 			 ON CONFLICT (subject_id) DO UPDATE SET version = subject_prerequisite_versions.version + 1`,
 			subjectID,
 		)
-		return err
+		if err != nil {
+			return err
+		}
+		return outbox.Write(ctx, tx, domain.TopicPrerequisiteAdded, domain.PrerequisiteAdded{
+			SubjectID:      subjectID,
+			PrerequisiteID: prerequisiteID,
+			OccurredAt:     time.Now(),
+		})
 	})
 }
 
@@ -221,7 +230,14 @@ func (r *PostgresPrerequisiteRepo) RemoveEdge(ctx context.Context, subjectID, pr
 			 ON CONFLICT (subject_id) DO UPDATE SET version = subject_prerequisite_versions.version + 1`,
 			subjectID,
 		)
-		return err
+		if err != nil {
+			return err
+		}
+		return outbox.Write(ctx, tx, domain.TopicPrerequisiteRemoved, domain.PrerequisiteRemoved{
+			SubjectID:      subjectID,
+			PrerequisiteID: prerequisiteID,
+			OccurredAt:     time.Now(),
+		})
 	})
 }
 
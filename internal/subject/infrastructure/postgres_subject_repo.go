@@ -2,7 +2,9 @@ package infrastructure
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -10,6 +12,9 @@ import (
 
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/subject/domain"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/database"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/history"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/outbox"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
 	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
 )
@@ -36,17 +41,35 @@ func (r *PostgresSubjectRepo) Save(ctx context.Context, s *domain.Subject) error
 	}
 
 	return database.WithTenantTx(ctx, r.pool, schema, func(tx pgx.Tx) error {
-		_, err := tx.Exec(ctx,
+		err := tx.QueryRow(ctx,
 			`INSERT INTO subjects (id, name, code, description, category_id, credits, hours_per_week, is_active, created_at, updated_at)
-			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			 RETURNING version`,
 			s.ID, s.Name, s.Code, s.Description, s.CategoryID, s.Credits, s.HoursPerWeek, s.IsActive, s.CreatedAt, s.UpdatedAt,
-		)
-		return err
+		).Scan(&s.Version)
+		if err != nil {
+			return err
+		}
+		return outbox.Write(ctx, tx, domain.TopicSubjectCreated, domain.SubjectCreated{
+			SubjectID:  s.ID,
+			Name:       s.Name,
+			Code:       s.Code,
+			OccurredAt: time.Now(),
+		})
 	})
 }
 
 // FindByID returns the subject with the given id or erptypes.ErrNotFound.
 func (r *PostgresSubjectRepo) FindByID(ctx context.Context, id uuid.UUID) (*domain.Subject, error) {
+	return r.findByID(ctx, "subjects", id)
+}
+
+// FindByIDAsOf returns the subject as it was at the given time.
+func (r *PostgresSubjectRepo) FindByIDAsOf(ctx context.Context, id uuid.UUID, at time.Time) (*domain.Subject, error) {
+	return r.findByID(ctx, history.AsOf("subjects", 2), id, at)
+}
+
+func (r *PostgresSubjectRepo) findByID(ctx context.Context, from string, args ...any) (*domain.Subject, error) {
 	schema, err := r.schema(ctx)
 	if err != nil {
 		return nil, err
@@ -55,14 +78,14 @@ func (r *PostgresSubjectRepo) FindByID(ctx context.Context, id uuid.UUID) (*doma
 	var s domain.Subject
 	err = database.WithTenantTx(ctx, r.pool, schema, func(tx pgx.Tx) error {
 		return tx.QueryRow(ctx,
-			`SELECT id, name, code, description, category_id, credits, hours_per_week, is_active, created_at, updated_at
-			 FROM subjects WHERE id = $1`,
-			id,
+			`SELECT id, name, code, description, category_id, credits, hours_per_week, is_active, version, created_at, updated_at
+			 FROM `+from+` WHERE id = $1`,
+			args...,
 		).Scan(&s.ID, &s.Name, &s.Code, &s.Description, &s.CategoryID,
-			&s.Credits, &s.HoursPerWeek, &s.IsActive, &s.CreatedAt, &s.UpdatedAt)
+			&s.Credits, &s.HoursPerWeek, &s.IsActive, &s.Version, &s.CreatedAt, &s.UpdatedAt)
 	})
 	if err != nil {
-		if err == pgx.ErrNoRows {
+		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, erptypes.ErrNotFound
 		}
 		return nil, fmt.Errorf("find subject by id: %w", err)
@@ -80,14 +103,14 @@ func (r *PostgresSubjectRepo) FindByCode(ctx context.Context, code string) (*dom
 	var s domain.Subject
 	err = database.WithTenantTx(ctx, r.pool, schema, func(tx pgx.Tx) error {
 		return tx.QueryRow(ctx,
-			`SELECT id, name, code, description, category_id, credits, hours_per_week, is_active, created_at, updated_at
+			`SELECT id, name, code, description, category_id, credits, hours_per_week, is_active, version, created_at, updated_at
 			 FROM subjects WHERE code = $1`,
 			code,
 		).Scan(&s.ID, &s.Name, &s.Code, &s.Description, &s.CategoryID,
-			&s.Credits, &s.HoursPerWeek, &s.IsActive, &s.CreatedAt, &s.UpdatedAt)
+			&s.Credits, &s.HoursPerWeek, &s.IsActive, &s.Version, &s.CreatedAt, &s.UpdatedAt)
 	})
 	if err != nil {
-		if err == pgx.ErrNoRows {
+		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, erptypes.ErrNotFound
 		}
 		return nil, fmt.Errorf("find subject by code: %w", err)
@@ -103,80 +126,65 @@ func (r *PostgresSubjectRepo) Update(ctx context.Context, s *domain.Subject) err
 	}
 
 	return database.WithTenantTx(ctx, r.pool, schema, func(tx pgx.Tx) error {
-		_, err := tx.Exec(ctx,
+		err := tx.QueryRow(ctx,
 			`UPDATE subjects
 			 SET name = $2, code = $3, description = $4, category_id = $5,
-			     credits = $6, hours_per_week = $7, is_active = $8, updated_at = now()
-			 WHERE id = $1`,
+			     credits = $6, hours_per_week = $7, is_active = $8,
+			     version = version + 1, updated_at = now()
+			 WHERE id = $1 AND version = $9
+			 RETURNING version, updated_at`,
 			s.ID, s.Name, s.Code, s.Description, s.CategoryID,
-			s.Credits, s.HoursPerWeek, s.IsActive,
-		)
+			s.Credits, s.HoursPerWeek, s.IsActive, s.Version,
+		).Scan(&s.Version, &s.UpdatedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return database.VersionMismatch(ctx, tx, "subjects", "version", s.ID, s.Version)
+		}
 		return err
 	})
 }
 
-// List returns a paginated slice of subjects and the total count.
-func (r *PostgresSubjectRepo) List(ctx context.Context, offset, limit int) ([]*domain.Subject, int, error) {
+// List returns one page of subjects matching spec.
+func (r *PostgresSubjectRepo) List(ctx context.Context, spec listquery.Spec) (listquery.Page[*domain.Subject], error) {
 	schema, err := r.schema(ctx)
 	if err != nil {
-		return nil, 0, err
+		return listquery.Page[*domain.Subject]{}, err
+	}
+	q, err := listquery.Build(spec, domain.SubjectListSchema)
+	if err != nil {
+		return listquery.Page[*domain.Subject]{}, err
 	}
 
-	var subjects []*domain.Subject
-	var total int
-
+	var page listquery.Page[*domain.Subject]
 	err = database.WithTenantTx(ctx, r.pool, schema, func(tx pgx.Tx) error {
-		if err := tx.QueryRow(ctx, "SELECT COUNT(*) FROM subjects").Scan(&total); err != nil {
-			return err
-		}
-
-		rows, err := tx.Query(ctx,
-			`SELECT id, name, code, description, category_id, credits, hours_per_week, is_active, created_at, updated_at
-			 FROM subjects ORDER BY created_at DESC LIMIT $1 OFFSET $2`,
-			limit, offset,
-		)
-		if err != nil {
-			return err
-		}
-		defer rows.Close()
-
-		for rows.Next() {
-			var s domain.Subject
-			if err := rows.Scan(&s.ID, &s.Name, &s.Code, &s.Description, &s.CategoryID,
-				&s.Credits, &s.HoursPerWeek, &s.IsActive, &s.CreatedAt, &s.UpdatedAt); err != nil {
-				return err
-			}
-			subjects = append(subjects, &s)
-		}
-		return rows.Err()
+		page, err = listquery.Fetch(ctx, tx, q,
+			"id, name, code, description, category_id, credits, hours_per_week, is_active, version, created_at, updated_at", "subjects",
+			func(rows pgx.Rows, cursor *string) (*domain.Subject, error) {
+				var s domain.Subject
+				return &s, rows.Scan(&s.ID, &s.Name, &s.Code, &s.Description, &s.CategoryID,
+					&s.Credits, &s.HoursPerWeek, &s.IsActive, &s.Version, &s.CreatedAt, &s.UpdatedAt, cursor)
+			})
+		return err
 	})
 	if err != nil {
-		return nil, 0, fmt.Errorf("list subjects: %w", err)
+		return listquery.Page[*domain.Subject]{}, fmt.Errorf("list subjects: %w", err)
 	}
-	return subjects, total, nil
+	return page, nil
 }
 
-// ListByCategory returns paginated subjects filtered by category.
-func (r *PostgresSubjectRepo) ListByCategory(ctx context.Context, categoryID uuid.UUID, offset, limit int) ([]*domain.Subject, int, error) {
+// History returns every version of the subject, oldest first, or
+// erptypes.ErrNotFound when it never existed.
+func (r *PostgresSubjectRepo) History(ctx context.Context, id uuid.UUID) ([]history.Version[*domain.Subject], error) {
 	schema, err := r.schema(ctx)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	var subjects []*domain.Subject
-	var total int
-
+	var versions []history.Version[*domain.Subject]
 	err = database.WithTenantTx(ctx, r.pool, schema, func(tx pgx.Tx) error {
-		if err := tx.QueryRow(ctx,
-			"SELECT COUNT(*) FROM subjects WHERE category_id = $1", categoryID,
-		).Scan(&total); err != nil {
-			return err
-		}
-
 		rows, err := tx.Query(ctx,
-			`SELECT id, name, code, description, category_id, credits, hours_per_week, is_active, created_at, updated_at
-			 FROM subjects WHERE category_id = $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3`,
-			categoryID, limit, offset,
+			`SELECT valid_from, valid_to, id, name, code, description, category_id, credits, hours_per_week, is_active, created_at, updated_at
+			 FROM `+history.Versions("subjects", 1)+` ORDER BY valid_from`,
+			id.String(),
 		)
 		if err != nil {
 			return err
@@ -185,18 +193,22 @@ func (r *PostgresSubjectRepo) ListByCategory(ctx context.Context, categoryID uui
 
 		for rows.Next() {
 			var s domain.Subject
-			if err := rows.Scan(&s.ID, &s.Name, &s.Code, &s.Description, &s.CategoryID,
+			v := history.Version[*domain.Subject]{Data: &s}
+			if err := rows.Scan(&v.ValidFrom, &v.ValidTo, &s.ID, &s.Name, &s.Code, &s.Description, &s.CategoryID,
 				&s.Credits, &s.HoursPerWeek, &s.IsActive, &s.CreatedAt, &s.UpdatedAt); err != nil {
 				return err
 			}
-			subjects = append(subjects, &s)
+			versions = append(versions, v)
 		}
 		return rows.Err()
 	})
 	if err != nil {
-		return nil, 0, fmt.Errorf("list subjects by category: %w", err)
+		return nil, fmt.Errorf("subject history: %w", err)
+	}
+	if len(versions) == 0 {
+		return nil, erptypes.ErrNotFound
 	}
-	return subjects, total, nil
+	return versions, nil
 }
 
 // Ensure interface compliance.
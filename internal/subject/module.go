@@ -5,14 +5,21 @@ import (
 	"net/http"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	"google.golang.org/grpc"
 
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/core/application/services"
 	coredomain "github.com/HuynhHoangPhuc/mcs-erp/internal/core/domain"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/core/delivery"
-	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/auth"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/database"
+	platformgrpc "github.com/HuynhHoangPhuc/mcs-erp/internal/platform/grpc"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/history"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/openapi"
 	subdelivery "github.com/HuynhHoangPhuc/mcs-erp/internal/subject/delivery"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/subject/domain"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/subject/infrastructure"
+	pkgmod "github.com/HuynhHoangPhuc/mcs-erp/pkg/module"
+	subjectv1 "github.com/HuynhHoangPhuc/mcs-erp/proto/gen/subject/v1"
 )
 
 // Module implements pkg/module.Module for the subject module.
@@ -22,6 +29,7 @@ type Module struct {
 	subjectRepo  domain.SubjectRepository
 	categoryRepo domain.CategoryRepository
 	prereqRepo   domain.PrerequisiteRepository
+	routes       []pkgmod.Route
 }
 
 // NewModule creates the subject module wired with concrete dependencies.
@@ -40,37 +48,84 @@ func (m *Module) SubjectRepo() domain.SubjectRepository { return m.subjectRepo }
 
 func (m *Module) Name() string          { return "subject" }
 func (m *Module) Dependencies() []string { return []string{"core"} }
-func (m *Module) Migrate(ctx context.Context) error { return nil }
+// Migrate applies the module's pending migrations to _template; tenants follow via database.TenantRunner.
+func (m *Module) Migrate(ctx context.Context) error {
+	return database.NewMigrator(m.pool).MigrateModule(ctx, m.Name())
+}
 func (m *Module) RegisterEvents(ctx context.Context) error { return nil }
 
+// RegisterGRPC exposes read access to subjects and prerequisites (PermSubjectRead).
+func (m *Module) RegisterGRPC(s grpc.ServiceRegistrar) map[string]string {
+	subjectv1.RegisterSubjectServiceServer(s, subdelivery.NewSubjectGRPCServer(m.subjectRepo, m.prereqRepo))
+	return platformgrpc.ServicePermissions(&subjectv1.SubjectService_ServiceDesc, coredomain.PermSubjectRead)
+}
+
+// Routes describes the routes mounted by RegisterRoutes.
+func (m *Module) Routes() []pkgmod.Route { return m.routes }
+
 // RegisterRoutes wires all subject, category, and prerequisite endpoints.
 func (m *Module) RegisterRoutes(mux *http.ServeMux) {
 	subjectHandler := subdelivery.NewSubjectHandler(m.subjectRepo)
 	categoryHandler := subdelivery.NewCategoryHandler(m.categoryRepo)
 	prereqHandler := subdelivery.NewPrerequisiteHandler(m.prereqRepo, m.subjectRepo)
 
-	authMw := delivery.AuthMiddleware(m.authSvc)
-	readPerm := auth.RequirePermission(coredomain.PermSubjectRead)
-	writePerm := auth.RequirePermission(coredomain.PermSubjectWrite)
+	api := openapi.NewRouter(mux, m.Name(), delivery.ModuleAuthMiddleware(m.authSvc, m.Name()))
+	readPerm, writePerm := coredomain.PermSubjectRead, coredomain.PermSubjectWrite
 
 	// Subject routes
-	mux.Handle("POST /api/v1/subjects", authMw(writePerm(http.HandlerFunc(subjectHandler.CreateSubject))))
-	mux.Handle("GET /api/v1/subjects", authMw(readPerm(http.HandlerFunc(subjectHandler.ListSubjects))))
-	mux.Handle("GET /api/v1/subjects/{id}", authMw(readPerm(http.HandlerFunc(subjectHandler.GetSubject))))
-	mux.Handle("PUT /api/v1/subjects/{id}", authMw(writePerm(http.HandlerFunc(subjectHandler.UpdateSubject))))
+	api.Handle(pkgmod.Route{
+		Method: "POST", Path: "/api/v1/subjects", Summary: "Create a subject", Permission: writePerm,
+		Request: subdelivery.CreateSubjectRequest{}, Response: subdelivery.SubjectResponse{}, Status: http.StatusCreated,
+	}, subjectHandler.CreateSubject)
+	api.Handle(pkgmod.Route{
+		Method: "GET", Path: "/api/v1/subjects", Summary: "List subjects", Permission: readPerm,
+		Query: openapi.ListParams(domain.SubjectListSchema), Response: listquery.Page[subdelivery.SubjectResponse]{},
+	}, subjectHandler.ListSubjects)
+	api.Handle(pkgmod.Route{
+		Method: "GET", Path: "/api/v1/subjects/{id}", Summary: "Get a subject", Permission: readPerm,
+		Query: []pkgmod.Param{openapi.AsOf}, Response: subdelivery.SubjectResponse{},
+	}, subjectHandler.GetSubject)
+	api.Handle(pkgmod.Route{
+		Method: "PUT", Path: "/api/v1/subjects/{id}", Summary: "Update a subject", Permission: writePerm,
+		Request: subdelivery.UpdateSubjectRequest{}, Response: subdelivery.SubjectResponse{},
+	}, subjectHandler.UpdateSubject)
+	api.Handle(pkgmod.Route{
+		Method: "GET", Path: "/api/v1/subjects/{id}/history", Summary: "List a subject's versions", Permission: readPerm,
+		Response: history.List[subdelivery.SubjectResponse]{},
+	}, subjectHandler.SubjectHistory)
 
 	// Category routes
-	mux.Handle("POST /api/v1/categories", authMw(writePerm(http.HandlerFunc(categoryHandler.CreateCategory))))
-	mux.Handle("GET /api/v1/categories", authMw(readPerm(http.HandlerFunc(categoryHandler.ListCategories))))
-	mux.Handle("GET /api/v1/categories/{id}", authMw(readPerm(http.HandlerFunc(categoryHandler.GetCategory))))
+	api.Handle(pkgmod.Route{
+		Method: "POST", Path: "/api/v1/categories", Summary: "Create a category", Permission: writePerm,
+		Request: subdelivery.CreateCategoryRequest{}, Response: subdelivery.CategoryResponse{}, Status: http.StatusCreated,
+	}, categoryHandler.CreateCategory)
+	api.Handle(pkgmod.Route{
+		Method: "GET", Path: "/api/v1/categories", Summary: "List categories", Permission: readPerm,
+		Query: openapi.ListParams(domain.CategoryListSchema), Response: listquery.Page[subdelivery.CategoryResponse]{},
+	}, categoryHandler.ListCategories)
+	api.Handle(pkgmod.Route{
+		Method: "GET", Path: "/api/v1/categories/{id}", Summary: "Get a category", Permission: readPerm,
+		Response: subdelivery.CategoryResponse{},
+	}, categoryHandler.GetCategory)
 
 	// Prerequisite routes
-	mux.Handle("POST /api/v1/subjects/{id}/prerequisites",
-		authMw(writePerm(http.HandlerFunc(prereqHandler.AddPrerequisite))))
-	mux.Handle("DELETE /api/v1/subjects/{id}/prerequisites/{prereqId}",
-		authMw(writePerm(http.HandlerFunc(prereqHandler.RemovePrerequisite))))
-	mux.Handle("GET /api/v1/subjects/{id}/prerequisites",
-		authMw(readPerm(http.HandlerFunc(prereqHandler.ListPrerequisites))))
-	mux.Handle("GET /api/v1/subjects/{id}/prerequisite-chain",
-		authMw(readPerm(http.HandlerFunc(prereqHandler.GetPrerequisiteChain))))
+	api.Handle(pkgmod.Route{
+		Method: "POST", Path: "/api/v1/subjects/{id}/prerequisites", Summary: "Add a prerequisite", Permission: writePerm,
+		Request: subdelivery.AddPrerequisiteRequest{}, Response: subdelivery.PrerequisiteEdge{}, Status: http.StatusCreated,
+	}, prereqHandler.AddPrerequisite)
+	api.Handle(pkgmod.Route{
+		Method: "DELETE", Path: "/api/v1/subjects/{id}/prerequisites/{prereqId}", Summary: "Remove a prerequisite", Permission: writePerm,
+		Query:    []pkgmod.Param{{Name: "expected_version", Type: "integer", Description: "Prerequisite version the caller read"}},
+		Response: map[string]string{},
+	}, prereqHandler.RemovePrerequisite)
+	api.Handle(pkgmod.Route{
+		Method: "GET", Path: "/api/v1/subjects/{id}/prerequisites", Summary: "List a subject's direct prerequisites", Permission: readPerm,
+		Response: subdelivery.PrerequisitesResponse{},
+	}, prereqHandler.ListPrerequisites)
+	api.Handle(pkgmod.Route{
+		Method: "GET", Path: "/api/v1/subjects/{id}/prerequisite-chain", Summary: "List a subject's transitive prerequisites", Permission: readPerm,
+		Response: subdelivery.PrerequisiteChainResponse{},
+	}, prereqHandler.GetPrerequisiteChain)
+
+	m.routes = api.Routes()
 }
@@ -84,7 +84,7 @@ func TestPrerequisiteCycleAndOptimisticLocking(t *testing.T) {
 	_ = getJSON(t, mustAuthReq(t, http.MethodPost, srv.URL+"/api/v1/subjects/"+b+"/prerequisites", token, schema, jsonBody(t, map[string]any{"prerequisite_id": c, "expected_version": 0})), http.StatusCreated)
 
 	cycleResp := getJSON(t, mustAuthReq(t, http.MethodPost, srv.URL+"/api/v1/subjects/"+c+"/prerequisites", token, schema, jsonBody(t, map[string]any{"prerequisite_id": a, "expected_version": 0})), http.StatusConflict)
-	if fmt.Sprintf("%v", cycleResp["error"]) == "" {
+	if cycleResp["code"] != "conflict" || cycleResp["detail"] == "" {
 		t.Fatalf("expected cycle detection error")
 	}
 
@@ -94,7 +94,7 @@ func TestPrerequisiteCycleAndOptimisticLocking(t *testing.T) {
 
 	_ = getJSON(t, mustAuthReq(t, http.MethodPost, srv.URL+"/api/v1/subjects/"+x+"/prerequisites", token, schema, jsonBody(t, map[string]any{"prerequisite_id": y, "expected_version": 0})), http.StatusCreated)
 	conflict := getJSON(t, mustAuthReq(t, http.MethodPost, srv.URL+"/api/v1/subjects/"+x+"/prerequisites", token, schema, jsonBody(t, map[string]any{"prerequisite_id": z, "expected_version": 0})), http.StatusConflict)
-	if fmt.Sprintf("%v", conflict["error"]) == "" {
+	if conflict["code"] != "conflict" {
 		t.Fatalf("expected optimistic locking conflict error")
 	}
 
@@ -54,7 +54,7 @@ func TestSubjectAndCategoryCRUD(t *testing.T) {
 	}
 
 	dupResp := getJSON(t, mustAuthReq(t, http.MethodPost, srv.URL+"/api/v1/subjects", token, schema, jsonBody(t, subjPayload)), http.StatusConflict)
-	if fmt.Sprintf("%v", dupResp["error"]) == "" {
+	if dupResp["code"] != "conflict" {
 		t.Fatalf("expected duplicate subject code conflict error message")
 	}
 
@@ -0,0 +1,51 @@
+package testutil
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+
+	platformgrpc "github.com/HuynhHoangPhuc/mcs-erp/internal/platform/grpc"
+	platformmod "github.com/HuynhHoangPhuc/mcs-erp/internal/platform/module"
+)
+
+// TestGRPCClient serves every module's gRPC services over an in-memory
+// listener and returns a connected client. Schemas are expected to be
+// migrated already (e.g. by TestServer).
+func TestGRPCClient(t *testing.T, pool *pgxpool.Pool) *grpc.ClientConn {
+	t.Helper()
+
+	registry, coreMod, _ := testRegistry(t, pool)
+	perms := platformgrpc.MethodPermissions{}
+	srv := platformgrpc.NewServer(coreMod.AuthService(), perms)
+	if err := platformmod.RegisterGRPC(registry, srv, perms); err != nil {
+		t.Fatalf("register grpc services: %v", err)
+	}
+
+	lis := bufconn.Listen(1 << 20)
+	go func() { _ = srv.Serve(lis) }()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dial grpc: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = conn.Close()
+		srv.Stop()
+	})
+	return conn
+}
+
+// GRPCContext attaches bearer token and tenant metadata to ctx.
+func GRPCContext(ctx context.Context, token, tenantID string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token, "x-tenant-id", tenantID)
+}
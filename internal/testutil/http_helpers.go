@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -18,46 +19,35 @@ import (
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/core"
 	coredelivery "github.com/HuynhHoangPhuc/mcs-erp/internal/core/delivery"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/hr"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/database"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/health"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/idempotency"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/jobs"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/metrics"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/middleware"
 	platformmod "github.com/HuynhHoangPhuc/mcs-erp/internal/platform/module"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/openapi"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/requestinfo"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tracing"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/room"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/subject"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/timetable"
+	"github.com/HuynhHoangPhuc/mcs-erp/migrations"
 	pkgmod "github.com/HuynhHoangPhuc/mcs-erp/pkg/module"
 )
 
+// TestPlatformToken is the platform operator token accepted by TestServer.
+const TestPlatformToken = "test-platform-token"
+
+// TestCORSOrigins are the browser origins TestServer allows.
+var TestCORSOrigins = []string{"http://localhost:5173", "http://{tenant}.app.test"}
+
 // TestServer creates an HTTP server wired with all modules against the test DB.
 func TestServer(t *testing.T, pool *pgxpool.Pool) *httptest.Server {
 	t.Helper()
 
-	registry := platformmod.NewRegistry()
-	coreMod := core.NewModuleWithDeps(pool, TestJWTService())
-	mustRegister(t, registry, coreMod)
-
-	hrMod := hr.NewModule(pool, coreMod.AuthService())
-	mustRegister(t, registry, hrMod)
-
-	subjectMod := subject.NewModule(pool, coreMod.AuthService())
-	mustRegister(t, registry, subjectMod)
-
-	roomMod := room.NewModule(pool, coreMod.AuthService())
-	mustRegister(t, registry, roomMod)
-
-	timetableMod := timetable.NewModuleWithRepos(
-		pool,
-		coreMod.AuthService(),
-		hrMod.TeacherRepo(),
-		hrMod.AvailabilityRepo(),
-		subjectMod.SubjectRepo(),
-		roomMod.RoomRepo(),
-		roomMod.RoomAvailabilityRepo(),
-	)
-	mustRegister(t, registry, timetableMod)
-
-	toolRegistry := agentinfra.NewToolRegistry()
-	providerSvc := agentsvc.NewProviderServiceWithLLM(agentdomain.LLMConfig{}, &MockLLMProvider{Response: "mock-response"})
-	agentMod := agent.NewModuleWithProvider(pool, coreMod.AuthService(), toolRegistry, providerSvc, TestRedis(t))
-	mustRegister(t, registry, agentMod)
+	registry, coreMod, jobQueue := testRegistry(t, pool)
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
@@ -65,6 +55,15 @@ func TestServer(t *testing.T, pool *pgxpool.Pool) *httptest.Server {
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte(`{"status":"ok"}`))
 	})
+	mux.Handle("GET /metrics", metrics.Handler())
+
+	checker := health.NewChecker(2 * time.Second)
+	checker.Add("platform", pkgmod.HealthCheck{Name: "postgres", Critical: true, Check: pool.Ping})
+	if err := platformmod.RegisterHealthChecks(registry, checker); err != nil {
+		t.Fatalf("register health checks: %v", err)
+	}
+	mux.Handle("GET /readyz", checker.ReadyHandler())
+	mux.Handle("GET /healthz/details", coredelivery.PlatformAdminMiddleware(TestPlatformToken)(checker.DetailsHandler()))
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -72,8 +71,43 @@ func TestServer(t *testing.T, pool *pgxpool.Pool) *httptest.Server {
 	if err := platformmod.Bootstrap(ctx, registry, mux); err != nil {
 		t.Fatalf("bootstrap test modules: %v", err)
 	}
+	routes, err := platformmod.Routes(registry)
+	if err != nil {
+		t.Fatalf("collect module routes: %v", err)
+	}
+	apiDoc := openapi.NewDocument(openapi.Info{Title: "MCS ERP API", Version: "1"}, routes)
+	mux.Handle("GET "+openapi.Path, apiDoc.Handler(coreMod.RouteFilter))
+	if err := platformmod.Start(ctx, registry); err != nil {
+		t.Fatalf("start test modules: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := platformmod.Stop(context.Background(), registry); err != nil {
+			t.Errorf("stop test modules: %v", err)
+		}
+	})
+	if err := jobQueue.Start(ctx); err != nil {
+		t.Fatalf("start job queue: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := jobQueue.Stop(context.Background()); err != nil {
+			t.Errorf("stop job queue: %v", err)
+		}
+	})
 
-	handler := coredelivery.MaxBodySize(1 << 20)(tenant.Middleware(mux))
+	idem := idempotency.Middleware(idempotency.NewStore(pool, 24*time.Hour), coreMod.AuthService().ValidateToken)
+	handler := middleware.Chain(mux,
+		tracing.HTTPHandler,
+		requestinfo.Middleware(nil),
+		middleware.AccessLog(slog.Default()),
+		middleware.Recover,
+		middleware.CORS(middleware.CORSConfig{Origins: TestCORSOrigins, CheckTenant: coreMod.TenantDirectory().Check}),
+		middleware.Timeout(mux, 30*time.Second, routes),
+		coredelivery.MaxBodySize(1<<20),
+		tenant.MiddlewareWithDirectory(coreMod.TenantDirectory()),
+		idem,
+		metrics.HTTPMiddleware,
+		tracing.RouteMiddleware,
+	)
 	return httptest.NewServer(handler)
 }
 
@@ -106,6 +140,48 @@ func DoJSON[T any](t *testing.T, client *http.Client, req *http.Request) (T, int
 	return out, resp.StatusCode
 }
 
+// testRegistry registers every module against pool, as cmd/server does. The
+// job queue is not started.
+func testRegistry(t *testing.T, pool *pgxpool.Pool) (*platformmod.Registry, *core.Module, *jobs.Queue) {
+	t.Helper()
+
+	jobQueue := jobs.NewQueue(pool, jobs.Config{PollInterval: 50 * time.Millisecond})
+	registry := platformmod.NewRegistry()
+	coreMod := core.NewModuleWithDeps(pool, TestJWTService())
+	coreMod.SetPlatformAdminToken(TestPlatformToken)
+	coreMod.SetMigrationRunner(database.NewTenantRunner(pool, migrations.TenantModules, 2))
+	coreMod.SetJobQueue(jobQueue)
+	mustRegister(t, registry, coreMod)
+
+	hrMod := hr.NewModule(pool, coreMod.AuthService())
+	mustRegister(t, registry, hrMod)
+
+	subjectMod := subject.NewModule(pool, coreMod.AuthService())
+	mustRegister(t, registry, subjectMod)
+
+	roomMod := room.NewModule(pool, coreMod.AuthService())
+	mustRegister(t, registry, roomMod)
+
+	timetableMod := timetable.NewModuleWithRepos(
+		pool,
+		coreMod.AuthService(),
+		hrMod.TeacherRepo(),
+		hrMod.AvailabilityRepo(),
+		subjectMod.SubjectRepo(),
+		roomMod.RoomRepo(),
+		roomMod.RoomAvailabilityRepo(),
+	)
+	timetableMod.SetJobQueue(jobQueue)
+	mustRegister(t, registry, timetableMod)
+
+	toolRegistry := agentinfra.NewToolRegistry()
+	providerSvc := agentsvc.NewProviderServiceWithLLM(agentdomain.LLMConfig{}, &MockLLMProvider{Response: "mock-response"})
+	agentMod := agent.NewModuleWithProvider(pool, coreMod.AuthService(), toolRegistry, providerSvc, TestRedis(t))
+	mustRegister(t, registry, agentMod)
+
+	return registry, coreMod, jobQueue
+}
+
 func mustRegister(t *testing.T, registry *platformmod.Registry, m pkgmod.Module) {
 	t.Helper()
 	if err := registry.Register(m); err != nil {
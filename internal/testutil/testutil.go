@@ -2,24 +2,19 @@ package testutil
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
-	"hash/fnv"
-	"io"
-	"log"
 	"net/url"
 	"os"
-	"path/filepath"
 	"strings"
-	"sync"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
-	"github.com/jackc/pgx/v5/stdlib"
-	"github.com/pressly/goose/v3"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/database"
+	"github.com/HuynhHoangPhuc/mcs-erp/migrations"
 )
 
 const (
@@ -27,22 +22,6 @@ const (
 	operationTimeout       = 20 * time.Second
 )
 
-var migrationDirs = []string{
-	"migrations",
-	"migrations/core",
-	"migrations/hr",
-	"migrations/subject",
-	"migrations/room",
-	"migrations/timetable",
-	"migrations/agent",
-}
-
-var (
-	gooseInitOnce sync.Once
-	gooseInitErr  error
-	gooseMu       sync.Mutex
-)
-
 // TestDB wraps a pgxpool.Pool connected to the integration test database.
 type TestDB struct {
 	Pool        *pgxpool.Pool
@@ -74,6 +53,7 @@ func NewTestDB(t *testing.T) *TestDB {
 	}
 	cfg.MinConns = 0
 	cfg.MaxConns = 10
+	cfg.ConnConfig.Tracer = database.NewQueryTracer()
 
 	pool, err := pgxpool.NewWithConfig(ctx, cfg)
 	if err != nil {
@@ -117,6 +97,16 @@ func (db *TestDB) CreateTenantSchema(t *testing.T) string {
 		t.Fatalf("apply migrations for schema %q: %v", schema, err)
 	}
 
+	// Register the tenant as active so the tenant directory lets requests through.
+	if _, err := db.Pool.Exec(ctx,
+		`INSERT INTO public.tenants (name, schema_name, status, is_active, created_at, updated_at)
+		 VALUES ($1, $2, 'active', true, now(), now())
+		 ON CONFLICT (schema_name) DO NOTHING`,
+		"Tenant "+schema, schema,
+	); err != nil {
+		t.Fatalf("register tenant %q: %v", schema, err)
+	}
+
 	return schema
 }
 
@@ -129,63 +119,11 @@ func (db *TestDB) Close(t *testing.T) {
 }
 
 func (db *TestDB) applyMigrations(ctx context.Context, schema string) error {
-	if err := initGoose(); err != nil {
-		return err
-	}
-
-	sqlDB, err := db.openSchemaSQLDB(schema)
-	if err != nil {
-		return err
-	}
-	defer sqlDB.Close()
-
-	root, err := projectRoot()
-	if err != nil {
-		return err
+	migrator := database.NewMigrator(db.Pool)
+	if err := migrator.MigratePublic(ctx); err != nil {
+		return fmt.Errorf("migrate public schema: %w", err)
 	}
-
-	gooseMu.Lock()
-	defer gooseMu.Unlock()
-
-	for _, rel := range migrationDirs {
-		sourceDir := filepath.Join(root, rel)
-		upOnlyDir, cleanup, err := prepareUpOnlyMigrationDir(sourceDir)
-		if err != nil {
-			return fmt.Errorf("prepare migrations %s: %w", rel, err)
-		}
-
-		goose.SetTableName(gooseTableName(schema, rel))
-		if err := goose.UpContext(ctx, sqlDB, upOnlyDir, goose.WithAllowMissing()); err != nil {
-			cleanup()
-			return fmt.Errorf("goose up %s: %w", rel, err)
-		}
-		cleanup()
-	}
-	return nil
-}
-
-func (db *TestDB) openSchemaSQLDB(schema string) (*sql.DB, error) {
-	cfg, err := pgx.ParseConfig(db.databaseURL)
-	if err != nil {
-		return nil, fmt.Errorf("parse pgx config: %w", err)
-	}
-	if cfg.RuntimeParams == nil {
-		cfg.RuntimeParams = map[string]string{}
-	}
-	cfg.RuntimeParams["search_path"] = schema + ",public"
-
-	sqlDB := stdlib.OpenDB(*cfg)
-	sqlDB.SetMaxOpenConns(1)
-	sqlDB.SetMaxIdleConns(1)
-	return sqlDB, nil
-}
-
-func initGoose() error {
-	gooseInitOnce.Do(func() {
-		goose.SetLogger(log.New(io.Discard, "", 0))
-		gooseInitErr = goose.SetDialect("postgres")
-	})
-	return gooseInitErr
+	return migrator.MigrateSchema(ctx, schema, migrations.TenantModules...)
 }
 
 func ensureDatabase(ctx context.Context, databaseURL string) error {
@@ -226,76 +164,3 @@ func ensureDatabase(ctx context.Context, databaseURL string) error {
 	}
 	return nil
 }
-
-func projectRoot() (string, error) {
-	wd, err := os.Getwd()
-	if err != nil {
-		return "", fmt.Errorf("get working directory: %w", err)
-	}
-
-	dir := wd
-	for {
-		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
-			return dir, nil
-		}
-		parent := filepath.Dir(dir)
-		if parent == dir {
-			break
-		}
-		dir = parent
-	}
-	return "", fmt.Errorf("go.mod not found from %s", wd)
-}
-
-func gooseTableName(schema, rel string) string {
-	h := fnv.New64a()
-	_, _ = h.Write([]byte(schema + "/" + rel))
-	return fmt.Sprintf("goose_%x", h.Sum64())
-}
-
-func prepareUpOnlyMigrationDir(sourceDir string) (string, func(), error) {
-	entries, err := os.ReadDir(sourceDir)
-	if err != nil {
-		return "", nil, err
-	}
-
-	tmpDir, err := os.MkdirTemp("", "mcs-erp-up-migrations-")
-	if err != nil {
-		return "", nil, err
-	}
-	cleanup := func() { _ = os.RemoveAll(tmpDir) }
-
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-		name := entry.Name()
-		if !strings.HasSuffix(name, ".up.sql") {
-			continue
-		}
-
-		srcPath := filepath.Join(sourceDir, name)
-		dstPath := filepath.Join(tmpDir, name)
-
-		content, err := os.ReadFile(srcPath)
-		if err != nil {
-			cleanup()
-			return "", nil, err
-		}
-		content = wrapGooseSQLMigration(content)
-		if err := os.WriteFile(dstPath, content, 0o644); err != nil {
-			cleanup()
-			return "", nil, err
-		}
-	}
-
-	return tmpDir, cleanup, nil
-}
-
-func wrapGooseSQLMigration(content []byte) []byte {
-	s := strings.TrimSpace(string(content))
-	if strings.Contains(s, "-- +goose Up") {
-		return []byte(s + "\n")
-	}
-	return []byte("-- +goose Up\n-- +goose StatementBegin\n" + s + "\n-- +goose StatementEnd\n")
-}
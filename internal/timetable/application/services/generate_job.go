@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/jobs"
+	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
+)
+
+// GenerateJobKind is the background job that runs Generate for a semester.
+const GenerateJobKind = "timetable.generate"
+
+// GenerateJob is the payload of a GenerateJobKind job.
+type GenerateJob struct {
+	SemesterID uuid.UUID `json:"semester_id"`
+}
+
+// GenerateJobResult summarises the schedule a GenerateJobKind job saved;
+// fetch the schedule itself from the semester.
+type GenerateJobResult struct {
+	SemesterID     uuid.UUID `json:"semester_id"`
+	Version        int       `json:"version"`
+	Assignments    int       `json:"assignments"`
+	HardViolations int       `json:"hard_violations"`
+	SoftPenalty    float64   `json:"soft_penalty"`
+}
+
+// JobDefinition runs Generate as a background job. A semester that is gone
+// or has no subjects fails the job at once, and so does failing to move the
+// semester to review once the schedule is saved: a retry would generate and
+// save another version. The next run of Generate moves such a semester on
+// from scheduling. Other errors are retried.
+func (g *ScheduleGenerator) JobDefinition() jobs.Definition[GenerateJob] {
+	return jobs.Definition[GenerateJob]{
+		Kind:        GenerateJobKind,
+		MaxAttempts: 2,
+		Timeout:     15 * time.Minute,
+		Run: func(ctx context.Context, _ *jobs.Job, p GenerateJob) (any, error) {
+			sched, err := g.Generate(ctx, p.SemesterID, nil)
+			if errors.Is(err, erptypes.ErrNotFound) || errors.Is(err, erptypes.ErrValidation) || errors.Is(err, ErrStatusNotSaved) {
+				return nil, jobs.Permanent(fmt.Errorf("semester %s: %w", p.SemesterID, err))
+			}
+			if err != nil {
+				return nil, err
+			}
+			return GenerateJobResult{
+				SemesterID:     sched.SemesterID,
+				Version:        sched.Version,
+				Assignments:    len(sched.Assignments),
+				HardViolations: sched.HardViolations,
+				SoftPenalty:    sched.SoftPenalty,
+			}, nil
+		},
+	}
+}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/metrics"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tracing"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/timetable/domain"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/timetable/scheduler"
+	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
+)
+
+var tracer = tracing.Tracer("mcs-erp/timetable")
+
+// ErrStatusNotSaved is returned by Generate when the schedule was saved but
+// the semester could not be moved to review.
+var ErrStatusNotSaved = errors.New("schedule saved, semester not moved to review")
+
+// ProblemBuilder assembles a scheduler.Problem from cross-module data.
+type ProblemBuilder interface {
+	BuildProblem(ctx context.Context, semesterSubjects []*domain.SemesterSubject) (scheduler.Problem, error)
+}
+
+// ScheduleGenerator runs GreedyAssign + ParallelAnneal for a semester and
+// persists the result as a new schedule version. Shared by REST and gRPC.
+type ScheduleGenerator struct {
+	semesterRepo   domain.SemesterRepository
+	scheduleRepo   domain.ScheduleRepository
+	problemBuilder ProblemBuilder
+	cfg            scheduler.SAConfig
+	workers        int // parallel annealers; 0 is one per CPU
+}
+
+// NewScheduleGenerator creates a generator using scheduler.DefaultSAConfig.
+func NewScheduleGenerator(
+	semesterRepo domain.SemesterRepository,
+	scheduleRepo domain.ScheduleRepository,
+	problemBuilder ProblemBuilder,
+) *ScheduleGenerator {
+	return &ScheduleGenerator{
+		semesterRepo:   semesterRepo,
+		scheduleRepo:   scheduleRepo,
+		problemBuilder: problemBuilder,
+		cfg:            scheduler.DefaultSAConfig(),
+	}
+}
+
+// Configure replaces the annealing parameters and worker count used by
+// later runs. workers <= 0 runs one annealer per CPU.
+func (g *ScheduleGenerator) Configure(cfg scheduler.SAConfig, workers int) {
+	g.cfg, g.workers = cfg, workers
+}
+
+// Generate schedules the semester, saves the schedule and moves the semester
+// to review. progress may be nil. Returns erptypes.ErrNotFound for an unknown
+// semester and erptypes.ErrValidation if it has no subjects. If ctx is
+// cancelled the workers stop, nothing is saved and the semester keeps its
+// previous status. Edits to the semester during the run are kept; if it
+// still cannot be moved to review, the error wraps ErrStatusNotSaved.
+func (g *ScheduleGenerator) Generate(ctx context.Context, semesterID uuid.UUID, progress scheduler.ProgressFunc) (sched *domain.Schedule, err error) {
+	ctx, span := tracer.Start(ctx, "timetable.GenerateSchedule",
+		trace.WithAttributes(attribute.String("timetable.semester_id", semesterID.String())))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	sem, err := g.semesterRepo.FindByID(ctx, semesterID)
+	if err != nil {
+		return nil, err
+	}
+
+	semSubjects, err := g.semesterRepo.GetSubjects(ctx, semesterID)
+	if err != nil {
+		return nil, fmt.Errorf("load semester subjects: %w", err)
+	}
+	if len(semSubjects) == 0 {
+		return nil, fmt.Errorf("%w: semester has no subjects", erptypes.ErrValidation)
+	}
+
+	// Mark semester as scheduling; restored below if the run does not complete.
+	prevStatus, err := g.restoreStatus(ctx, sem)
+	if err != nil {
+		return nil, err
+	}
+	sem.Status = domain.SemesterStatusScheduling
+	if err := g.semesterRepo.Update(ctx, sem); err != nil {
+		return nil, fmt.Errorf("update semester status: %w", err)
+	}
+
+	sched, err = g.run(ctx, semSubjects, semesterID, progress)
+	if err != nil {
+		if uerr := g.setStatus(context.WithoutCancel(ctx), sem, prevStatus); uerr != nil {
+			slog.Error("restore semester status failed", "semester_id", semesterID, "error", uerr)
+		}
+		return nil, err
+	}
+
+	if err := g.setStatus(ctx, sem, domain.SemesterStatusReview); err != nil {
+		slog.Error("move semester to review failed", "semester_id", semesterID, "version", sched.Version, "error", err)
+		return nil, fmt.Errorf("%w: %w", ErrStatusNotSaved, err)
+	}
+	return sched, nil
+}
+
+// restoreStatus is the status a run that does not complete returns sem to.
+// A semester already in scheduling was left there by a run that died, such
+// as a job whose worker lost its lease; it goes back to review if it has a
+// schedule and to draft if not.
+func (g *ScheduleGenerator) restoreStatus(ctx context.Context, sem *domain.Semester) (domain.SemesterStatus, error) {
+	if sem.Status != domain.SemesterStatusScheduling {
+		return sem.Status, nil
+	}
+	_, err := g.scheduleRepo.FindLatestBySemester(ctx, sem.ID)
+	switch {
+	case err == nil:
+		return domain.SemesterStatusReview, nil
+	case errors.Is(err, erptypes.ErrNotFound):
+		return domain.SemesterStatusDraft, nil
+	default:
+		return "", fmt.Errorf("load latest schedule: %w", err)
+	}
+}
+
+// setStatus stores status on sem. The semester may have been edited while
+// the run was under way, so on a version conflict it is reloaded and the
+// status applied again, a few times at most.
+func (g *ScheduleGenerator) setStatus(ctx context.Context, sem *domain.Semester, status domain.SemesterStatus) error {
+	for attempt := 1; ; attempt++ {
+		sem.Status = status
+		err := g.semesterRepo.Update(ctx, sem)
+		if !errors.Is(err, erptypes.ErrConflict) || attempt == 3 {
+			return err
+		}
+		current, err := g.semesterRepo.FindByID(ctx, sem.ID)
+		if err != nil {
+			return err
+		}
+		*sem = *current
+	}
+}
+
+func (g *ScheduleGenerator) run(ctx context.Context, semSubjects []*domain.SemesterSubject, semesterID uuid.UUID, progress scheduler.ProgressFunc) (*domain.Schedule, error) {
+	problem, err := g.problemBuilder.BuildProblem(ctx, semSubjects)
+	if err != nil {
+		return nil, fmt.Errorf("build scheduling problem: %w", err)
+	}
+
+	start := time.Now()
+	iterations := &iterationCounter{perWorker: map[int]int{}}
+	assignments, err := scheduler.ParallelAnnealContext(ctx, problem, g.cfg, g.workers, iterations.track(progress))
+	if err != nil {
+		metrics.ObserveSchedulerRun(time.Since(start), 0, 0, err)
+		return nil, err
+	}
+
+	hard := scheduler.BuildHardConstraints(problem)
+	soft := scheduler.BuildSoftConstraints()
+	hardViolations, softPenalty := scheduler.Violations(assignments, hard, soft)
+	metrics.ObserveSchedulerRun(time.Since(start), iterations.total(), scheduler.Cost(assignments, hard, soft), nil)
+
+	// Determine next version number.
+	version := 1
+	if latest, err := g.scheduleRepo.FindLatestBySemester(ctx, semesterID); err == nil {
+		version = latest.Version + 1
+	}
+
+	for i := range assignments {
+		assignments[i].SemesterID = semesterID
+		assignments[i].Version = version
+		if assignments[i].ID == uuid.Nil {
+			assignments[i].ID = uuid.New()
+		}
+	}
+
+	sched := &domain.Schedule{
+		SemesterID:     semesterID,
+		Version:        version,
+		Assignments:    assignments,
+		HardViolations: hardViolations,
+		SoftPenalty:    float64(softPenalty),
+		GeneratedAt:    time.Now(),
+	}
+	if err := g.scheduleRepo.Save(ctx, sched); err != nil {
+		return nil, fmt.Errorf("save schedule: %w", err)
+	}
+	return sched, nil
+}
+
+// iterationCounter keeps the latest iteration reported by each worker.
+type iterationCounter struct {
+	mu        sync.Mutex
+	perWorker map[int]int
+}
+
+// track records iterations and forwards progress to next (which may be nil).
+func (c *iterationCounter) track(next scheduler.ProgressFunc) scheduler.ProgressFunc {
+	return func(p scheduler.Progress) {
+		c.mu.Lock()
+		c.perWorker[p.Worker] = p.Iteration
+		c.mu.Unlock()
+		if next != nil {
+			next(p)
+		}
+	}
+}
+
+func (c *iterationCounter) total() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	for _, iter := range c.perWorker {
+		n += iter
+	}
+	return n
+}
@@ -0,0 +1,184 @@
+package delivery
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	platformgrpc "github.com/HuynhHoangPhuc/mcs-erp/internal/platform/grpc"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/timetable/application/services"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/timetable/domain"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/timetable/scheduler"
+	timetablev1 "github.com/HuynhHoangPhuc/mcs-erp/proto/gen/timetable/v1"
+)
+
+// SemesterGRPCServer implements timetablev1.SemesterServiceServer.
+type SemesterGRPCServer struct {
+	timetablev1.UnimplementedSemesterServiceServer
+	semesters domain.SemesterRepository
+}
+
+func NewSemesterGRPCServer(semesters domain.SemesterRepository) *SemesterGRPCServer {
+	return &SemesterGRPCServer{semesters: semesters}
+}
+
+func (s *SemesterGRPCServer) GetSemester(ctx context.Context, req *timetablev1.GetSemesterRequest) (*timetablev1.GetSemesterResponse, error) {
+	id, err := platformgrpc.ParseUUID("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	sem, err := s.semesters.FindByID(ctx, id)
+	if err != nil {
+		return nil, platformgrpc.Error(err)
+	}
+	subjects, err := s.semesters.GetSubjects(ctx, id)
+	if err != nil {
+		return nil, platformgrpc.Error(err)
+	}
+	pb := semesterToProto(sem)
+	for _, ss := range subjects {
+		pb.Subjects = append(pb.Subjects, &timetablev1.SemesterSubject{
+			SubjectId: ss.SubjectID.String(),
+			TeacherId: platformgrpc.UUIDString(ss.TeacherID),
+		})
+	}
+	return &timetablev1.GetSemesterResponse{Semester: pb}, nil
+}
+
+func (s *SemesterGRPCServer) ListSemesters(ctx context.Context, req *timetablev1.ListSemestersRequest) (*timetablev1.ListSemestersResponse, error) {
+	page, err := s.semesters.List(ctx, listquery.Spec{Offset: int(req.GetOffset()), Limit: platformgrpc.PageLimit(req.GetLimit())})
+	if err != nil {
+		return nil, platformgrpc.Error(err)
+	}
+	resp := &timetablev1.ListSemestersResponse{Semesters: make([]*timetablev1.Semester, 0, len(page.Items)), Total: int32(page.Total)}
+	for _, sem := range page.Items {
+		resp.Semesters = append(resp.Semesters, semesterToProto(sem))
+	}
+	return resp, nil
+}
+
+// progressBuffer bounds queued progress messages; workers drop reports
+// rather than wait on a slow client.
+const progressBuffer = 64
+
+// ScheduleGRPCServer implements timetablev1.ScheduleServiceServer.
+type ScheduleGRPCServer struct {
+	timetablev1.UnimplementedScheduleServiceServer
+	schedules domain.ScheduleRepository
+	generator *services.ScheduleGenerator
+}
+
+func NewScheduleGRPCServer(schedules domain.ScheduleRepository, generator *services.ScheduleGenerator) *ScheduleGRPCServer {
+	return &ScheduleGRPCServer{schedules: schedules, generator: generator}
+}
+
+func (s *ScheduleGRPCServer) GetSchedule(ctx context.Context, req *timetablev1.GetScheduleRequest) (*timetablev1.GetScheduleResponse, error) {
+	semesterID, err := platformgrpc.ParseUUID("semester_id", req.GetSemesterId())
+	if err != nil {
+		return nil, err
+	}
+
+	var sched *domain.Schedule
+	if req.GetVersion() > 0 {
+		sched, err = s.schedules.FindBySemester(ctx, semesterID, int(req.GetVersion()))
+	} else {
+		sched, err = s.schedules.FindLatestBySemester(ctx, semesterID)
+	}
+	if err != nil {
+		return nil, platformgrpc.Error(err)
+	}
+
+	return &timetablev1.GetScheduleResponse{Schedule: scheduleToProto(sched)}, nil
+}
+
+// GenerateSchedule streams worker progress while the semester is scheduled,
+// then sends the saved schedule. The stream context cancels the workers.
+func (s *ScheduleGRPCServer) GenerateSchedule(req *timetablev1.GenerateScheduleRequest, stream grpc.ServerStreamingServer[timetablev1.GenerateScheduleResponse]) error {
+	semesterID, err := platformgrpc.ParseUUID("semester_id", req.GetSemesterId())
+	if err != nil {
+		return err
+	}
+
+	type result struct {
+		sched *domain.Schedule
+		err   error
+	}
+	progress := make(chan scheduler.Progress, progressBuffer)
+	done := make(chan result, 1)
+	go func() {
+		sched, err := s.generator.Generate(stream.Context(), semesterID, func(p scheduler.Progress) {
+			select {
+			case progress <- p:
+			default:
+			}
+		})
+		done <- result{sched: sched, err: err}
+	}()
+
+	for {
+		select {
+		case p := <-progress:
+			if err := stream.Send(&timetablev1.GenerateScheduleResponse{
+				Event: &timetablev1.GenerateScheduleResponse_Progress{Progress: &timetablev1.WorkerProgress{
+					Worker:         int32(p.Worker),
+					Iteration:      int32(p.Iteration),
+					Temperature:    p.Temperature,
+					BestCost:       int64(p.BestCost),
+					HardViolations: int32(p.HardViolations),
+					SoftPenalty:    int32(p.SoftPenalty),
+				}},
+			}); err != nil {
+				// Returning cancels the stream context, which stops the workers.
+				return err
+			}
+		case r := <-done:
+			if r.err != nil {
+				return platformgrpc.Error(r.err)
+			}
+			return stream.Send(&timetablev1.GenerateScheduleResponse{
+				Event: &timetablev1.GenerateScheduleResponse_Schedule{Schedule: scheduleToProto(r.sched)},
+			})
+		}
+	}
+}
+
+func scheduleToProto(sched *domain.Schedule) *timetablev1.Schedule {
+	pb := &timetablev1.Schedule{
+		SemesterId:     sched.SemesterID.String(),
+		Version:        int32(sched.Version),
+		Assignments:    make([]*timetablev1.Assignment, 0, len(sched.Assignments)),
+		HardViolations: int32(sched.HardViolations),
+		SoftPenalty:    sched.SoftPenalty,
+		GeneratedAt:    timestamppb.New(sched.GeneratedAt),
+	}
+	for _, a := range sched.Assignments {
+		pb.Assignments = append(pb.Assignments, &timetablev1.Assignment{
+			Id:        a.ID.String(),
+			SubjectId: a.SubjectID.String(),
+			TeacherId: a.TeacherID.String(),
+			RoomId:    a.RoomID.String(),
+			Day:       int32(a.Day),
+			Period:    int32(a.Period),
+		})
+	}
+	return pb
+}
+
+func semesterToProto(s *domain.Semester) *timetablev1.Semester {
+	return &timetablev1.Semester{
+		Id:        s.ID.String(),
+		Name:      s.Name,
+		StartDate: timestamppb.New(s.StartDate),
+		EndDate:   timestamppb.New(s.EndDate),
+		Status:    string(s.Status),
+		CreatedAt: timestamppb.New(s.CreatedAt),
+		UpdatedAt: timestamppb.New(s.UpdatedAt),
+	}
+}
+
+var (
+	_ timetablev1.SemesterServiceServer = (*SemesterGRPCServer)(nil)
+	_ timetablev1.ScheduleServiceServer = (*ScheduleGRPCServer)(nil)
+)
@@ -1,238 +1,202 @@
 package delivery
 
 import (
-	"context"
-	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/etag"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/jobs"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/problem"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/validate"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/timetable/application/services"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/timetable/domain"
-	"github.com/HuynhHoangPhuc/mcs-erp/internal/timetable/scheduler"
 	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
 )
 
-// ProblemBuilder assembles a scheduler.Problem from cross-module data.
-type ProblemBuilder interface {
-	BuildProblem(ctx context.Context, semesterSubjects []*domain.SemesterSubject) (scheduler.Problem, error)
-}
-
 // ScheduleHandler handles schedule generation, retrieval, approval and manual edits.
 type ScheduleHandler struct {
-	semesterRepo  domain.SemesterRepository
-	scheduleRepo  domain.ScheduleRepository
-	problemBuilder ProblemBuilder
+	semesterRepo domain.SemesterRepository
+	scheduleRepo domain.ScheduleRepository
+	generator    *services.ScheduleGenerator
+	queue        *jobs.Queue
 }
 
 // NewScheduleHandler creates a new schedule handler.
 func NewScheduleHandler(
 	semesterRepo domain.SemesterRepository,
 	scheduleRepo domain.ScheduleRepository,
-	problemBuilder ProblemBuilder,
+	generator *services.ScheduleGenerator,
+	queue *jobs.Queue,
 ) *ScheduleHandler {
 	return &ScheduleHandler{
-		semesterRepo:   semesterRepo,
-		scheduleRepo:   scheduleRepo,
-		problemBuilder: problemBuilder,
+		semesterRepo: semesterRepo,
+		scheduleRepo: scheduleRepo,
+		generator:    generator,
+		queue:        queue,
 	}
 }
 
 // GenerateSchedule handles POST /api/v1/timetable/semesters/{id}/generate
-// Runs GreedyAssign + ParallelAnneal synchronously and persists the result.
+// Checks the semester can be scheduled, then queues GreedyAssign +
+// ParallelAnneal as a background job and answers 202 Accepted with it; the
+// finished job's result names the new schedule version. While a generation
+// for the semester is queued or running, that job is returned instead.
+// If-Match is checked against the semester, whose status the run changes.
 func (h *ScheduleHandler) GenerateSchedule(w http.ResponseWriter, r *http.Request) {
-	semID, err := uuid.Parse(r.PathValue("id"))
+	semID, err := validate.PathUUID(r, "id")
 	if err != nil {
-		writeJSON(w, http.StatusBadRequest, errResp("invalid semester id"))
+		problem.Write(w, r, err)
 		return
 	}
 
 	sem, err := h.semesterRepo.FindByID(r.Context(), semID)
 	if err != nil {
-		if err == erptypes.ErrNotFound {
-			writeJSON(w, http.StatusNotFound, errResp("semester not found"))
+		if errors.Is(err, erptypes.ErrNotFound) {
+			problem.Write(w, r, erptypes.NotFound("semester not found"))
 			return
 		}
-		writeJSON(w, http.StatusInternalServerError, errResp("failed to load semester"))
+		problem.Write(w, r, erptypes.Internal("failed to load semester", err))
 		return
 	}
-
-	// Mark semester as scheduling.
-	sem.Status = domain.SemesterStatusScheduling
-	if err := h.semesterRepo.Update(r.Context(), sem); err != nil {
-		writeJSON(w, http.StatusInternalServerError, errResp("failed to update semester status"))
+	if etag.Conditional(r) && !etag.Match(r, sem.Version) {
+		problem.Write(w, r, erptypes.PreconditionFailed("semester was modified; reload and retry"))
 		return
 	}
-
-	// Load semester subjects.
-	semSubjects, err := h.semesterRepo.GetSubjects(r.Context(), semID)
+	subjects, err := h.semesterRepo.GetSubjects(r.Context(), semID)
 	if err != nil {
-		writeJSON(w, http.StatusInternalServerError, errResp("failed to load semester subjects"))
+		problem.Write(w, r, erptypes.Internal("failed to load semester subjects", err))
 		return
 	}
-	if len(semSubjects) == 0 {
-		writeJSON(w, http.StatusBadRequest, errResp("semester has no subjects"))
+	if len(subjects) == 0 {
+		problem.Write(w, r, erptypes.InvalidRequest("semester has no subjects"))
 		return
 	}
 
-	// Build the scheduling problem.
-	problem, err := h.problemBuilder.BuildProblem(r.Context(), semSubjects)
+	job, err := h.queue.Enqueue(r.Context(), services.GenerateJobKind, services.GenerateJob{SemesterID: semID},
+		jobs.Unique(services.GenerateJobKind+":"+semID.String()))
 	if err != nil {
-		writeJSON(w, http.StatusInternalServerError, errResp("failed to build scheduling problem"))
+		problem.Write(w, r, erptypes.Internal("failed to queue schedule generation", fmt.Errorf("semester %s: %w", semID, err)))
 		return
 	}
-
-	// Run parallel simulated annealing.
-	cfg := scheduler.DefaultSAConfig()
-	assignments := scheduler.ParallelAnneal(problem, cfg, 0)
-
-	// Stamp semester ID and compute metrics.
-	hard := scheduler.BuildHardConstraints(problem)
-	soft := scheduler.BuildSoftConstraints()
-
-	for i := range assignments {
-		assignments[i].SemesterID = semID
-	}
-
-	hardViolations := 0
-	for _, c := range hard {
-		hardViolations += c.Evaluate(assignments)
-	}
-	softPenalty := 0
-	for _, c := range soft {
-		softPenalty += c.Evaluate(assignments)
-	}
-
-	// Determine next version number.
-	version := 1
-	if latest, err := h.scheduleRepo.FindLatestBySemester(r.Context(), semID); err == nil {
-		version = latest.Version + 1
-	}
-
-	// Tag each assignment with version.
-	for i := range assignments {
-		assignments[i].Version = version
-		if assignments[i].ID == uuid.Nil {
-			assignments[i].ID = uuid.New()
-		}
-	}
-
-	sched := &domain.Schedule{
-		SemesterID:     semID,
-		Version:        version,
-		Assignments:    assignments,
-		HardViolations: hardViolations,
-		SoftPenalty:    float64(softPenalty),
-		GeneratedAt:    time.Now(),
-	}
-
-	if err := h.scheduleRepo.Save(r.Context(), sched); err != nil {
-		writeJSON(w, http.StatusInternalServerError, errResp("failed to save schedule"))
-		return
-	}
-
-	// Move semester to review status.
-	sem.Status = domain.SemesterStatusReview
-	_ = h.semesterRepo.Update(r.Context(), sem)
-
-	writeJSON(w, http.StatusOK, scheduleResponse(sched))
+	jobs.WriteAccepted(w, job)
 }
 
 // GetLatestSchedule handles GET /api/v1/timetable/semesters/{id}/schedule
 func (h *ScheduleHandler) GetLatestSchedule(w http.ResponseWriter, r *http.Request) {
-	semID, err := uuid.Parse(r.PathValue("id"))
+	semID, err := validate.PathUUID(r, "id")
 	if err != nil {
-		writeJSON(w, http.StatusBadRequest, errResp("invalid semester id"))
+		problem.Write(w, r, err)
 		return
 	}
 
 	sched, err := h.scheduleRepo.FindLatestBySemester(r.Context(), semID)
 	if err != nil {
-		if err == erptypes.ErrNotFound {
-			writeJSON(w, http.StatusNotFound, errResp("no schedule found for semester"))
+		if errors.Is(err, erptypes.ErrNotFound) {
+			problem.Write(w, r, erptypes.NotFound("no schedule found for semester"))
 			return
 		}
-		writeJSON(w, http.StatusInternalServerError, errResp("failed to load schedule"))
+		problem.Write(w, r, erptypes.Internal("failed to load schedule", err))
 		return
 	}
-	writeJSON(w, http.StatusOK, scheduleResponse(sched))
+	writeJSON(w, http.StatusOK, toScheduleResponse(sched))
 }
 
 // ApproveSchedule handles POST /api/v1/timetable/semesters/{id}/approve
 func (h *ScheduleHandler) ApproveSchedule(w http.ResponseWriter, r *http.Request) {
-	semID, err := uuid.Parse(r.PathValue("id"))
+	semID, err := validate.PathUUID(r, "id")
 	if err != nil {
-		writeJSON(w, http.StatusBadRequest, errResp("invalid semester id"))
+		problem.Write(w, r, err)
 		return
 	}
 
 	sem, err := h.semesterRepo.FindByID(r.Context(), semID)
 	if err != nil {
-		if err == erptypes.ErrNotFound {
-			writeJSON(w, http.StatusNotFound, errResp("semester not found"))
+		if errors.Is(err, erptypes.ErrNotFound) {
+			problem.Write(w, r, erptypes.NotFound("semester not found"))
 			return
 		}
-		writeJSON(w, http.StatusInternalServerError, errResp("failed to load semester"))
+		problem.Write(w, r, erptypes.Internal("failed to load semester", err))
 		return
 	}
 
+	if !etag.Match(r, sem.Version) {
+		problem.Write(w, r, erptypes.PreconditionFailed("semester was modified; reload and retry"))
+		return
+	}
 	if sem.Status != domain.SemesterStatusReview {
-		writeJSON(w, http.StatusBadRequest, errResp("semester must be in review status to approve"))
+		problem.Write(w, r, erptypes.InvalidRequest("semester must be in review status to approve"))
 		return
 	}
 
 	sem.Status = domain.SemesterStatusApproved
 	if err := h.semesterRepo.Update(r.Context(), sem); err != nil {
-		writeJSON(w, http.StatusInternalServerError, errResp("failed to approve schedule"))
+		switch {
+		case errors.Is(err, erptypes.ErrNotFound):
+			problem.Write(w, r, erptypes.NotFound("semester not found"))
+		case errors.Is(err, erptypes.ErrConflict):
+			problem.Write(w, r, etag.Conflict(r, "semester was modified; reload and retry"))
+		default:
+			problem.Write(w, r, erptypes.Internal("failed to approve schedule", err))
+		}
 		return
 	}
-	writeJSON(w, http.StatusOK, map[string]any{"status": sem.Status})
+	etag.Set(w, sem.Version)
+	writeJSON(w, http.StatusOK, StatusResponse{Status: sem.Status})
+}
+
+// UpdateAssignmentRequest moves an assignment; empty ids and out-of-range
+// day/period keep the current value.
+type UpdateAssignmentRequest struct {
+	TeacherID string `json:"teacher_id"`
+	RoomID    string `json:"room_id"`
+	Day       int    `json:"day"`
+	Period    int    `json:"period"`
+
+	teacherID, roomID *uuid.UUID
+}
+
+func (req *UpdateAssignmentRequest) Validate(v *validate.Validator) {
+	req.teacherID = v.OptionalUUID("teacher_id", req.TeacherID)
+	req.roomID = v.OptionalUUID("room_id", req.RoomID)
 }
 
 // UpdateAssignment handles PUT /api/v1/timetable/assignments/{id}
 func (h *ScheduleHandler) UpdateAssignment(w http.ResponseWriter, r *http.Request) {
-	assignID, err := uuid.Parse(r.PathValue("id"))
+	assignID, err := validate.PathUUID(r, "id")
 	if err != nil {
-		writeJSON(w, http.StatusBadRequest, errResp("invalid assignment id"))
+		problem.Write(w, r, err)
 		return
 	}
 
 	existing, err := h.scheduleRepo.FindAssignmentByID(r.Context(), assignID)
 	if err != nil {
-		if err == erptypes.ErrNotFound {
-			writeJSON(w, http.StatusNotFound, errResp("assignment not found"))
+		if errors.Is(err, erptypes.ErrNotFound) {
+			problem.Write(w, r, erptypes.NotFound("assignment not found"))
 			return
 		}
-		writeJSON(w, http.StatusInternalServerError, errResp("failed to load assignment"))
+		problem.Write(w, r, erptypes.Internal("failed to load assignment", err))
 		return
 	}
-
-	var req struct {
-		TeacherID string `json:"teacher_id"`
-		RoomID    string `json:"room_id"`
-		Day       int    `json:"day"`
-		Period    int    `json:"period"`
+	if !etag.Match(r, existing.Revision) {
+		problem.Write(w, r, erptypes.PreconditionFailed("assignment was modified; reload and retry"))
+		return
 	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSON(w, http.StatusBadRequest, errResp("invalid request body"))
+
+	var req UpdateAssignmentRequest
+	if err := validate.DecodeJSON(r, &req); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
-	if req.TeacherID != "" {
-		tid, err := uuid.Parse(req.TeacherID)
-		if err != nil {
-			writeJSON(w, http.StatusBadRequest, errResp("invalid teacher_id"))
-			return
-		}
-		existing.TeacherID = tid
+	if req.teacherID != nil {
+		existing.TeacherID = *req.teacherID
 	}
-	if req.RoomID != "" {
-		rid, err := uuid.Parse(req.RoomID)
-		if err != nil {
-			writeJSON(w, http.StatusBadRequest, errResp("invalid room_id"))
-			return
-		}
-		existing.RoomID = rid
+	if req.roomID != nil {
+		existing.RoomID = *req.roomID
 	}
 	if req.Day >= 0 && req.Day <= 5 {
 		existing.Day = req.Day
@@ -242,38 +206,75 @@ func (h *ScheduleHandler) UpdateAssignment(w http.ResponseWriter, r *http.Reques
 	}
 
 	if err := h.scheduleRepo.UpdateAssignment(r.Context(), existing); err != nil {
-		writeJSON(w, http.StatusInternalServerError, errResp("failed to update assignment"))
+		switch {
+		case errors.Is(err, erptypes.ErrNotFound):
+			problem.Write(w, r, erptypes.NotFound("assignment not found"))
+		case errors.Is(err, erptypes.ErrConflict):
+			problem.Write(w, r, etag.Conflict(r, "assignment was modified; reload and retry"))
+		default:
+			problem.Write(w, r, erptypes.Internal("failed to update assignment", err))
+		}
 		return
 	}
-	writeJSON(w, http.StatusOK, assignmentResponse(existing))
+	etag.Set(w, existing.Revision)
+	writeJSON(w, http.StatusOK, toAssignmentResponse(existing))
 }
 
 // --- Helpers ---
 
-func scheduleResponse(s *domain.Schedule) map[string]any {
-	assignments := make([]map[string]any, len(s.Assignments))
+// ScheduleResponse is the JSON representation of a generated Schedule.
+type ScheduleResponse struct {
+	SemesterID     uuid.UUID            `json:"semester_id"`
+	Version        int                  `json:"version"`
+	HardViolations int                  `json:"hard_violations"`
+	SoftPenalty    float64              `json:"soft_penalty"`
+	GeneratedAt    time.Time            `json:"generated_at"`
+	Assignments    []AssignmentResponse `json:"assignments"`
+}
+
+// AssignmentResponse is the JSON representation of an Assignment.
+type AssignmentResponse struct {
+	ID         uuid.UUID `json:"id"`
+	SemesterID uuid.UUID `json:"semester_id"`
+	SubjectID  uuid.UUID `json:"subject_id"`
+	TeacherID  uuid.UUID `json:"teacher_id"`
+	RoomID     uuid.UUID `json:"room_id"`
+	Day        int       `json:"day"`
+	Period     int       `json:"period"`
+	Version    int       `json:"version"`
+	Revision   int       `json:"revision"` // ETag of the assignment, for If-Match on edits
+}
+
+// StatusResponse reports a semester's status after a transition.
+type StatusResponse struct {
+	Status domain.SemesterStatus `json:"status"`
+}
+
+func toScheduleResponse(s *domain.Schedule) ScheduleResponse {
+	assignments := make([]AssignmentResponse, len(s.Assignments))
 	for i, a := range s.Assignments {
-		assignments[i] = assignmentResponse(&a)
-	}
-	return map[string]any{
-		"semester_id":     s.SemesterID,
-		"version":         s.Version,
-		"hard_violations": s.HardViolations,
-		"soft_penalty":    s.SoftPenalty,
-		"generated_at":    s.GeneratedAt,
-		"assignments":     assignments,
+		assignments[i] = toAssignmentResponse(&a)
+	}
+	return ScheduleResponse{
+		SemesterID:     s.SemesterID,
+		Version:        s.Version,
+		HardViolations: s.HardViolations,
+		SoftPenalty:    s.SoftPenalty,
+		GeneratedAt:    s.GeneratedAt,
+		Assignments:    assignments,
 	}
 }
 
-func assignmentResponse(a *domain.Assignment) map[string]any {
-	return map[string]any{
-		"id":          a.ID,
-		"semester_id": a.SemesterID,
-		"subject_id":  a.SubjectID,
-		"teacher_id":  a.TeacherID,
-		"room_id":     a.RoomID,
-		"day":         a.Day,
-		"period":      a.Period,
-		"version":     a.Version,
+func toAssignmentResponse(a *domain.Assignment) AssignmentResponse {
+	return AssignmentResponse{
+		ID:         a.ID,
+		SemesterID: a.SemesterID,
+		SubjectID:  a.SubjectID,
+		TeacherID:  a.TeacherID,
+		RoomID:     a.RoomID,
+		Day:        a.Day,
+		Period:     a.Period,
+		Version:    a.Version,
+		Revision:   a.Revision,
 	}
 }
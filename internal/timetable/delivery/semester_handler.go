@@ -1,13 +1,18 @@
 package delivery
 
 import (
-	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/etag"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/history"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/problem"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/validate"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/timetable/domain"
 	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
 )
@@ -24,46 +29,73 @@ func NewSemesterHandler(semesterRepo domain.SemesterRepository) *SemesterHandler
 
 // --- Request/response types ---
 
-type createSemesterRequest struct {
+// CreateSemesterRequest is the body of POST /api/v1/timetable/semesters.
+type CreateSemesterRequest struct {
 	Name      string `json:"name"`
 	StartDate string `json:"start_date"` // RFC3339 date
 	EndDate   string `json:"end_date"`
+
+	startDate, endDate time.Time
+}
+
+func (req *CreateSemesterRequest) Validate(v *validate.Validator) {
+	v.Required("name", req.Name)
+	req.startDate = v.Time("start_date", req.StartDate)
+	req.endDate = v.Time("end_date", req.EndDate)
+	if !v.Has("start_date") && !v.Has("end_date") {
+		v.Check(req.endDate.After(req.startDate), "end_date", validate.OutOfRange, "must be after start_date")
+	}
 }
 
-type setSubjectsRequest struct {
+// SetSubjectsRequest adds subjects to a semester.
+type SetSubjectsRequest struct {
 	SubjectIDs []string `json:"subject_ids"`
+
+	subjectIDs []uuid.UUID
+}
+
+func (req *SetSubjectsRequest) Validate(v *validate.Validator) {
+	v.Check(len(req.SubjectIDs) > 0, "subject_ids", validate.Required, "must not be empty")
+	for i, raw := range req.SubjectIDs {
+		req.subjectIDs = append(req.subjectIDs, v.UUID(fmt.Sprintf("subject_ids[%d]", i), raw))
+	}
 }
 
-type assignTeacherRequest struct {
+// AssignTeacherRequest sets (or, when empty, clears) the teacher of a
+// semester subject.
+type AssignTeacherRequest struct {
 	TeacherID string `json:"teacher_id"`
+
+	teacherID *uuid.UUID
+}
+
+func (req *AssignTeacherRequest) Validate(v *validate.Validator) {
+	req.teacherID = v.OptionalUUID("teacher_id", req.TeacherID)
+}
+
+// SemesterResponse is the JSON representation of a Semester.
+type SemesterResponse struct {
+	ID        uuid.UUID             `json:"id"`
+	Name      string                `json:"name"`
+	StartDate time.Time             `json:"start_date"`
+	EndDate   time.Time             `json:"end_date"`
+	Status    domain.SemesterStatus `json:"status"`
+	CreatedAt time.Time             `json:"created_at"`
+	UpdatedAt time.Time             `json:"updated_at"`
+}
+
+// SetSubjectsResponse reports how many subjects were added.
+type SetSubjectsResponse struct {
+	Added int `json:"added"`
 }
 
 // --- Handlers ---
 
 // CreateSemester handles POST /api/v1/timetable/semesters
 func (h *SemesterHandler) CreateSemester(w http.ResponseWriter, r *http.Request) {
-	var req createSemesterRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSON(w, http.StatusBadRequest, errResp("invalid request body"))
-		return
-	}
-	if req.Name == "" {
-		writeJSON(w, http.StatusBadRequest, errResp("name is required"))
-		return
-	}
-
-	startDate, err := time.Parse(time.RFC3339, req.StartDate)
-	if err != nil {
-		writeJSON(w, http.StatusBadRequest, errResp("start_date must be RFC3339"))
-		return
-	}
-	endDate, err := time.Parse(time.RFC3339, req.EndDate)
-	if err != nil {
-		writeJSON(w, http.StatusBadRequest, errResp("end_date must be RFC3339"))
-		return
-	}
-	if !endDate.After(startDate) {
-		writeJSON(w, http.StatusBadRequest, errResp("end_date must be after start_date"))
+	var req CreateSemesterRequest
+	if err := validate.DecodeJSON(r, &req); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -71,157 +103,188 @@ func (h *SemesterHandler) CreateSemester(w http.ResponseWriter, r *http.Request)
 	s := &domain.Semester{
 		ID:        uuid.New(),
 		Name:      req.Name,
-		StartDate: startDate,
-		EndDate:   endDate,
+		StartDate: req.startDate,
+		EndDate:   req.endDate,
 		Status:    domain.SemesterStatusDraft,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
 
 	if err := h.semesterRepo.Save(r.Context(), s); err != nil {
-		writeJSON(w, http.StatusInternalServerError, errResp("failed to create semester"))
+		problem.Write(w, r, erptypes.Internal("failed to create semester", err))
 		return
 	}
-	writeJSON(w, http.StatusCreated, semesterResponse(s))
+	etag.Set(w, s.Version)
+	writeJSON(w, http.StatusCreated, toSemesterResponse(s))
 }
 
 // ListSemesters handles GET /api/v1/timetable/semesters
 func (h *SemesterHandler) ListSemesters(w http.ResponseWriter, r *http.Request) {
-	q := r.URL.Query()
-	offset, _ := strconv.Atoi(q.Get("offset"))
-	limit, _ := strconv.Atoi(q.Get("limit"))
-	if limit <= 0 || limit > 100 {
-		limit = 20
-	}
-
-	semesters, total, err := h.semesterRepo.List(r.Context(), offset, limit)
+	spec, err := listquery.Parse(r.URL.Query(), domain.SemesterListSchema)
 	if err != nil {
-		writeJSON(w, http.StatusInternalServerError, errResp("failed to list semesters"))
+		problem.Write(w, r, validate.Query(err))
 		return
 	}
 
-	items := make([]map[string]any, len(semesters))
-	for i, s := range semesters {
-		items[i] = semesterResponse(s)
+	page, err := h.semesterRepo.List(r.Context(), spec)
+	if err != nil {
+		problem.Write(w, r, erptypes.Internal("failed to list semesters", err))
+		return
 	}
-	writeJSON(w, http.StatusOK, map[string]any{"items": items, "total": total})
+	writeJSON(w, http.StatusOK, listquery.Map(page, toSemesterResponse))
 }
 
-// GetSemester handles GET /api/v1/timetable/semesters/{id}
+// GetSemester handles GET /api/v1/timetable/semesters/{id}[?as_of=<RFC 3339>]
 func (h *SemesterHandler) GetSemester(w http.ResponseWriter, r *http.Request) {
-	id, err := uuid.Parse(r.PathValue("id"))
+	id, err := validate.PathUUID(r, "id")
 	if err != nil {
-		writeJSON(w, http.StatusBadRequest, errResp("invalid semester id"))
+		problem.Write(w, r, err)
+		return
+	}
+	asOf, err := history.ParseAsOf(r.URL.Query())
+	if err != nil {
+		problem.Write(w, r, validate.Query(err))
 		return
 	}
 
-	s, err := h.semesterRepo.FindByID(r.Context(), id)
+	var s *domain.Semester
+	if asOf != nil {
+		s, err = h.semesterRepo.FindByIDAsOf(r.Context(), id, *asOf)
+	} else {
+		s, err = h.semesterRepo.FindByID(r.Context(), id)
+	}
 	if err != nil {
-		if err == erptypes.ErrNotFound {
-			writeJSON(w, http.StatusNotFound, errResp("semester not found"))
+		if errors.Is(err, erptypes.ErrNotFound) {
+			problem.Write(w, r, erptypes.NotFound("semester not found"))
 			return
 		}
-		writeJSON(w, http.StatusInternalServerError, errResp("failed to get semester"))
+		problem.Write(w, r, erptypes.Internal("failed to get semester", err))
 		return
 	}
-	writeJSON(w, http.StatusOK, semesterResponse(s))
+	if asOf == nil {
+		etag.Set(w, s.Version)
+	}
+	writeJSON(w, http.StatusOK, toSemesterResponse(s))
 }
 
-// SetSubjects handles POST /api/v1/timetable/semesters/{id}/subjects
-func (h *SemesterHandler) SetSubjects(w http.ResponseWriter, r *http.Request) {
-	semID, err := uuid.Parse(r.PathValue("id"))
+// SemesterHistory handles GET /api/v1/timetable/semesters/{id}/history
+func (h *SemesterHandler) SemesterHistory(w http.ResponseWriter, r *http.Request) {
+	id, err := validate.PathUUID(r, "id")
 	if err != nil {
-		writeJSON(w, http.StatusBadRequest, errResp("invalid semester id"))
+		problem.Write(w, r, err)
 		return
 	}
 
-	// Verify semester exists.
-	if _, err := h.semesterRepo.FindByID(r.Context(), semID); err != nil {
-		if err == erptypes.ErrNotFound {
-			writeJSON(w, http.StatusNotFound, errResp("semester not found"))
+	versions, err := h.semesterRepo.History(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, erptypes.ErrNotFound) {
+			problem.Write(w, r, erptypes.NotFound("semester not found"))
 			return
 		}
-		writeJSON(w, http.StatusInternalServerError, errResp("failed to load semester"))
+		problem.Write(w, r, erptypes.Internal("failed to get semester history", err))
 		return
 	}
+	writeJSON(w, http.StatusOK, history.List[SemesterResponse]{Items: history.Map(versions, toSemesterResponse)})
+}
 
-	var req setSubjectsRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSON(w, http.StatusBadRequest, errResp("invalid request body"))
+// SetSubjects handles POST /api/v1/timetable/semesters/{id}/subjects
+// The subjects are part of the semester: If-Match is checked against it and
+// its version bumped.
+func (h *SemesterHandler) SetSubjects(w http.ResponseWriter, r *http.Request) {
+	sem, ok := h.loadForUpdate(w, r)
+	if !ok {
 		return
 	}
-	if len(req.SubjectIDs) == 0 {
-		writeJSON(w, http.StatusBadRequest, errResp("subject_ids must not be empty"))
+
+	var req SetSubjectsRequest
+	if err := validate.DecodeJSON(r, &req); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
-	subjectIDs := make([]uuid.UUID, 0, len(req.SubjectIDs))
-	for _, raw := range req.SubjectIDs {
-		sid, err := uuid.Parse(raw)
-		if err != nil {
-			writeJSON(w, http.StatusBadRequest, errResp("invalid subject_id: "+raw))
-			return
+	if err := h.semesterRepo.AddSubjects(r.Context(), sem, req.subjectIDs); err != nil {
+		switch {
+		case errors.Is(err, erptypes.ErrNotFound):
+			problem.Write(w, r, erptypes.NotFound("semester not found"))
+		case errors.Is(err, erptypes.ErrConflict):
+			problem.Write(w, r, etag.Conflict(r, "semester was modified; reload and retry"))
+		default:
+			problem.Write(w, r, erptypes.Internal("failed to set subjects", err))
 		}
-		subjectIDs = append(subjectIDs, sid)
-	}
-
-	if err := h.semesterRepo.AddSubjects(r.Context(), semID, subjectIDs); err != nil {
-		writeJSON(w, http.StatusInternalServerError, errResp("failed to set subjects"))
 		return
 	}
-	writeJSON(w, http.StatusOK, map[string]any{"added": len(subjectIDs)})
+	etag.Set(w, sem.Version)
+	writeJSON(w, http.StatusOK, SetSubjectsResponse{Added: len(req.subjectIDs)})
 }
 
 // AssignTeacher handles POST /api/v1/timetable/semesters/{id}/subjects/{subjectId}/teacher
+// Like SetSubjects it is checked against and bumps the semester version.
 func (h *SemesterHandler) AssignTeacher(w http.ResponseWriter, r *http.Request) {
-	semID, err := uuid.Parse(r.PathValue("id"))
+	subjectID, err := validate.PathUUID(r, "subjectId")
 	if err != nil {
-		writeJSON(w, http.StatusBadRequest, errResp("invalid semester id"))
+		problem.Write(w, r, err)
 		return
 	}
-	subjectID, err := uuid.Parse(r.PathValue("subjectId"))
-	if err != nil {
-		writeJSON(w, http.StatusBadRequest, errResp("invalid subject id"))
+	sem, ok := h.loadForUpdate(w, r)
+	if !ok {
 		return
 	}
 
-	var req assignTeacherRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSON(w, http.StatusBadRequest, errResp("invalid request body"))
+	var req AssignTeacherRequest
+	if err := validate.DecodeJSON(r, &req); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
-	var teacherID *uuid.UUID
-	if req.TeacherID != "" {
-		tid, err := uuid.Parse(req.TeacherID)
-		if err != nil {
-			writeJSON(w, http.StatusBadRequest, errResp("invalid teacher_id"))
-			return
+	if err := h.semesterRepo.SetTeacherAssignment(r.Context(), sem, subjectID, req.teacherID); err != nil {
+		switch {
+		case errors.Is(err, erptypes.ErrNotFound):
+			problem.Write(w, r, erptypes.NotFound("semester subject not found"))
+		case errors.Is(err, erptypes.ErrConflict):
+			problem.Write(w, r, etag.Conflict(r, "semester was modified; reload and retry"))
+		default:
+			problem.Write(w, r, erptypes.Internal("failed to assign teacher", err))
 		}
-		teacherID = &tid
-	}
-
-	if err := h.semesterRepo.SetTeacherAssignment(r.Context(), semID, subjectID, teacherID); err != nil {
-		writeJSON(w, http.StatusInternalServerError, errResp("failed to assign teacher"))
 		return
 	}
+	etag.Set(w, sem.Version)
 	writeJSON(w, http.StatusOK, map[string]any{"ok": true})
 }
 
-// --- Helpers ---
-
-func semesterResponse(s *domain.Semester) map[string]any {
-	return map[string]any{
-		"id":         s.ID,
-		"name":       s.Name,
-		"start_date": s.StartDate,
-		"end_date":   s.EndDate,
-		"status":     s.Status,
-		"created_at": s.CreatedAt,
-		"updated_at": s.UpdatedAt,
+// loadForUpdate loads the semester named by the path and checks If-Match
+// against it, writing the error response when it returns false.
+func (h *SemesterHandler) loadForUpdate(w http.ResponseWriter, r *http.Request) (*domain.Semester, bool) {
+	semID, err := validate.PathUUID(r, "id")
+	if err != nil {
+		problem.Write(w, r, err)
+		return nil, false
 	}
+	sem, err := h.semesterRepo.FindByID(r.Context(), semID)
+	if err != nil {
+		if errors.Is(err, erptypes.ErrNotFound) {
+			problem.Write(w, r, erptypes.NotFound("semester not found"))
+			return nil, false
+		}
+		problem.Write(w, r, erptypes.Internal("failed to load semester", err))
+		return nil, false
+	}
+	if !etag.Match(r, sem.Version) {
+		problem.Write(w, r, erptypes.PreconditionFailed("semester was modified; reload and retry"))
+		return nil, false
+	}
+	return sem, true
 }
 
-func errResp(msg string) map[string]string {
-	return map[string]string{"error": msg}
+// --- Helpers ---
+
+func toSemesterResponse(s *domain.Semester) SemesterResponse {
+	return SemesterResponse{
+		ID:        s.ID,
+		Name:      s.Name,
+		StartDate: s.StartDate,
+		EndDate:   s.EndDate,
+		Status:    s.Status,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}
 }
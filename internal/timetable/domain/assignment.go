@@ -12,6 +12,7 @@ type Assignment struct {
 	Day        int // 0-5 (Mon-Sat)
 	Period     int // 1-10
 	Version    int // schedule generation version
+	Revision   int // optimistic lock, bumped by every manual edit
 }
 
 // Slot returns the TimeSlot for this assignment.
@@ -6,6 +6,13 @@ import (
 	"github.com/google/uuid"
 )
 
+// Event topics published through the transactional outbox.
+const (
+	TopicScheduleGenerated  = "timetable.schedule.generated"
+	TopicScheduleApproved   = "timetable.schedule.approved"
+	TopicAssignmentModified = "timetable.assignment.modified"
+)
+
 // ScheduleGenerated is published when the scheduler produces a new schedule version.
 type ScheduleGenerated struct {
 	SemesterID     uuid.UUID
@@ -2,23 +2,51 @@ package domain
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/history"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
 )
 
+// SemesterListSchema declares how semesters can be filtered and sorted.
+var SemesterListSchema = &listquery.Schema{
+	Fields: map[string]listquery.Field{
+		"id":         {Kind: listquery.UUID, Filter: listquery.Eq | listquery.In, Sort: true},
+		"name":       {Kind: listquery.Text, Filter: listquery.Eq | listquery.Contains, Sort: true},
+		"status":     {Kind: listquery.Text, Filter: listquery.Eq | listquery.In},
+		"start_date": {Kind: listquery.Time, Filter: listquery.Range, Sort: true},
+		"end_date":   {Kind: listquery.Time, Filter: listquery.Range, Sort: true},
+		"created_at": {Kind: listquery.Time, Filter: listquery.Range, Sort: true},
+		"updated_at": {Kind: listquery.Time, Filter: listquery.Range, Sort: true},
+	},
+	Key:         "id",
+	DefaultSort: []listquery.Sort{{Field: "created_at", Desc: true}},
+}
+
 // SemesterRepository defines persistence operations for Semester aggregates.
 type SemesterRepository interface {
 	FindByID(ctx context.Context, id uuid.UUID) (*Semester, error)
+	// FindByIDAsOf returns the semester as it was at the given time.
+	FindByIDAsOf(ctx context.Context, id uuid.UUID, at time.Time) (*Semester, error)
+	// History returns every version of the semester, oldest first.
+	History(ctx context.Context, id uuid.UUID) ([]history.Version[*Semester], error)
 	Save(ctx context.Context, s *Semester) error
+	// Update stores the semester if it is still at s.Version and bumps the
+	// version; otherwise it returns erptypes.ErrConflict.
 	Update(ctx context.Context, s *Semester) error
-	List(ctx context.Context, offset, limit int) ([]*Semester, int, error)
+	List(ctx context.Context, spec listquery.Spec) (listquery.Page[*Semester], error)
 
-	// AddSubjects links a set of subjects to the semester (idempotent).
-	AddSubjects(ctx context.Context, semesterID uuid.UUID, subjectIDs []uuid.UUID) error
+	// AddSubjects links a set of subjects to the semester (idempotent). Like
+	// Update it requires the semester to still be at s.Version and bumps it.
+	AddSubjects(ctx context.Context, s *Semester, subjectIDs []uuid.UUID) error
 	// GetSubjects returns all SemesterSubject rows for the given semester.
 	GetSubjects(ctx context.Context, semesterID uuid.UUID) ([]*SemesterSubject, error)
-	// SetTeacherAssignment assigns (or clears) a teacher for a semester subject.
-	SetTeacherAssignment(ctx context.Context, semesterID, subjectID uuid.UUID, teacherID *uuid.UUID) error
+	// SetTeacherAssignment assigns (or clears) a teacher for a semester
+	// subject, checking and bumping s.Version like Update. A subject not in
+	// the semester is erptypes.ErrNotFound.
+	SetTeacherAssignment(ctx context.Context, s *Semester, subjectID uuid.UUID, teacherID *uuid.UUID) error
 }
 
 // ScheduleRepository persists generated schedule versions and their assignments.
@@ -29,7 +57,9 @@ type ScheduleRepository interface {
 	FindBySemester(ctx context.Context, semesterID uuid.UUID, version int) (*Schedule, error)
 	// FindLatestBySemester retrieves the highest-version schedule for a semester.
 	FindLatestBySemester(ctx context.Context, semesterID uuid.UUID) (*Schedule, error)
-	// UpdateAssignment modifies a single assignment (manual override).
+	// UpdateAssignment modifies a single assignment (manual override) if it is
+	// still at a.Revision and bumps the revision; otherwise it returns
+	// erptypes.ErrConflict.
 	UpdateAssignment(ctx context.Context, a *Assignment) error
 	// FindAssignmentByID retrieves a single assignment.
 	FindAssignmentByID(ctx context.Context, id uuid.UUID) (*Assignment, error)
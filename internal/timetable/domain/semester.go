@@ -24,6 +24,7 @@ type Semester struct {
 	StartDate time.Time
 	EndDate   time.Time
 	Status    SemesterStatus
+	Version   int // optimistic lock, bumped by every update
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
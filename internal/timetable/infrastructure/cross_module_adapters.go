@@ -8,6 +8,7 @@ import (
 	hrDomain      "github.com/HuynhHoangPhuc/mcs-erp/internal/hr/domain"
 	roomDomain    "github.com/HuynhHoangPhuc/mcs-erp/internal/room/domain"
 	subjectDomain "github.com/HuynhHoangPhuc/mcs-erp/internal/subject/domain"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
 )
 
 // This file provides thin adapters that convert the real HR/Subject/Room
@@ -27,26 +28,18 @@ func NewTeacherRepoAdapter(repo hrDomain.TeacherRepository) *TeacherRepoAdapter
 	return &TeacherRepoAdapter{repo: repo}
 }
 
-// ListAll paginates through all teachers and returns minimal rows.
+// ListAll pages through all teachers and returns minimal rows.
 func (a *TeacherRepoAdapter) ListAll(ctx context.Context) ([]teacherRow, error) {
-	const pageSize = 200
-	var result []teacherRow
-	offset := 0
-	for {
-		teachers, _, err := a.repo.List(ctx, hrDomain.TeacherFilter{}, offset, pageSize)
-		if err != nil {
-			return nil, err
-		}
-		for _, t := range teachers {
-			result = append(result, teacherRow{
-				ID:             t.ID,
-				Qualifications: t.Qualifications,
-			})
-		}
-		if len(teachers) < pageSize {
-			break
+	teachers, err := listquery.All(ctx, listquery.Spec{Limit: listquery.MaxLimit}, a.repo.List)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]teacherRow, len(teachers))
+	for i, t := range teachers {
+		result[i] = teacherRow{
+			ID:             t.ID,
+			Qualifications: t.Qualifications,
 		}
-		offset += pageSize
 	}
 	return result, nil
 }
@@ -92,26 +85,18 @@ func NewSubjectRepoAdapter(repo subjectDomain.SubjectRepository) *SubjectRepoAda
 	return &SubjectRepoAdapter{repo: repo}
 }
 
-// ListAll paginates through all subjects and returns minimal rows.
+// ListAll pages through all subjects and returns minimal rows.
 func (a *SubjectRepoAdapter) ListAll(ctx context.Context) ([]subjectRow, error) {
-	const pageSize = 200
-	var result []subjectRow
-	offset := 0
-	for {
-		subjects, _, err := a.repo.List(ctx, offset, pageSize)
-		if err != nil {
-			return nil, err
-		}
-		for _, s := range subjects {
-			result = append(result, subjectRow{
-				ID:           s.ID,
-				HoursPerWeek: s.HoursPerWeek,
-			})
-		}
-		if len(subjects) < pageSize {
-			break
+	subjects, err := listquery.All(ctx, listquery.Spec{Limit: listquery.MaxLimit}, a.repo.List)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]subjectRow, len(subjects))
+	for i, s := range subjects {
+		result[i] = subjectRow{
+			ID:           s.ID,
+			HoursPerWeek: s.HoursPerWeek,
 		}
-		offset += pageSize
 	}
 	return result, nil
 }
@@ -128,9 +113,9 @@ func NewRoomRepoAdapter(repo roomDomain.RoomRepository) *RoomRepoAdapter {
 	return &RoomRepoAdapter{repo: repo}
 }
 
-// ListAll fetches all active rooms and returns minimal rows.
+// ListAll pages through all rooms and returns minimal rows.
 func (a *RoomRepoAdapter) ListAll(ctx context.Context) ([]roomRow, error) {
-	rooms, err := a.repo.List(ctx, roomDomain.ListFilter{})
+	rooms, err := listquery.All(ctx, listquery.Spec{Limit: listquery.MaxLimit}, a.repo.List)
 	if err != nil {
 		return nil, err
 	}
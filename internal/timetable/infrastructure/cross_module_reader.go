@@ -4,7 +4,10 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tracing"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/timetable/domain"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/timetable/scheduler"
 )
@@ -93,11 +96,34 @@ func NewCrossModuleReader(
 	}
 }
 
+var tracer = tracing.Tracer("mcs-erp/timetable")
+
 // BuildProblem assembles a scheduler.Problem for the given semester.
 // semesterSubjects constrains which subjects and teacher assignments to include.
 func (r *CrossModuleReader) BuildProblem(
 	ctx context.Context,
 	semesterSubjects []*domain.SemesterSubject,
+) (scheduler.Problem, error) {
+	ctx, span := tracer.Start(ctx, "timetable.BuildProblem",
+		trace.WithAttributes(attribute.Int("timetable.semester_subjects", len(semesterSubjects))))
+	defer span.End()
+
+	p, err := r.buildProblem(ctx, semesterSubjects)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return p, err
+	}
+	span.SetAttributes(
+		attribute.Int("timetable.subjects", len(p.Subjects)),
+		attribute.Int("timetable.teachers", len(p.Teachers)),
+		attribute.Int("timetable.rooms", len(p.Rooms)),
+	)
+	return p, nil
+}
+
+func (r *CrossModuleReader) buildProblem(
+	ctx context.Context,
+	semesterSubjects []*domain.SemesterSubject,
 ) (scheduler.Problem, error) {
 	// --- Subjects ---
 	allSubjects, err := r.subjects.ListAll(ctx)
@@ -2,6 +2,7 @@ package infrastructure
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/database"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/outbox"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/timetable/domain"
 	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
@@ -61,21 +63,29 @@ func (r *PostgresScheduleRepo) Save(ctx context.Context, sched *domain.Schedule)
 		}
 
 		// Bulk-insert assignments.
-		for _, a := range sched.Assignments {
+		for i := range sched.Assignments {
+			a := &sched.Assignments[i]
 			if a.ID == uuid.Nil {
 				a.ID = uuid.New()
 			}
-			if _, err := tx.Exec(ctx,
+			if err := tx.QueryRow(ctx,
 				`INSERT INTO assignments
 				   (id, semester_id, subject_id, teacher_id, room_id, day, period, version)
-				 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+				 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+				 RETURNING revision`,
 				a.ID, sched.SemesterID, a.SubjectID, a.TeacherID, a.RoomID,
 				a.Day, a.Period, sched.Version,
-			); err != nil {
+			).Scan(&a.Revision); err != nil {
 				return fmt.Errorf("insert assignment: %w", err)
 			}
 		}
-		return nil
+		return outbox.Write(ctx, tx, domain.TopicScheduleGenerated, domain.ScheduleGenerated{
+			SemesterID:     sched.SemesterID,
+			Version:        sched.Version,
+			HardViolations: sched.HardViolations,
+			SoftPenalty:    sched.SoftPenalty,
+			GeneratedAt:    sched.GeneratedAt,
+		})
 	})
 }
 
@@ -105,7 +115,7 @@ func (r *PostgresScheduleRepo) FindBySemester(
 		return r.loadAssignments(ctx, tx, semesterID, version, &sched)
 	})
 	if err != nil {
-		if err == pgx.ErrNoRows {
+		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, erptypes.ErrNotFound
 		}
 		return nil, fmt.Errorf("find schedule: %w", err)
@@ -137,7 +147,7 @@ func (r *PostgresScheduleRepo) FindLatestBySemester(
 		return r.loadAssignments(ctx, tx, semesterID, sched.Version, &sched)
 	})
 	if err != nil {
-		if err == pgx.ErrNoRows {
+		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, erptypes.ErrNotFound
 		}
 		return nil, fmt.Errorf("find latest schedule: %w", err)
@@ -153,13 +163,24 @@ func (r *PostgresScheduleRepo) UpdateAssignment(ctx context.Context, a *domain.A
 	}
 
 	return database.WithTenantTx(ctx, r.pool, schema, func(tx pgx.Tx) error {
-		_, err := tx.Exec(ctx,
+		err := tx.QueryRow(ctx,
 			`UPDATE assignments
-			 SET teacher_id = $2, room_id = $3, day = $4, period = $5
-			 WHERE id = $1`,
-			a.ID, a.TeacherID, a.RoomID, a.Day, a.Period,
-		)
-		return err
+			 SET teacher_id = $2, room_id = $3, day = $4, period = $5, revision = revision + 1
+			 WHERE id = $1 AND revision = $6
+			 RETURNING revision`,
+			a.ID, a.TeacherID, a.RoomID, a.Day, a.Period, a.Revision,
+		).Scan(&a.Revision)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return database.VersionMismatch(ctx, tx, "assignments", "revision", a.ID, a.Revision)
+		}
+		if err != nil {
+			return err
+		}
+		return outbox.Write(ctx, tx, domain.TopicAssignmentModified, domain.AssignmentModified{
+			AssignmentID: a.ID,
+			SemesterID:   a.SemesterID,
+			ModifiedAt:   time.Now(),
+		})
 	})
 }
 
@@ -175,14 +196,14 @@ func (r *PostgresScheduleRepo) FindAssignmentByID(
 	var a domain.Assignment
 	err = database.WithTenantTx(ctx, r.pool, schema, func(tx pgx.Tx) error {
 		return tx.QueryRow(ctx,
-			`SELECT id, semester_id, subject_id, teacher_id, room_id, day, period, version
+			`SELECT id, semester_id, subject_id, teacher_id, room_id, day, period, version, revision
 			 FROM assignments WHERE id = $1`,
 			id,
 		).Scan(&a.ID, &a.SemesterID, &a.SubjectID, &a.TeacherID, &a.RoomID,
-			&a.Day, &a.Period, &a.Version)
+			&a.Day, &a.Period, &a.Version, &a.Revision)
 	})
 	if err != nil {
-		if err == pgx.ErrNoRows {
+		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, erptypes.ErrNotFound
 		}
 		return nil, fmt.Errorf("find assignment by id: %w", err)
@@ -197,7 +218,7 @@ func (r *PostgresScheduleRepo) loadAssignments(
 	sched *domain.Schedule,
 ) error {
 	rows, err := tx.Query(ctx,
-		`SELECT id, semester_id, subject_id, teacher_id, room_id, day, period, version
+		`SELECT id, semester_id, subject_id, teacher_id, room_id, day, period, version, revision
 		 FROM assignments WHERE semester_id = $1 AND version = $2
 		 ORDER BY day, period`,
 		semesterID, version,
@@ -210,7 +231,7 @@ func (r *PostgresScheduleRepo) loadAssignments(
 	for rows.Next() {
 		var a domain.Assignment
 		if err := rows.Scan(&a.ID, &a.SemesterID, &a.SubjectID, &a.TeacherID,
-			&a.RoomID, &a.Day, &a.Period, &a.Version); err != nil {
+			&a.RoomID, &a.Day, &a.Period, &a.Version, &a.Revision); err != nil {
 			return err
 		}
 		sched.Assignments = append(sched.Assignments, a)
@@ -2,13 +2,18 @@ package infrastructure
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/database"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/history"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/outbox"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/timetable/domain"
 	"github.com/HuynhHoangPhuc/mcs-erp/pkg/erptypes"
@@ -29,6 +34,15 @@ func (r *PostgresSemesterRepo) schema(ctx context.Context) (string, error) {
 }
 
 func (r *PostgresSemesterRepo) FindByID(ctx context.Context, id uuid.UUID) (*domain.Semester, error) {
+	return r.findByID(ctx, "semesters", id)
+}
+
+// FindByIDAsOf returns the semester as it was at the given time.
+func (r *PostgresSemesterRepo) FindByIDAsOf(ctx context.Context, id uuid.UUID, at time.Time) (*domain.Semester, error) {
+	return r.findByID(ctx, history.AsOf("semesters", 2), id, at)
+}
+
+func (r *PostgresSemesterRepo) findByID(ctx context.Context, from string, args ...any) (*domain.Semester, error) {
 	schema, err := r.schema(ctx)
 	if err != nil {
 		return nil, err
@@ -37,13 +51,13 @@ func (r *PostgresSemesterRepo) FindByID(ctx context.Context, id uuid.UUID) (*dom
 	var s domain.Semester
 	err = database.WithTenantTx(ctx, r.pool, schema, func(tx pgx.Tx) error {
 		return tx.QueryRow(ctx,
-			`SELECT id, name, start_date, end_date, status, created_at, updated_at
-			 FROM semesters WHERE id = $1`,
-			id,
-		).Scan(&s.ID, &s.Name, &s.StartDate, &s.EndDate, &s.Status, &s.CreatedAt, &s.UpdatedAt)
+			`SELECT id, name, start_date, end_date, status, version, created_at, updated_at
+			 FROM `+from+` WHERE id = $1`,
+			args...,
+		).Scan(&s.ID, &s.Name, &s.StartDate, &s.EndDate, &s.Status, &s.Version, &s.CreatedAt, &s.UpdatedAt)
 	})
 	if err != nil {
-		if err == pgx.ErrNoRows {
+		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, erptypes.ErrNotFound
 		}
 		return nil, fmt.Errorf("find semester by id: %w", err)
@@ -58,12 +72,12 @@ func (r *PostgresSemesterRepo) Save(ctx context.Context, s *domain.Semester) err
 	}
 
 	return database.WithTenantTx(ctx, r.pool, schema, func(tx pgx.Tx) error {
-		_, err := tx.Exec(ctx,
+		return tx.QueryRow(ctx,
 			`INSERT INTO semesters (id, name, start_date, end_date, status, created_at, updated_at)
-			 VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			 VALUES ($1, $2, $3, $4, $5, $6, $7)
+			 RETURNING version`,
 			s.ID, s.Name, s.StartDate, s.EndDate, s.Status, s.CreatedAt, s.UpdatedAt,
-		)
-		return err
+		).Scan(&s.Version)
 	})
 }
 
@@ -74,69 +88,93 @@ func (r *PostgresSemesterRepo) Update(ctx context.Context, s *domain.Semester) e
 	}
 
 	return database.WithTenantTx(ctx, r.pool, schema, func(tx pgx.Tx) error {
-		_, err := tx.Exec(ctx,
+		var prevStatus domain.SemesterStatus
+		var current int
+		err := tx.QueryRow(ctx,
+			`SELECT status, version FROM semesters WHERE id = $1 FOR UPDATE`, s.ID,
+		).Scan(&prevStatus, &current)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return erptypes.ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if current != s.Version {
+			return fmt.Errorf("semesters: expected version %d, got %d: %w", s.Version, current, erptypes.ErrConflict)
+		}
+
+		err = tx.QueryRow(ctx,
 			`UPDATE semesters
-			 SET name = $2, start_date = $3, end_date = $4, status = $5, updated_at = now()
-			 WHERE id = $1`,
+			 SET name = $2, start_date = $3, end_date = $4, status = $5,
+			     version = version + 1, updated_at = now()
+			 WHERE id = $1
+			 RETURNING version, updated_at`,
 			s.ID, s.Name, s.StartDate, s.EndDate, s.Status,
-		)
-		return err
+		).Scan(&s.Version, &s.UpdatedAt)
+		if err != nil {
+			return err
+		}
+
+		// Approval is a status transition; emit ScheduleApproved for the latest version.
+		if s.Status != domain.SemesterStatusApproved || prevStatus == domain.SemesterStatusApproved {
+			return nil
+		}
+		var version int
+		if err := tx.QueryRow(ctx,
+			`SELECT COALESCE(MAX(version), 0) FROM schedules WHERE semester_id = $1`, s.ID,
+		).Scan(&version); err != nil {
+			return err
+		}
+		return outbox.Write(ctx, tx, domain.TopicScheduleApproved, domain.ScheduleApproved{
+			SemesterID: s.ID,
+			Version:    version,
+			ApprovedAt: time.Now(),
+		})
 	})
 }
 
-func (r *PostgresSemesterRepo) List(ctx context.Context, offset, limit int) ([]*domain.Semester, int, error) {
+func (r *PostgresSemesterRepo) List(ctx context.Context, spec listquery.Spec) (listquery.Page[*domain.Semester], error) {
 	schema, err := r.schema(ctx)
 	if err != nil {
-		return nil, 0, err
+		return listquery.Page[*domain.Semester]{}, err
+	}
+	q, err := listquery.Build(spec, domain.SemesterListSchema)
+	if err != nil {
+		return listquery.Page[*domain.Semester]{}, err
 	}
 
-	var semesters []*domain.Semester
-	var total int
-
+	var page listquery.Page[*domain.Semester]
 	err = database.WithTenantTx(ctx, r.pool, schema, func(tx pgx.Tx) error {
-		if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM semesters`).Scan(&total); err != nil {
-			return err
-		}
-
-		rows, err := tx.Query(ctx,
-			`SELECT id, name, start_date, end_date, status, created_at, updated_at
-			 FROM semesters ORDER BY created_at DESC LIMIT $1 OFFSET $2`,
-			limit, offset,
-		)
-		if err != nil {
-			return err
-		}
-		defer rows.Close()
-
-		for rows.Next() {
-			var s domain.Semester
-			if err := rows.Scan(&s.ID, &s.Name, &s.StartDate, &s.EndDate, &s.Status,
-				&s.CreatedAt, &s.UpdatedAt); err != nil {
-				return err
-			}
-			semesters = append(semesters, &s)
-		}
-		return rows.Err()
+		page, err = listquery.Fetch(ctx, tx, q, "id, name, start_date, end_date, status, version, created_at, updated_at", "semesters",
+			func(rows pgx.Rows, cursor *string) (*domain.Semester, error) {
+				var s domain.Semester
+				return &s, rows.Scan(&s.ID, &s.Name, &s.StartDate, &s.EndDate, &s.Status,
+					&s.Version, &s.CreatedAt, &s.UpdatedAt, cursor)
+			})
+		return err
 	})
 	if err != nil {
-		return nil, 0, fmt.Errorf("list semesters: %w", err)
+		return listquery.Page[*domain.Semester]{}, fmt.Errorf("list semesters: %w", err)
 	}
-	return semesters, total, nil
+	return page, nil
 }
 
-func (r *PostgresSemesterRepo) AddSubjects(ctx context.Context, semesterID uuid.UUID, subjectIDs []uuid.UUID) error {
+func (r *PostgresSemesterRepo) AddSubjects(ctx context.Context, s *domain.Semester, subjectIDs []uuid.UUID) error {
 	schema, err := r.schema(ctx)
 	if err != nil {
 		return err
 	}
 
 	return database.WithTenantTx(ctx, r.pool, schema, func(tx pgx.Tx) error {
+		if err := bumpVersion(ctx, tx, s); err != nil {
+			return err
+		}
 		for _, sid := range subjectIDs {
 			_, err := tx.Exec(ctx,
 				`INSERT INTO semester_subjects (semester_id, subject_id)
 				 VALUES ($1, $2)
 				 ON CONFLICT (semester_id, subject_id) DO NOTHING`,
-				semesterID, sid,
+				s.ID, sid,
 			)
 			if err != nil {
 				return fmt.Errorf("add subject %s: %w", sid, err)
@@ -180,7 +218,7 @@ func (r *PostgresSemesterRepo) GetSubjects(ctx context.Context, semesterID uuid.
 }
 
 func (r *PostgresSemesterRepo) SetTeacherAssignment(
-	ctx context.Context, semesterID, subjectID uuid.UUID, teacherID *uuid.UUID,
+	ctx context.Context, s *domain.Semester, subjectID uuid.UUID, teacherID *uuid.UUID,
 ) error {
 	schema, err := r.schema(ctx)
 	if err != nil {
@@ -188,13 +226,76 @@ func (r *PostgresSemesterRepo) SetTeacherAssignment(
 	}
 
 	return database.WithTenantTx(ctx, r.pool, schema, func(tx pgx.Tx) error {
-		_, err := tx.Exec(ctx,
+		if err := bumpVersion(ctx, tx, s); err != nil {
+			return err
+		}
+		tag, err := tx.Exec(ctx,
 			`UPDATE semester_subjects SET teacher_id = $3
 			 WHERE semester_id = $1 AND subject_id = $2`,
-			semesterID, subjectID, teacherID,
+			s.ID, subjectID, teacherID,
 		)
-		return err
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("subject %s is not in the semester: %w", subjectID, erptypes.ErrNotFound)
+		}
+		return nil
+	})
+}
+
+// bumpVersion increments the version of s in tx if it is still at s.Version,
+// for changes to the semester's subjects, which belong to the aggregate.
+func bumpVersion(ctx context.Context, tx pgx.Tx, s *domain.Semester) error {
+	err := tx.QueryRow(ctx,
+		`UPDATE semesters SET version = version + 1, updated_at = now()
+		 WHERE id = $1 AND version = $2
+		 RETURNING version, updated_at`,
+		s.ID, s.Version,
+	).Scan(&s.Version, &s.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return database.VersionMismatch(ctx, tx, "semesters", "version", s.ID, s.Version)
+	}
+	return err
+}
+
+// History returns every version of the semester, oldest first, or
+// erptypes.ErrNotFound when it never existed.
+func (r *PostgresSemesterRepo) History(ctx context.Context, id uuid.UUID) ([]history.Version[*domain.Semester], error) {
+	schema, err := r.schema(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []history.Version[*domain.Semester]
+	err = database.WithTenantTx(ctx, r.pool, schema, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx,
+			`SELECT valid_from, valid_to, id, name, start_date, end_date, status, created_at, updated_at
+			 FROM `+history.Versions("semesters", 1)+` ORDER BY valid_from`,
+			id.String(),
+		)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var s domain.Semester
+			v := history.Version[*domain.Semester]{Data: &s}
+			if err := rows.Scan(&v.ValidFrom, &v.ValidTo, &s.ID, &s.Name, &s.StartDate, &s.EndDate, &s.Status, &s.CreatedAt, &s.UpdatedAt); err != nil {
+				return err
+			}
+			versions = append(versions, v)
+		}
+		return rows.Err()
 	})
+	if err != nil {
+		return nil, fmt.Errorf("semester history: %w", err)
+	}
+	if len(versions) == 0 {
+		return nil, erptypes.ErrNotFound
+	}
+	return versions, nil
 }
 
 // Ensure interface compliance.
@@ -5,17 +5,27 @@ import (
 	"net/http"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	"google.golang.org/grpc"
 
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/core/application/services"
 	coredelivery "github.com/HuynhHoangPhuc/mcs-erp/internal/core/delivery"
 	coredomain "github.com/HuynhHoangPhuc/mcs-erp/internal/core/domain"
 	hrDomain      "github.com/HuynhHoangPhuc/mcs-erp/internal/hr/domain"
-	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/auth"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/database"
+	platformgrpc "github.com/HuynhHoangPhuc/mcs-erp/internal/platform/grpc"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/history"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/jobs"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/listquery"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/openapi"
 	roomDomain    "github.com/HuynhHoangPhuc/mcs-erp/internal/room/domain"
 	subjectDomain "github.com/HuynhHoangPhuc/mcs-erp/internal/subject/domain"
+	timetablesvc "github.com/HuynhHoangPhuc/mcs-erp/internal/timetable/application/services"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/timetable/delivery"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/timetable/domain"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/timetable/infrastructure"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/timetable/scheduler"
+	pkgmod "github.com/HuynhHoangPhuc/mcs-erp/pkg/module"
+	timetablev1 "github.com/HuynhHoangPhuc/mcs-erp/proto/gen/timetable/v1"
 )
 
 // Module implements pkg/module.Module for the Timetable scheduling module.
@@ -24,7 +34,9 @@ type Module struct {
 	authSvc        *services.AuthService
 	semesterRepo   domain.SemesterRepository
 	scheduleRepo   domain.ScheduleRepository
-	problemBuilder delivery.ProblemBuilder
+	generator      *timetablesvc.ScheduleGenerator
+	jobQueue       *jobs.Queue
+	routes         []pkgmod.Route
 }
 
 // NewModule creates the Timetable module with a pre-built ProblemBuilder.
@@ -32,17 +44,32 @@ type Module struct {
 func NewModule(
 	pool *pgxpool.Pool,
 	authSvc *services.AuthService,
-	problemBuilder delivery.ProblemBuilder,
+	problemBuilder timetablesvc.ProblemBuilder,
 ) *Module {
+	semesterRepo := infrastructure.NewPostgresSemesterRepo(pool)
+	scheduleRepo := infrastructure.NewPostgresScheduleRepo(pool)
 	return &Module{
-		pool:           pool,
-		authSvc:        authSvc,
-		semesterRepo:   infrastructure.NewPostgresSemesterRepo(pool),
-		scheduleRepo:   infrastructure.NewPostgresScheduleRepo(pool),
-		problemBuilder: problemBuilder,
+		pool:         pool,
+		authSvc:      authSvc,
+		semesterRepo: semesterRepo,
+		scheduleRepo: scheduleRepo,
+		generator:    timetablesvc.NewScheduleGenerator(semesterRepo, scheduleRepo, problemBuilder),
 	}
 }
 
+// SetSchedulerConfig tunes schedule generation (REST and gRPC); see
+// ScheduleGenerator.Configure.
+func (m *Module) SetSchedulerConfig(cfg scheduler.SAConfig, workers int) {
+	m.generator.Configure(cfg, workers)
+}
+
+// SetJobQueue registers schedule generation as a background job and enables
+// the REST generate route, which queues it and answers 202 Accepted.
+func (m *Module) SetJobQueue(q *jobs.Queue) {
+	jobs.Register(q, m.generator.JobDefinition())
+	m.jobQueue = q
+}
+
 // NewModuleWithRepos is a convenience constructor that wires cross-module
 // adapters internally. Pass concrete repo instances from the hr/subject/room
 // modules; no manual adapter creation needed in main.go.
@@ -63,40 +90,85 @@ func NewModuleWithRepos(
 
 func (m *Module) Name() string           { return "timetable" }
 func (m *Module) Dependencies() []string { return []string{"core", "hr", "subject", "room"} }
-func (m *Module) Migrate(_ context.Context) error        { return nil }
+// Migrate applies the module's pending migrations to _template; tenants follow via database.TenantRunner.
+func (m *Module) Migrate(ctx context.Context) error {
+	return database.NewMigrator(m.pool).MigrateModule(ctx, m.Name())
+}
 func (m *Module) RegisterEvents(_ context.Context) error { return nil }
 
+// RegisterGRPC exposes read access to semesters and schedules (PermTimetableRead)
+// and streamed schedule generation (PermTimetableWrite).
+func (m *Module) RegisterGRPC(s grpc.ServiceRegistrar) map[string]string {
+	timetablev1.RegisterSemesterServiceServer(s, delivery.NewSemesterGRPCServer(m.semesterRepo))
+	timetablev1.RegisterScheduleServiceServer(s, delivery.NewScheduleGRPCServer(m.scheduleRepo, m.generator))
+
+	perms := platformgrpc.ServicePermissions(&timetablev1.SemesterService_ServiceDesc, coredomain.PermTimetableRead)
+	for method, perm := range platformgrpc.ServicePermissions(&timetablev1.ScheduleService_ServiceDesc, coredomain.PermTimetableRead) {
+		perms[method] = perm
+	}
+	perms[timetablev1.ScheduleService_GenerateSchedule_FullMethodName] = coredomain.PermTimetableWrite
+	return perms
+}
+
+// Routes describes the routes mounted by RegisterRoutes.
+func (m *Module) Routes() []pkgmod.Route { return m.routes }
+
 func (m *Module) RegisterRoutes(mux *http.ServeMux) {
 	semHandler := delivery.NewSemesterHandler(m.semesterRepo)
-	schedHandler := delivery.NewScheduleHandler(m.semesterRepo, m.scheduleRepo, m.problemBuilder)
+	schedHandler := delivery.NewScheduleHandler(m.semesterRepo, m.scheduleRepo, m.generator, m.jobQueue)
 
-	authMw := coredelivery.AuthMiddleware(m.authSvc)
-	read  := auth.RequirePermission(coredomain.PermTimetableRead)
-	write := auth.RequirePermission(coredomain.PermTimetableWrite)
+	api := openapi.NewRouter(mux, m.Name(), coredelivery.ModuleAuthMiddleware(m.authSvc, m.Name()))
+	read, write := coredomain.PermTimetableRead, coredomain.PermTimetableWrite
 
 	// Semester CRUD
-	mux.Handle("POST /api/v1/timetable/semesters",
-		authMw(write(http.HandlerFunc(semHandler.CreateSemester))))
-	mux.Handle("GET /api/v1/timetable/semesters",
-		authMw(read(http.HandlerFunc(semHandler.ListSemesters))))
-	mux.Handle("GET /api/v1/timetable/semesters/{id}",
-		authMw(read(http.HandlerFunc(semHandler.GetSemester))))
+	api.Handle(pkgmod.Route{
+		Method: "POST", Path: "/api/v1/timetable/semesters", Summary: "Create a semester", Permission: write,
+		Request: delivery.CreateSemesterRequest{}, Response: delivery.SemesterResponse{}, Status: http.StatusCreated,
+	}, semHandler.CreateSemester)
+	api.Handle(pkgmod.Route{
+		Method: "GET", Path: "/api/v1/timetable/semesters", Summary: "List semesters", Permission: read,
+		Query: openapi.ListParams(domain.SemesterListSchema), Response: listquery.Page[delivery.SemesterResponse]{},
+	}, semHandler.ListSemesters)
+	api.Handle(pkgmod.Route{
+		Method: "GET", Path: "/api/v1/timetable/semesters/{id}", Summary: "Get a semester", Permission: read,
+		Query: []pkgmod.Param{openapi.AsOf}, Response: delivery.SemesterResponse{},
+	}, semHandler.GetSemester)
+	api.Handle(pkgmod.Route{
+		Method: "GET", Path: "/api/v1/timetable/semesters/{id}/history", Summary: "List a semester's versions", Permission: read,
+		Response: history.List[delivery.SemesterResponse]{},
+	}, semHandler.SemesterHistory)
 
 	// Semester subject management
-	mux.Handle("POST /api/v1/timetable/semesters/{id}/subjects",
-		authMw(write(http.HandlerFunc(semHandler.SetSubjects))))
-	mux.Handle("POST /api/v1/timetable/semesters/{id}/subjects/{subjectId}/teacher",
-		authMw(write(http.HandlerFunc(semHandler.AssignTeacher))))
+	api.Handle(pkgmod.Route{
+		Method: "POST", Path: "/api/v1/timetable/semesters/{id}/subjects", Summary: "Add subjects to a semester", Permission: write,
+		Request: delivery.SetSubjectsRequest{}, Response: delivery.SetSubjectsResponse{},
+	}, semHandler.SetSubjects)
+	api.Handle(pkgmod.Route{
+		Method: "POST", Path: "/api/v1/timetable/semesters/{id}/subjects/{subjectId}/teacher", Summary: "Assign the teacher of a semester subject", Permission: write,
+		Request: delivery.AssignTeacherRequest{}, Response: map[string]bool{},
+	}, semHandler.AssignTeacher)
 
 	// Schedule generation, retrieval, approval
-	mux.Handle("POST /api/v1/timetable/semesters/{id}/generate",
-		authMw(write(http.HandlerFunc(schedHandler.GenerateSchedule))))
-	mux.Handle("GET /api/v1/timetable/semesters/{id}/schedule",
-		authMw(read(http.HandlerFunc(schedHandler.GetLatestSchedule))))
-	mux.Handle("POST /api/v1/timetable/semesters/{id}/approve",
-		authMw(write(http.HandlerFunc(schedHandler.ApproveSchedule))))
+	if m.jobQueue != nil {
+		api.Handle(pkgmod.Route{
+			Method: "POST", Path: "/api/v1/timetable/semesters/{id}/generate", Summary: "Queue schedule generation", Permission: write,
+			Response: jobs.Job{}, Status: http.StatusAccepted, RateClass: pkgmod.RateCompute,
+		}, schedHandler.GenerateSchedule)
+	}
+	api.Handle(pkgmod.Route{
+		Method: "GET", Path: "/api/v1/timetable/semesters/{id}/schedule", Summary: "Get the latest schedule", Permission: read,
+		Response: delivery.ScheduleResponse{},
+	}, schedHandler.GetLatestSchedule)
+	api.Handle(pkgmod.Route{
+		Method: "POST", Path: "/api/v1/timetable/semesters/{id}/approve", Summary: "Approve the latest schedule", Permission: write,
+		Response: delivery.StatusResponse{},
+	}, schedHandler.ApproveSchedule)
 
 	// Manual assignment override
-	mux.Handle("PUT /api/v1/timetable/assignments/{id}",
-		authMw(write(http.HandlerFunc(schedHandler.UpdateAssignment))))
+	api.Handle(pkgmod.Route{
+		Method: "PUT", Path: "/api/v1/timetable/assignments/{id}", Summary: "Move an assignment", Permission: write,
+		Request: delivery.UpdateAssignmentRequest{}, Response: delivery.AssignmentResponse{},
+	}, schedHandler.UpdateAssignment)
+
+	m.routes = api.Routes()
 }
@@ -0,0 +1,138 @@
+//go:build integration
+
+package timetable_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	coredomain "github.com/HuynhHoangPhuc/mcs-erp/internal/core/domain"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/testutil"
+	timetabledomain "github.com/HuynhHoangPhuc/mcs-erp/internal/timetable/domain"
+	timetableinfra "github.com/HuynhHoangPhuc/mcs-erp/internal/timetable/infrastructure"
+	timetablescheduler "github.com/HuynhHoangPhuc/mcs-erp/internal/timetable/scheduler"
+	timetablev1 "github.com/HuynhHoangPhuc/mcs-erp/proto/gen/timetable/v1"
+)
+
+func TestGenerateScheduleGRPC_StreamsProgressAndSchedule(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	schema := db.CreateTenantSchema(t)
+	srv := testutil.TestServer(t, db.Pool)
+	defer srv.Close()
+
+	semesterID := seedSchedulableSemester(t, db, schema)
+	client := timetablev1.NewScheduleServiceClient(testutil.TestGRPCClient(t, db.Pool))
+
+	readToken := testutil.GenerateTestToken(t, uuid.New(), schema, []string{coredomain.PermTimetableRead})
+	readOnly, err := client.GenerateSchedule(testutil.GRPCContext(context.Background(), readToken, schema), &timetablev1.GenerateScheduleRequest{SemesterId: semesterID.String()})
+	if err == nil {
+		_, err = readOnly.Recv()
+	}
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied without write permission, got %v", err)
+	}
+
+	token := testutil.GenerateTestToken(t, uuid.New(), schema, []string{coredomain.PermTimetableWrite})
+	stream, err := client.GenerateSchedule(testutil.GRPCContext(context.Background(), token, schema), &timetablev1.GenerateScheduleRequest{SemesterId: semesterID.String()})
+	if err != nil {
+		t.Fatalf("start generation: %v", err)
+	}
+
+	progress := 0
+	var sched *timetablev1.Schedule
+	for {
+		msg, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("receive: %v", err)
+		}
+		if sched != nil {
+			t.Fatal("expected schedule to be the last message")
+		}
+		switch ev := msg.GetEvent().(type) {
+		case *timetablev1.GenerateScheduleResponse_Progress:
+			progress++
+		case *timetablev1.GenerateScheduleResponse_Schedule:
+			sched = ev.Schedule
+		}
+	}
+	if progress == 0 {
+		t.Fatal("expected at least one progress message")
+	}
+	if sched == nil || len(sched.GetAssignments()) == 0 {
+		t.Fatalf("expected a saved schedule with assignments, got %v", sched)
+	}
+
+	saved, err := timetableinfra.NewPostgresScheduleRepo(db.Pool).FindLatestBySemester(tenant.WithTenant(context.Background(), schema), semesterID)
+	if err != nil {
+		t.Fatalf("load saved schedule: %v", err)
+	}
+	if int32(saved.Version) != sched.GetVersion() {
+		t.Fatalf("expected streamed version %d to be saved, got %d", sched.GetVersion(), saved.Version)
+	}
+}
+
+func TestParallelAnnealContext_CancelStopsWorkers(t *testing.T) {
+	teacherID := uuid.New()
+	available := map[timetabledomain.TimeSlot]bool{}
+	for _, slot := range timetabledomain.AllSlots() {
+		available[slot] = true
+	}
+	problem := timetablescheduler.Problem{
+		Subjects: []timetablescheduler.SubjectInfo{{ID: uuid.New(), HoursPerWeek: 3}, {ID: uuid.New(), HoursPerWeek: 2}},
+		Teachers: []timetablescheduler.TeacherInfo{{ID: teacherID, Available: available}},
+		Rooms:    []timetablescheduler.RoomInfo{{ID: uuid.New(), Capacity: 30, Available: available}},
+		Slots:    timetabledomain.AllSlots(),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var mu sync.Mutex
+	var reports []timetablescheduler.Progress
+	best, err := timetablescheduler.ParallelAnnealContext(ctx, problem, timetablescheduler.DefaultSAConfig(), 2, func(p timetablescheduler.Progress) {
+		mu.Lock()
+		reports = append(reports, p)
+		mu.Unlock()
+		cancel()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if best != nil {
+		t.Fatalf("expected no result after cancel, got %d assignments", len(best))
+	}
+	if len(reports) == 0 || len(reports) > 2 {
+		t.Fatalf("expected workers to stop soon after the first report, got %d reports", len(reports))
+	}
+}
+
+func seedSchedulableSemester(t *testing.T, db *testutil.TestDB, schema string) uuid.UUID {
+	t.Helper()
+
+	_ = testutil.SeedTeacher(t, db.Pool, schema)
+	_ = testutil.SeedRoom(t, db.Pool, schema)
+	subjects := []uuid.UUID{
+		testutil.SeedSubject(t, db.Pool, schema).ID,
+		testutil.SeedSubject(t, db.Pool, schema).ID,
+	}
+	ctx := tenant.WithTenant(context.Background(), schema)
+	repo := timetableinfra.NewPostgresSemesterRepo(db.Pool)
+	sem, err := repo.FindByID(ctx, testutil.SeedSemester(t, db.Pool, schema).ID)
+	if err != nil {
+		t.Fatalf("load semester: %v", err)
+	}
+	if err := repo.AddSubjects(ctx, sem, subjects); err != nil {
+		t.Fatalf("add semester subjects: %v", err)
+	}
+	return sem.ID
+}
@@ -1,12 +1,26 @@
 package scheduler
 
 import (
+	"context"
 	"math"
 	"math/rand"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/timetable/domain"
 )
 
+const (
+	// progressEvery is how many iterations pass between progress reports.
+	progressEvery = 1000
+	// cancelCheckEvery is how many iterations pass between context checks.
+	cancelCheckEvery = 256
+)
+
+// reportFunc receives a worker's best assignments so far.
+type reportFunc func(iter int, temperature float64, best []domain.Assignment, bestCost int)
+
 // Anneal runs simulated annealing starting from the given assignments.
 // It uses the standard SA acceptance criterion:
 //   - always accept improvements (delta < 0)
@@ -14,6 +28,14 @@ import (
 //
 // Returns the best assignment set found over the entire run.
 func Anneal(assignments []domain.Assignment, p Problem, cfg SAConfig, rng *rand.Rand) []domain.Assignment {
+	best, _ := anneal(context.Background(), assignments, p, cfg, rng, nil)
+	return best
+}
+
+// anneal is Anneal with cancellation and optional progress reports every
+// progressEvery iterations and once at the end. On cancellation it returns
+// the best result so far together with ctx.Err().
+func anneal(ctx context.Context, assignments []domain.Assignment, p Problem, cfg SAConfig, rng *rand.Rand, report reportFunc) ([]domain.Assignment, error) {
 	hard := BuildHardConstraints(p)
 	soft := BuildSoftConstraints()
 
@@ -27,7 +49,17 @@ func Anneal(assignments []domain.Assignment, p Problem, cfg SAConfig, rng *rand.
 
 	T := cfg.TInitial
 
-	for iter := 0; iter < cfg.MaxIter && T > cfg.TMin; iter++ {
+	iter := 0
+	for ; iter < cfg.MaxIter && T > cfg.TMin; iter++ {
+		if iter%cancelCheckEvery == 0 {
+			if err := ctx.Err(); err != nil {
+				return best, err
+			}
+		}
+		if report != nil && iter > 0 && iter%progressEvery == 0 {
+			report(iter, T, best, bestCost)
+		}
+
 		candidate := Neighbor(current, p, rng)
 		candidateCost := Cost(candidate, hard, soft)
 
@@ -48,5 +80,13 @@ func Anneal(assignments []domain.Assignment, p Problem, cfg SAConfig, rng *rand.
 		T *= cfg.CoolingRate
 	}
 
-	return best
+	if report != nil {
+		report(iter, T, best, bestCost)
+	}
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.Int("scheduler.iterations", iter),
+		attribute.Float64("scheduler.temperature", T),
+		attribute.Int("scheduler.best_cost", bestCost),
+	)
+	return best, nil
 }
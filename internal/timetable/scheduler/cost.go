@@ -18,3 +18,14 @@ func Cost(assignments []domain.Assignment, hard, soft []domain.Constraint) int {
 	}
 	return total
 }
+
+// Violations returns the unweighted hard-violation count and soft penalty.
+func Violations(assignments []domain.Assignment, hard, soft []domain.Constraint) (hardViolations, softPenalty int) {
+	for _, c := range hard {
+		hardViolations += c.Evaluate(assignments)
+	}
+	for _, c := range soft {
+		softPenalty += c.Evaluate(assignments)
+	}
+	return hardViolations, softPenalty
+}
@@ -1,13 +1,37 @@
 package scheduler
 
 import (
+	"context"
 	"math/rand"
 	"runtime"
 	"sync"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/timetable/domain"
 )
 
+// tracer uses the OpenTelemetry API directly so the engine stays free of
+// platform imports; spans are no-ops until a provider is installed.
+var tracer = otel.Tracer("mcs-erp/scheduler")
+
+// Progress is a periodic snapshot of one annealing worker's best result.
+type Progress struct {
+	Worker         int
+	Iteration      int
+	Temperature    float64
+	BestCost       int
+	HardViolations int
+	SoftPenalty    int
+}
+
+// ProgressFunc receives worker progress. It is called concurrently from every
+// worker goroutine and must not block for long.
+type ProgressFunc func(Progress)
+
 // workerResult carries a single worker's best schedule and its cost.
 type workerResult struct {
 	assignments []domain.Assignment
@@ -18,10 +42,24 @@ type workerResult struct {
 // running GreedyAssign + Anneal independently, and returns the best result.
 // If numWorkers <= 0, it defaults to runtime.NumCPU().
 func ParallelAnneal(p Problem, cfg SAConfig, numWorkers int) []domain.Assignment {
+	best, _ := ParallelAnnealContext(context.Background(), p, cfg, numWorkers, nil)
+	return best
+}
+
+// ParallelAnnealContext is ParallelAnneal with cancellation and progress
+// reporting. Cancelling ctx stops every worker; the error is then ctx.Err().
+// progress may be nil.
+func ParallelAnnealContext(ctx context.Context, p Problem, cfg SAConfig, numWorkers int, progress ProgressFunc) ([]domain.Assignment, error) {
 	if numWorkers <= 0 {
 		numWorkers = runtime.NumCPU()
 	}
 
+	ctx, span := tracer.Start(ctx, "scheduler.ParallelAnneal", trace.WithAttributes(
+		attribute.Int("scheduler.workers", numWorkers),
+		attribute.Int("scheduler.subjects", len(p.Subjects)),
+	))
+	defer span.End()
+
 	hard := BuildHardConstraints(p)
 	soft := BuildSoftConstraints()
 
@@ -31,16 +69,48 @@ func ParallelAnneal(p Problem, cfg SAConfig, numWorkers int) []domain.Assignment
 	for w := 0; w < numWorkers; w++ {
 		wg.Add(1)
 		seed := int64(w*1_000_003 + 42) // deterministic but distinct per worker
-		go func(seed int64) {
+		go func(worker int, seed int64) {
 			defer wg.Done()
+			var report reportFunc
+			if progress != nil {
+				report = func(iter int, temperature float64, best []domain.Assignment, bestCost int) {
+					hardViolations, softPenalty := Violations(best, hard, soft)
+					progress(Progress{
+						Worker:         worker,
+						Iteration:      iter,
+						Temperature:    temperature,
+						BestCost:       bestCost,
+						HardViolations: hardViolations,
+						SoftPenalty:    softPenalty,
+					})
+				}
+			}
+			ctx, span := tracer.Start(ctx, "scheduler.worker", trace.WithAttributes(attribute.Int("scheduler.worker", worker)))
+			defer span.End()
+
 			rng := rand.New(rand.NewSource(seed))
+			_, greedySpan := tracer.Start(ctx, "scheduler.greedy")
 			initial := GreedyAssign(p)
-			best := Anneal(initial, p, cfg, rng)
+			greedySpan.End()
+
+			annealCtx, annealSpan := tracer.Start(ctx, "scheduler.anneal")
+			best, err := anneal(annealCtx, initial, p, cfg, rng, report)
+			annealSpan.End()
+			if err != nil {
+				span.SetStatus(codes.Error, err.Error())
+				return
+			}
+
+			_, costSpan := tracer.Start(ctx, "scheduler.cost")
+			cost := Cost(best, hard, soft)
+			costSpan.SetAttributes(attribute.Int("scheduler.cost", cost))
+			costSpan.End()
+
 			results <- workerResult{
 				assignments: best,
-				cost:        Cost(best, hard, soft),
+				cost:        cost,
 			}
-		}(seed)
+		}(w, seed)
 	}
 
 	// Close channel once all workers finish.
@@ -60,5 +130,10 @@ func ParallelAnneal(p Problem, cfg SAConfig, numWorkers int) []domain.Assignment
 		}
 	}
 
-	return best
+	if err := ctx.Err(); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("scheduler.best_cost", bestCost))
+	return best, nil
 }
@@ -4,13 +4,18 @@ package timetable_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"testing"
 	"time"
 
+	hrinfra "github.com/HuynhHoangPhuc/mcs-erp/internal/hr/infrastructure"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
+	roominfra "github.com/HuynhHoangPhuc/mcs-erp/internal/room/infrastructure"
+	subjectinfra "github.com/HuynhHoangPhuc/mcs-erp/internal/subject/infrastructure"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/testutil"
+	timetablesvc "github.com/HuynhHoangPhuc/mcs-erp/internal/timetable/application/services"
 	timetabledomain "github.com/HuynhHoangPhuc/mcs-erp/internal/timetable/domain"
 	timetableinfra "github.com/HuynhHoangPhuc/mcs-erp/internal/timetable/infrastructure"
 	timetablescheduler "github.com/HuynhHoangPhuc/mcs-erp/internal/timetable/scheduler"
@@ -40,18 +45,20 @@ func TestScheduleGenerationAndApprovalFlow(t *testing.T) {
 		"teacher_id": teacherA.ID.String(),
 	})), http.StatusOK)
 
-	start := time.Now()
-	generated := getJSON(t, mustAuthReq(t, http.MethodPost, srv.URL+"/api/v1/timetable/semesters/"+semesterID+"/generate", token, schema, nil), http.StatusOK)
-	elapsed := time.Since(start)
-	if elapsed > 20*time.Second {
-		t.Fatalf("expected schedule generation to finish within sanity bound, got %v", elapsed)
+	// Generation is queued: 202 with the job, then the job's result.
+	queued := getJSON(t, mustAuthReq(t, http.MethodPost, srv.URL+"/api/v1/timetable/semesters/"+semesterID+"/generate", token, schema, nil), http.StatusAccepted)
+	if queued["kind"] != "timetable.generate" {
+		t.Fatalf("expected a timetable.generate job, got %v", queued)
 	}
+	job := waitForJob(t, srv.URL, token, schema, fmt.Sprint(queued["id"]), 60*time.Second)
+	if job["status"] != "succeeded" {
+		t.Fatalf("expected generation job to succeed, got %v (error %v)", job["status"], job["error"])
+	}
+	generated, _ := job["result"].(map[string]any)
 	if int(generated["hard_violations"].(float64)) != 0 {
 		t.Fatalf("expected 0 hard violations after generation, got %v", generated["hard_violations"])
 	}
-
-	assignmentsAny, ok := generated["assignments"].([]any)
-	if !ok || len(assignmentsAny) == 0 {
+	if n, _ := generated["assignments"].(float64); n == 0 {
 		t.Fatalf("expected non-empty generated assignments, got %v", generated["assignments"])
 	}
 
@@ -124,6 +131,110 @@ func TestScheduleGenerationValidationErrors(t *testing.T) {
 	_ = getJSON(t, mustAuthReq(t, http.MethodPost, srv.URL+"/api/v1/timetable/semesters/not-a-uuid/generate", token, schema, nil), http.StatusBadRequest)
 }
 
+// editingBuilder edits the semester while the generator builds its problem.
+type editingBuilder struct {
+	timetablesvc.ProblemBuilder
+	edit func(ctx context.Context)
+}
+
+func (b editingBuilder) BuildProblem(ctx context.Context, subjects []*timetabledomain.SemesterSubject) (timetablescheduler.Problem, error) {
+	b.edit(ctx)
+	return b.ProblemBuilder.BuildProblem(ctx, subjects)
+}
+
+func TestScheduleGenerator_SemesterEditedDuringRun(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	schema := db.CreateTenantSchema(t)
+	ctx := tenant.WithTenant(context.Background(), schema)
+	semesterID := seedSchedulableSemester(t, db, schema)
+
+	semesterRepo := timetableinfra.NewPostgresSemesterRepo(db.Pool)
+	reader := timetableinfra.NewCrossModuleReaderFromRepos(
+		hrinfra.NewPostgresTeacherRepo(db.Pool),
+		hrinfra.NewPostgresAvailabilityRepo(db.Pool),
+		subjectinfra.NewPostgresSubjectRepo(db.Pool),
+		roominfra.NewPostgresRoomRepo(db.Pool),
+		roominfra.NewPostgresAvailabilityRepo(db.Pool),
+	)
+	builder := editingBuilder{ProblemBuilder: reader, edit: func(ctx context.Context) {
+		sem, err := semesterRepo.FindByID(ctx, semesterID)
+		if err != nil {
+			t.Errorf("load semester during run: %v", err)
+			return
+		}
+		sem.Name = "Renamed during run"
+		if err := semesterRepo.Update(ctx, sem); err != nil {
+			t.Errorf("edit semester during run: %v", err)
+		}
+	}}
+	generator := timetablesvc.NewScheduleGenerator(semesterRepo, timetableinfra.NewPostgresScheduleRepo(db.Pool), builder)
+
+	if _, err := generator.Generate(ctx, semesterID, nil); err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	sem, err := semesterRepo.FindByID(ctx, semesterID)
+	if err != nil {
+		t.Fatalf("load semester: %v", err)
+	}
+	if sem.Status != timetabledomain.SemesterStatusReview || sem.Name != "Renamed during run" {
+		t.Fatalf("expected the edited semester in review, got %q in %s", sem.Name, sem.Status)
+	}
+}
+
+// failingBuilder fails every run after the semester was marked scheduling.
+type failingBuilder struct{}
+
+func (failingBuilder) BuildProblem(context.Context, []*timetabledomain.SemesterSubject) (timetablescheduler.Problem, error) {
+	return timetablescheduler.Problem{}, errors.New("builder unavailable")
+}
+
+func TestScheduleGenerator_RetryAfterInterruptedRunRestoresStatus(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	schema := db.CreateTenantSchema(t)
+	ctx := tenant.WithTenant(context.Background(), schema)
+	semesterID := seedSchedulableSemester(t, db, schema)
+
+	// A run that died left the semester in scheduling.
+	semesterRepo := timetableinfra.NewPostgresSemesterRepo(db.Pool)
+	sem, err := semesterRepo.FindByID(ctx, semesterID)
+	if err != nil {
+		t.Fatalf("load semester: %v", err)
+	}
+	sem.Status = timetabledomain.SemesterStatusScheduling
+	if err := semesterRepo.Update(ctx, sem); err != nil {
+		t.Fatalf("mark semester scheduling: %v", err)
+	}
+
+	generator := timetablesvc.NewScheduleGenerator(semesterRepo, timetableinfra.NewPostgresScheduleRepo(db.Pool), failingBuilder{})
+	if _, err := generator.Generate(ctx, semesterID, nil); err == nil {
+		t.Fatal("expected generate to fail")
+	}
+	sem, err = semesterRepo.FindByID(ctx, semesterID)
+	if err != nil {
+		t.Fatalf("load semester: %v", err)
+	}
+	if sem.Status != timetabledomain.SemesterStatusDraft {
+		t.Fatalf("expected a semester without schedules back in draft, got %s", sem.Status)
+	}
+}
+
+// waitForJob polls the job status API until the job finishes or timeout passes.
+func waitForJob(t *testing.T, baseURL, token, schema, id string, timeout time.Duration) map[string]any {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for {
+		job := getJSON(t, mustAuthReq(t, http.MethodGet, baseURL+"/api/v1/jobs/"+id, token, schema, nil), http.StatusOK)
+		switch job["status"] {
+		case "succeeded", "failed", "cancelled":
+			return job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job %s still %v after %s", id, job["status"], timeout)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
 func assertNoDoubleBookingConflicts(t *testing.T, sched *timetabledomain.Schedule) {
 	t.Helper()
 	violations := timetablescheduler.TeacherConflictConstraint{}.Evaluate(sched.Assignments) +
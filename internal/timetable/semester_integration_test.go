@@ -14,6 +14,7 @@ import (
 	"github.com/google/uuid"
 
 	coredomain "github.com/HuynhHoangPhuc/mcs-erp/internal/core/domain"
+	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/etag"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/platform/tenant"
 	"github.com/HuynhHoangPhuc/mcs-erp/internal/testutil"
 	timetabledomain "github.com/HuynhHoangPhuc/mcs-erp/internal/timetable/domain"
@@ -74,6 +75,29 @@ func TestSemesterCRUDAndSubjectManagement(t *testing.T) {
 		t.Fatalf("expected subject %s assigned to teacher %s", subjectA.ID, teacher.ID)
 	}
 
+	// Subject changes are semester changes: each bumped the version, so the
+	// ETag from creation is stale.
+	stale := mustAuthReq(t, http.MethodPost, srv.URL+"/api/v1/timetable/semesters/"+semesterID+"/subjects", token, schema, jsonBody(t, map[string]any{
+		"subject_ids": []string{subjectB.ID.String()},
+	}))
+	stale.Header.Set(etag.IfMatchHeader, etag.Of(1))
+	_ = getJSON(t, stale, http.StatusPreconditionFailed)
+	current := mustAuthReq(t, http.MethodPost, srv.URL+"/api/v1/timetable/semesters/"+semesterID+"/subjects/"+subjectB.ID.String()+"/teacher", token, schema, jsonBody(t, map[string]any{
+		"teacher_id": teacher.ID.String(),
+	}))
+	current.Header.Set(etag.IfMatchHeader, etag.Of(3))
+	resp, err := http.DefaultClient.Do(current)
+	if err != nil {
+		t.Fatalf("assign teacher: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get(etag.Header) != etag.Of(4) {
+		t.Fatalf("expected 200 with ETag %s, got %d %q", etag.Of(4), resp.StatusCode, resp.Header.Get(etag.Header))
+	}
+	_ = getJSON(t, mustAuthReq(t, http.MethodPost, srv.URL+"/api/v1/timetable/semesters/"+semesterID+"/subjects/"+uuid.NewString()+"/teacher", token, schema, jsonBody(t, map[string]any{
+		"teacher_id": teacher.ID.String(),
+	})), http.StatusNotFound)
+
 	_ = getJSON(t, mustAuthReq(t, http.MethodPost, srv.URL+"/api/v1/timetable/semesters/"+semesterID+"/subjects", token, schema, jsonBody(t, map[string]any{
 		"subject_ids": []string{"not-a-uuid"},
 	})), http.StatusBadRequest)
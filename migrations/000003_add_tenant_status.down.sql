@@ -0,0 +1 @@
+ALTER TABLE public.tenants DROP COLUMN IF EXISTS status;
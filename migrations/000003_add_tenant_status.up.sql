@@ -0,0 +1,4 @@
+-- Tenant lifecycle state. A tenant is only routable once provisioning finished
+-- and is_active is set; rows stuck in 'provisioning' are safe to resume or purge.
+ALTER TABLE public.tenants
+    ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active';
@@ -0,0 +1,11 @@
+-- Outcome of the last migration run per tenant. Tenants in 'failed' are
+-- quarantined: they get a maintenance response until a retry succeeds.
+CREATE TABLE IF NOT EXISTS public.tenant_migration_status (
+    schema_name VARCHAR(63) PRIMARY KEY REFERENCES public.tenants(schema_name) ON DELETE CASCADE,
+    status      VARCHAR(20) NOT NULL DEFAULT 'pending',
+    last_error  TEXT        NOT NULL DEFAULT '',
+    attempts    INT         NOT NULL DEFAULT 0,
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_tenant_migration_status_status ON public.tenant_migration_status(status);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS public.tenant_settings;
@@ -0,0 +1,9 @@
+-- Per-tenant module switches and feature flags. A module missing from
+-- `modules` is enabled; a flag missing from `features` is off. Tenants without
+-- a row get every module and no flags.
+CREATE TABLE IF NOT EXISTS public.tenant_settings (
+    schema_name VARCHAR(63) PRIMARY KEY REFERENCES public.tenants(schema_name) ON DELETE CASCADE,
+    modules     JSONB       NOT NULL DEFAULT '{}',
+    features    JSONB       NOT NULL DEFAULT '{}',
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
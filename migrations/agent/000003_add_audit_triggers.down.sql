@@ -0,0 +1 @@
+DROP TRIGGER IF EXISTS audit_conversations ON conversations;
@@ -0,0 +1,5 @@
+-- Record conversation changes in audit_log. Messages are chat content, not
+-- business data, and are not audited.
+CREATE OR REPLACE TRIGGER audit_conversations
+    AFTER INSERT OR UPDATE OR DELETE ON conversations
+    FOR EACH ROW EXECUTE FUNCTION audit_row_change();
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS audit_user_roles ON user_roles;
+DROP TRIGGER IF EXISTS audit_roles ON roles;
+DROP TRIGGER IF EXISTS audit_users ON users;
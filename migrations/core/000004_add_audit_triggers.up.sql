@@ -0,0 +1,17 @@
+-- Record every change to users, roles and role grants in audit_log.
+-- password_hash changes are logged without their values.
+CREATE OR REPLACE TRIGGER audit_users
+    AFTER INSERT OR UPDATE OR DELETE ON users
+    FOR EACH ROW EXECUTE FUNCTION audit_row_change('id', 'password_hash');
+
+CREATE OR REPLACE TRIGGER audit_roles
+    AFTER INSERT OR UPDATE OR DELETE ON roles
+    FOR EACH ROW EXECUTE FUNCTION audit_row_change();
+
+CREATE OR REPLACE TRIGGER audit_user_roles
+    AFTER INSERT OR UPDATE OR DELETE ON user_roles
+    FOR EACH ROW EXECUTE FUNCTION audit_row_change('user_id,role_id');
+
+-- Existing tenants' admin role gets the new audit read permission.
+UPDATE roles SET permissions = array_append(permissions, 'core:audit:read')
+WHERE name = 'admin' AND NOT ('core:audit:read' = ANY (permissions));
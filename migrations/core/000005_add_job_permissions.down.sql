@@ -0,0 +1,2 @@
+UPDATE roles SET permissions = array_remove(array_remove(permissions, 'core:job:read'), 'core:job:write')
+WHERE name = 'admin';
@@ -0,0 +1,7 @@
+-- Existing tenants' admin role gets the background job permissions: read
+-- and cancel every user's jobs (anyone may see and cancel their own).
+UPDATE roles SET permissions = array_append(permissions, 'core:job:read')
+WHERE name = 'admin' AND NOT ('core:job:read' = ANY (permissions));
+
+UPDATE roles SET permissions = array_append(permissions, 'core:job:write')
+WHERE name = 'admin' AND NOT ('core:job:write' = ANY (permissions));
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS audit_teacher_availability ON teacher_availability;
+DROP TRIGGER IF EXISTS audit_teachers ON teachers;
+DROP TRIGGER IF EXISTS audit_departments ON departments;
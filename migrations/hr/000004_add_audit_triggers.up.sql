@@ -0,0 +1,12 @@
+-- Record every change to departments, teachers and availability in audit_log.
+CREATE OR REPLACE TRIGGER audit_departments
+    AFTER INSERT OR UPDATE OR DELETE ON departments
+    FOR EACH ROW EXECUTE FUNCTION audit_row_change();
+
+CREATE OR REPLACE TRIGGER audit_teachers
+    AFTER INSERT OR UPDATE OR DELETE ON teachers
+    FOR EACH ROW EXECUTE FUNCTION audit_row_change();
+
+CREATE OR REPLACE TRIGGER audit_teacher_availability
+    AFTER INSERT OR UPDATE OR DELETE ON teacher_availability
+    FOR EACH ROW EXECUTE FUNCTION audit_row_change('teacher_id,day,period');
@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS history_teacher_availability ON teacher_availability;
+DELETE FROM entity_history WHERE entity_type = 'teacher_availability';
+DROP TRIGGER IF EXISTS history_teachers ON teachers;
+DELETE FROM entity_history WHERE entity_type = 'teachers';
+DROP TRIGGER IF EXISTS history_departments ON departments;
+DELETE FROM entity_history WHERE entity_type = 'departments';
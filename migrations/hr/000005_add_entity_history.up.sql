@@ -0,0 +1,23 @@
+-- Version departments, teachers and teacher availability in entity_history.
+-- Existing rows start their history at their last update (availability, which
+-- has no timestamp, at migration time).
+CREATE OR REPLACE TRIGGER history_departments
+    AFTER INSERT OR UPDATE OR DELETE ON departments
+    FOR EACH ROW EXECUTE FUNCTION record_entity_version();
+
+CREATE OR REPLACE TRIGGER history_teachers
+    AFTER INSERT OR UPDATE OR DELETE ON teachers
+    FOR EACH ROW EXECUTE FUNCTION record_entity_version();
+
+CREATE OR REPLACE TRIGGER history_teacher_availability
+    AFTER INSERT OR UPDATE OR DELETE ON teacher_availability
+    FOR EACH ROW EXECUTE FUNCTION record_entity_version('teacher_id,day,period');
+
+INSERT INTO entity_history (entity_type, entity_id, data, valid)
+SELECT 'departments', t.id::text, to_jsonb(t), tstzrange(LEAST(t.created_at, now()), NULL) FROM departments t;
+
+INSERT INTO entity_history (entity_type, entity_id, data, valid)
+SELECT 'teachers', t.id::text, to_jsonb(t), tstzrange(LEAST(t.updated_at, now()), NULL) FROM teachers t;
+
+INSERT INTO entity_history (entity_type, entity_id, data, valid)
+SELECT 'teacher_availability', t.teacher_id || ':' || t.day || ':' || t.period, to_jsonb(t), tstzrange(now(), NULL) FROM teacher_availability t;
@@ -0,0 +1,2 @@
+UPDATE entity_history SET data = data - 'version' WHERE entity_type = 'teachers';
+ALTER TABLE teachers DROP COLUMN IF EXISTS version;
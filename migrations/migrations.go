@@ -0,0 +1,31 @@
+// Package migrations embeds the versioned SQL migrations shipped with the server.
+//
+// Files are named <version>_<description>.up.sql / .down.sql. Files at the root
+// target the shared public schema; each subdirectory holds one module's
+// tenant-scoped migrations, applied to _template and every tenant schema.
+package migrations
+
+import "embed"
+
+// FS holds every migration file, keyed by "<module>/<file>" (root files for public).
+//
+//go:embed *.sql */*.sql
+var FS embed.FS
+
+// Public is the pseudo-module name for the root migrations applied to the public schema.
+const Public = "public"
+
+// Platform is the module name for cross-cutting tenant tables (outbox, ...).
+const Platform = "platform"
+
+// TenantModules lists every module with tenant-scoped migrations, in dependency
+// order. A new tenant schema is brought up to date by applying all of them.
+var TenantModules = []string{Platform, "core", "hr", "subject", "room", "timetable", "agent"}
+
+// Dir returns the directory inside FS that holds the given module's migrations.
+func Dir(module string) string {
+	if module == Public {
+		return "."
+	}
+	return module
+}
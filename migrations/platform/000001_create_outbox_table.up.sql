@@ -0,0 +1,13 @@
+-- outbox holds domain events written in the same transaction as the entity
+-- change. The relay forwards unpublished rows to the event bus (schema-per-tenant).
+CREATE TABLE IF NOT EXISTS outbox (
+    id           UUID         PRIMARY KEY,
+    topic        VARCHAR(255) NOT NULL,
+    payload      JSONB        NOT NULL,
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    published_at TIMESTAMPTZ,
+    attempts     INTEGER      NOT NULL DEFAULT 0,
+    last_error   TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_unpublished ON outbox(created_at) WHERE published_at IS NULL;
@@ -0,0 +1,98 @@
+-- audit_log is the append-only record of every row change in the tenant schema.
+-- Rows are written by the audit_row_change trigger, which modules attach to
+-- their tables; the actor and request come from the transaction-local mcs.*
+-- settings set by database.WithTenantTx.
+CREATE TABLE IF NOT EXISTS audit_log (
+    id          BIGSERIAL    PRIMARY KEY,
+    occurred_at TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    tenant      VARCHAR(63)  NOT NULL,
+    actor_id    UUID,
+    actor_email VARCHAR(255),
+    action      VARCHAR(10)  NOT NULL CHECK (action IN ('create', 'update', 'delete')),
+    entity_type VARCHAR(63)  NOT NULL,
+    entity_id   TEXT         NOT NULL,
+    changes     JSONB        NOT NULL DEFAULT '{}',
+    request_id  VARCHAR(128),
+    client_ip   VARCHAR(45)
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_occurred_at ON audit_log(occurred_at);
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON audit_log(entity_type, entity_id, occurred_at);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor ON audit_log(actor_id, occurred_at);
+CREATE INDEX IF NOT EXISTS idx_audit_log_request ON audit_log(request_id);
+
+-- audit_row_change records one audit_log row per changed table row.
+--   TG_ARGV[0]: comma-separated key columns forming entity_id (default "id")
+--   TG_ARGV[1]: comma-separated columns whose values are never stored; a change
+--               to them is recorded as {"redacted": true}
+-- changes maps each changed column to {"before": ..., "after": ...}. Updates
+-- that change nothing are not recorded.
+CREATE OR REPLACE FUNCTION audit_row_change() RETURNS trigger
+LANGUAGE plpgsql AS $$
+DECLARE
+    key_cols TEXT[] := string_to_array(COALESCE(NULLIF(TG_ARGV[0], ''), 'id'), ',');
+    redacted TEXT[] := COALESCE(string_to_array(NULLIF(TG_ARGV[1], ''), ','), '{}');
+    old_row  JSONB  := '{}';
+    new_row  JSONB  := '{}';
+    diff     JSONB  := '{}';
+    col      TEXT;
+    entity   TEXT;
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        old_row := to_jsonb(OLD);
+    END IF;
+    IF TG_OP <> 'DELETE' THEN
+        new_row := to_jsonb(NEW);
+    END IF;
+
+    FOR col IN SELECT jsonb_object_keys(old_row || new_row) LOOP
+        IF COALESCE(old_row -> col, 'null') IS DISTINCT FROM COALESCE(new_row -> col, 'null') THEN
+            IF col = ANY (redacted) THEN
+                diff := diff || jsonb_build_object(col, jsonb_build_object('redacted', true));
+            ELSE
+                diff := diff || jsonb_build_object(col,
+                    jsonb_build_object('before', old_row -> col, 'after', new_row -> col));
+            END IF;
+        END IF;
+    END LOOP;
+
+    IF TG_OP = 'UPDATE' AND diff = '{}' THEN
+        RETURN NULL;
+    END IF;
+
+    SELECT string_agg(COALESCE(new_row ->> k, old_row ->> k), ':' ORDER BY ord)
+      INTO entity
+      FROM unnest(key_cols) WITH ORDINALITY AS t(k, ord);
+
+    EXECUTE format(
+        'INSERT INTO %I.audit_log (tenant, actor_id, actor_email, action, entity_type, entity_id, changes, request_id, client_ip)
+         VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)', TG_TABLE_SCHEMA)
+    USING TG_TABLE_SCHEMA,
+          NULLIF(current_setting('mcs.actor_id', true), '')::uuid,
+          NULLIF(current_setting('mcs.actor_email', true), ''),
+          CASE TG_OP WHEN 'INSERT' THEN 'create' WHEN 'UPDATE' THEN 'update' ELSE 'delete' END,
+          TG_TABLE_NAME::text,
+          COALESCE(entity, ''),
+          diff,
+          NULLIF(current_setting('mcs.request_id', true), ''),
+          NULLIF(current_setting('mcs.client_ip', true), '');
+    RETURN NULL;
+END;
+$$;
+
+-- The log is append-only: rows cannot be changed or removed short of dropping
+-- the tenant schema.
+CREATE OR REPLACE FUNCTION audit_log_immutable() RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only' USING ERRCODE = 'insufficient_privilege';
+END;
+$$;
+
+CREATE OR REPLACE TRIGGER audit_log_no_modify
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_immutable();
+
+CREATE OR REPLACE TRIGGER audit_log_no_truncate
+    BEFORE TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_log_immutable();
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS entity_history;
+DROP FUNCTION IF EXISTS record_entity_version() CASCADE;
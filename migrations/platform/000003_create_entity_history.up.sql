@@ -0,0 +1,68 @@
+-- entity_history keeps every version of the rows of versioned tables (full row
+-- as JSON) with the period in which it was current. Rows are written by the
+-- record_entity_version trigger, which modules attach to their tables; at most
+-- one version per entity is open (upper bound infinite).
+CREATE TABLE IF NOT EXISTS entity_history (
+    id          BIGSERIAL   PRIMARY KEY,
+    entity_type VARCHAR(63) NOT NULL,
+    entity_id   TEXT        NOT NULL,
+    data        JSONB       NOT NULL,
+    valid       TSTZRANGE   NOT NULL CHECK (NOT isempty(valid))
+);
+
+CREATE INDEX IF NOT EXISTS idx_entity_history_entity ON entity_history(entity_type, entity_id, lower(valid));
+CREATE UNIQUE INDEX IF NOT EXISTS idx_entity_history_open ON entity_history(entity_type, entity_id) WHERE upper_inf(valid);
+
+-- record_entity_version closes the entity's open version at now() and opens a
+-- new one from now(). TG_ARGV[0] lists the key columns forming entity_id
+-- (default "id"). Within one transaction only the last state is kept, and a
+-- row deleted and re-inserted unchanged keeps its version.
+CREATE OR REPLACE FUNCTION record_entity_version() RETURNS trigger
+LANGUAGE plpgsql AS $$
+DECLARE
+    key_cols TEXT[] := string_to_array(COALESCE(NULLIF(TG_ARGV[0], ''), 'id'), ',');
+    old_row  JSONB;
+    new_row  JSONB;
+    old_key  TEXT;
+    new_key  TEXT;
+    reopened INT;
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        old_row := to_jsonb(OLD);
+        SELECT string_agg(old_row ->> k, ':' ORDER BY ord) INTO old_key
+          FROM unnest(key_cols) WITH ORDINALITY AS t(k, ord);
+    END IF;
+    IF TG_OP <> 'DELETE' THEN
+        new_row := to_jsonb(NEW);
+        SELECT string_agg(new_row ->> k, ':' ORDER BY ord) INTO new_key
+          FROM unnest(key_cols) WITH ORDINALITY AS t(k, ord);
+    END IF;
+    IF TG_OP = 'UPDATE' AND old_row = new_row THEN
+        RETURN NULL;
+    END IF;
+
+    IF old_key IS NOT NULL THEN
+        -- A version opened earlier in this transaction was never visible: drop it.
+        EXECUTE format('DELETE FROM %I.entity_history
+            WHERE entity_type = $1 AND entity_id = $2 AND upper_inf(valid) AND lower(valid) = now()', TG_TABLE_SCHEMA)
+        USING TG_TABLE_NAME::text, old_key;
+        EXECUTE format('UPDATE %I.entity_history SET valid = tstzrange(lower(valid), now())
+            WHERE entity_type = $1 AND entity_id = $2 AND upper_inf(valid)', TG_TABLE_SCHEMA)
+        USING TG_TABLE_NAME::text, old_key;
+    END IF;
+
+    IF new_key IS NOT NULL THEN
+        -- Replace-all writes (delete + insert) must not split an unchanged version.
+        EXECUTE format('UPDATE %I.entity_history SET valid = tstzrange(lower(valid), NULL)
+            WHERE entity_type = $1 AND entity_id = $2 AND upper(valid) = now() AND data = $3', TG_TABLE_SCHEMA)
+        USING TG_TABLE_NAME::text, new_key, new_row;
+        GET DIAGNOSTICS reopened = ROW_COUNT;
+        IF reopened = 0 THEN
+            EXECUTE format('INSERT INTO %I.entity_history (entity_type, entity_id, data, valid)
+                VALUES ($1, $2, $3, tstzrange(now(), NULL))', TG_TABLE_SCHEMA)
+            USING TG_TABLE_NAME::text, new_key, new_row;
+        END IF;
+    END IF;
+    RETURN NULL;
+END;
+$$;
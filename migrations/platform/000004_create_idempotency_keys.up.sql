@@ -0,0 +1,19 @@
+-- idempotency_keys remembers the outcome of mutating requests sent with an
+-- Idempotency-Key header, per user, so retries replay the first response
+-- instead of repeating the write. Rows expire after the configured TTL.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    user_id          UUID         NOT NULL,
+    key              VARCHAR(255) NOT NULL,
+    fingerprint      CHAR(64)     NOT NULL,
+    method           VARCHAR(10)  NOT NULL,
+    path             TEXT         NOT NULL,
+    status           VARCHAR(20)  NOT NULL DEFAULT 'in_progress' CHECK (status IN ('in_progress', 'completed')),
+    response_status  INTEGER,
+    response_headers JSONB,
+    response_body    BYTEA,
+    created_at       TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    expires_at       TIMESTAMPTZ  NOT NULL,
+    PRIMARY KEY (user_id, key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
@@ -0,0 +1,36 @@
+-- jobs is the tenant's background job queue (platform/jobs). Workers claim
+-- queued rows whose run_at has passed with FOR UPDATE SKIP LOCKED and hold a
+-- lease (locked_until) they renew while running; a job whose lease expires is
+-- picked up again. Finished rows are purged after the retention period.
+CREATE TABLE IF NOT EXISTS jobs (
+    id               UUID         PRIMARY KEY,
+    kind             VARCHAR(100) NOT NULL,
+    payload          JSONB        NOT NULL DEFAULT '{}',
+    status           VARCHAR(20)  NOT NULL DEFAULT 'queued'
+                     CHECK (status IN ('queued', 'running', 'succeeded', 'failed', 'cancelled')),
+    attempts         INTEGER      NOT NULL DEFAULT 0,
+    max_attempts     INTEGER      NOT NULL DEFAULT 3 CHECK (max_attempts > 0),
+    run_at           TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    locked_by        VARCHAR(100),
+    locked_until     TIMESTAMPTZ,
+    cancel_requested BOOLEAN      NOT NULL DEFAULT false,
+    result           JSONB,
+    last_error       TEXT,
+    unique_key       VARCHAR(255),
+    created_by       UUID,
+    created_by_email VARCHAR(255),
+    request_id       VARCHAR(128),
+    created_at       TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    started_at       TIMESTAMPTZ,
+    finished_at      TIMESTAMPTZ,
+    updated_at       TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_jobs_queued ON jobs(run_at) WHERE status = 'queued';
+CREATE INDEX IF NOT EXISTS idx_jobs_running ON jobs(locked_until) WHERE status = 'running';
+CREATE INDEX IF NOT EXISTS idx_jobs_created_by ON jobs(created_by, created_at);
+CREATE INDEX IF NOT EXISTS idx_jobs_finished_at ON jobs(finished_at) WHERE finished_at IS NOT NULL;
+
+-- At most one unfinished job per unique_key (deduplicated submissions, cron runs).
+CREATE UNIQUE INDEX IF NOT EXISTS idx_jobs_unique_key ON jobs(unique_key)
+    WHERE unique_key IS NOT NULL AND status IN ('queued', 'running');
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS audit_room_availability ON room_availability;
+DROP TRIGGER IF EXISTS audit_rooms ON rooms;
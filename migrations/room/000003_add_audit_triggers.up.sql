@@ -0,0 +1,8 @@
+-- Record every change to rooms and availability in audit_log.
+CREATE OR REPLACE TRIGGER audit_rooms
+    AFTER INSERT OR UPDATE OR DELETE ON rooms
+    FOR EACH ROW EXECUTE FUNCTION audit_row_change();
+
+CREATE OR REPLACE TRIGGER audit_room_availability
+    AFTER INSERT OR UPDATE OR DELETE ON room_availability
+    FOR EACH ROW EXECUTE FUNCTION audit_row_change('room_id,day,period');
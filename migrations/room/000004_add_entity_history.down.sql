@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS history_room_availability ON room_availability;
+DELETE FROM entity_history WHERE entity_type = 'room_availability';
+DROP TRIGGER IF EXISTS history_rooms ON rooms;
+DELETE FROM entity_history WHERE entity_type = 'rooms';
@@ -0,0 +1,15 @@
+-- Version rooms and room availability in entity_history. Existing rooms start
+-- their history at their last update, availability at migration time.
+CREATE OR REPLACE TRIGGER history_rooms
+    AFTER INSERT OR UPDATE OR DELETE ON rooms
+    FOR EACH ROW EXECUTE FUNCTION record_entity_version();
+
+CREATE OR REPLACE TRIGGER history_room_availability
+    AFTER INSERT OR UPDATE OR DELETE ON room_availability
+    FOR EACH ROW EXECUTE FUNCTION record_entity_version('room_id,day,period');
+
+INSERT INTO entity_history (entity_type, entity_id, data, valid)
+SELECT 'rooms', t.id::text, to_jsonb(t), tstzrange(LEAST(t.updated_at, now()), NULL) FROM rooms t;
+
+INSERT INTO entity_history (entity_type, entity_id, data, valid)
+SELECT 'room_availability', t.room_id || ':' || t.day || ':' || t.period, to_jsonb(t), tstzrange(now(), NULL) FROM room_availability t;
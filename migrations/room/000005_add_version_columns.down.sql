@@ -0,0 +1,2 @@
+UPDATE entity_history SET data = data - 'version' WHERE entity_type = 'rooms';
+ALTER TABLE rooms DROP COLUMN IF EXISTS version;
@@ -0,0 +1,2 @@
+ALTER TABLE subjects DROP CONSTRAINT IF EXISTS subjects_category_id_fk;
+DROP TABLE IF EXISTS subject_categories;
@@ -7,6 +7,16 @@ CREATE TABLE IF NOT EXISTS subject_categories (
 );
 
 -- Add foreign key from subjects to categories now that both tables exist.
-ALTER TABLE subjects
-    ADD CONSTRAINT subjects_category_id_fk
-    FOREIGN KEY (category_id) REFERENCES subject_categories (id) ON DELETE SET NULL;
+-- Guarded so schemas created before versioned migrations can be adopted.
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint c
+        JOIN pg_namespace n ON n.oid = c.connamespace
+        WHERE c.conname = 'subjects_category_id_fk' AND n.nspname = current_schema()
+    ) THEN
+        ALTER TABLE subjects
+            ADD CONSTRAINT subjects_category_id_fk
+            FOREIGN KEY (category_id) REFERENCES subject_categories (id) ON DELETE SET NULL;
+    END IF;
+END $$;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS subject_prerequisite_versions;
+DROP TABLE IF EXISTS subject_prerequisites;
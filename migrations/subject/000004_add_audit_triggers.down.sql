@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS audit_subject_prerequisites ON subject_prerequisites;
+DROP TRIGGER IF EXISTS audit_subject_categories ON subject_categories;
+DROP TRIGGER IF EXISTS audit_subjects ON subjects;
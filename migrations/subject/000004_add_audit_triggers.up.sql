@@ -0,0 +1,12 @@
+-- Record every change to subjects, categories and prerequisites in audit_log.
+CREATE OR REPLACE TRIGGER audit_subjects
+    AFTER INSERT OR UPDATE OR DELETE ON subjects
+    FOR EACH ROW EXECUTE FUNCTION audit_row_change();
+
+CREATE OR REPLACE TRIGGER audit_subject_categories
+    AFTER INSERT OR UPDATE OR DELETE ON subject_categories
+    FOR EACH ROW EXECUTE FUNCTION audit_row_change();
+
+CREATE OR REPLACE TRIGGER audit_subject_prerequisites
+    AFTER INSERT OR UPDATE OR DELETE ON subject_prerequisites
+    FOR EACH ROW EXECUTE FUNCTION audit_row_change('subject_id,prerequisite_id');
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS history_subjects ON subjects;
+DELETE FROM entity_history WHERE entity_type = 'subjects';
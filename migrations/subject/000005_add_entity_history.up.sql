@@ -0,0 +1,7 @@
+-- Version subjects in entity_history, starting existing rows at their last update.
+CREATE OR REPLACE TRIGGER history_subjects
+    AFTER INSERT OR UPDATE OR DELETE ON subjects
+    FOR EACH ROW EXECUTE FUNCTION record_entity_version();
+
+INSERT INTO entity_history (entity_type, entity_id, data, valid)
+SELECT 'subjects', t.id::text, to_jsonb(t), tstzrange(LEAST(t.updated_at, now()), NULL) FROM subjects t;
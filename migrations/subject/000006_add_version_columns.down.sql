@@ -0,0 +1,2 @@
+UPDATE entity_history SET data = data - 'version' WHERE entity_type = 'subjects';
+ALTER TABLE subjects DROP COLUMN IF EXISTS version;
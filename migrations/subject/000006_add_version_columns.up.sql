@@ -0,0 +1,8 @@
+-- Optimistic concurrency: every update bumps version and is rejected when the
+-- writer's version is stale (surfaced over HTTP as ETag / If-Match).
+ALTER TABLE subjects ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+
+-- Existing history versions predate the column; give them version 1 so they
+-- still read back with the live column list.
+UPDATE entity_history SET data = data || '{"version": 1}'
+ WHERE entity_type = 'subjects' AND NOT data ? 'version';
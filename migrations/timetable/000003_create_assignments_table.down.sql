@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS assignments;
+DROP TABLE IF EXISTS schedules;
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS audit_assignments ON assignments;
+DROP TRIGGER IF EXISTS audit_schedules ON schedules;
+DROP TRIGGER IF EXISTS audit_semester_subjects ON semester_subjects;
+DROP TRIGGER IF EXISTS audit_semesters ON semesters;
@@ -0,0 +1,16 @@
+-- Record every change to semesters, their subjects, schedules and assignments in audit_log.
+CREATE OR REPLACE TRIGGER audit_semesters
+    AFTER INSERT OR UPDATE OR DELETE ON semesters
+    FOR EACH ROW EXECUTE FUNCTION audit_row_change();
+
+CREATE OR REPLACE TRIGGER audit_semester_subjects
+    AFTER INSERT OR UPDATE OR DELETE ON semester_subjects
+    FOR EACH ROW EXECUTE FUNCTION audit_row_change('semester_id,subject_id');
+
+CREATE OR REPLACE TRIGGER audit_schedules
+    AFTER INSERT OR UPDATE OR DELETE ON schedules
+    FOR EACH ROW EXECUTE FUNCTION audit_row_change('semester_id,version');
+
+CREATE OR REPLACE TRIGGER audit_assignments
+    AFTER INSERT OR UPDATE OR DELETE ON assignments
+    FOR EACH ROW EXECUTE FUNCTION audit_row_change();
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS history_semesters ON semesters;
+DELETE FROM entity_history WHERE entity_type = 'semesters';
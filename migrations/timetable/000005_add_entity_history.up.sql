@@ -0,0 +1,7 @@
+-- Version semesters in entity_history, starting existing rows at their last update.
+CREATE OR REPLACE TRIGGER history_semesters
+    AFTER INSERT OR UPDATE OR DELETE ON semesters
+    FOR EACH ROW EXECUTE FUNCTION record_entity_version();
+
+INSERT INTO entity_history (entity_type, entity_id, data, valid)
+SELECT 'semesters', t.id::text, to_jsonb(t), tstzrange(LEAST(t.updated_at, now()), NULL) FROM semesters t;
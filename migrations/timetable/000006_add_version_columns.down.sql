@@ -0,0 +1,3 @@
+UPDATE entity_history SET data = data - 'version' WHERE entity_type = 'semesters';
+ALTER TABLE assignments DROP COLUMN IF EXISTS revision;
+ALTER TABLE semesters   DROP COLUMN IF EXISTS version;
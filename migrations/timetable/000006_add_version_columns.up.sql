@@ -0,0 +1,11 @@
+-- Optimistic concurrency: every update bumps the row's version and is rejected
+-- when the writer's version is stale (surfaced over HTTP as ETag / If-Match).
+-- assignments.version already numbers schedule generations, so assignment rows
+-- count their edits in revision.
+ALTER TABLE semesters   ADD COLUMN IF NOT EXISTS version  INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE assignments ADD COLUMN IF NOT EXISTS revision INTEGER NOT NULL DEFAULT 1;
+
+-- Existing history versions predate the column; give them version 1 so they
+-- still read back with the live column list.
+UPDATE entity_history SET data = data || '{"version": 1}'
+ WHERE entity_type = 'semesters' AND NOT data ? 'version';
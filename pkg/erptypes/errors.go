@@ -1,11 +1,229 @@
 package erptypes
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
 
-// Standard domain errors used across all modules.
+// Standard domain errors used across all modules. Repositories and services
+// return or wrap them (fmt.Errorf("...: %w", ErrConflict)); *Error values
+// match them too, so callers always test with errors.Is.
 var (
 	ErrNotFound   = errors.New("not found")
 	ErrConflict   = errors.New("conflict")
 	ErrForbidden  = errors.New("forbidden")
 	ErrValidation = errors.New("validation error")
 )
+
+// Code is a stable, machine-readable error code. Clients switch on it; the
+// message next to it is for humans and may change.
+type Code string
+
+const (
+	CodeInvalidRequest     Code = "invalid_request"     // malformed body, query or path
+	CodeValidation         Code = "validation_failed"   // see Error.Violations
+	CodeUnauthorized       Code = "unauthorized"        // missing or invalid credentials
+	CodeForbidden          Code = "forbidden"           // authenticated but not allowed
+	CodeNotFound           Code = "not_found"           //
+	CodeConflict           Code = "conflict"            // clashes with the current state
+	CodeGone               Code = "gone"                // permanently unavailable
+	CodePreconditionFailed Code = "precondition_failed" // If-Match did not match
+	CodeTooLarge           Code = "payload_too_large"   //
+	CodeUnprocessable      Code = "unprocessable"       // well-formed but cannot be processed
+	CodeRateLimited        Code = "rate_limited"        // too many requests, see Retry-After
+	CodeUnavailable        Code = "unavailable"         // temporarily unavailable, retry later
+	CodeInternal           Code = "internal"            // details are logged, never returned
+)
+
+var codeStatus = map[Code]int{
+	CodeInvalidRequest:     http.StatusBadRequest,
+	CodeValidation:         http.StatusBadRequest,
+	CodeUnauthorized:       http.StatusUnauthorized,
+	CodeForbidden:          http.StatusForbidden,
+	CodeNotFound:           http.StatusNotFound,
+	CodeConflict:           http.StatusConflict,
+	CodeGone:               http.StatusGone,
+	CodePreconditionFailed: http.StatusPreconditionFailed,
+	CodeTooLarge:           http.StatusRequestEntityTooLarge,
+	CodeUnprocessable:      http.StatusUnprocessableEntity,
+	CodeRateLimited:        http.StatusTooManyRequests,
+	CodeUnavailable:        http.StatusServiceUnavailable,
+	CodeInternal:           http.StatusInternalServerError,
+}
+
+// Status returns the HTTP status the code is served with.
+func (c Code) Status() int {
+	if s, ok := codeStatus[c]; ok {
+		return s
+	}
+	return http.StatusInternalServerError
+}
+
+// sentinel is the standard error a code corresponds to, if any.
+func (c Code) sentinel() error {
+	switch c {
+	case CodeNotFound:
+		return ErrNotFound
+	case CodeConflict, CodePreconditionFailed:
+		return ErrConflict
+	case CodeForbidden:
+		return ErrForbidden
+	case CodeValidation, CodeInvalidRequest:
+		return ErrValidation
+	default:
+		return nil
+	}
+}
+
+// FieldViolation is one problem with one field of a request. Field is the
+// JSON path of the value, e.g. "email" or "slots[2].day".
+type FieldViolation struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error is a client-facing error. Message, Violations and Meta are returned
+// to the client as they are; Cause is only logged.
+type Error struct {
+	Code       Code
+	Status     int // HTTP status; Code.Status() when zero
+	Message    string
+	Violations []FieldViolation
+	Meta       map[string]any
+	Cause      error
+}
+
+// New returns an Error with the code's status.
+func New(code Code, msg string) *Error {
+	return &Error{Code: code, Status: code.Status(), Message: msg}
+}
+
+// Newf is New with a formatted message.
+func Newf(code Code, format string, args ...any) *Error {
+	return New(code, fmt.Sprintf(format, args...))
+}
+
+// InvalidRequest reports a request that could not be read.
+func InvalidRequest(msg string) *Error { return New(CodeInvalidRequest, msg) }
+
+// Unauthorized reports missing or invalid credentials.
+func Unauthorized(msg string) *Error { return New(CodeUnauthorized, msg) }
+
+// Forbidden reports an action the caller is not allowed to take.
+func Forbidden(msg string) *Error { return New(CodeForbidden, msg) }
+
+// NotFound reports a missing resource, e.g. NotFound("teacher not found").
+func NotFound(msg string) *Error { return New(CodeNotFound, msg) }
+
+// Conflict reports a change that clashes with the current state.
+func Conflict(msg string) *Error { return New(CodeConflict, msg) }
+
+// PreconditionFailed reports a stale If-Match.
+func PreconditionFailed(msg string) *Error { return New(CodePreconditionFailed, msg) }
+
+// Validation reports field violations.
+func Validation(violations ...FieldViolation) *Error {
+	e := New(CodeValidation, "request validation failed")
+	e.Violations = violations
+	return e
+}
+
+// Internal reports an unexpected failure; msg is what the client sees and
+// cause is logged.
+func Internal(msg string, cause error) *Error {
+	e := New(CodeInternal, msg)
+	e.Cause = cause
+	return e
+}
+
+// NotFoundAs returns NotFound(msg) when err is or wraps ErrNotFound and err
+// otherwise, so handlers name the missing resource without hiding other
+// failures: problem.Write(w, r, erptypes.NotFoundAs(err, "teacher not found")).
+func NotFoundAs(err error, msg string) error {
+	if errors.Is(err, ErrNotFound) {
+		return NotFound(msg).WithCause(err)
+	}
+	return err
+}
+
+// WithMeta adds a client-safe detail, e.g. the id of a conflicting row.
+func (e *Error) WithMeta(key string, value any) *Error {
+	if e.Meta == nil {
+		e.Meta = map[string]any{}
+	}
+	e.Meta[key] = value
+	return e
+}
+
+// WithCause records the underlying error for logs.
+func (e *Error) WithCause(err error) *Error {
+	e.Cause = err
+	return e
+}
+
+func (e *Error) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = string(e.Code)
+	}
+	if e.Cause != nil {
+		return msg + ": " + e.Cause.Error()
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error { return e.Cause }
+
+// Is makes errors.Is(err, ErrNotFound) and friends hold for the matching codes.
+func (e *Error) Is(target error) bool {
+	s := e.Code.sentinel()
+	return s != nil && target == s
+}
+
+// HTTPStatus returns Status, or the code's status when unset.
+func (e *Error) HTTPStatus() int {
+	if e.Status != 0 {
+		return e.Status
+	}
+	return e.Code.Status()
+}
+
+// AsError returns err as an *Error: the first *Error in its chain, an Error
+// with the matching code for the standard errors (keeping the wrapping text
+// as the message), or an internal error with err as the cause.
+func AsError(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	for _, m := range []struct {
+		sentinel error
+		code     Code
+	}{
+		{ErrNotFound, CodeNotFound},
+		{ErrConflict, CodeConflict},
+		{ErrForbidden, CodeForbidden},
+		{ErrValidation, CodeValidation},
+	} {
+		if errors.Is(err, m.sentinel) {
+			return &Error{Code: m.code, Status: m.code.Status(), Message: sentinelText(err, m.sentinel), Cause: err}
+		}
+	}
+	return Internal("internal error", err)
+}
+
+// sentinelText is the message of an error wrapping a standard error, without
+// the standard error's own text: "tenant is archived" for
+// fmt.Errorf("%w: tenant is archived", ErrConflict).
+func sentinelText(err, sentinel error) string {
+	msg, s := err.Error(), sentinel.Error()
+	if msg == s {
+		return s
+	}
+	msg = strings.TrimPrefix(msg, s+": ")
+	msg = strings.TrimSuffix(msg, ": "+s)
+	return msg
+}
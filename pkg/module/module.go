@@ -3,6 +3,9 @@ package module
 import (
 	"context"
 	"net/http"
+	"time"
+
+	"google.golang.org/grpc"
 )
 
 // Module defines the interface every ERP module must implement.
@@ -23,3 +26,90 @@ type Module interface {
 	// Migrate runs database migrations for the module.
 	Migrate(ctx context.Context) error
 }
+
+// GRPCRegistrar is implemented by modules that expose gRPC services.
+type GRPCRegistrar interface {
+	// RegisterGRPC registers the module's services and returns the permission
+	// required by each full method name.
+	RegisterGRPC(s grpc.ServiceRegistrar) map[string]string
+}
+
+// Starter is implemented by modules that run background work. Start is
+// called in dependency order after every module is bootstrapped and before
+// the servers accept traffic; it must not block.
+type Starter interface {
+	Start(ctx context.Context) error
+}
+
+// Stopper is implemented by modules holding resources or background work
+// that must be released on shutdown. Stop is called in reverse dependency
+// order once the servers and event router have drained; ctx bounds the wait.
+type Stopper interface {
+	Stop(ctx context.Context) error
+}
+
+// HealthCheck probes one dependency. A failing Critical check makes the
+// instance not ready; any other failure only marks it degraded.
+type HealthCheck struct {
+	Name     string
+	Critical bool
+	Check    func(ctx context.Context) error
+}
+
+// HealthChecker is implemented by modules that contribute dependency checks
+// to /readyz and /healthz/details.
+type HealthChecker interface {
+	HealthChecks() []HealthCheck
+}
+
+// RouteDescriber is implemented by modules that document their REST routes.
+// Routes returns the routes RegisterRoutes mounted; it is read after
+// Bootstrap to build the OpenAPI document.
+type RouteDescriber interface {
+	Routes() []Route
+}
+
+// Access says who may call a route.
+type Access int
+
+const (
+	AccessUser     Access = iota // a tenant user's bearer token, holding Permission if set
+	AccessPublic                 // anyone
+	AccessPlatform               // the platform operator token
+)
+
+// Route documents one REST route.
+type Route struct {
+	Module      string // set by the router
+	Method      string // "GET", "POST", ...
+	Path        string // mux pattern path, e.g. "/api/v1/teachers/{id}"
+	OperationID string // defaults to the handler's name, e.g. "createTeacher"
+	Summary     string
+	Access      Access
+	Permission  string        // Perm* the caller needs; "" lets any signed-in user call it
+	Query       []Param       // query parameters
+	Request     any           // zero value of the JSON request body type; nil for none
+	Response    any           // zero value of the response body type; nil for none
+	Status      int           // success status; 200 when zero
+	Produces    string        // response media type when not application/json
+	Timeout     time.Duration // request time limit; 0 for the server default, negative for none (streams)
+	RateClass   string        // rate limit class, one of the Rate* constants
+}
+
+// Rate limit classes (Route.RateClass). platform/ratelimit holds the limits
+// of each class.
+const (
+	RateDefault = ""        // ordinary API calls: per user, or per client IP when anonymous
+	RateAuth    = "auth"    // credential checks (login, refresh): per client IP
+	RateLLM     = "llm"     // LLM calls: per user and per tenant
+	RateCompute = "compute" // CPU-heavy work and exports: per tenant
+)
+
+// Param documents one query parameter.
+type Param struct {
+	Name        string
+	Type        string // JSON schema type: "string" (default), "integer", "boolean"
+	Format      string // e.g. "date-time", "uuid"
+	Description string
+	Required    bool
+}
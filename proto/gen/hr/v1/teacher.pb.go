@@ -0,0 +1,614 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: hr/v1/teacher.proto
+
+package hrv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Teacher struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Email string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	// Empty when the teacher has no department.
+	DepartmentId   string                 `protobuf:"bytes,4,opt,name=department_id,json=departmentId,proto3" json:"department_id,omitempty"`
+	Qualifications []string               `protobuf:"bytes,5,rep,name=qualifications,proto3" json:"qualifications,omitempty"`
+	IsActive       bool                   `protobuf:"varint,6,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt      *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Teacher) Reset() {
+	*x = Teacher{}
+	mi := &file_hr_v1_teacher_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Teacher) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Teacher) ProtoMessage() {}
+
+func (x *Teacher) ProtoReflect() protoreflect.Message {
+	mi := &file_hr_v1_teacher_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Teacher.ProtoReflect.Descriptor instead.
+func (*Teacher) Descriptor() ([]byte, []int) {
+	return file_hr_v1_teacher_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Teacher) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Teacher) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Teacher) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *Teacher) GetDepartmentId() string {
+	if x != nil {
+		return x.DepartmentId
+	}
+	return ""
+}
+
+func (x *Teacher) GetQualifications() []string {
+	if x != nil {
+		return x.Qualifications
+	}
+	return nil
+}
+
+func (x *Teacher) GetIsActive() bool {
+	if x != nil {
+		return x.IsActive
+	}
+	return false
+}
+
+func (x *Teacher) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Teacher) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// AvailabilitySlot is one weekly period. day: 0=Monday..6=Sunday, period: 1-10.
+type AvailabilitySlot struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Day           int32                  `protobuf:"varint,1,opt,name=day,proto3" json:"day,omitempty"`
+	Period        int32                  `protobuf:"varint,2,opt,name=period,proto3" json:"period,omitempty"`
+	IsAvailable   bool                   `protobuf:"varint,3,opt,name=is_available,json=isAvailable,proto3" json:"is_available,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AvailabilitySlot) Reset() {
+	*x = AvailabilitySlot{}
+	mi := &file_hr_v1_teacher_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AvailabilitySlot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AvailabilitySlot) ProtoMessage() {}
+
+func (x *AvailabilitySlot) ProtoReflect() protoreflect.Message {
+	mi := &file_hr_v1_teacher_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AvailabilitySlot.ProtoReflect.Descriptor instead.
+func (*AvailabilitySlot) Descriptor() ([]byte, []int) {
+	return file_hr_v1_teacher_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AvailabilitySlot) GetDay() int32 {
+	if x != nil {
+		return x.Day
+	}
+	return 0
+}
+
+func (x *AvailabilitySlot) GetPeriod() int32 {
+	if x != nil {
+		return x.Period
+	}
+	return 0
+}
+
+func (x *AvailabilitySlot) GetIsAvailable() bool {
+	if x != nil {
+		return x.IsAvailable
+	}
+	return false
+}
+
+type GetTeacherRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTeacherRequest) Reset() {
+	*x = GetTeacherRequest{}
+	mi := &file_hr_v1_teacher_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTeacherRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTeacherRequest) ProtoMessage() {}
+
+func (x *GetTeacherRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hr_v1_teacher_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTeacherRequest.ProtoReflect.Descriptor instead.
+func (*GetTeacherRequest) Descriptor() ([]byte, []int) {
+	return file_hr_v1_teacher_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetTeacherRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetTeacherResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Teacher       *Teacher               `protobuf:"bytes,1,opt,name=teacher,proto3" json:"teacher,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTeacherResponse) Reset() {
+	*x = GetTeacherResponse{}
+	mi := &file_hr_v1_teacher_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTeacherResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTeacherResponse) ProtoMessage() {}
+
+func (x *GetTeacherResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hr_v1_teacher_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTeacherResponse.ProtoReflect.Descriptor instead.
+func (*GetTeacherResponse) Descriptor() ([]byte, []int) {
+	return file_hr_v1_teacher_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetTeacherResponse) GetTeacher() *Teacher {
+	if x != nil {
+		return x.Teacher
+	}
+	return nil
+}
+
+type ListTeachersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DepartmentId  string                 `protobuf:"bytes,1,opt,name=department_id,json=departmentId,proto3" json:"department_id,omitempty"`
+	IsActive      *bool                  `protobuf:"varint,2,opt,name=is_active,json=isActive,proto3,oneof" json:"is_active,omitempty"`
+	Qualification string                 `protobuf:"bytes,3,opt,name=qualification,proto3" json:"qualification,omitempty"`
+	Offset        int32                  `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+	// Defaults to 20, capped at 100.
+	Limit         int32 `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTeachersRequest) Reset() {
+	*x = ListTeachersRequest{}
+	mi := &file_hr_v1_teacher_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTeachersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTeachersRequest) ProtoMessage() {}
+
+func (x *ListTeachersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hr_v1_teacher_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTeachersRequest.ProtoReflect.Descriptor instead.
+func (*ListTeachersRequest) Descriptor() ([]byte, []int) {
+	return file_hr_v1_teacher_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListTeachersRequest) GetDepartmentId() string {
+	if x != nil {
+		return x.DepartmentId
+	}
+	return ""
+}
+
+func (x *ListTeachersRequest) GetIsActive() bool {
+	if x != nil && x.IsActive != nil {
+		return *x.IsActive
+	}
+	return false
+}
+
+func (x *ListTeachersRequest) GetQualification() string {
+	if x != nil {
+		return x.Qualification
+	}
+	return ""
+}
+
+func (x *ListTeachersRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ListTeachersRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListTeachersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Teachers      []*Teacher             `protobuf:"bytes,1,rep,name=teachers,proto3" json:"teachers,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTeachersResponse) Reset() {
+	*x = ListTeachersResponse{}
+	mi := &file_hr_v1_teacher_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTeachersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTeachersResponse) ProtoMessage() {}
+
+func (x *ListTeachersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hr_v1_teacher_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTeachersResponse.ProtoReflect.Descriptor instead.
+func (*ListTeachersResponse) Descriptor() ([]byte, []int) {
+	return file_hr_v1_teacher_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListTeachersResponse) GetTeachers() []*Teacher {
+	if x != nil {
+		return x.Teachers
+	}
+	return nil
+}
+
+func (x *ListTeachersResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type GetTeacherAvailabilityRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TeacherId     string                 `protobuf:"bytes,1,opt,name=teacher_id,json=teacherId,proto3" json:"teacher_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTeacherAvailabilityRequest) Reset() {
+	*x = GetTeacherAvailabilityRequest{}
+	mi := &file_hr_v1_teacher_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTeacherAvailabilityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTeacherAvailabilityRequest) ProtoMessage() {}
+
+func (x *GetTeacherAvailabilityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hr_v1_teacher_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTeacherAvailabilityRequest.ProtoReflect.Descriptor instead.
+func (*GetTeacherAvailabilityRequest) Descriptor() ([]byte, []int) {
+	return file_hr_v1_teacher_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetTeacherAvailabilityRequest) GetTeacherId() string {
+	if x != nil {
+		return x.TeacherId
+	}
+	return ""
+}
+
+type GetTeacherAvailabilityResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TeacherId     string                 `protobuf:"bytes,1,opt,name=teacher_id,json=teacherId,proto3" json:"teacher_id,omitempty"`
+	Slots         []*AvailabilitySlot    `protobuf:"bytes,2,rep,name=slots,proto3" json:"slots,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTeacherAvailabilityResponse) Reset() {
+	*x = GetTeacherAvailabilityResponse{}
+	mi := &file_hr_v1_teacher_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTeacherAvailabilityResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTeacherAvailabilityResponse) ProtoMessage() {}
+
+func (x *GetTeacherAvailabilityResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hr_v1_teacher_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTeacherAvailabilityResponse.ProtoReflect.Descriptor instead.
+func (*GetTeacherAvailabilityResponse) Descriptor() ([]byte, []int) {
+	return file_hr_v1_teacher_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetTeacherAvailabilityResponse) GetTeacherId() string {
+	if x != nil {
+		return x.TeacherId
+	}
+	return ""
+}
+
+func (x *GetTeacherAvailabilityResponse) GetSlots() []*AvailabilitySlot {
+	if x != nil {
+		return x.Slots
+	}
+	return nil
+}
+
+var File_hr_v1_teacher_proto protoreflect.FileDescriptor
+
+const file_hr_v1_teacher_proto_rawDesc = "" +
+	"\n" +
+	"\x13hr/v1/teacher.proto\x12\x05hr.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa3\x02\n" +
+	"\aTeacher\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\x12#\n" +
+	"\rdepartment_id\x18\x04 \x01(\tR\fdepartmentId\x12&\n" +
+	"\x0equalifications\x18\x05 \x03(\tR\x0equalifications\x12\x1b\n" +
+	"\tis_active\x18\x06 \x01(\bR\bisActive\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"_\n" +
+	"\x10AvailabilitySlot\x12\x10\n" +
+	"\x03day\x18\x01 \x01(\x05R\x03day\x12\x16\n" +
+	"\x06period\x18\x02 \x01(\x05R\x06period\x12!\n" +
+	"\fis_available\x18\x03 \x01(\bR\visAvailable\"#\n" +
+	"\x11GetTeacherRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\">\n" +
+	"\x12GetTeacherResponse\x12(\n" +
+	"\ateacher\x18\x01 \x01(\v2\x0e.hr.v1.TeacherR\ateacher\"\xbe\x01\n" +
+	"\x13ListTeachersRequest\x12#\n" +
+	"\rdepartment_id\x18\x01 \x01(\tR\fdepartmentId\x12 \n" +
+	"\tis_active\x18\x02 \x01(\bH\x00R\bisActive\x88\x01\x01\x12$\n" +
+	"\rqualification\x18\x03 \x01(\tR\rqualification\x12\x16\n" +
+	"\x06offset\x18\x04 \x01(\x05R\x06offset\x12\x14\n" +
+	"\x05limit\x18\x05 \x01(\x05R\x05limitB\f\n" +
+	"\n" +
+	"_is_active\"X\n" +
+	"\x14ListTeachersResponse\x12*\n" +
+	"\bteachers\x18\x01 \x03(\v2\x0e.hr.v1.TeacherR\bteachers\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\">\n" +
+	"\x1dGetTeacherAvailabilityRequest\x12\x1d\n" +
+	"\n" +
+	"teacher_id\x18\x01 \x01(\tR\tteacherId\"n\n" +
+	"\x1eGetTeacherAvailabilityResponse\x12\x1d\n" +
+	"\n" +
+	"teacher_id\x18\x01 \x01(\tR\tteacherId\x12-\n" +
+	"\x05slots\x18\x02 \x03(\v2\x17.hr.v1.AvailabilitySlotR\x05slots2\x83\x02\n" +
+	"\x0eTeacherService\x12A\n" +
+	"\n" +
+	"GetTeacher\x12\x18.hr.v1.GetTeacherRequest\x1a\x19.hr.v1.GetTeacherResponse\x12G\n" +
+	"\fListTeachers\x12\x1a.hr.v1.ListTeachersRequest\x1a\x1b.hr.v1.ListTeachersResponse\x12e\n" +
+	"\x16GetTeacherAvailability\x12$.hr.v1.GetTeacherAvailabilityRequest\x1a%.hr.v1.GetTeacherAvailabilityResponseB8Z6github.com/HuynhHoangPhuc/mcs-erp/proto/gen/hr/v1;hrv1b\x06proto3"
+
+var (
+	file_hr_v1_teacher_proto_rawDescOnce sync.Once
+	file_hr_v1_teacher_proto_rawDescData []byte
+)
+
+func file_hr_v1_teacher_proto_rawDescGZIP() []byte {
+	file_hr_v1_teacher_proto_rawDescOnce.Do(func() {
+		file_hr_v1_teacher_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_hr_v1_teacher_proto_rawDesc), len(file_hr_v1_teacher_proto_rawDesc)))
+	})
+	return file_hr_v1_teacher_proto_rawDescData
+}
+
+var file_hr_v1_teacher_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_hr_v1_teacher_proto_goTypes = []any{
+	(*Teacher)(nil),                        // 0: hr.v1.Teacher
+	(*AvailabilitySlot)(nil),               // 1: hr.v1.AvailabilitySlot
+	(*GetTeacherRequest)(nil),              // 2: hr.v1.GetTeacherRequest
+	(*GetTeacherResponse)(nil),             // 3: hr.v1.GetTeacherResponse
+	(*ListTeachersRequest)(nil),            // 4: hr.v1.ListTeachersRequest
+	(*ListTeachersResponse)(nil),           // 5: hr.v1.ListTeachersResponse
+	(*GetTeacherAvailabilityRequest)(nil),  // 6: hr.v1.GetTeacherAvailabilityRequest
+	(*GetTeacherAvailabilityResponse)(nil), // 7: hr.v1.GetTeacherAvailabilityResponse
+	(*timestamppb.Timestamp)(nil),          // 8: google.protobuf.Timestamp
+}
+var file_hr_v1_teacher_proto_depIdxs = []int32{
+	8, // 0: hr.v1.Teacher.created_at:type_name -> google.protobuf.Timestamp
+	8, // 1: hr.v1.Teacher.updated_at:type_name -> google.protobuf.Timestamp
+	0, // 2: hr.v1.GetTeacherResponse.teacher:type_name -> hr.v1.Teacher
+	0, // 3: hr.v1.ListTeachersResponse.teachers:type_name -> hr.v1.Teacher
+	1, // 4: hr.v1.GetTeacherAvailabilityResponse.slots:type_name -> hr.v1.AvailabilitySlot
+	2, // 5: hr.v1.TeacherService.GetTeacher:input_type -> hr.v1.GetTeacherRequest
+	4, // 6: hr.v1.TeacherService.ListTeachers:input_type -> hr.v1.ListTeachersRequest
+	6, // 7: hr.v1.TeacherService.GetTeacherAvailability:input_type -> hr.v1.GetTeacherAvailabilityRequest
+	3, // 8: hr.v1.TeacherService.GetTeacher:output_type -> hr.v1.GetTeacherResponse
+	5, // 9: hr.v1.TeacherService.ListTeachers:output_type -> hr.v1.ListTeachersResponse
+	7, // 10: hr.v1.TeacherService.GetTeacherAvailability:output_type -> hr.v1.GetTeacherAvailabilityResponse
+	8, // [8:11] is the sub-list for method output_type
+	5, // [5:8] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_hr_v1_teacher_proto_init() }
+func file_hr_v1_teacher_proto_init() {
+	if File_hr_v1_teacher_proto != nil {
+		return
+	}
+	file_hr_v1_teacher_proto_msgTypes[4].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_hr_v1_teacher_proto_rawDesc), len(file_hr_v1_teacher_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_hr_v1_teacher_proto_goTypes,
+		DependencyIndexes: file_hr_v1_teacher_proto_depIdxs,
+		MessageInfos:      file_hr_v1_teacher_proto_msgTypes,
+	}.Build()
+	File_hr_v1_teacher_proto = out.File
+	file_hr_v1_teacher_proto_goTypes = nil
+	file_hr_v1_teacher_proto_depIdxs = nil
+}
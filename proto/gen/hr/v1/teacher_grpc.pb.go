@@ -0,0 +1,203 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: hr/v1/teacher.proto
+
+package hrv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	TeacherService_GetTeacher_FullMethodName             = "/hr.v1.TeacherService/GetTeacher"
+	TeacherService_ListTeachers_FullMethodName           = "/hr.v1.TeacherService/ListTeachers"
+	TeacherService_GetTeacherAvailability_FullMethodName = "/hr.v1.TeacherService/GetTeacherAvailability"
+)
+
+// TeacherServiceClient is the client API for TeacherService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// TeacherService exposes read access to teachers and their weekly availability.
+// Requires hr:teacher:read.
+type TeacherServiceClient interface {
+	GetTeacher(ctx context.Context, in *GetTeacherRequest, opts ...grpc.CallOption) (*GetTeacherResponse, error)
+	ListTeachers(ctx context.Context, in *ListTeachersRequest, opts ...grpc.CallOption) (*ListTeachersResponse, error)
+	GetTeacherAvailability(ctx context.Context, in *GetTeacherAvailabilityRequest, opts ...grpc.CallOption) (*GetTeacherAvailabilityResponse, error)
+}
+
+type teacherServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTeacherServiceClient(cc grpc.ClientConnInterface) TeacherServiceClient {
+	return &teacherServiceClient{cc}
+}
+
+func (c *teacherServiceClient) GetTeacher(ctx context.Context, in *GetTeacherRequest, opts ...grpc.CallOption) (*GetTeacherResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTeacherResponse)
+	err := c.cc.Invoke(ctx, TeacherService_GetTeacher_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *teacherServiceClient) ListTeachers(ctx context.Context, in *ListTeachersRequest, opts ...grpc.CallOption) (*ListTeachersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTeachersResponse)
+	err := c.cc.Invoke(ctx, TeacherService_ListTeachers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *teacherServiceClient) GetTeacherAvailability(ctx context.Context, in *GetTeacherAvailabilityRequest, opts ...grpc.CallOption) (*GetTeacherAvailabilityResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTeacherAvailabilityResponse)
+	err := c.cc.Invoke(ctx, TeacherService_GetTeacherAvailability_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TeacherServiceServer is the server API for TeacherService service.
+// All implementations must embed UnimplementedTeacherServiceServer
+// for forward compatibility.
+//
+// TeacherService exposes read access to teachers and their weekly availability.
+// Requires hr:teacher:read.
+type TeacherServiceServer interface {
+	GetTeacher(context.Context, *GetTeacherRequest) (*GetTeacherResponse, error)
+	ListTeachers(context.Context, *ListTeachersRequest) (*ListTeachersResponse, error)
+	GetTeacherAvailability(context.Context, *GetTeacherAvailabilityRequest) (*GetTeacherAvailabilityResponse, error)
+	mustEmbedUnimplementedTeacherServiceServer()
+}
+
+// UnimplementedTeacherServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTeacherServiceServer struct{}
+
+func (UnimplementedTeacherServiceServer) GetTeacher(context.Context, *GetTeacherRequest) (*GetTeacherResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetTeacher not implemented")
+}
+func (UnimplementedTeacherServiceServer) ListTeachers(context.Context, *ListTeachersRequest) (*ListTeachersResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListTeachers not implemented")
+}
+func (UnimplementedTeacherServiceServer) GetTeacherAvailability(context.Context, *GetTeacherAvailabilityRequest) (*GetTeacherAvailabilityResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetTeacherAvailability not implemented")
+}
+func (UnimplementedTeacherServiceServer) mustEmbedUnimplementedTeacherServiceServer() {}
+func (UnimplementedTeacherServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafeTeacherServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TeacherServiceServer will
+// result in compilation errors.
+type UnsafeTeacherServiceServer interface {
+	mustEmbedUnimplementedTeacherServiceServer()
+}
+
+func RegisterTeacherServiceServer(s grpc.ServiceRegistrar, srv TeacherServiceServer) {
+	// If the following call panics, it indicates UnimplementedTeacherServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TeacherService_ServiceDesc, srv)
+}
+
+func _TeacherService_GetTeacher_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTeacherRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TeacherServiceServer).GetTeacher(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TeacherService_GetTeacher_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TeacherServiceServer).GetTeacher(ctx, req.(*GetTeacherRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TeacherService_ListTeachers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTeachersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TeacherServiceServer).ListTeachers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TeacherService_ListTeachers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TeacherServiceServer).ListTeachers(ctx, req.(*ListTeachersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TeacherService_GetTeacherAvailability_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTeacherAvailabilityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TeacherServiceServer).GetTeacherAvailability(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TeacherService_GetTeacherAvailability_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TeacherServiceServer).GetTeacherAvailability(ctx, req.(*GetTeacherAvailabilityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TeacherService_ServiceDesc is the grpc.ServiceDesc for TeacherService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TeacherService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "hr.v1.TeacherService",
+	HandlerType: (*TeacherServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetTeacher",
+			Handler:    _TeacherService_GetTeacher_Handler,
+		},
+		{
+			MethodName: "ListTeachers",
+			Handler:    _TeacherService_ListTeachers_Handler,
+		},
+		{
+			MethodName: "GetTeacherAvailability",
+			Handler:    _TeacherService_GetTeacherAvailability_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "hr/v1/teacher.proto",
+}
@@ -0,0 +1,705 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: subject/v1/subject.proto
+
+package subjectv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Subject struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name        string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Code        string                 `protobuf:"bytes,3,opt,name=code,proto3" json:"code,omitempty"`
+	Description string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	// Empty when the subject has no category.
+	CategoryId    string                 `protobuf:"bytes,5,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	Credits       int32                  `protobuf:"varint,6,opt,name=credits,proto3" json:"credits,omitempty"`
+	HoursPerWeek  int32                  `protobuf:"varint,7,opt,name=hours_per_week,json=hoursPerWeek,proto3" json:"hours_per_week,omitempty"`
+	IsActive      bool                   `protobuf:"varint,8,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Subject) Reset() {
+	*x = Subject{}
+	mi := &file_subject_v1_subject_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Subject) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Subject) ProtoMessage() {}
+
+func (x *Subject) ProtoReflect() protoreflect.Message {
+	mi := &file_subject_v1_subject_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Subject.ProtoReflect.Descriptor instead.
+func (*Subject) Descriptor() ([]byte, []int) {
+	return file_subject_v1_subject_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Subject) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Subject) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Subject) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Subject) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Subject) GetCategoryId() string {
+	if x != nil {
+		return x.CategoryId
+	}
+	return ""
+}
+
+func (x *Subject) GetCredits() int32 {
+	if x != nil {
+		return x.Credits
+	}
+	return 0
+}
+
+func (x *Subject) GetHoursPerWeek() int32 {
+	if x != nil {
+		return x.HoursPerWeek
+	}
+	return 0
+}
+
+func (x *Subject) GetIsActive() bool {
+	if x != nil {
+		return x.IsActive
+	}
+	return false
+}
+
+func (x *Subject) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Subject) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// PrerequisiteEdge means subject_id requires prerequisite_id to be completed first.
+type PrerequisiteEdge struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SubjectId      string                 `protobuf:"bytes,1,opt,name=subject_id,json=subjectId,proto3" json:"subject_id,omitempty"`
+	PrerequisiteId string                 `protobuf:"bytes,2,opt,name=prerequisite_id,json=prerequisiteId,proto3" json:"prerequisite_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PrerequisiteEdge) Reset() {
+	*x = PrerequisiteEdge{}
+	mi := &file_subject_v1_subject_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PrerequisiteEdge) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PrerequisiteEdge) ProtoMessage() {}
+
+func (x *PrerequisiteEdge) ProtoReflect() protoreflect.Message {
+	mi := &file_subject_v1_subject_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PrerequisiteEdge.ProtoReflect.Descriptor instead.
+func (*PrerequisiteEdge) Descriptor() ([]byte, []int) {
+	return file_subject_v1_subject_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PrerequisiteEdge) GetSubjectId() string {
+	if x != nil {
+		return x.SubjectId
+	}
+	return ""
+}
+
+func (x *PrerequisiteEdge) GetPrerequisiteId() string {
+	if x != nil {
+		return x.PrerequisiteId
+	}
+	return ""
+}
+
+type GetSubjectRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSubjectRequest) Reset() {
+	*x = GetSubjectRequest{}
+	mi := &file_subject_v1_subject_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSubjectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSubjectRequest) ProtoMessage() {}
+
+func (x *GetSubjectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subject_v1_subject_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSubjectRequest.ProtoReflect.Descriptor instead.
+func (*GetSubjectRequest) Descriptor() ([]byte, []int) {
+	return file_subject_v1_subject_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetSubjectRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetSubjectResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subject       *Subject               `protobuf:"bytes,1,opt,name=subject,proto3" json:"subject,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSubjectResponse) Reset() {
+	*x = GetSubjectResponse{}
+	mi := &file_subject_v1_subject_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSubjectResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSubjectResponse) ProtoMessage() {}
+
+func (x *GetSubjectResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subject_v1_subject_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSubjectResponse.ProtoReflect.Descriptor instead.
+func (*GetSubjectResponse) Descriptor() ([]byte, []int) {
+	return file_subject_v1_subject_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetSubjectResponse) GetSubject() *Subject {
+	if x != nil {
+		return x.Subject
+	}
+	return nil
+}
+
+type ListSubjectsRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	CategoryId string                 `protobuf:"bytes,1,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	Offset     int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	// Defaults to 20, capped at 100.
+	Limit         int32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSubjectsRequest) Reset() {
+	*x = ListSubjectsRequest{}
+	mi := &file_subject_v1_subject_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSubjectsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSubjectsRequest) ProtoMessage() {}
+
+func (x *ListSubjectsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subject_v1_subject_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSubjectsRequest.ProtoReflect.Descriptor instead.
+func (*ListSubjectsRequest) Descriptor() ([]byte, []int) {
+	return file_subject_v1_subject_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListSubjectsRequest) GetCategoryId() string {
+	if x != nil {
+		return x.CategoryId
+	}
+	return ""
+}
+
+func (x *ListSubjectsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ListSubjectsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListSubjectsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subjects      []*Subject             `protobuf:"bytes,1,rep,name=subjects,proto3" json:"subjects,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSubjectsResponse) Reset() {
+	*x = ListSubjectsResponse{}
+	mi := &file_subject_v1_subject_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSubjectsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSubjectsResponse) ProtoMessage() {}
+
+func (x *ListSubjectsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subject_v1_subject_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSubjectsResponse.ProtoReflect.Descriptor instead.
+func (*ListSubjectsResponse) Descriptor() ([]byte, []int) {
+	return file_subject_v1_subject_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListSubjectsResponse) GetSubjects() []*Subject {
+	if x != nil {
+		return x.Subjects
+	}
+	return nil
+}
+
+func (x *ListSubjectsResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type GetPrerequisitesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SubjectId     string                 `protobuf:"bytes,1,opt,name=subject_id,json=subjectId,proto3" json:"subject_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPrerequisitesRequest) Reset() {
+	*x = GetPrerequisitesRequest{}
+	mi := &file_subject_v1_subject_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPrerequisitesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPrerequisitesRequest) ProtoMessage() {}
+
+func (x *GetPrerequisitesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subject_v1_subject_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPrerequisitesRequest.ProtoReflect.Descriptor instead.
+func (*GetPrerequisitesRequest) Descriptor() ([]byte, []int) {
+	return file_subject_v1_subject_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetPrerequisitesRequest) GetSubjectId() string {
+	if x != nil {
+		return x.SubjectId
+	}
+	return ""
+}
+
+type GetPrerequisitesResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	SubjectId       string                 `protobuf:"bytes,1,opt,name=subject_id,json=subjectId,proto3" json:"subject_id,omitempty"`
+	PrerequisiteIds []string               `protobuf:"bytes,2,rep,name=prerequisite_ids,json=prerequisiteIds,proto3" json:"prerequisite_ids,omitempty"`
+	// Optimistic-locking version of the subject's prerequisite set.
+	Version       int32 `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPrerequisitesResponse) Reset() {
+	*x = GetPrerequisitesResponse{}
+	mi := &file_subject_v1_subject_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPrerequisitesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPrerequisitesResponse) ProtoMessage() {}
+
+func (x *GetPrerequisitesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subject_v1_subject_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPrerequisitesResponse.ProtoReflect.Descriptor instead.
+func (*GetPrerequisitesResponse) Descriptor() ([]byte, []int) {
+	return file_subject_v1_subject_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetPrerequisitesResponse) GetSubjectId() string {
+	if x != nil {
+		return x.SubjectId
+	}
+	return ""
+}
+
+func (x *GetPrerequisitesResponse) GetPrerequisiteIds() []string {
+	if x != nil {
+		return x.PrerequisiteIds
+	}
+	return nil
+}
+
+func (x *GetPrerequisitesResponse) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type GetPrerequisiteGraphRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPrerequisiteGraphRequest) Reset() {
+	*x = GetPrerequisiteGraphRequest{}
+	mi := &file_subject_v1_subject_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPrerequisiteGraphRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPrerequisiteGraphRequest) ProtoMessage() {}
+
+func (x *GetPrerequisiteGraphRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subject_v1_subject_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPrerequisiteGraphRequest.ProtoReflect.Descriptor instead.
+func (*GetPrerequisiteGraphRequest) Descriptor() ([]byte, []int) {
+	return file_subject_v1_subject_proto_rawDescGZIP(), []int{8}
+}
+
+type GetPrerequisiteGraphResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Edges         []*PrerequisiteEdge    `protobuf:"bytes,1,rep,name=edges,proto3" json:"edges,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPrerequisiteGraphResponse) Reset() {
+	*x = GetPrerequisiteGraphResponse{}
+	mi := &file_subject_v1_subject_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPrerequisiteGraphResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPrerequisiteGraphResponse) ProtoMessage() {}
+
+func (x *GetPrerequisiteGraphResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subject_v1_subject_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPrerequisiteGraphResponse.ProtoReflect.Descriptor instead.
+func (*GetPrerequisiteGraphResponse) Descriptor() ([]byte, []int) {
+	return file_subject_v1_subject_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetPrerequisiteGraphResponse) GetEdges() []*PrerequisiteEdge {
+	if x != nil {
+		return x.Edges
+	}
+	return nil
+}
+
+var File_subject_v1_subject_proto protoreflect.FileDescriptor
+
+const file_subject_v1_subject_proto_rawDesc = "" +
+	"\n" +
+	"\x18subject/v1/subject.proto\x12\n" +
+	"subject.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xd7\x02\n" +
+	"\aSubject\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04code\x18\x03 \x01(\tR\x04code\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12\x1f\n" +
+	"\vcategory_id\x18\x05 \x01(\tR\n" +
+	"categoryId\x12\x18\n" +
+	"\acredits\x18\x06 \x01(\x05R\acredits\x12$\n" +
+	"\x0ehours_per_week\x18\a \x01(\x05R\fhoursPerWeek\x12\x1b\n" +
+	"\tis_active\x18\b \x01(\bR\bisActive\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"Z\n" +
+	"\x10PrerequisiteEdge\x12\x1d\n" +
+	"\n" +
+	"subject_id\x18\x01 \x01(\tR\tsubjectId\x12'\n" +
+	"\x0fprerequisite_id\x18\x02 \x01(\tR\x0eprerequisiteId\"#\n" +
+	"\x11GetSubjectRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"C\n" +
+	"\x12GetSubjectResponse\x12-\n" +
+	"\asubject\x18\x01 \x01(\v2\x13.subject.v1.SubjectR\asubject\"d\n" +
+	"\x13ListSubjectsRequest\x12\x1f\n" +
+	"\vcategory_id\x18\x01 \x01(\tR\n" +
+	"categoryId\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offset\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\"]\n" +
+	"\x14ListSubjectsResponse\x12/\n" +
+	"\bsubjects\x18\x01 \x03(\v2\x13.subject.v1.SubjectR\bsubjects\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\"8\n" +
+	"\x17GetPrerequisitesRequest\x12\x1d\n" +
+	"\n" +
+	"subject_id\x18\x01 \x01(\tR\tsubjectId\"~\n" +
+	"\x18GetPrerequisitesResponse\x12\x1d\n" +
+	"\n" +
+	"subject_id\x18\x01 \x01(\tR\tsubjectId\x12)\n" +
+	"\x10prerequisite_ids\x18\x02 \x03(\tR\x0fprerequisiteIds\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x05R\aversion\"\x1d\n" +
+	"\x1bGetPrerequisiteGraphRequest\"R\n" +
+	"\x1cGetPrerequisiteGraphResponse\x122\n" +
+	"\x05edges\x18\x01 \x03(\v2\x1c.subject.v1.PrerequisiteEdgeR\x05edges2\xfa\x02\n" +
+	"\x0eSubjectService\x12K\n" +
+	"\n" +
+	"GetSubject\x12\x1d.subject.v1.GetSubjectRequest\x1a\x1e.subject.v1.GetSubjectResponse\x12Q\n" +
+	"\fListSubjects\x12\x1f.subject.v1.ListSubjectsRequest\x1a .subject.v1.ListSubjectsResponse\x12]\n" +
+	"\x10GetPrerequisites\x12#.subject.v1.GetPrerequisitesRequest\x1a$.subject.v1.GetPrerequisitesResponse\x12i\n" +
+	"\x14GetPrerequisiteGraph\x12'.subject.v1.GetPrerequisiteGraphRequest\x1a(.subject.v1.GetPrerequisiteGraphResponseBBZ@github.com/HuynhHoangPhuc/mcs-erp/proto/gen/subject/v1;subjectv1b\x06proto3"
+
+var (
+	file_subject_v1_subject_proto_rawDescOnce sync.Once
+	file_subject_v1_subject_proto_rawDescData []byte
+)
+
+func file_subject_v1_subject_proto_rawDescGZIP() []byte {
+	file_subject_v1_subject_proto_rawDescOnce.Do(func() {
+		file_subject_v1_subject_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_subject_v1_subject_proto_rawDesc), len(file_subject_v1_subject_proto_rawDesc)))
+	})
+	return file_subject_v1_subject_proto_rawDescData
+}
+
+var file_subject_v1_subject_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_subject_v1_subject_proto_goTypes = []any{
+	(*Subject)(nil),                      // 0: subject.v1.Subject
+	(*PrerequisiteEdge)(nil),             // 1: subject.v1.PrerequisiteEdge
+	(*GetSubjectRequest)(nil),            // 2: subject.v1.GetSubjectRequest
+	(*GetSubjectResponse)(nil),           // 3: subject.v1.GetSubjectResponse
+	(*ListSubjectsRequest)(nil),          // 4: subject.v1.ListSubjectsRequest
+	(*ListSubjectsResponse)(nil),         // 5: subject.v1.ListSubjectsResponse
+	(*GetPrerequisitesRequest)(nil),      // 6: subject.v1.GetPrerequisitesRequest
+	(*GetPrerequisitesResponse)(nil),     // 7: subject.v1.GetPrerequisitesResponse
+	(*GetPrerequisiteGraphRequest)(nil),  // 8: subject.v1.GetPrerequisiteGraphRequest
+	(*GetPrerequisiteGraphResponse)(nil), // 9: subject.v1.GetPrerequisiteGraphResponse
+	(*timestamppb.Timestamp)(nil),        // 10: google.protobuf.Timestamp
+}
+var file_subject_v1_subject_proto_depIdxs = []int32{
+	10, // 0: subject.v1.Subject.created_at:type_name -> google.protobuf.Timestamp
+	10, // 1: subject.v1.Subject.updated_at:type_name -> google.protobuf.Timestamp
+	0,  // 2: subject.v1.GetSubjectResponse.subject:type_name -> subject.v1.Subject
+	0,  // 3: subject.v1.ListSubjectsResponse.subjects:type_name -> subject.v1.Subject
+	1,  // 4: subject.v1.GetPrerequisiteGraphResponse.edges:type_name -> subject.v1.PrerequisiteEdge
+	2,  // 5: subject.v1.SubjectService.GetSubject:input_type -> subject.v1.GetSubjectRequest
+	4,  // 6: subject.v1.SubjectService.ListSubjects:input_type -> subject.v1.ListSubjectsRequest
+	6,  // 7: subject.v1.SubjectService.GetPrerequisites:input_type -> subject.v1.GetPrerequisitesRequest
+	8,  // 8: subject.v1.SubjectService.GetPrerequisiteGraph:input_type -> subject.v1.GetPrerequisiteGraphRequest
+	3,  // 9: subject.v1.SubjectService.GetSubject:output_type -> subject.v1.GetSubjectResponse
+	5,  // 10: subject.v1.SubjectService.ListSubjects:output_type -> subject.v1.ListSubjectsResponse
+	7,  // 11: subject.v1.SubjectService.GetPrerequisites:output_type -> subject.v1.GetPrerequisitesResponse
+	9,  // 12: subject.v1.SubjectService.GetPrerequisiteGraph:output_type -> subject.v1.GetPrerequisiteGraphResponse
+	9,  // [9:13] is the sub-list for method output_type
+	5,  // [5:9] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_subject_v1_subject_proto_init() }
+func file_subject_v1_subject_proto_init() {
+	if File_subject_v1_subject_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_subject_v1_subject_proto_rawDesc), len(file_subject_v1_subject_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_subject_v1_subject_proto_goTypes,
+		DependencyIndexes: file_subject_v1_subject_proto_depIdxs,
+		MessageInfos:      file_subject_v1_subject_proto_msgTypes,
+	}.Build()
+	File_subject_v1_subject_proto = out.File
+	file_subject_v1_subject_proto_goTypes = nil
+	file_subject_v1_subject_proto_depIdxs = nil
+}
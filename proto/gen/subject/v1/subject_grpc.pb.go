@@ -0,0 +1,241 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: subject/v1/subject.proto
+
+package subjectv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	SubjectService_GetSubject_FullMethodName           = "/subject.v1.SubjectService/GetSubject"
+	SubjectService_ListSubjects_FullMethodName         = "/subject.v1.SubjectService/ListSubjects"
+	SubjectService_GetPrerequisites_FullMethodName     = "/subject.v1.SubjectService/GetPrerequisites"
+	SubjectService_GetPrerequisiteGraph_FullMethodName = "/subject.v1.SubjectService/GetPrerequisiteGraph"
+)
+
+// SubjectServiceClient is the client API for SubjectService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// SubjectService exposes read access to subjects and the prerequisite graph.
+// Requires subject:subject:read.
+type SubjectServiceClient interface {
+	GetSubject(ctx context.Context, in *GetSubjectRequest, opts ...grpc.CallOption) (*GetSubjectResponse, error)
+	ListSubjects(ctx context.Context, in *ListSubjectsRequest, opts ...grpc.CallOption) (*ListSubjectsResponse, error)
+	GetPrerequisites(ctx context.Context, in *GetPrerequisitesRequest, opts ...grpc.CallOption) (*GetPrerequisitesResponse, error)
+	GetPrerequisiteGraph(ctx context.Context, in *GetPrerequisiteGraphRequest, opts ...grpc.CallOption) (*GetPrerequisiteGraphResponse, error)
+}
+
+type subjectServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSubjectServiceClient(cc grpc.ClientConnInterface) SubjectServiceClient {
+	return &subjectServiceClient{cc}
+}
+
+func (c *subjectServiceClient) GetSubject(ctx context.Context, in *GetSubjectRequest, opts ...grpc.CallOption) (*GetSubjectResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSubjectResponse)
+	err := c.cc.Invoke(ctx, SubjectService_GetSubject_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subjectServiceClient) ListSubjects(ctx context.Context, in *ListSubjectsRequest, opts ...grpc.CallOption) (*ListSubjectsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSubjectsResponse)
+	err := c.cc.Invoke(ctx, SubjectService_ListSubjects_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subjectServiceClient) GetPrerequisites(ctx context.Context, in *GetPrerequisitesRequest, opts ...grpc.CallOption) (*GetPrerequisitesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetPrerequisitesResponse)
+	err := c.cc.Invoke(ctx, SubjectService_GetPrerequisites_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subjectServiceClient) GetPrerequisiteGraph(ctx context.Context, in *GetPrerequisiteGraphRequest, opts ...grpc.CallOption) (*GetPrerequisiteGraphResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetPrerequisiteGraphResponse)
+	err := c.cc.Invoke(ctx, SubjectService_GetPrerequisiteGraph_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SubjectServiceServer is the server API for SubjectService service.
+// All implementations must embed UnimplementedSubjectServiceServer
+// for forward compatibility.
+//
+// SubjectService exposes read access to subjects and the prerequisite graph.
+// Requires subject:subject:read.
+type SubjectServiceServer interface {
+	GetSubject(context.Context, *GetSubjectRequest) (*GetSubjectResponse, error)
+	ListSubjects(context.Context, *ListSubjectsRequest) (*ListSubjectsResponse, error)
+	GetPrerequisites(context.Context, *GetPrerequisitesRequest) (*GetPrerequisitesResponse, error)
+	GetPrerequisiteGraph(context.Context, *GetPrerequisiteGraphRequest) (*GetPrerequisiteGraphResponse, error)
+	mustEmbedUnimplementedSubjectServiceServer()
+}
+
+// UnimplementedSubjectServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSubjectServiceServer struct{}
+
+func (UnimplementedSubjectServiceServer) GetSubject(context.Context, *GetSubjectRequest) (*GetSubjectResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetSubject not implemented")
+}
+func (UnimplementedSubjectServiceServer) ListSubjects(context.Context, *ListSubjectsRequest) (*ListSubjectsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListSubjects not implemented")
+}
+func (UnimplementedSubjectServiceServer) GetPrerequisites(context.Context, *GetPrerequisitesRequest) (*GetPrerequisitesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetPrerequisites not implemented")
+}
+func (UnimplementedSubjectServiceServer) GetPrerequisiteGraph(context.Context, *GetPrerequisiteGraphRequest) (*GetPrerequisiteGraphResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetPrerequisiteGraph not implemented")
+}
+func (UnimplementedSubjectServiceServer) mustEmbedUnimplementedSubjectServiceServer() {}
+func (UnimplementedSubjectServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafeSubjectServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SubjectServiceServer will
+// result in compilation errors.
+type UnsafeSubjectServiceServer interface {
+	mustEmbedUnimplementedSubjectServiceServer()
+}
+
+func RegisterSubjectServiceServer(s grpc.ServiceRegistrar, srv SubjectServiceServer) {
+	// If the following call panics, it indicates UnimplementedSubjectServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SubjectService_ServiceDesc, srv)
+}
+
+func _SubjectService_GetSubject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSubjectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubjectServiceServer).GetSubject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SubjectService_GetSubject_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubjectServiceServer).GetSubject(ctx, req.(*GetSubjectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubjectService_ListSubjects_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSubjectsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubjectServiceServer).ListSubjects(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SubjectService_ListSubjects_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubjectServiceServer).ListSubjects(ctx, req.(*ListSubjectsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubjectService_GetPrerequisites_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPrerequisitesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubjectServiceServer).GetPrerequisites(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SubjectService_GetPrerequisites_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubjectServiceServer).GetPrerequisites(ctx, req.(*GetPrerequisitesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubjectService_GetPrerequisiteGraph_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPrerequisiteGraphRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubjectServiceServer).GetPrerequisiteGraph(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SubjectService_GetPrerequisiteGraph_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubjectServiceServer).GetPrerequisiteGraph(ctx, req.(*GetPrerequisiteGraphRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SubjectService_ServiceDesc is the grpc.ServiceDesc for SubjectService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SubjectService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "subject.v1.SubjectService",
+	HandlerType: (*SubjectServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetSubject",
+			Handler:    _SubjectService_GetSubject_Handler,
+		},
+		{
+			MethodName: "ListSubjects",
+			Handler:    _SubjectService_ListSubjects_Handler,
+		},
+		{
+			MethodName: "GetPrerequisites",
+			Handler:    _SubjectService_GetPrerequisites_Handler,
+		},
+		{
+			MethodName: "GetPrerequisiteGraph",
+			Handler:    _SubjectService_GetPrerequisiteGraph_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "subject/v1/subject.proto",
+}
@@ -0,0 +1,1010 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: timetable/v1/timetable.proto
+
+package timetablev1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Semester struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name      string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	StartDate *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate   *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	// draft | scheduling | review | approved | rejected
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	Subjects      []*SemesterSubject     `protobuf:"bytes,6,rep,name=subjects,proto3" json:"subjects,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Semester) Reset() {
+	*x = Semester{}
+	mi := &file_timetable_v1_timetable_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Semester) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Semester) ProtoMessage() {}
+
+func (x *Semester) ProtoReflect() protoreflect.Message {
+	mi := &file_timetable_v1_timetable_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Semester.ProtoReflect.Descriptor instead.
+func (*Semester) Descriptor() ([]byte, []int) {
+	return file_timetable_v1_timetable_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Semester) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Semester) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Semester) GetStartDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *Semester) GetEndDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+func (x *Semester) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Semester) GetSubjects() []*SemesterSubject {
+	if x != nil {
+		return x.Subjects
+	}
+	return nil
+}
+
+func (x *Semester) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Semester) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type SemesterSubject struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	SubjectId string                 `protobuf:"bytes,1,opt,name=subject_id,json=subjectId,proto3" json:"subject_id,omitempty"`
+	// Empty until a teacher is assigned.
+	TeacherId     string `protobuf:"bytes,2,opt,name=teacher_id,json=teacherId,proto3" json:"teacher_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SemesterSubject) Reset() {
+	*x = SemesterSubject{}
+	mi := &file_timetable_v1_timetable_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SemesterSubject) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SemesterSubject) ProtoMessage() {}
+
+func (x *SemesterSubject) ProtoReflect() protoreflect.Message {
+	mi := &file_timetable_v1_timetable_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SemesterSubject.ProtoReflect.Descriptor instead.
+func (*SemesterSubject) Descriptor() ([]byte, []int) {
+	return file_timetable_v1_timetable_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SemesterSubject) GetSubjectId() string {
+	if x != nil {
+		return x.SubjectId
+	}
+	return ""
+}
+
+func (x *SemesterSubject) GetTeacherId() string {
+	if x != nil {
+		return x.TeacherId
+	}
+	return ""
+}
+
+type Assignment struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	SubjectId string                 `protobuf:"bytes,2,opt,name=subject_id,json=subjectId,proto3" json:"subject_id,omitempty"`
+	TeacherId string                 `protobuf:"bytes,3,opt,name=teacher_id,json=teacherId,proto3" json:"teacher_id,omitempty"`
+	RoomId    string                 `protobuf:"bytes,4,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	// 0=Monday..5=Saturday
+	Day           int32 `protobuf:"varint,5,opt,name=day,proto3" json:"day,omitempty"`
+	Period        int32 `protobuf:"varint,6,opt,name=period,proto3" json:"period,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Assignment) Reset() {
+	*x = Assignment{}
+	mi := &file_timetable_v1_timetable_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Assignment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Assignment) ProtoMessage() {}
+
+func (x *Assignment) ProtoReflect() protoreflect.Message {
+	mi := &file_timetable_v1_timetable_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Assignment.ProtoReflect.Descriptor instead.
+func (*Assignment) Descriptor() ([]byte, []int) {
+	return file_timetable_v1_timetable_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Assignment) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Assignment) GetSubjectId() string {
+	if x != nil {
+		return x.SubjectId
+	}
+	return ""
+}
+
+func (x *Assignment) GetTeacherId() string {
+	if x != nil {
+		return x.TeacherId
+	}
+	return ""
+}
+
+func (x *Assignment) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *Assignment) GetDay() int32 {
+	if x != nil {
+		return x.Day
+	}
+	return 0
+}
+
+func (x *Assignment) GetPeriod() int32 {
+	if x != nil {
+		return x.Period
+	}
+	return 0
+}
+
+type Schedule struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SemesterId     string                 `protobuf:"bytes,1,opt,name=semester_id,json=semesterId,proto3" json:"semester_id,omitempty"`
+	Version        int32                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	Assignments    []*Assignment          `protobuf:"bytes,3,rep,name=assignments,proto3" json:"assignments,omitempty"`
+	HardViolations int32                  `protobuf:"varint,4,opt,name=hard_violations,json=hardViolations,proto3" json:"hard_violations,omitempty"`
+	SoftPenalty    float64                `protobuf:"fixed64,5,opt,name=soft_penalty,json=softPenalty,proto3" json:"soft_penalty,omitempty"`
+	GeneratedAt    *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=generated_at,json=generatedAt,proto3" json:"generated_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Schedule) Reset() {
+	*x = Schedule{}
+	mi := &file_timetable_v1_timetable_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Schedule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Schedule) ProtoMessage() {}
+
+func (x *Schedule) ProtoReflect() protoreflect.Message {
+	mi := &file_timetable_v1_timetable_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Schedule.ProtoReflect.Descriptor instead.
+func (*Schedule) Descriptor() ([]byte, []int) {
+	return file_timetable_v1_timetable_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Schedule) GetSemesterId() string {
+	if x != nil {
+		return x.SemesterId
+	}
+	return ""
+}
+
+func (x *Schedule) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Schedule) GetAssignments() []*Assignment {
+	if x != nil {
+		return x.Assignments
+	}
+	return nil
+}
+
+func (x *Schedule) GetHardViolations() int32 {
+	if x != nil {
+		return x.HardViolations
+	}
+	return 0
+}
+
+func (x *Schedule) GetSoftPenalty() float64 {
+	if x != nil {
+		return x.SoftPenalty
+	}
+	return 0
+}
+
+func (x *Schedule) GetGeneratedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.GeneratedAt
+	}
+	return nil
+}
+
+type GetSemesterRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSemesterRequest) Reset() {
+	*x = GetSemesterRequest{}
+	mi := &file_timetable_v1_timetable_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSemesterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSemesterRequest) ProtoMessage() {}
+
+func (x *GetSemesterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_timetable_v1_timetable_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSemesterRequest.ProtoReflect.Descriptor instead.
+func (*GetSemesterRequest) Descriptor() ([]byte, []int) {
+	return file_timetable_v1_timetable_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetSemesterRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetSemesterResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Semester      *Semester              `protobuf:"bytes,1,opt,name=semester,proto3" json:"semester,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSemesterResponse) Reset() {
+	*x = GetSemesterResponse{}
+	mi := &file_timetable_v1_timetable_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSemesterResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSemesterResponse) ProtoMessage() {}
+
+func (x *GetSemesterResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_timetable_v1_timetable_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSemesterResponse.ProtoReflect.Descriptor instead.
+func (*GetSemesterResponse) Descriptor() ([]byte, []int) {
+	return file_timetable_v1_timetable_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetSemesterResponse) GetSemester() *Semester {
+	if x != nil {
+		return x.Semester
+	}
+	return nil
+}
+
+type ListSemestersRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Offset int32                  `protobuf:"varint,1,opt,name=offset,proto3" json:"offset,omitempty"`
+	// Defaults to 20, capped at 100.
+	Limit         int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSemestersRequest) Reset() {
+	*x = ListSemestersRequest{}
+	mi := &file_timetable_v1_timetable_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSemestersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSemestersRequest) ProtoMessage() {}
+
+func (x *ListSemestersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_timetable_v1_timetable_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSemestersRequest.ProtoReflect.Descriptor instead.
+func (*ListSemestersRequest) Descriptor() ([]byte, []int) {
+	return file_timetable_v1_timetable_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListSemestersRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ListSemestersRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListSemestersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Semesters     []*Semester            `protobuf:"bytes,1,rep,name=semesters,proto3" json:"semesters,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSemestersResponse) Reset() {
+	*x = ListSemestersResponse{}
+	mi := &file_timetable_v1_timetable_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSemestersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSemestersResponse) ProtoMessage() {}
+
+func (x *ListSemestersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_timetable_v1_timetable_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSemestersResponse.ProtoReflect.Descriptor instead.
+func (*ListSemestersResponse) Descriptor() ([]byte, []int) {
+	return file_timetable_v1_timetable_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListSemestersResponse) GetSemesters() []*Semester {
+	if x != nil {
+		return x.Semesters
+	}
+	return nil
+}
+
+func (x *ListSemestersResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type GetScheduleRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	SemesterId string                 `protobuf:"bytes,1,opt,name=semester_id,json=semesterId,proto3" json:"semester_id,omitempty"`
+	// Zero selects the latest version.
+	Version       int32 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetScheduleRequest) Reset() {
+	*x = GetScheduleRequest{}
+	mi := &file_timetable_v1_timetable_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetScheduleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetScheduleRequest) ProtoMessage() {}
+
+func (x *GetScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_timetable_v1_timetable_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetScheduleRequest.ProtoReflect.Descriptor instead.
+func (*GetScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_timetable_v1_timetable_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetScheduleRequest) GetSemesterId() string {
+	if x != nil {
+		return x.SemesterId
+	}
+	return ""
+}
+
+func (x *GetScheduleRequest) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type GetScheduleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Schedule      *Schedule              `protobuf:"bytes,1,opt,name=schedule,proto3" json:"schedule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetScheduleResponse) Reset() {
+	*x = GetScheduleResponse{}
+	mi := &file_timetable_v1_timetable_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetScheduleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetScheduleResponse) ProtoMessage() {}
+
+func (x *GetScheduleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_timetable_v1_timetable_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetScheduleResponse.ProtoReflect.Descriptor instead.
+func (*GetScheduleResponse) Descriptor() ([]byte, []int) {
+	return file_timetable_v1_timetable_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetScheduleResponse) GetSchedule() *Schedule {
+	if x != nil {
+		return x.Schedule
+	}
+	return nil
+}
+
+type GenerateScheduleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SemesterId    string                 `protobuf:"bytes,1,opt,name=semester_id,json=semesterId,proto3" json:"semester_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateScheduleRequest) Reset() {
+	*x = GenerateScheduleRequest{}
+	mi := &file_timetable_v1_timetable_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateScheduleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateScheduleRequest) ProtoMessage() {}
+
+func (x *GenerateScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_timetable_v1_timetable_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateScheduleRequest.ProtoReflect.Descriptor instead.
+func (*GenerateScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_timetable_v1_timetable_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GenerateScheduleRequest) GetSemesterId() string {
+	if x != nil {
+		return x.SemesterId
+	}
+	return ""
+}
+
+// WorkerProgress is a snapshot of one annealing worker's best result so far.
+type WorkerProgress struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Worker         int32                  `protobuf:"varint,1,opt,name=worker,proto3" json:"worker,omitempty"`
+	Iteration      int32                  `protobuf:"varint,2,opt,name=iteration,proto3" json:"iteration,omitempty"`
+	Temperature    float64                `protobuf:"fixed64,3,opt,name=temperature,proto3" json:"temperature,omitempty"`
+	BestCost       int64                  `protobuf:"varint,4,opt,name=best_cost,json=bestCost,proto3" json:"best_cost,omitempty"`
+	HardViolations int32                  `protobuf:"varint,5,opt,name=hard_violations,json=hardViolations,proto3" json:"hard_violations,omitempty"`
+	SoftPenalty    int32                  `protobuf:"varint,6,opt,name=soft_penalty,json=softPenalty,proto3" json:"soft_penalty,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *WorkerProgress) Reset() {
+	*x = WorkerProgress{}
+	mi := &file_timetable_v1_timetable_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkerProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerProgress) ProtoMessage() {}
+
+func (x *WorkerProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_timetable_v1_timetable_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerProgress.ProtoReflect.Descriptor instead.
+func (*WorkerProgress) Descriptor() ([]byte, []int) {
+	return file_timetable_v1_timetable_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *WorkerProgress) GetWorker() int32 {
+	if x != nil {
+		return x.Worker
+	}
+	return 0
+}
+
+func (x *WorkerProgress) GetIteration() int32 {
+	if x != nil {
+		return x.Iteration
+	}
+	return 0
+}
+
+func (x *WorkerProgress) GetTemperature() float64 {
+	if x != nil {
+		return x.Temperature
+	}
+	return 0
+}
+
+func (x *WorkerProgress) GetBestCost() int64 {
+	if x != nil {
+		return x.BestCost
+	}
+	return 0
+}
+
+func (x *WorkerProgress) GetHardViolations() int32 {
+	if x != nil {
+		return x.HardViolations
+	}
+	return 0
+}
+
+func (x *WorkerProgress) GetSoftPenalty() int32 {
+	if x != nil {
+		return x.SoftPenalty
+	}
+	return 0
+}
+
+type GenerateScheduleResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*GenerateScheduleResponse_Progress
+	//	*GenerateScheduleResponse_Schedule
+	Event         isGenerateScheduleResponse_Event `protobuf_oneof:"event"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateScheduleResponse) Reset() {
+	*x = GenerateScheduleResponse{}
+	mi := &file_timetable_v1_timetable_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateScheduleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateScheduleResponse) ProtoMessage() {}
+
+func (x *GenerateScheduleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_timetable_v1_timetable_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateScheduleResponse.ProtoReflect.Descriptor instead.
+func (*GenerateScheduleResponse) Descriptor() ([]byte, []int) {
+	return file_timetable_v1_timetable_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GenerateScheduleResponse) GetEvent() isGenerateScheduleResponse_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *GenerateScheduleResponse) GetProgress() *WorkerProgress {
+	if x != nil {
+		if x, ok := x.Event.(*GenerateScheduleResponse_Progress); ok {
+			return x.Progress
+		}
+	}
+	return nil
+}
+
+func (x *GenerateScheduleResponse) GetSchedule() *Schedule {
+	if x != nil {
+		if x, ok := x.Event.(*GenerateScheduleResponse_Schedule); ok {
+			return x.Schedule
+		}
+	}
+	return nil
+}
+
+type isGenerateScheduleResponse_Event interface {
+	isGenerateScheduleResponse_Event()
+}
+
+type GenerateScheduleResponse_Progress struct {
+	Progress *WorkerProgress `protobuf:"bytes,1,opt,name=progress,proto3,oneof"`
+}
+
+type GenerateScheduleResponse_Schedule struct {
+	// Sent once, as the last message.
+	Schedule *Schedule `protobuf:"bytes,2,opt,name=schedule,proto3,oneof"`
+}
+
+func (*GenerateScheduleResponse_Progress) isGenerateScheduleResponse_Event() {}
+
+func (*GenerateScheduleResponse_Schedule) isGenerateScheduleResponse_Event() {}
+
+var File_timetable_v1_timetable_proto protoreflect.FileDescriptor
+
+const file_timetable_v1_timetable_proto_rawDesc = "" +
+	"\n" +
+	"\x1ctimetable/v1/timetable.proto\x12\ftimetable.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe9\x02\n" +
+	"\bSemester\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x129\n" +
+	"\n" +
+	"start_date\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tstartDate\x125\n" +
+	"\bend_date\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\aendDate\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x129\n" +
+	"\bsubjects\x18\x06 \x03(\v2\x1d.timetable.v1.SemesterSubjectR\bsubjects\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"O\n" +
+	"\x0fSemesterSubject\x12\x1d\n" +
+	"\n" +
+	"subject_id\x18\x01 \x01(\tR\tsubjectId\x12\x1d\n" +
+	"\n" +
+	"teacher_id\x18\x02 \x01(\tR\tteacherId\"\x9d\x01\n" +
+	"\n" +
+	"Assignment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"subject_id\x18\x02 \x01(\tR\tsubjectId\x12\x1d\n" +
+	"\n" +
+	"teacher_id\x18\x03 \x01(\tR\tteacherId\x12\x17\n" +
+	"\aroom_id\x18\x04 \x01(\tR\x06roomId\x12\x10\n" +
+	"\x03day\x18\x05 \x01(\x05R\x03day\x12\x16\n" +
+	"\x06period\x18\x06 \x01(\x05R\x06period\"\x8c\x02\n" +
+	"\bSchedule\x12\x1f\n" +
+	"\vsemester_id\x18\x01 \x01(\tR\n" +
+	"semesterId\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x05R\aversion\x12:\n" +
+	"\vassignments\x18\x03 \x03(\v2\x18.timetable.v1.AssignmentR\vassignments\x12'\n" +
+	"\x0fhard_violations\x18\x04 \x01(\x05R\x0ehardViolations\x12!\n" +
+	"\fsoft_penalty\x18\x05 \x01(\x01R\vsoftPenalty\x12=\n" +
+	"\fgenerated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\vgeneratedAt\"$\n" +
+	"\x12GetSemesterRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"I\n" +
+	"\x13GetSemesterResponse\x122\n" +
+	"\bsemester\x18\x01 \x01(\v2\x16.timetable.v1.SemesterR\bsemester\"D\n" +
+	"\x14ListSemestersRequest\x12\x16\n" +
+	"\x06offset\x18\x01 \x01(\x05R\x06offset\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"c\n" +
+	"\x15ListSemestersResponse\x124\n" +
+	"\tsemesters\x18\x01 \x03(\v2\x16.timetable.v1.SemesterR\tsemesters\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\"O\n" +
+	"\x12GetScheduleRequest\x12\x1f\n" +
+	"\vsemester_id\x18\x01 \x01(\tR\n" +
+	"semesterId\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x05R\aversion\"I\n" +
+	"\x13GetScheduleResponse\x122\n" +
+	"\bschedule\x18\x01 \x01(\v2\x16.timetable.v1.ScheduleR\bschedule\":\n" +
+	"\x17GenerateScheduleRequest\x12\x1f\n" +
+	"\vsemester_id\x18\x01 \x01(\tR\n" +
+	"semesterId\"\xd1\x01\n" +
+	"\x0eWorkerProgress\x12\x16\n" +
+	"\x06worker\x18\x01 \x01(\x05R\x06worker\x12\x1c\n" +
+	"\titeration\x18\x02 \x01(\x05R\titeration\x12 \n" +
+	"\vtemperature\x18\x03 \x01(\x01R\vtemperature\x12\x1b\n" +
+	"\tbest_cost\x18\x04 \x01(\x03R\bbestCost\x12'\n" +
+	"\x0fhard_violations\x18\x05 \x01(\x05R\x0ehardViolations\x12!\n" +
+	"\fsoft_penalty\x18\x06 \x01(\x05R\vsoftPenalty\"\x95\x01\n" +
+	"\x18GenerateScheduleResponse\x12:\n" +
+	"\bprogress\x18\x01 \x01(\v2\x1c.timetable.v1.WorkerProgressH\x00R\bprogress\x124\n" +
+	"\bschedule\x18\x02 \x01(\v2\x16.timetable.v1.ScheduleH\x00R\bscheduleB\a\n" +
+	"\x05event2\xbf\x01\n" +
+	"\x0fSemesterService\x12R\n" +
+	"\vGetSemester\x12 .timetable.v1.GetSemesterRequest\x1a!.timetable.v1.GetSemesterResponse\x12X\n" +
+	"\rListSemesters\x12\".timetable.v1.ListSemestersRequest\x1a#.timetable.v1.ListSemestersResponse2\xca\x01\n" +
+	"\x0fScheduleService\x12R\n" +
+	"\vGetSchedule\x12 .timetable.v1.GetScheduleRequest\x1a!.timetable.v1.GetScheduleResponse\x12c\n" +
+	"\x10GenerateSchedule\x12%.timetable.v1.GenerateScheduleRequest\x1a&.timetable.v1.GenerateScheduleResponse0\x01BFZDgithub.com/HuynhHoangPhuc/mcs-erp/proto/gen/timetable/v1;timetablev1b\x06proto3"
+
+var (
+	file_timetable_v1_timetable_proto_rawDescOnce sync.Once
+	file_timetable_v1_timetable_proto_rawDescData []byte
+)
+
+func file_timetable_v1_timetable_proto_rawDescGZIP() []byte {
+	file_timetable_v1_timetable_proto_rawDescOnce.Do(func() {
+		file_timetable_v1_timetable_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_timetable_v1_timetable_proto_rawDesc), len(file_timetable_v1_timetable_proto_rawDesc)))
+	})
+	return file_timetable_v1_timetable_proto_rawDescData
+}
+
+var file_timetable_v1_timetable_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_timetable_v1_timetable_proto_goTypes = []any{
+	(*Semester)(nil),                 // 0: timetable.v1.Semester
+	(*SemesterSubject)(nil),          // 1: timetable.v1.SemesterSubject
+	(*Assignment)(nil),               // 2: timetable.v1.Assignment
+	(*Schedule)(nil),                 // 3: timetable.v1.Schedule
+	(*GetSemesterRequest)(nil),       // 4: timetable.v1.GetSemesterRequest
+	(*GetSemesterResponse)(nil),      // 5: timetable.v1.GetSemesterResponse
+	(*ListSemestersRequest)(nil),     // 6: timetable.v1.ListSemestersRequest
+	(*ListSemestersResponse)(nil),    // 7: timetable.v1.ListSemestersResponse
+	(*GetScheduleRequest)(nil),       // 8: timetable.v1.GetScheduleRequest
+	(*GetScheduleResponse)(nil),      // 9: timetable.v1.GetScheduleResponse
+	(*GenerateScheduleRequest)(nil),  // 10: timetable.v1.GenerateScheduleRequest
+	(*WorkerProgress)(nil),           // 11: timetable.v1.WorkerProgress
+	(*GenerateScheduleResponse)(nil), // 12: timetable.v1.GenerateScheduleResponse
+	(*timestamppb.Timestamp)(nil),    // 13: google.protobuf.Timestamp
+}
+var file_timetable_v1_timetable_proto_depIdxs = []int32{
+	13, // 0: timetable.v1.Semester.start_date:type_name -> google.protobuf.Timestamp
+	13, // 1: timetable.v1.Semester.end_date:type_name -> google.protobuf.Timestamp
+	1,  // 2: timetable.v1.Semester.subjects:type_name -> timetable.v1.SemesterSubject
+	13, // 3: timetable.v1.Semester.created_at:type_name -> google.protobuf.Timestamp
+	13, // 4: timetable.v1.Semester.updated_at:type_name -> google.protobuf.Timestamp
+	2,  // 5: timetable.v1.Schedule.assignments:type_name -> timetable.v1.Assignment
+	13, // 6: timetable.v1.Schedule.generated_at:type_name -> google.protobuf.Timestamp
+	0,  // 7: timetable.v1.GetSemesterResponse.semester:type_name -> timetable.v1.Semester
+	0,  // 8: timetable.v1.ListSemestersResponse.semesters:type_name -> timetable.v1.Semester
+	3,  // 9: timetable.v1.GetScheduleResponse.schedule:type_name -> timetable.v1.Schedule
+	11, // 10: timetable.v1.GenerateScheduleResponse.progress:type_name -> timetable.v1.WorkerProgress
+	3,  // 11: timetable.v1.GenerateScheduleResponse.schedule:type_name -> timetable.v1.Schedule
+	4,  // 12: timetable.v1.SemesterService.GetSemester:input_type -> timetable.v1.GetSemesterRequest
+	6,  // 13: timetable.v1.SemesterService.ListSemesters:input_type -> timetable.v1.ListSemestersRequest
+	8,  // 14: timetable.v1.ScheduleService.GetSchedule:input_type -> timetable.v1.GetScheduleRequest
+	10, // 15: timetable.v1.ScheduleService.GenerateSchedule:input_type -> timetable.v1.GenerateScheduleRequest
+	5,  // 16: timetable.v1.SemesterService.GetSemester:output_type -> timetable.v1.GetSemesterResponse
+	7,  // 17: timetable.v1.SemesterService.ListSemesters:output_type -> timetable.v1.ListSemestersResponse
+	9,  // 18: timetable.v1.ScheduleService.GetSchedule:output_type -> timetable.v1.GetScheduleResponse
+	12, // 19: timetable.v1.ScheduleService.GenerateSchedule:output_type -> timetable.v1.GenerateScheduleResponse
+	16, // [16:20] is the sub-list for method output_type
+	12, // [12:16] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
+}
+
+func init() { file_timetable_v1_timetable_proto_init() }
+func file_timetable_v1_timetable_proto_init() {
+	if File_timetable_v1_timetable_proto != nil {
+		return
+	}
+	file_timetable_v1_timetable_proto_msgTypes[12].OneofWrappers = []any{
+		(*GenerateScheduleResponse_Progress)(nil),
+		(*GenerateScheduleResponse_Schedule)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_timetable_v1_timetable_proto_rawDesc), len(file_timetable_v1_timetable_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   13,
+			NumExtensions: 0,
+			NumServices:   2,
+		},
+		GoTypes:           file_timetable_v1_timetable_proto_goTypes,
+		DependencyIndexes: file_timetable_v1_timetable_proto_depIdxs,
+		MessageInfos:      file_timetable_v1_timetable_proto_msgTypes,
+	}.Build()
+	File_timetable_v1_timetable_proto = out.File
+	file_timetable_v1_timetable_proto_goTypes = nil
+	file_timetable_v1_timetable_proto_depIdxs = nil
+}
@@ -0,0 +1,319 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: timetable/v1/timetable.proto
+
+package timetablev1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	SemesterService_GetSemester_FullMethodName   = "/timetable.v1.SemesterService/GetSemester"
+	SemesterService_ListSemesters_FullMethodName = "/timetable.v1.SemesterService/ListSemesters"
+)
+
+// SemesterServiceClient is the client API for SemesterService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// SemesterService exposes read access to semesters. Requires timetable:timetable:read.
+type SemesterServiceClient interface {
+	GetSemester(ctx context.Context, in *GetSemesterRequest, opts ...grpc.CallOption) (*GetSemesterResponse, error)
+	ListSemesters(ctx context.Context, in *ListSemestersRequest, opts ...grpc.CallOption) (*ListSemestersResponse, error)
+}
+
+type semesterServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSemesterServiceClient(cc grpc.ClientConnInterface) SemesterServiceClient {
+	return &semesterServiceClient{cc}
+}
+
+func (c *semesterServiceClient) GetSemester(ctx context.Context, in *GetSemesterRequest, opts ...grpc.CallOption) (*GetSemesterResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSemesterResponse)
+	err := c.cc.Invoke(ctx, SemesterService_GetSemester_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *semesterServiceClient) ListSemesters(ctx context.Context, in *ListSemestersRequest, opts ...grpc.CallOption) (*ListSemestersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSemestersResponse)
+	err := c.cc.Invoke(ctx, SemesterService_ListSemesters_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SemesterServiceServer is the server API for SemesterService service.
+// All implementations must embed UnimplementedSemesterServiceServer
+// for forward compatibility.
+//
+// SemesterService exposes read access to semesters. Requires timetable:timetable:read.
+type SemesterServiceServer interface {
+	GetSemester(context.Context, *GetSemesterRequest) (*GetSemesterResponse, error)
+	ListSemesters(context.Context, *ListSemestersRequest) (*ListSemestersResponse, error)
+	mustEmbedUnimplementedSemesterServiceServer()
+}
+
+// UnimplementedSemesterServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSemesterServiceServer struct{}
+
+func (UnimplementedSemesterServiceServer) GetSemester(context.Context, *GetSemesterRequest) (*GetSemesterResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetSemester not implemented")
+}
+func (UnimplementedSemesterServiceServer) ListSemesters(context.Context, *ListSemestersRequest) (*ListSemestersResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListSemesters not implemented")
+}
+func (UnimplementedSemesterServiceServer) mustEmbedUnimplementedSemesterServiceServer() {}
+func (UnimplementedSemesterServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeSemesterServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SemesterServiceServer will
+// result in compilation errors.
+type UnsafeSemesterServiceServer interface {
+	mustEmbedUnimplementedSemesterServiceServer()
+}
+
+func RegisterSemesterServiceServer(s grpc.ServiceRegistrar, srv SemesterServiceServer) {
+	// If the following call panics, it indicates UnimplementedSemesterServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SemesterService_ServiceDesc, srv)
+}
+
+func _SemesterService_GetSemester_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSemesterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SemesterServiceServer).GetSemester(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SemesterService_GetSemester_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SemesterServiceServer).GetSemester(ctx, req.(*GetSemesterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SemesterService_ListSemesters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSemestersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SemesterServiceServer).ListSemesters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SemesterService_ListSemesters_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SemesterServiceServer).ListSemesters(ctx, req.(*ListSemestersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SemesterService_ServiceDesc is the grpc.ServiceDesc for SemesterService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SemesterService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "timetable.v1.SemesterService",
+	HandlerType: (*SemesterServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetSemester",
+			Handler:    _SemesterService_GetSemester_Handler,
+		},
+		{
+			MethodName: "ListSemesters",
+			Handler:    _SemesterService_ListSemesters_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "timetable/v1/timetable.proto",
+}
+
+const (
+	ScheduleService_GetSchedule_FullMethodName      = "/timetable.v1.ScheduleService/GetSchedule"
+	ScheduleService_GenerateSchedule_FullMethodName = "/timetable.v1.ScheduleService/GenerateSchedule"
+)
+
+// ScheduleServiceClient is the client API for ScheduleService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ScheduleService exposes generated schedules. Requires timetable:timetable:read,
+// except GenerateSchedule which requires timetable:timetable:write.
+type ScheduleServiceClient interface {
+	GetSchedule(ctx context.Context, in *GetScheduleRequest, opts ...grpc.CallOption) (*GetScheduleResponse, error)
+	// GenerateSchedule runs the scheduler for a semester, streaming worker
+	// progress and finishing with the saved schedule. Cancelling the call stops
+	// the workers and saves nothing.
+	GenerateSchedule(ctx context.Context, in *GenerateScheduleRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GenerateScheduleResponse], error)
+}
+
+type scheduleServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewScheduleServiceClient(cc grpc.ClientConnInterface) ScheduleServiceClient {
+	return &scheduleServiceClient{cc}
+}
+
+func (c *scheduleServiceClient) GetSchedule(ctx context.Context, in *GetScheduleRequest, opts ...grpc.CallOption) (*GetScheduleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetScheduleResponse)
+	err := c.cc.Invoke(ctx, ScheduleService_GetSchedule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scheduleServiceClient) GenerateSchedule(ctx context.Context, in *GenerateScheduleRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GenerateScheduleResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ScheduleService_ServiceDesc.Streams[0], ScheduleService_GenerateSchedule_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GenerateScheduleRequest, GenerateScheduleResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ScheduleService_GenerateScheduleClient = grpc.ServerStreamingClient[GenerateScheduleResponse]
+
+// ScheduleServiceServer is the server API for ScheduleService service.
+// All implementations must embed UnimplementedScheduleServiceServer
+// for forward compatibility.
+//
+// ScheduleService exposes generated schedules. Requires timetable:timetable:read,
+// except GenerateSchedule which requires timetable:timetable:write.
+type ScheduleServiceServer interface {
+	GetSchedule(context.Context, *GetScheduleRequest) (*GetScheduleResponse, error)
+	// GenerateSchedule runs the scheduler for a semester, streaming worker
+	// progress and finishing with the saved schedule. Cancelling the call stops
+	// the workers and saves nothing.
+	GenerateSchedule(*GenerateScheduleRequest, grpc.ServerStreamingServer[GenerateScheduleResponse]) error
+	mustEmbedUnimplementedScheduleServiceServer()
+}
+
+// UnimplementedScheduleServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedScheduleServiceServer struct{}
+
+func (UnimplementedScheduleServiceServer) GetSchedule(context.Context, *GetScheduleRequest) (*GetScheduleResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetSchedule not implemented")
+}
+func (UnimplementedScheduleServiceServer) GenerateSchedule(*GenerateScheduleRequest, grpc.ServerStreamingServer[GenerateScheduleResponse]) error {
+	return status.Error(codes.Unimplemented, "method GenerateSchedule not implemented")
+}
+func (UnimplementedScheduleServiceServer) mustEmbedUnimplementedScheduleServiceServer() {}
+func (UnimplementedScheduleServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeScheduleServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ScheduleServiceServer will
+// result in compilation errors.
+type UnsafeScheduleServiceServer interface {
+	mustEmbedUnimplementedScheduleServiceServer()
+}
+
+func RegisterScheduleServiceServer(s grpc.ServiceRegistrar, srv ScheduleServiceServer) {
+	// If the following call panics, it indicates UnimplementedScheduleServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ScheduleService_ServiceDesc, srv)
+}
+
+func _ScheduleService_GetSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScheduleServiceServer).GetSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ScheduleService_GetSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScheduleServiceServer).GetSchedule(ctx, req.(*GetScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ScheduleService_GenerateSchedule_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GenerateScheduleRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ScheduleServiceServer).GenerateSchedule(m, &grpc.GenericServerStream[GenerateScheduleRequest, GenerateScheduleResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ScheduleService_GenerateScheduleServer = grpc.ServerStreamingServer[GenerateScheduleResponse]
+
+// ScheduleService_ServiceDesc is the grpc.ServiceDesc for ScheduleService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ScheduleService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "timetable.v1.ScheduleService",
+	HandlerType: (*ScheduleServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetSchedule",
+			Handler:    _ScheduleService_GetSchedule_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GenerateSchedule",
+			Handler:       _ScheduleService_GenerateSchedule_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "timetable/v1/timetable.proto",
+}
@@ -1,7 +1,9 @@
 import { useQuery, useMutation, useQueryClient } from "@tanstack/react-query";
 import { apiFetch } from "../lib/api-client";
+import { waitForJob } from "../lib/jobs";
 import { queryKeys } from "../query-keys";
-import type { Schedule, Assignment, UpdateAssignmentRequest } from "../types/timetable";
+import type { Job } from "../types/common";
+import type { Schedule, Assignment, UpdateAssignmentRequest, GenerateScheduleResult } from "../types/timetable";
 
 export function useSchedule(semesterId: string) {
   return useQuery({
@@ -11,11 +13,12 @@ export function useSchedule(semesterId: string) {
   });
 }
 
+/** Generation runs as a background job; the mutation settles when the job does. */
 export function useGenerateSchedule(semesterId: string) {
   const qc = useQueryClient();
   return useMutation({
-    mutationFn: () =>
-      apiFetch<Schedule>(`/timetable/semesters/${semesterId}/generate`, { method: "POST" }),
+    mutationFn: async () =>
+      waitForJob(await apiFetch<Job<GenerateScheduleResult>>(`/timetable/semesters/${semesterId}/generate`, { method: "POST" })),
     onSuccess: () => {
       qc.invalidateQueries({ queryKey: queryKeys.semesters.schedule(semesterId) });
       qc.invalidateQueries({ queryKey: queryKeys.semesters.detail(semesterId) });
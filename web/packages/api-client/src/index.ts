@@ -2,6 +2,7 @@
 
 // Core fetch wrapper
 export { apiFetch, ApiError, setAccessToken, getAccessToken } from "./lib/api-client";
+export { waitForJob } from "./lib/jobs";
 
 // Query keys
 export { queryKeys } from "./query-keys";
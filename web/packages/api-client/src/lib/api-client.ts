@@ -1,5 +1,7 @@
 // Fetch wrapper with JWT token management and auto-refresh on 401.
 
+import type { ApiErrorResponse } from "../types/common";
+
 const API_BASE_URL = typeof import.meta !== "undefined"
   ? (import.meta as any).env?.VITE_API_BASE_URL ?? ""
   : "";
@@ -79,10 +81,24 @@ async function tryRefresh(): Promise<boolean> {
 }
 
 export class ApiError extends Error {
+  /** Parsed problem details, when the body is one. */
+  public problem?: ApiErrorResponse;
+
   constructor(
     public status: number,
     public body: string,
   ) {
     super(`API error ${status}: ${body}`);
+    try {
+      const parsed = JSON.parse(body);
+      if (parsed && typeof parsed.code === "string") this.problem = parsed;
+    } catch {
+      // not JSON
+    }
+  }
+
+  /** Message for one field of a validation_failed error, if any. */
+  fieldError(field: string): string | undefined {
+    return this.problem?.errors?.find((e) => e.field === field)?.message;
   }
 }
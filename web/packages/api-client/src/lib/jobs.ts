@@ -0,0 +1,18 @@
+// Follows background jobs submitted by endpoints that answer 202 Accepted.
+
+import type { Job } from "../types/common";
+import { apiFetch } from "./api-client";
+
+const FINISHED = new Set<Job["status"]>(["succeeded", "failed", "cancelled"]);
+
+/** Polls the job until it finishes; resolves with it once succeeded, rejects otherwise. */
+export async function waitForJob<R>(job: Job<R>, intervalMs = 1000): Promise<Job<R>> {
+  while (!FINISHED.has(job.status)) {
+    await new Promise((resolve) => setTimeout(resolve, intervalMs));
+    job = await apiFetch<Job<R>>(`/jobs/${job.id}`);
+  }
+  if (job.status !== "succeeded") {
+    throw new Error(job.error ?? `job ${job.status}`);
+  }
+  return job;
+}
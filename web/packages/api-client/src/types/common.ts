@@ -3,6 +3,8 @@
 export interface ListResponse<T> {
   items: T[];
   total: number;
+  /** Pass as `cursor` to fetch the next page; absent on the last page. */
+  next_cursor?: string;
 }
 
 export interface PaginationParams {
@@ -10,6 +12,45 @@ export interface PaginationParams {
   limit?: number;
 }
 
+/** One invalid field of a request; `code` is stable (required, invalid, ...). */
+export interface FieldViolation {
+  field: string;
+  code: string;
+  message: string;
+}
+
+/** RFC 7807 problem details (application/problem+json) returned on every error. */
 export interface ApiErrorResponse {
-  error: string;
+  type: string;
+  title: string;
+  status: number;
+  detail?: string;
+  instance?: string;
+  /** Stable error code, e.g. validation_failed, not_found, conflict. */
+  code: string;
+  errors?: FieldViolation[];
+  request_id?: string;
+  [member: string]: unknown;
+}
+
+/** A background job. Long-running requests answer 202 Accepted with one; follow it at /jobs/{id}. */
+export interface Job<R = unknown> {
+  id: string;
+  kind: string;
+  status: "queued" | "running" | "succeeded" | "failed" | "cancelled";
+  payload: unknown;
+  /** Set once succeeded. */
+  result?: R;
+  /** Error of the last failed attempt. */
+  error?: string;
+  attempts: number;
+  max_attempts: number;
+  run_at: string;
+  cancel_requested: boolean;
+  created_by?: string;
+  request_id?: string;
+  created_at: string;
+  started_at?: string;
+  finished_at?: string;
+  updated_at: string;
 }
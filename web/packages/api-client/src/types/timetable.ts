@@ -41,6 +41,7 @@ export interface Assignment {
   day: number;     // 0-5 (Mon-Sat)
   period: number;  // 1-10
   version: number;
+  revision: number; // send as If-Match: "<revision>" when editing
 }
 
 export interface Schedule {
@@ -52,6 +53,15 @@ export interface Schedule {
   generated_at: string;
 }
 
+/** Result of the timetable.generate job. */
+export interface GenerateScheduleResult {
+  semester_id: string;
+  version: number;
+  assignments: number;
+  hard_violations: number;
+  soft_penalty: number;
+}
+
 export interface UpdateAssignmentRequest {
   teacher_id?: string;
   room_id?: string;